@@ -363,6 +363,8 @@ type Config struct {
 
 	EnableUnsupportedPathSupport bool `yaml:"enable-unsupported-path-support"`
 
+	Encryption EncryptionConfig `yaml:"encryption"`
+
 	FileCache FileCacheConfig `yaml:"file-cache"`
 
 	FileSystem FileSystemConfig `yaml:"file-system"`
@@ -375,6 +377,8 @@ type Config struct {
 
 	GcsRetries GcsRetriesConfig `yaml:"gcs-retries"`
 
+	GenerationManifest ResolvedPath `yaml:"generation-manifest"`
+
 	ImplicitDirs bool `yaml:"implicit-dirs"`
 
 	List ListConfig `yaml:"list"`
@@ -383,6 +387,8 @@ type Config struct {
 
 	MachineType string `yaml:"machine-type"`
 
+	MemoryPressure MemoryPressureConfig `yaml:"memory-pressure"`
+
 	MetadataCache MetadataCacheConfig `yaml:"metadata-cache"`
 
 	Metrics MetricsConfig `yaml:"metrics"`
@@ -395,12 +401,18 @@ type Config struct {
 
 	Read ReadConfig `yaml:"read"`
 
+	SnapshotTime string `yaml:"snapshot-time"`
+
+	Trash TrashConfig `yaml:"trash"`
+
 	WorkloadInsight WorkloadInsightConfig `yaml:"workload-insight"`
 
 	Write WriteConfig `yaml:"write"`
 }
 
 type DebugConfig struct {
+	ControlSocket ResolvedPath `yaml:"control-socket"`
+
 	ExitOnInvariantViolation bool `yaml:"exit-on-invariant-violation"`
 
 	Fuse bool `yaml:"fuse"`
@@ -410,17 +422,31 @@ type DebugConfig struct {
 	LogMutex bool `yaml:"log-mutex"`
 }
 
+type EncryptionConfig struct {
+	KeyFile ResolvedPath `yaml:"key-file"`
+
+	KeyPlugin ResolvedPath `yaml:"key-plugin"`
+}
+
 type FileCacheConfig struct {
 	CacheFileForRangeRead bool `yaml:"cache-file-for-range-read"`
 
+	DirReadaheadFiles int64 `yaml:"dir-readahead-files"`
+
 	DownloadChunkSizeMb int64 `yaml:"download-chunk-size-mb"`
 
 	EnableCrc bool `yaml:"enable-crc"`
 
+	EnableJournal bool `yaml:"enable-journal"`
+
 	EnableODirect bool `yaml:"enable-o-direct"`
 
 	EnableParallelDownloads bool `yaml:"enable-parallel-downloads"`
 
+	EnableSharedCache bool `yaml:"enable-shared-cache"`
+
+	EvictionPolicy string `yaml:"eviction-policy"`
+
 	ExcludeRegex string `yaml:"exclude-regex"`
 
 	ExperimentalParallelDownloadsDefaultOn bool `yaml:"experimental-parallel-downloads-default-on"`
@@ -433,6 +459,12 @@ type FileCacheConfig struct {
 
 	ParallelDownloadsPerFile int64 `yaml:"parallel-downloads-per-file"`
 
+	PrefetchConcurrency int64 `yaml:"prefetch-concurrency"`
+
+	PrefetchManifest ResolvedPath `yaml:"prefetch-manifest"`
+
+	PrefetchPaths []string `yaml:"prefetch-paths"`
+
 	WriteBufferSize int64 `yaml:"write-buffer-size"`
 }
 
@@ -441,6 +473,12 @@ type FileSystemConfig struct {
 
 	DisableParallelDirops bool `yaml:"disable-parallel-dirops"`
 
+	EnableHardLinks bool `yaml:"enable-hard-links"`
+
+	EnableVersionsDirs bool `yaml:"enable-versions-dirs"`
+
+	EnableXattrs bool `yaml:"enable-xattrs"`
+
 	ExperimentalEnableDentryCache bool `yaml:"experimental-enable-dentry-cache"`
 
 	ExperimentalEnableReaddirplus bool `yaml:"experimental-enable-readdirplus"`
@@ -453,6 +491,8 @@ type FileSystemConfig struct {
 
 	IgnoreInterrupts bool `yaml:"ignore-interrupts"`
 
+	InvalidateKernelCaches bool `yaml:"invalidate-kernel-caches"`
+
 	KernelListCacheTtlSecs int64 `yaml:"kernel-list-cache-ttl-secs"`
 
 	MaxReadAheadKb int64 `yaml:"max-read-ahead-kb"`
@@ -485,6 +525,10 @@ type GcsConnectionConfig struct {
 
 	CustomEndpoint string `yaml:"custom-endpoint"`
 
+	CustomerSuppliedKeyFile ResolvedPath `yaml:"customer-supplied-key-file"`
+
+	CustomerSuppliedKeyMap ResolvedPath `yaml:"customer-supplied-key-map"`
+
 	EnableHttpDnsCache bool `yaml:"enable-http-dns-cache"`
 
 	ExperimentalEnableJsonRead bool `yaml:"experimental-enable-json-read"`
@@ -540,7 +584,19 @@ type LoggingConfig struct {
 	Severity LogSeverity `yaml:"severity"`
 }
 
+type MemoryPressureConfig struct {
+	CriticalPercent int64 `yaml:"critical-percent"`
+
+	Enable bool `yaml:"enable"`
+
+	ModeratePercent int64 `yaml:"moderate-percent"`
+
+	SampleInterval time.Duration `yaml:"sample-interval"`
+}
+
 type MetadataCacheConfig struct {
+	ChangeFeed string `yaml:"change-feed"`
+
 	DeprecatedStatCacheCapacity int64 `yaml:"deprecated-stat-cache-capacity"`
 
 	DeprecatedStatCacheTtl time.Duration `yaml:"deprecated-stat-cache-ttl"`
@@ -549,10 +605,14 @@ type MetadataCacheConfig struct {
 
 	EnableNonexistentTypeCache bool `yaml:"enable-nonexistent-type-cache"`
 
+	EnablePersistence bool `yaml:"enable-persistence"`
+
 	ExperimentalMetadataPrefetchOnMount string `yaml:"experimental-metadata-prefetch-on-mount"`
 
 	NegativeTtlSecs int64 `yaml:"negative-ttl-secs"`
 
+	StatCacheEvictionPolicy string `yaml:"stat-cache-eviction-policy"`
+
 	StatCacheMaxSizeMb int64 `yaml:"stat-cache-max-size-mb"`
 
 	TtlSecs int64 `yaml:"ttl-secs"`
@@ -583,6 +643,8 @@ type MonitoringConfig struct {
 }
 
 type ReadConfig struct {
+	AdaptivePrefetch bool `yaml:"adaptive-prefetch"`
+
 	BlockSizeMb int64 `yaml:"block-size-mb"`
 
 	EnableBufferedRead bool `yaml:"enable-buffered-read"`
@@ -614,6 +676,14 @@ type ReadStallGcsRetriesConfig struct {
 	ReqTargetPercentile float64 `yaml:"req-target-percentile"`
 }
 
+type TrashConfig struct {
+	Enable bool `yaml:"enable"`
+
+	Prefix string `yaml:"prefix"`
+
+	Retention time.Duration `yaml:"retention"`
+}
+
 type WorkloadInsightConfig struct {
 	ForwardMergeThresholdMb int64 `yaml:"forward-merge-threshold-mb"`
 
@@ -631,10 +701,14 @@ type WriteConfig struct {
 
 	EnableStreamingWrites bool `yaml:"enable-streaming-writes"`
 
+	EnableUploadJournal bool `yaml:"enable-upload-journal"`
+
 	FinalizeFileForRapid bool `yaml:"finalize-file-for-rapid"`
 
 	GlobalMaxBlocks int64 `yaml:"global-max-blocks"`
 
+	InterruptedUploadAction string `yaml:"interrupted-upload-action"`
+
 	MaxBlocksPerFile int64 `yaml:"max-blocks-per-file"`
 }
 
@@ -648,6 +722,8 @@ func BuildFlagSet(flagSet *pflag.FlagSet) error {
 
 	flagSet.StringP("cache-dir", "", "", "Enables file-caching. Specifies the directory to use for file-cache.")
 
+	flagSet.StringP("change-feed", "", "", "Where to receive GCS object change notifications from, to drop the cached metadata and contents of changed objects without waiting for the TTLs: \"pubsub://projects/PROJECT/subscriptions/SUBSCRIPTION\" to pull from a Pub/Sub subscription of the bucket's notifications, \"http://HOST:PORT/PATH\" to serve a Pub/Sub push endpoint, which only accepts authenticated requests with \"?audience=AUDIENCE\" and listens on localhost without it unless HOST is given, or \"file:///PATH\" to follow a file with one push request body per line. Disabled if empty.")
+
 	flagSet.IntP("chunk-transfer-timeout-secs", "", 10, "We send larger file uploads in 16 MiB chunks. This flag controls the duration that the HTTP client will wait for a response after making a request to upload a chunk. As an example, a value of 10 indicates that the client will wait 10 seconds for upload completion; otherwise, it cancels the request and retries for that chunk till chunkRetryDeadline(32s). 0 means no timeout.")
 
 	if err := flagSet.MarkHidden("chunk-transfer-timeout-secs"); err != nil {
@@ -694,6 +770,8 @@ func BuildFlagSet(flagSet *pflag.FlagSet) error {
 		return err
 	}
 
+	flagSet.StringP("control-socket", "", "", "Path of a Unix domain socket to serve an HTTP/JSON API on, for inspecting and controlling the running mount with 'gcsfuse ctl'. Disabled if empty.")
+
 	flagSet.BoolP("create-empty-file", "", false, "For a new file, it creates an empty file in Cloud Storage bucket as a hold.")
 
 	if err := flagSet.MarkHidden("create-empty-file"); err != nil {
@@ -702,6 +780,10 @@ func BuildFlagSet(flagSet *pflag.FlagSet) error {
 
 	flagSet.StringP("custom-endpoint", "", "", "To specify a custom storage endpoint, ensure it supports the same resources as the default storage.googleapis.com:443 and includes the port number.")
 
+	flagSet.StringP("customer-supplied-key-file", "", "", "Path of a file holding a customer-supplied encryption key (CSEK), a 256-bit AES key, raw or base64 encoded, that objects are encrypted with in GCS. The key is passed on all the requests for the contents and metadata of objects, and objects written are encrypted with it.")
+
+	flagSet.StringP("customer-supplied-key-map", "", "", "Path of a file mapping prefixes of object names to the files of their customer-supplied encryption keys, one 'PREFIX KEY_FILE' per line. The longest prefix matching a name wins, and objects matching none use --customer-supplied-key-file, if set.")
+
 	flagSet.BoolP("debug_fs", "", false, "This flag is unused.")
 
 	if err := flagSet.MarkDeprecated("debug_fs", "This flag is currently unused."); err != nil {
@@ -776,6 +858,8 @@ func BuildFlagSet(flagSet *pflag.FlagSet) error {
 		return err
 	}
 
+	flagSet.BoolP("enable-hard-links", "", false, "Enables hard links on files. Linking makes a server-side copy of the backing object and records the link count in its metadata, so the new name does not share subsequent writes with the original.")
+
 	flagSet.BoolP("enable-hns", "", true, "Enables support for HNS buckets")
 
 	if err := flagSet.MarkHidden("enable-hns"); err != nil {
@@ -806,12 +890,22 @@ func BuildFlagSet(flagSet *pflag.FlagSet) error {
 
 	flagSet.BoolP("enable-streaming-writes", "", true, "Enables streaming uploads during write file operation.")
 
+	flagSet.BoolP("enable-trash", "", false, "Moves the objects of unlinked files, and the placeholder objects of removed directories, into a trash prefix of the bucket instead of deleting them, so that they can be restored. Objects already in the trash are deleted.")
+
 	flagSet.BoolP("enable-unsupported-path-support", "", false, "Enables support for file system paths with unsupported GCS names (e.g., names containing '//' or starting with /).  When set, GCSFuse will ignore these objects during listing and copying operations.  For rename and delete operations, the flag allows the action to proceed for all specified objects, including those with unsupported names.")
 
 	if err := flagSet.MarkHidden("enable-unsupported-path-support"); err != nil {
 		return err
 	}
 
+	flagSet.BoolP("enable-versions-dirs", "", false, "Exposes the noncurrent generations of each object in a versioned bucket as read-only files in a virtual directory named after the object with an \"@versions\" suffix, e.g. \"file.txt@versions\". These directories are not listed in their parents.")
+
+	flagSet.BoolP("enable-xattrs", "", false, "Enables extended attributes on files. Attributes in the user namespace are stored as custom metadata on the backing object, and read-only attributes in the gcsfuse namespace expose the object's generation, metageneration, content type, storage class and CRC32C checksum.")
+
+	flagSet.StringP("encryption-key-file", "", "", "Path of a file holding a 256-bit key-encryption key, raw or base64 encoded. When set, the contents of objects written are encrypted on the client with per-object data keys wrapped by this key, and encrypted objects are decrypted when read.")
+
+	flagSet.StringP("encryption-key-plugin", "", "", "Path of an executable that wraps and unwraps the data keys of encrypted objects, e.g. with a KMS, instead of a local key file. It is run as '<plugin> id', '<plugin> wrap' or '<plugin> unwrap', with the key on standard input and the result on standard output.")
+
 	flagSet.BoolP("experimental-enable-dentry-cache", "", false, "When enabled, it sets the Dentry cache entry timeout same as metadata-cache-ttl. This enables kernel to use cached entry to map the file paths to inodes, instead of making LookUpInode calls to GCSFuse.")
 
 	if err := flagSet.MarkHidden("experimental-enable-dentry-cache"); err != nil {
@@ -868,6 +962,8 @@ func BuildFlagSet(flagSet *pflag.FlagSet) error {
 
 	flagSet.BoolP("file-cache-cache-file-for-range-read", "", false, "Whether to cache file for range reads.")
 
+	flagSet.IntP("file-cache-dir-readahead-files", "", 0, "When files of a directory are opened one after the other in lexical order, as data loaders reading shards do, prefetch this many of the following files of the directory into the file-cache before they are opened. 0 disables it.")
+
 	flagSet.IntP("file-cache-download-chunk-size-mb", "", 200, "Size of chunks in MiB that each concurrent request downloads.")
 
 	flagSet.BoolP("file-cache-enable-crc", "", false, "Performs CRC to ensure that file is correctly downloaded into cache. No op for rapid storage.")
@@ -876,6 +972,8 @@ func BuildFlagSet(flagSet *pflag.FlagSet) error {
 		return err
 	}
 
+	flagSet.BoolP("file-cache-enable-journal", "", false, "Keeps a journal of the files in the file-cache so that they are reused, instead of downloaded again, after gcsfuse restarts. Only supported when a single bucket is mounted.")
+
 	flagSet.BoolP("file-cache-enable-o-direct", "", false, "Whether to use O_DIRECT while writing to file-cache in case of parallel downloads.")
 
 	if err := flagSet.MarkHidden("file-cache-enable-o-direct"); err != nil {
@@ -884,6 +982,10 @@ func BuildFlagSet(flagSet *pflag.FlagSet) error {
 
 	flagSet.BoolP("file-cache-enable-parallel-downloads", "", false, "Enable parallel downloads.")
 
+	flagSet.BoolP("file-cache-enable-shared-cache", "", false, "Lets several gcsfuse processes on the host share the file-cache in cache-dir, so that an object is downloaded only once across them. file-cache-max-size-mb then bounds the total size of the files downloaded by all of them.")
+
+	flagSet.StringP("file-cache-eviction-policy", "", "lru", "The policy deciding which files are evicted from the file-cache when it is full. Supported values: \"lru\", \"tinylfu\" and \"2q\". See stat-cache-eviction-policy.")
+
 	flagSet.StringP("file-cache-exclude-regex", "", "", "Exclude file paths (in the format bucket_name/object_key) specified by this regex from file caching.")
 
 	if err := flagSet.MarkHidden("file-cache-exclude-regex"); err != nil {
@@ -908,6 +1010,12 @@ func BuildFlagSet(flagSet *pflag.FlagSet) error {
 
 	flagSet.IntP("file-cache-parallel-downloads-per-file", "", 16, "Number of concurrent download requests per file.")
 
+	flagSet.IntP("file-cache-prefetch-concurrency", "", 16, "The maximum number of objects prefetched into the file-cache at a time, across file-cache-prefetch-paths, file-cache-prefetch-manifest and the prefetch control action.")
+
+	flagSet.StringP("file-cache-prefetch-manifest", "", "", "A file listing paths or glob patterns to prefetch into the file-cache after mounting, one per line, like file-cache-prefetch-paths. Blank lines and lines starting with # are ignored.")
+
+	flagSet.StringSliceP("file-cache-prefetch-paths", "", []string{}, "Paths, relative to the mount point, of the objects to prefetch into the file-cache in the background after mounting. A path matches the objects whose names start with it, unless it contains glob characters (*, ? or [), in which case it is matched as a shell pattern. When all buckets are mounted, paths start with the bucket name.")
+
 	flagSet.IntP("file-cache-write-buffer-size", "", 4194304, "Size of in-memory buffer that is used per goroutine in parallel downloads while writing to file-cache.")
 
 	if err := flagSet.MarkHidden("file-cache-write-buffer-size"); err != nil {
@@ -924,6 +1032,8 @@ func BuildFlagSet(flagSet *pflag.FlagSet) error {
 
 	flagSet.BoolP("foreground", "", false, "Stay in the foreground after mounting.")
 
+	flagSet.StringP("generation-manifest", "", "", "Mount a read-only snapshot of the bucket made of the object generations listed in this file, one \"gs://BUCKET/OBJECT#GENERATION\" per line, as printed by \"gcloud storage ls -a\". Objects that aren't listed don't exist in the mount. Can't be combined with snapshot-time.")
+
 	flagSet.IntP("gid", "", -1, "GID owner of all inodes.")
 
 	flagSet.DurationP("http-client-timeout", "", 0*time.Nanosecond, "The time duration that http client will wait to get response from the server. A value of 0 indicates no timeout.")
@@ -932,6 +1042,8 @@ func BuildFlagSet(flagSet *pflag.FlagSet) error {
 
 	flagSet.BoolP("implicit-dirs", "", false, "Implicitly define directories based on content. See files and directories in docs/semantics for more information")
 
+	flagSet.BoolP("invalidate-kernel-caches", "", false, "Asks the kernel to drop what it cached for inodes that lookups and listings find backed by an older generation than GCS, and for the paths invalidated through the control socket or the change feed. Checking the objects fetched from GCS costs some CPU on every lookup and listing.")
+
 	flagSet.IntP("kernel-list-cache-ttl-secs", "", 0, "How long the directory listing (output of ls <dir>) should be cached in the kernel page cache. If a particular directory cache entry is kept by kernel for longer than TTL, then it will be sent for invalidation by gcsfuse on next opendir (comes in the start, as part of next listing) call. 0 means no caching. Use -1 to cache for lifetime (no ttl). Negative value other than -1 will throw error.")
 
 	flagSet.StringP("key-file", "", "", "Absolute path to JSON key file for use with GCS. If this flag is left unset, Google application default credentials are used.")
@@ -978,6 +1090,16 @@ func BuildFlagSet(flagSet *pflag.FlagSet) error {
 
 	flagSet.DurationP("max-retry-sleep", "", 30000000000*time.Nanosecond, "The maximum duration allowed to sleep in a retry loop with exponential backoff for failed requests to GCS backend. Once the backoff duration exceeds this limit, the retry continues with this specified maximum value.")
 
+	flagSet.IntP("memory-pressure-critical-percent", "", 90, "The memory usage, as a percentage of the memory limit of the cgroup of gcsfuse, at which the memory pressure is critical: no more blocks are allocated for buffered reads and streaming writes.")
+
+	flagSet.BoolP("memory-pressure-enable", "", false, "Watch the memory usage of the cgroup v2 of gcsfuse (memory.current less the inactive page cache against memory.max, and memory.pressure) and lower read-global-max-blocks and write-global-max-blocks as it nears the limit, to avoid being killed for running out of memory.")
+
+	flagSet.IntP("memory-pressure-moderate-percent", "", 80, "The memory usage, as a percentage of the memory limit of the cgroup of gcsfuse, at which the memory pressure is moderate: blocks are no longer prefetched for buffered reads, and fewer are used by streaming writes.")
+
+	flagSet.DurationP("memory-pressure-sample-interval", "", 1000000000*time.Nanosecond, "How often the memory usage of the cgroup of gcsfuse is sampled.")
+
+	flagSet.BoolP("metadata-cache-enable-persistence", "", false, "Persists the stat and type caches to a snapshot under cache-dir on unmount, and reloads it on the next mount of the same bucket and only-dir on the same mount point with the same metadata-cache config. Entries keep their original expiry time. Requires cache-dir, and applies only to mounts of a single bucket.")
+
 	flagSet.IntP("metadata-cache-negative-ttl-secs", "", 5, "The negative-ttl-secs value in seconds to be used for expiring negative entries in metadata-cache. It can be set to -1 for no-ttl, 0 for no cache and > 0 for ttl-controlled negative entries in metadata-cache. Any value set below -1 will throw an error.")
 
 	flagSet.IntP("metadata-cache-ttl-secs", "", 60, "The ttl value in seconds to be used for expiring items in metadata-cache. It can be set to -1 for no-ttl, 0 for no cache and > 0 for ttl-controlled metadata-cache. Any value set below -1 will throw an error.")
@@ -1020,6 +1142,8 @@ func BuildFlagSet(flagSet *pflag.FlagSet) error {
 
 	flagSet.IntP("prometheus-port", "", 0, "Expose Prometheus metrics endpoint on this port and a path of /metrics.")
 
+	flagSet.BoolP("read-adaptive-prefetch", "", false, "With buffered reads, adapt the number of blocks prefetched by each file handle, up to read-max-blocks-per-handle, to how fast it is read and how long blocks take to download: more when reads wait for blocks, fewer when prefetched blocks are discarded unread. The blocks of read-global-max-blocks are shared evenly among the file handles reading.")
+
 	flagSet.IntP("read-block-size-mb", "", 16, "Specifies the block size for buffered reads. The value should be more than 0. This is used to read data in chunks from GCS.")
 
 	if err := flagSet.MarkHidden("read-block-size-mb"); err != nil {
@@ -1096,6 +1220,8 @@ func BuildFlagSet(flagSet *pflag.FlagSet) error {
 
 	flagSet.IntP("sequential-read-size-mb", "", 200, "File chunk size to read from GCS in one call. Need to specify the value in MB. ChunkSize less than 1MB is not supported")
 
+	flagSet.StringP("snapshot-time", "", "", "Mount a read-only snapshot of the bucket as it was at this RFC 3339 time, e.g. \"2026-01-02T15:04:05Z\", made of the object generations that were live then. Requires object versioning on the bucket, for the generations replaced or deleted since to be listed. Can't be combined with generation-manifest.")
+
 	flagSet.DurationP("stackdriver-export-interval", "", 0*time.Nanosecond, "Export metrics to stackdriver with this interval. A value of 0 indicates no exporting.")
 
 	if err := flagSet.MarkDeprecated("stackdriver-export-interval", "Please use --cloud-metrics-export-interval-secs instead."); err != nil {
@@ -1108,6 +1234,8 @@ func BuildFlagSet(flagSet *pflag.FlagSet) error {
 		return err
 	}
 
+	flagSet.StringP("stat-cache-eviction-policy", "", "lru", "The policy deciding which entries of the stat-cache are evicted when it is full. Supported values: \"lru\" (least recently used), \"tinylfu\" (least recently used, but only admitting new entries that are used more often than the ones they would evict) and \"2q\" (which protects entries used more than once from one-off scans).")
+
 	flagSet.IntP("stat-cache-max-size-mb", "", 33, "The maximum size of stat-cache in MiBs. It can also be set to -1 for no-size-limit, 0 for no cache. Values below -1 are not supported.")
 
 	flagSet.DurationP("stat-cache-ttl", "", 60000000000*time.Nanosecond, "How long to cache StatObject results and inode attributes. This flag has been deprecated (starting v2.0) in favor of metadata-cache-ttl-secs. For now, the minimum of stat-cache-ttl and type-cache-ttl values, rounded up to the next higher multiple of a second is used as ttl for both stat-cache and type-cache, when metadata-cache-ttl-secs is not set.")
@@ -1120,6 +1248,10 @@ func BuildFlagSet(flagSet *pflag.FlagSet) error {
 
 	flagSet.StringP("token-url", "", "", "A url for getting an access token when the key-file is absent.")
 
+	flagSet.StringP("trash-prefix", "", ".trash/", "The prefix, relative to the mounted directory, under which unlinked objects are kept when trash is enabled, each in a subdirectory named after the time it was unlinked.")
+
+	flagSet.DurationP("trash-retention", "", 604800000000000*time.Nanosecond, "How long objects are kept in the trash before they are purged. A value of '0s' keeps them forever.")
+
 	flagSet.IntP("type-cache-max-size-mb", "", 4, "Max size of type-cache maps which are maintained at a per-directory level.")
 
 	flagSet.DurationP("type-cache-ttl", "", 60000000000*time.Nanosecond, "Usage: How long to cache StatObject results and inode attributes. This flag has been deprecated (starting v2.0) in favor of metadata-cache-ttl-secs. For now, the minimum of stat-cache-ttl and type-cache-ttl values, rounded up to the next higher multiple of a second is used as ttl for both stat-cache and type-cache, when metadata-cache-ttl-secs is not set.")
@@ -1154,8 +1286,12 @@ func BuildFlagSet(flagSet *pflag.FlagSet) error {
 		return err
 	}
 
+	flagSet.BoolP("write-enable-upload-journal", "", false, "Journals the streaming uploads in progress under cache-dir, so that those interrupted by a crash are resumed or aborted, as set by write-interrupted-upload-action, and reported on the next mount of the same bucket and only-dir on the same mount point. Requires cache-dir, and applies only to mounts of a single bucket. Only uploads to zonal buckets can be resumed: those to other buckets send their data in resumable upload sessions, which are cancelled or left to expire since the data left to send was lost with the crashed process, and are reported as lost.")
+
 	flagSet.IntP("write-global-max-blocks", "", 4, "Specifies the maximum number of blocks available for streaming writes across all files. The value should be >= 0 or -1 (for infinite blocks). A value of 0 disables streaming writes.")
 
+	flagSet.StringP("write-interrupted-upload-action", "", "resume", "What to do at mount with the streaming uploads journaled by write-enable-upload-journal that didn't complete. Supported values: \"resume\", which keeps the data appended to unfinalized objects so that writing can resume where it stopped, and \"abort\", which deletes the unfinalized objects the interrupted uploads created and cancels their resumable upload sessions.")
+
 	flagSet.IntP("write-max-blocks-per-file", "", 1, "Specifies the maximum number of blocks to be used by a single file for streaming writes. The value should be >= 1 or -1 (for infinite blocks).")
 
 	if err := flagSet.MarkHidden("write-max-blocks-per-file"); err != nil {
@@ -1183,6 +1319,10 @@ func BindFlags(v *viper.Viper, flagSet *pflag.FlagSet) error {
 		return err
 	}
 
+	if err := v.BindPFlag("metadata-cache.change-feed", flagSet.Lookup("change-feed")); err != nil {
+		return err
+	}
+
 	if err := v.BindPFlag("gcs-retries.chunk-transfer-timeout-secs", flagSet.Lookup("chunk-transfer-timeout-secs")); err != nil {
 		return err
 	}
@@ -1219,6 +1359,10 @@ func BindFlags(v *viper.Viper, flagSet *pflag.FlagSet) error {
 		return err
 	}
 
+	if err := v.BindPFlag("debug.control-socket", flagSet.Lookup("control-socket")); err != nil {
+		return err
+	}
+
 	if err := v.BindPFlag("write.create-empty-file", flagSet.Lookup("create-empty-file")); err != nil {
 		return err
 	}
@@ -1227,6 +1371,14 @@ func BindFlags(v *viper.Viper, flagSet *pflag.FlagSet) error {
 		return err
 	}
 
+	if err := v.BindPFlag("gcs-connection.customer-supplied-key-file", flagSet.Lookup("customer-supplied-key-file")); err != nil {
+		return err
+	}
+
+	if err := v.BindPFlag("gcs-connection.customer-supplied-key-map", flagSet.Lookup("customer-supplied-key-map")); err != nil {
+		return err
+	}
+
 	if err := v.BindPFlag("debug.fuse", flagSet.Lookup("debug_fuse")); err != nil {
 		return err
 	}
@@ -1275,6 +1427,10 @@ func BindFlags(v *viper.Viper, flagSet *pflag.FlagSet) error {
 		return err
 	}
 
+	if err := v.BindPFlag("file-system.enable-hard-links", flagSet.Lookup("enable-hard-links")); err != nil {
+		return err
+	}
+
 	if err := v.BindPFlag("enable-hns", flagSet.Lookup("enable-hns")); err != nil {
 		return err
 	}
@@ -1303,10 +1459,30 @@ func BindFlags(v *viper.Viper, flagSet *pflag.FlagSet) error {
 		return err
 	}
 
+	if err := v.BindPFlag("trash.enable", flagSet.Lookup("enable-trash")); err != nil {
+		return err
+	}
+
 	if err := v.BindPFlag("enable-unsupported-path-support", flagSet.Lookup("enable-unsupported-path-support")); err != nil {
 		return err
 	}
 
+	if err := v.BindPFlag("file-system.enable-versions-dirs", flagSet.Lookup("enable-versions-dirs")); err != nil {
+		return err
+	}
+
+	if err := v.BindPFlag("file-system.enable-xattrs", flagSet.Lookup("enable-xattrs")); err != nil {
+		return err
+	}
+
+	if err := v.BindPFlag("encryption.key-file", flagSet.Lookup("encryption-key-file")); err != nil {
+		return err
+	}
+
+	if err := v.BindPFlag("encryption.key-plugin", flagSet.Lookup("encryption-key-plugin")); err != nil {
+		return err
+	}
+
 	if err := v.BindPFlag("file-system.experimental-enable-dentry-cache", flagSet.Lookup("experimental-enable-dentry-cache")); err != nil {
 		return err
 	}
@@ -1347,6 +1523,10 @@ func BindFlags(v *viper.Viper, flagSet *pflag.FlagSet) error {
 		return err
 	}
 
+	if err := v.BindPFlag("file-cache.dir-readahead-files", flagSet.Lookup("file-cache-dir-readahead-files")); err != nil {
+		return err
+	}
+
 	if err := v.BindPFlag("file-cache.download-chunk-size-mb", flagSet.Lookup("file-cache-download-chunk-size-mb")); err != nil {
 		return err
 	}
@@ -1355,6 +1535,10 @@ func BindFlags(v *viper.Viper, flagSet *pflag.FlagSet) error {
 		return err
 	}
 
+	if err := v.BindPFlag("file-cache.enable-journal", flagSet.Lookup("file-cache-enable-journal")); err != nil {
+		return err
+	}
+
 	if err := v.BindPFlag("file-cache.enable-o-direct", flagSet.Lookup("file-cache-enable-o-direct")); err != nil {
 		return err
 	}
@@ -1363,6 +1547,14 @@ func BindFlags(v *viper.Viper, flagSet *pflag.FlagSet) error {
 		return err
 	}
 
+	if err := v.BindPFlag("file-cache.enable-shared-cache", flagSet.Lookup("file-cache-enable-shared-cache")); err != nil {
+		return err
+	}
+
+	if err := v.BindPFlag("file-cache.eviction-policy", flagSet.Lookup("file-cache-eviction-policy")); err != nil {
+		return err
+	}
+
 	if err := v.BindPFlag("file-cache.exclude-regex", flagSet.Lookup("file-cache-exclude-regex")); err != nil {
 		return err
 	}
@@ -1387,6 +1579,18 @@ func BindFlags(v *viper.Viper, flagSet *pflag.FlagSet) error {
 		return err
 	}
 
+	if err := v.BindPFlag("file-cache.prefetch-concurrency", flagSet.Lookup("file-cache-prefetch-concurrency")); err != nil {
+		return err
+	}
+
+	if err := v.BindPFlag("file-cache.prefetch-manifest", flagSet.Lookup("file-cache-prefetch-manifest")); err != nil {
+		return err
+	}
+
+	if err := v.BindPFlag("file-cache.prefetch-paths", flagSet.Lookup("file-cache-prefetch-paths")); err != nil {
+		return err
+	}
+
 	if err := v.BindPFlag("file-cache.write-buffer-size", flagSet.Lookup("file-cache-write-buffer-size")); err != nil {
 		return err
 	}
@@ -1403,6 +1607,10 @@ func BindFlags(v *viper.Viper, flagSet *pflag.FlagSet) error {
 		return err
 	}
 
+	if err := v.BindPFlag("generation-manifest", flagSet.Lookup("generation-manifest")); err != nil {
+		return err
+	}
+
 	if err := v.BindPFlag("file-system.gid", flagSet.Lookup("gid")); err != nil {
 		return err
 	}
@@ -1419,6 +1627,10 @@ func BindFlags(v *viper.Viper, flagSet *pflag.FlagSet) error {
 		return err
 	}
 
+	if err := v.BindPFlag("file-system.invalidate-kernel-caches", flagSet.Lookup("invalidate-kernel-caches")); err != nil {
+		return err
+	}
+
 	if err := v.BindPFlag("file-system.kernel-list-cache-ttl-secs", flagSet.Lookup("kernel-list-cache-ttl-secs")); err != nil {
 		return err
 	}
@@ -1483,6 +1695,26 @@ func BindFlags(v *viper.Viper, flagSet *pflag.FlagSet) error {
 		return err
 	}
 
+	if err := v.BindPFlag("memory-pressure.critical-percent", flagSet.Lookup("memory-pressure-critical-percent")); err != nil {
+		return err
+	}
+
+	if err := v.BindPFlag("memory-pressure.enable", flagSet.Lookup("memory-pressure-enable")); err != nil {
+		return err
+	}
+
+	if err := v.BindPFlag("memory-pressure.moderate-percent", flagSet.Lookup("memory-pressure-moderate-percent")); err != nil {
+		return err
+	}
+
+	if err := v.BindPFlag("memory-pressure.sample-interval", flagSet.Lookup("memory-pressure-sample-interval")); err != nil {
+		return err
+	}
+
+	if err := v.BindPFlag("metadata-cache.enable-persistence", flagSet.Lookup("metadata-cache-enable-persistence")); err != nil {
+		return err
+	}
+
 	if err := v.BindPFlag("metadata-cache.negative-ttl-secs", flagSet.Lookup("metadata-cache-negative-ttl-secs")); err != nil {
 		return err
 	}
@@ -1527,6 +1759,10 @@ func BindFlags(v *viper.Viper, flagSet *pflag.FlagSet) error {
 		return err
 	}
 
+	if err := v.BindPFlag("read.adaptive-prefetch", flagSet.Lookup("read-adaptive-prefetch")); err != nil {
+		return err
+	}
+
 	if err := v.BindPFlag("read.block-size-mb", flagSet.Lookup("read-block-size-mb")); err != nil {
 		return err
 	}
@@ -1591,6 +1827,10 @@ func BindFlags(v *viper.Viper, flagSet *pflag.FlagSet) error {
 		return err
 	}
 
+	if err := v.BindPFlag("snapshot-time", flagSet.Lookup("snapshot-time")); err != nil {
+		return err
+	}
+
 	if err := v.BindPFlag("metrics.stackdriver-export-interval", flagSet.Lookup("stackdriver-export-interval")); err != nil {
 		return err
 	}
@@ -1599,6 +1839,10 @@ func BindFlags(v *viper.Viper, flagSet *pflag.FlagSet) error {
 		return err
 	}
 
+	if err := v.BindPFlag("metadata-cache.stat-cache-eviction-policy", flagSet.Lookup("stat-cache-eviction-policy")); err != nil {
+		return err
+	}
+
 	if err := v.BindPFlag("metadata-cache.stat-cache-max-size-mb", flagSet.Lookup("stat-cache-max-size-mb")); err != nil {
 		return err
 	}
@@ -1615,6 +1859,14 @@ func BindFlags(v *viper.Viper, flagSet *pflag.FlagSet) error {
 		return err
 	}
 
+	if err := v.BindPFlag("trash.prefix", flagSet.Lookup("trash-prefix")); err != nil {
+		return err
+	}
+
+	if err := v.BindPFlag("trash.retention", flagSet.Lookup("trash-retention")); err != nil {
+		return err
+	}
+
 	if err := v.BindPFlag("metadata-cache.type-cache-max-size-mb", flagSet.Lookup("type-cache-max-size-mb")); err != nil {
 		return err
 	}
@@ -1643,10 +1895,18 @@ func BindFlags(v *viper.Viper, flagSet *pflag.FlagSet) error {
 		return err
 	}
 
+	if err := v.BindPFlag("write.enable-upload-journal", flagSet.Lookup("write-enable-upload-journal")); err != nil {
+		return err
+	}
+
 	if err := v.BindPFlag("write.global-max-blocks", flagSet.Lookup("write-global-max-blocks")); err != nil {
 		return err
 	}
 
+	if err := v.BindPFlag("write.interrupted-upload-action", flagSet.Lookup("write-interrupted-upload-action")); err != nil {
+		return err
+	}
+
 	if err := v.BindPFlag("write.max-blocks-per-file", flagSet.Lookup("write-max-blocks-per-file")); err != nil {
 		return err
 	}
@@ -48,6 +48,26 @@ const (
 	ExperimentalMetadataPrefetchOnMountAsynchronous = "async"
 )
 
+const (
+	// EvictionPolicyLRU evicts the least recently used entries of a cache.
+	EvictionPolicyLRU = "lru"
+	// EvictionPolicyTinyLFU evicts like EvictionPolicyLRU, but admits a new
+	// entry only if it is used more often than the entry it would evict.
+	EvictionPolicyTinyLFU = "tinylfu"
+	// EvictionPolicy2Q keeps entries used more than once apart from those used
+	// just once, and evicts the latter first.
+	EvictionPolicy2Q = "2q"
+)
+
+const (
+	// InterruptedUploadActionResume keeps the data of interrupted streaming
+	// uploads that reached GCS, so that writing can resume.
+	InterruptedUploadActionResume = "resume"
+	// InterruptedUploadActionAbort deletes the unfinalized objects created by
+	// interrupted streaming uploads.
+	InterruptedUploadActionAbort = "abort"
+)
+
 const (
 	// maxSequentialReadSizeMb is the max value supported by sequential-read-size-mb flag.
 	maxSequentialReadSizeMB = 1024
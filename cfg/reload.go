@@ -0,0 +1,101 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfg
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// ReloadableConfigPaths are the config paths whose values can be changed
+// without remounting, by reloading the config.
+var ReloadableConfigPaths = []string{
+	"gcs-connection.limit-bytes-per-sec",
+	"gcs-connection.limit-ops-per-sec",
+	"logging.severity",
+	"metadata-cache.negative-ttl-secs",
+	"metadata-cache.ttl-secs",
+	"read.global-max-blocks",
+	"write.global-max-blocks",
+}
+
+// ConfigChange is a change of the value at a config path, such as
+// "metadata-cache.ttl-secs".
+type ConfigChange struct {
+	Path     string
+	Old, New any
+
+	// RequiresRemount is true if the new value can't take effect without
+	// remounting.
+	RequiresRemount bool
+}
+
+func (c ConfigChange) String() string {
+	return fmt.Sprintf("%s: %v -> %v", c.Path, c.Old, c.New)
+}
+
+// DiffConfigs returns the changes from the old config to the new one, in the
+// order of the fields of Config.
+func DiffConfigs(oldConfig, newConfig *Config) []ConfigChange {
+	var changes []ConfigChange
+	diffValues("", reflect.ValueOf(*oldConfig), reflect.ValueOf(*newConfig), &changes)
+	for i := range changes {
+		changes[i].RequiresRemount = requiresRemount(oldConfig, newConfig, changes[i].Path)
+	}
+	return changes
+}
+
+func diffValues(path string, oldValue, newValue reflect.Value, changes *[]ConfigChange) {
+	if oldValue.Kind() != reflect.Struct {
+		if !reflect.DeepEqual(oldValue.Interface(), newValue.Interface()) {
+			*changes = append(*changes, ConfigChange{
+				Path: path,
+				Old:  oldValue.Interface(),
+				New:  newValue.Interface(),
+			})
+		}
+		return
+	}
+
+	t := oldValue.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		if path != "" {
+			name = path + "." + name
+		}
+		diffValues(name, oldValue.Field(i), newValue.Field(i), changes)
+	}
+}
+
+// requiresRemount returns true if a change of the value at the supplied path
+// can't be applied to the file system mounted with the old config.
+func requiresRemount(oldConfig, newConfig *Config, path string) bool {
+	switch path {
+	case "gcs-connection.limit-bytes-per-sec", "gcs-connection.limit-ops-per-sec":
+		// Rate limiting is only set up if either limit was enabled at mount.
+		return !(oldConfig.GcsConnection.LimitOpsPerSec > 0 || oldConfig.GcsConnection.LimitBytesPerSec > 0)
+	case "metadata-cache.ttl-secs", "metadata-cache.negative-ttl-secs":
+		// The stat cache isn't set up at all with a zero TTL, and enabling or
+		// disabling it changes the behaviour of the kernel caches too.
+		return oldConfig.MetadataCache.TtlSecs == 0 || newConfig.MetadataCache.TtlSecs == 0
+	default:
+		return !slices.Contains(ReloadableConfigPaths, path)
+	}
+}
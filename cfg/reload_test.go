@@ -0,0 +1,108 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func reloadTestConfig() *Config {
+	return &Config{
+		GcsConnection: GcsConnectionConfig{LimitOpsPerSec: 10, LimitBytesPerSec: -1},
+		Logging:       LoggingConfig{Severity: InfoLogSeverity},
+		MetadataCache: MetadataCacheConfig{TtlSecs: 60, NegativeTtlSecs: 5},
+		FileCache:     FileCacheConfig{MaxSizeMb: 100},
+		OnlyDir:       "a",
+	}
+}
+
+func TestDiffConfigsWithoutChanges(t *testing.T) {
+	assert.Empty(t, DiffConfigs(reloadTestConfig(), reloadTestConfig()))
+}
+
+func TestDiffConfigsWithReloadableChanges(t *testing.T) {
+	newConfig := reloadTestConfig()
+	newConfig.GcsConnection.LimitBytesPerSec = 1024
+	newConfig.Logging.Severity = TraceLogSeverity
+	newConfig.MetadataCache.TtlSecs = 30
+
+	changes := DiffConfigs(reloadTestConfig(), newConfig)
+
+	assert.Equal(t, []ConfigChange{
+		{Path: "gcs-connection.limit-bytes-per-sec", Old: float64(-1), New: float64(1024)},
+		{Path: "logging.severity", Old: InfoLogSeverity, New: TraceLogSeverity},
+		{Path: "metadata-cache.ttl-secs", Old: int64(60), New: int64(30)},
+	}, changes)
+	assert.Equal(t, "metadata-cache.ttl-secs: 60 -> 30", changes[2].String())
+}
+
+func TestDiffConfigsWithChangesRequiringRemount(t *testing.T) {
+	testCases := []struct {
+		name   string
+		old    func(*Config)
+		change func(*Config)
+		path   string
+	}{
+		{
+			name:   "not_reloadable",
+			change: func(c *Config) { c.OnlyDir = "b" },
+			path:   "only-dir",
+		},
+		{
+			name:   "nested_not_reloadable",
+			change: func(c *Config) { c.FileCache.MaxSizeMb = 200 },
+			path:   "file-cache.max-size-mb",
+		},
+		{
+			name:   "disable_metadata_cache",
+			change: func(c *Config) { c.MetadataCache.TtlSecs = 0 },
+			path:   "metadata-cache.ttl-secs",
+		},
+		{
+			name:   "enable_metadata_cache",
+			old:    func(c *Config) { c.MetadataCache.TtlSecs = 0 },
+			change: func(c *Config) { c.MetadataCache.NegativeTtlSecs = 10 },
+			path:   "metadata-cache.negative-ttl-secs",
+		},
+		{
+			name:   "enable_rate_limiting",
+			old:    func(c *Config) { c.GcsConnection.LimitOpsPerSec = -1 },
+			change: func(c *Config) { c.GcsConnection.LimitOpsPerSec = 5 },
+			path:   "gcs-connection.limit-ops-per-sec",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			oldConfig := reloadTestConfig()
+			if tc.old != nil {
+				tc.old(oldConfig)
+			}
+			newConfig := reloadTestConfig()
+			if tc.old != nil {
+				tc.old(newConfig)
+			}
+			tc.change(newConfig)
+
+			changes := DiffConfigs(oldConfig, newConfig)
+
+			if assert.Len(t, changes, 1) {
+				assert.Equal(t, tc.path, changes[0].Path)
+				assert.True(t, changes[0].RequiresRemount)
+			}
+		})
+	}
+}
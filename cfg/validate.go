@@ -18,7 +18,10 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"path"
 	"regexp"
+	"strings"
+	"time"
 
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/util"
 )
@@ -27,6 +30,8 @@ const (
 	FileCacheMaxSizeMBInvalidValueError       = "the value of max-size-mb for file-cache can't be less than -1"
 	MaxParallelDownloadsInvalidValueError     = "the value of max-parallel-downloads for file-cache can't be less than -1"
 	ParallelDownloadsPerFileInvalidValueError = "the value of parallel-downloads-per-file for file-cache can't be less than 1"
+	PrefetchConcurrencyInvalidValueError      = "the value of prefetch-concurrency for file-cache can't be less than 1"
+	DirReadaheadFilesInvalidValueError        = "the value of dir-readahead-files for file-cache can't be less than 0"
 	DownloadChunkSizeMBInvalidValueError      = "the value of download-chunk-size-mb for file-cache can't be less than 1"
 	MaxParallelDownloadsCantBeZeroError       = "the value of max-parallel-downloads for file-cache must not be 0 when enable-parallel-downloads is true"
 	ProfileAIMLTraining                       = "aiml-training"
@@ -89,9 +94,49 @@ func isValidFileCacheConfig(config *FileCacheConfig) error {
 		return fmt.Errorf("invalid regex value %q provided for include-regex", config.IncludeRegex)
 	}
 
+	if err := isValidEvictionPolicy(config.EvictionPolicy); err != nil {
+		return fmt.Errorf("invalid eviction-policy: %w", err)
+	}
+
+	if config.PrefetchConcurrency < 1 {
+		return errors.New(PrefetchConcurrencyInvalidValueError)
+	}
+	if config.DirReadaheadFiles < 0 {
+		return errors.New(DirReadaheadFilesInvalidValueError)
+	}
+
 	return nil
 }
 
+func isValidFileCachePrefetchConfig(config *Config) error {
+	if config.FileCache.DirReadaheadFiles > 0 && !IsFileCacheEnabled(config) {
+		return errors.New("file cache should be enabled for directory readahead")
+	}
+	if len(config.FileCache.PrefetchPaths) == 0 && config.FileCache.PrefetchManifest == "" {
+		return nil
+	}
+	if !IsFileCacheEnabled(config) {
+		return errors.New("file cache should be enabled for prefetching")
+	}
+	for _, p := range config.FileCache.PrefetchPaths {
+		if _, err := path.Match(p, ""); err != nil {
+			return fmt.Errorf("invalid pattern %q provided for prefetch-paths: %w", p, err)
+		}
+	}
+	return nil
+}
+
+// isValidEvictionPolicy checks that policy is a supported cache eviction
+// policy. The empty policy stands for the default, EvictionPolicyLRU.
+func isValidEvictionPolicy(policy string) error {
+	switch policy {
+	case "", EvictionPolicyLRU, EvictionPolicyTinyLFU, EvictionPolicy2Q:
+		return nil
+	default:
+		return fmt.Errorf("unsupported eviction policy %q; supported values: lru, tinylfu, 2q", policy)
+	}
+}
+
 func IsValidExperimentalMetadataPrefetchOnMount(mode string) error {
 	switch mode {
 	case ExperimentalMetadataPrefetchOnMountDisabled,
@@ -128,6 +173,59 @@ func isValidKernelListCacheTTL(TTLSecs int64) error {
 	return nil
 }
 
+func isValidMetadataCachePersistenceConfig(config *Config) error {
+	if config.MetadataCache.EnablePersistence && string(config.CacheDir) == "" {
+		return errors.New("cache-dir must be set to persist the metadata cache")
+	}
+	return nil
+}
+
+func isValidSnapshotConfig(config *Config) error {
+	if config.SnapshotTime == "" {
+		return nil
+	}
+	if string(config.GenerationManifest) != "" {
+		return errors.New("snapshot-time and generation-manifest can't both be set")
+	}
+	if _, err := time.Parse(time.RFC3339, config.SnapshotTime); err != nil {
+		return fmt.Errorf("snapshot-time must be an RFC 3339 time: %w", err)
+	}
+	return nil
+}
+
+func isValidTrashConfig(c *TrashConfig) error {
+	if !c.Enable {
+		return nil
+	}
+	if p := strings.Trim(c.Prefix, "/"); p == "" || p == "." || strings.HasPrefix(c.Prefix, "/") {
+		return fmt.Errorf("prefix must be a relative path: %q", c.Prefix)
+	}
+	if c.Retention < 0 {
+		return errors.New("retention can't be negative")
+	}
+	return nil
+}
+
+func isValidMemoryPressureConfig(c *MemoryPressureConfig) error {
+	if !c.Enable {
+		return nil
+	}
+	if c.ModeratePercent < 1 || c.ModeratePercent > c.CriticalPercent || c.CriticalPercent > 100 {
+		return fmt.Errorf("moderate-percent (%d) and critical-percent (%d) must satisfy 1 <= moderate-percent <= critical-percent <= 100", c.ModeratePercent, c.CriticalPercent)
+	}
+	if c.SampleInterval <= 0 {
+		return errors.New("sample-interval must be positive")
+	}
+	return nil
+}
+
+func isValidEncryptionConfig(c *EncryptionConfig) error {
+	if c.KeyFile != "" && c.KeyPlugin != "" {
+		return errors.New("key-file and key-plugin can't both be set")
+	}
+	return nil
+}
+
 func isValidMetadataCache(v isSet, c *MetadataCacheConfig) error {
 	// Validate ttl-secs.
 	if v.IsSet(MetadataCacheTTLConfigKey) {
@@ -164,6 +262,10 @@ func isValidMetadataCache(v isSet, c *MetadataCacheConfig) error {
 		}
 	}
 
+	if err := isValidEvictionPolicy(c.StatCacheEvictionPolicy); err != nil {
+		return fmt.Errorf("invalid stat-cache-eviction-policy: %w", err)
+	}
+
 	// [Deprecated] Validate stat-cache-capacity.
 	if c.DeprecatedStatCacheCapacity < 0 {
 		return fmt.Errorf("invalid value of stat-cache-capacity (%v), can't be less than 0", c.DeprecatedStatCacheCapacity)
@@ -189,6 +291,21 @@ func isValidWriteStreamingConfig(wc *WriteConfig) error {
 	return nil
 }
 
+func isValidUploadJournalConfig(config *Config) error {
+	if !config.Write.EnableUploadJournal {
+		return nil
+	}
+	if string(config.CacheDir) == "" {
+		return errors.New("cache-dir must be set to journal streaming uploads")
+	}
+	switch config.Write.InterruptedUploadAction {
+	case InterruptedUploadActionResume, InterruptedUploadActionAbort:
+		return nil
+	default:
+		return fmt.Errorf("unsupported interrupted-upload-action: %q", config.Write.InterruptedUploadAction)
+	}
+}
+
 func isValidReadStallGcsRetriesConfig(rsrc *ReadStallGcsRetriesConfig) error {
 	if rsrc == nil {
 		return nil
@@ -307,10 +424,34 @@ func ValidateConfig(v isSet, config *Config) error {
 		return fmt.Errorf("error parsing metadata-cache config: %w", err)
 	}
 
+	if err = isValidMetadataCachePersistenceConfig(config); err != nil {
+		return fmt.Errorf("error parsing metadata-cache config: %w", err)
+	}
+
+	if err = isValidSnapshotConfig(config); err != nil {
+		return fmt.Errorf("error parsing snapshot config: %w", err)
+	}
+
+	if err = isValidTrashConfig(&config.Trash); err != nil {
+		return fmt.Errorf("error parsing trash config: %w", err)
+	}
+
+	if err = isValidMemoryPressureConfig(&config.MemoryPressure); err != nil {
+		return fmt.Errorf("error parsing memory pressure config: %w", err)
+	}
+
+	if err = isValidEncryptionConfig(&config.Encryption); err != nil {
+		return fmt.Errorf("error parsing encryption config: %w", err)
+	}
+
 	if err = isValidWriteStreamingConfig(&config.Write); err != nil {
 		return fmt.Errorf("error parsing write config: %w", err)
 	}
 
+	if err = isValidUploadJournalConfig(config); err != nil {
+		return fmt.Errorf("error parsing write config: %w", err)
+	}
+
 	if err = isValidReadStallGcsRetriesConfig(&config.GcsRetries.ReadStall); err != nil {
 		return fmt.Errorf("error parsing read-stall-gcs-retries config: %w", err)
 	}
@@ -327,6 +468,10 @@ func ValidateConfig(v isSet, config *Config) error {
 		return fmt.Errorf("error parsing parallel download config: %w", err)
 	}
 
+	if err = isValidFileCachePrefetchConfig(config); err != nil {
+		return fmt.Errorf("error parsing file cache prefetch config: %w", err)
+	}
+
 	if err = isValidBufferedReadConfig(&config.Read); err != nil {
 		return fmt.Errorf("error parsing buffered read config: %w", err)
 	}
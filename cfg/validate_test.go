@@ -42,6 +42,7 @@ func validFileCacheConfig(t *testing.T) FileCacheConfig {
 		ParallelDownloadsPerFile: 16,
 		WriteBufferSize:          4 * 1024 * 1024,
 		EnableODirect:            true,
+		PrefetchConcurrency:      16,
 	}
 }
 
@@ -215,6 +216,7 @@ func TestValidateConfigSuccessful(t *testing.T) {
 					ParallelDownloadsPerFile: 16,
 					MaxSizeMb:                -1,
 					WriteBufferSize:          4 * 1024 * 1024,
+					PrefetchConcurrency:      16,
 				},
 				GcsConnection: GcsConnectionConfig{
 					CustomEndpoint:       "https://bing.com/search?q=dotnet",
@@ -392,6 +394,176 @@ func TestValidateConfig_ErrorScenarios(t *testing.T) {
 				FileSystem: FileSystemConfig{KernelListCacheTtlSecs: 88888888888888888},
 			},
 		},
+		{
+			name: "metadata_cache_persistence_without_cache_dir",
+			config: &Config{
+				Logging:   LoggingConfig{LogRotate: validLogRotateConfig()},
+				FileCache: validFileCacheConfig(t),
+				MetadataCache: MetadataCacheConfig{
+					ExperimentalMetadataPrefetchOnMount: "sync",
+					EnablePersistence:                   true,
+				},
+				GcsConnection: GcsConnectionConfig{
+					SequentialReadSizeMb: 200,
+				},
+			},
+		},
+		{
+			name: "malformed_snapshot_time",
+			config: &Config{
+				Logging:   LoggingConfig{LogRotate: validLogRotateConfig()},
+				FileCache: validFileCacheConfig(t),
+				MetadataCache: MetadataCacheConfig{
+					ExperimentalMetadataPrefetchOnMount: "sync",
+				},
+				GcsConnection: GcsConnectionConfig{
+					SequentialReadSizeMb: 200,
+				},
+				SnapshotTime: "yesterday",
+			},
+		},
+		{
+			name: "snapshot_time_with_generation_manifest",
+			config: &Config{
+				Logging:   LoggingConfig{LogRotate: validLogRotateConfig()},
+				FileCache: validFileCacheConfig(t),
+				MetadataCache: MetadataCacheConfig{
+					ExperimentalMetadataPrefetchOnMount: "sync",
+				},
+				GcsConnection: GcsConnectionConfig{
+					SequentialReadSizeMb: 200,
+				},
+				SnapshotTime:       "2026-01-02T15:04:05Z",
+				GenerationManifest: "/tmp/manifest",
+			},
+		},
+		{
+			name: "absolute_trash_prefix",
+			config: &Config{
+				Logging:   LoggingConfig{LogRotate: validLogRotateConfig()},
+				FileCache: validFileCacheConfig(t),
+				MetadataCache: MetadataCacheConfig{
+					ExperimentalMetadataPrefetchOnMount: "sync",
+				},
+				GcsConnection: GcsConnectionConfig{
+					SequentialReadSizeMb: 200,
+				},
+				Trash: TrashConfig{Enable: true, Prefix: "/trash/"},
+			},
+		},
+		{
+			name: "negative_trash_retention",
+			config: &Config{
+				Logging:   LoggingConfig{LogRotate: validLogRotateConfig()},
+				FileCache: validFileCacheConfig(t),
+				MetadataCache: MetadataCacheConfig{
+					ExperimentalMetadataPrefetchOnMount: "sync",
+				},
+				GcsConnection: GcsConnectionConfig{
+					SequentialReadSizeMb: 200,
+				},
+				Trash: TrashConfig{Enable: true, Prefix: ".trash/", Retention: -time.Hour},
+			},
+		},
+		{
+			name: "memory_pressure_moderate_above_critical",
+			config: &Config{
+				Logging:   LoggingConfig{LogRotate: validLogRotateConfig()},
+				FileCache: validFileCacheConfig(t),
+				MetadataCache: MetadataCacheConfig{
+					ExperimentalMetadataPrefetchOnMount: "sync",
+				},
+				GcsConnection: GcsConnectionConfig{
+					SequentialReadSizeMb: 200,
+				},
+				MemoryPressure: MemoryPressureConfig{Enable: true, ModeratePercent: 95, CriticalPercent: 90, SampleInterval: time.Second},
+			},
+		},
+		{
+			name: "memory_pressure_critical_above_100",
+			config: &Config{
+				Logging:   LoggingConfig{LogRotate: validLogRotateConfig()},
+				FileCache: validFileCacheConfig(t),
+				MetadataCache: MetadataCacheConfig{
+					ExperimentalMetadataPrefetchOnMount: "sync",
+				},
+				GcsConnection: GcsConnectionConfig{
+					SequentialReadSizeMb: 200,
+				},
+				MemoryPressure: MemoryPressureConfig{Enable: true, ModeratePercent: 80, CriticalPercent: 110, SampleInterval: time.Second},
+			},
+		},
+		{
+			name: "memory_pressure_zero_sample_interval",
+			config: &Config{
+				Logging:   LoggingConfig{LogRotate: validLogRotateConfig()},
+				FileCache: validFileCacheConfig(t),
+				MetadataCache: MetadataCacheConfig{
+					ExperimentalMetadataPrefetchOnMount: "sync",
+				},
+				GcsConnection: GcsConnectionConfig{
+					SequentialReadSizeMb: 200,
+				},
+				MemoryPressure: MemoryPressureConfig{Enable: true, ModeratePercent: 80, CriticalPercent: 90},
+			},
+		},
+		{
+			name: "upload_journal_without_cache_dir",
+			config: &Config{
+				Logging:   LoggingConfig{LogRotate: validLogRotateConfig()},
+				FileCache: validFileCacheConfig(t),
+				MetadataCache: MetadataCacheConfig{
+					ExperimentalMetadataPrefetchOnMount: "sync",
+				},
+				GcsConnection: GcsConnectionConfig{
+					SequentialReadSizeMb: 200,
+				},
+				Write: WriteConfig{EnableUploadJournal: true, InterruptedUploadAction: "resume"},
+			},
+		},
+		{
+			name: "unsupported_interrupted_upload_action",
+			config: &Config{
+				CacheDir:  "/tmp/cache",
+				Logging:   LoggingConfig{LogRotate: validLogRotateConfig()},
+				FileCache: validFileCacheConfig(t),
+				MetadataCache: MetadataCacheConfig{
+					ExperimentalMetadataPrefetchOnMount: "sync",
+				},
+				GcsConnection: GcsConnectionConfig{
+					SequentialReadSizeMb: 200,
+				},
+				Write: WriteConfig{EnableUploadJournal: true, InterruptedUploadAction: "finalize"},
+			},
+		},
+		{
+			name: "both_encryption_key_file_and_plugin",
+			config: &Config{
+				Logging:   LoggingConfig{LogRotate: validLogRotateConfig()},
+				FileCache: validFileCacheConfig(t),
+				MetadataCache: MetadataCacheConfig{
+					ExperimentalMetadataPrefetchOnMount: "sync",
+				},
+				GcsConnection: GcsConnectionConfig{
+					SequentialReadSizeMb: 200,
+				},
+				Encryption: EncryptionConfig{KeyFile: "/tmp/kek", KeyPlugin: "/usr/bin/kek-plugin"},
+			},
+		},
+		{
+			name: "unsupported_stat_cache_eviction_policy",
+			config: &Config{
+				Logging:   LoggingConfig{LogRotate: validLogRotateConfig()},
+				FileCache: validFileCacheConfig(t),
+				MetadataCache: MetadataCacheConfig{
+					ExperimentalMetadataPrefetchOnMount: "sync",
+					StatCacheEvictionPolicy:             "fifo",
+				},
+				GcsConnection: GcsConnectionConfig{
+					SequentialReadSizeMb: 200,
+				},
+			},
+		},
 		{
 			name: "read_stall_req_increase_rate_negative",
 			config: &Config{
@@ -491,6 +663,73 @@ func TestValidateConfig_ErrorScenarios(t *testing.T) {
 				FileCache: validFileCacheConfigWithIncludeRegex(t, "["),
 			},
 		},
+		{
+			name: "file_cache_eviction_policy",
+			config: &Config{
+				Logging: LoggingConfig{LogRotate: validLogRotateConfig()},
+				FileCache: func() FileCacheConfig {
+					c := validFileCacheConfig(t)
+					c.EvictionPolicy = "fifo"
+					return c
+				}(),
+			},
+		},
+		{
+			name: "file_cache_prefetch_concurrency",
+			config: &Config{
+				Logging: LoggingConfig{LogRotate: validLogRotateConfig()},
+				FileCache: func() FileCacheConfig {
+					c := validFileCacheConfig(t)
+					c.PrefetchConcurrency = 0
+					return c
+				}(),
+			},
+		},
+		{
+			name: "file_cache_prefetch_paths",
+			config: &Config{
+				Logging:  LoggingConfig{LogRotate: validLogRotateConfig()},
+				CacheDir: "/some/valid/path",
+				FileCache: func() FileCacheConfig {
+					c := validFileCacheConfig(t)
+					c.PrefetchPaths = []string{"data/[a-"}
+					return c
+				}(),
+			},
+		},
+		{
+			name: "file_cache_prefetch_without_file_cache",
+			config: &Config{
+				Logging: LoggingConfig{LogRotate: validLogRotateConfig()},
+				FileCache: func() FileCacheConfig {
+					c := validFileCacheConfig(t)
+					c.PrefetchManifest = "/some/manifest"
+					return c
+				}(),
+			},
+		},
+		{
+			name: "file_cache_dir_readahead_files",
+			config: &Config{
+				Logging: LoggingConfig{LogRotate: validLogRotateConfig()},
+				FileCache: func() FileCacheConfig {
+					c := validFileCacheConfig(t)
+					c.DirReadaheadFiles = -1
+					return c
+				}(),
+			},
+		},
+		{
+			name: "file_cache_dir_readahead_without_file_cache",
+			config: &Config{
+				Logging: LoggingConfig{LogRotate: validLogRotateConfig()},
+				FileCache: func() FileCacheConfig {
+					c := validFileCacheConfig(t)
+					c.DirReadaheadFiles = 8
+					return c
+				}(),
+			},
+		},
 		{
 			name: "chunk_transfer_timeout_in_negative",
 			config: &Config{
@@ -57,10 +57,13 @@ func defaultFileCacheConfig(t *testing.T) cfg.FileCacheConfig {
 		DownloadChunkSizeMb:                    200,
 		EnableCrc:                              false,
 		EnableParallelDownloads:                false,
+		EvictionPolicy:                         "lru",
 		ExperimentalParallelDownloadsDefaultOn: true,
 		MaxParallelDownloads:                   int64(max(16, 2*runtime.NumCPU())),
 		MaxSizeMb:                              -1,
 		ParallelDownloadsPerFile:               16,
+		PrefetchConcurrency:                    16,
+		PrefetchPaths:                          []string{},
 		WriteBufferSize:                        4 * 1024 * 1024,
 		EnableODirect:                          false,
 	}
@@ -209,12 +212,13 @@ func TestValidateConfigFile_WriteConfig(t *testing.T) {
 			configFile: "testdata/empty_file.yaml",
 			expectedConfig: &cfg.Config{
 				Write: cfg.WriteConfig{
-					CreateEmptyFile:       false,
-					BlockSizeMb:           32,
-					EnableStreamingWrites: true,
-					GlobalMaxBlocks:       4,
-					MaxBlocksPerFile:      1,
-					EnableRapidAppends:    true,
+					CreateEmptyFile:         false,
+					BlockSizeMb:             32,
+					EnableStreamingWrites:   true,
+					GlobalMaxBlocks:         4,
+					MaxBlocksPerFile:        1,
+					EnableRapidAppends:      true,
+					InterruptedUploadAction: "resume",
 				},
 			},
 		},
@@ -223,11 +227,12 @@ func TestValidateConfigFile_WriteConfig(t *testing.T) {
 			configFile: "testdata/valid_config.yaml",
 			expectedConfig: &cfg.Config{
 				Write: cfg.WriteConfig{
-					CreateEmptyFile:       false, // changed due to enabled streaming writes.
-					BlockSizeMb:           10,
-					EnableStreamingWrites: true,
-					GlobalMaxBlocks:       20,
-					MaxBlocksPerFile:      2,
+					CreateEmptyFile:         false, // changed due to enabled streaming writes.
+					BlockSizeMb:             10,
+					EnableStreamingWrites:   true,
+					GlobalMaxBlocks:         20,
+					MaxBlocksPerFile:        2,
+					InterruptedUploadAction: "resume",
 				},
 			},
 		},
@@ -429,9 +434,12 @@ func TestValidateConfigFile_FileCacheConfigSuccessful(t *testing.T) {
 					DownloadChunkSizeMb:                    300,
 					EnableCrc:                              true,
 					EnableParallelDownloads:                false,
+					EvictionPolicy:                         "lru",
 					MaxParallelDownloads:                   200,
 					MaxSizeMb:                              40,
 					ParallelDownloadsPerFile:               10,
+					PrefetchConcurrency:                    16,
+					PrefetchPaths:                          []string{},
 					WriteBufferSize:                        8192,
 					EnableODirect:                          true,
 					ExperimentalParallelDownloadsDefaultOn: true,
@@ -737,6 +745,7 @@ func TestValidateConfigFile_MetadataCacheConfigSuccessful(t *testing.T) {
 					DeprecatedTypeCacheTtl:              60 * time.Second,
 					EnableNonexistentTypeCache:          false,
 					ExperimentalMetadataPrefetchOnMount: "disabled",
+					StatCacheEvictionPolicy:             "lru",
 					StatCacheMaxSizeMb:                  33,
 					TtlSecs:                             60,
 					NegativeTtlSecs:                     5,
@@ -754,6 +763,7 @@ func TestValidateConfigFile_MetadataCacheConfigSuccessful(t *testing.T) {
 					DeprecatedTypeCacheTtl:              20 * time.Second,
 					EnableNonexistentTypeCache:          true,
 					ExperimentalMetadataPrefetchOnMount: "sync",
+					StatCacheEvictionPolicy:             "lru",
 					StatCacheMaxSizeMb:                  40,
 					TtlSecs:                             100,
 					NegativeTtlSecs:                     5,
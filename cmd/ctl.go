@@ -0,0 +1,128 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/control"
+	"github.com/spf13/cobra"
+)
+
+const ctlCmdName = "ctl"
+
+var ctlActionUsage = map[string]string{
+	control.ActionInvalidate: "Drop the cached metadata and contents of the objects whose names start with the path",
+	control.ActionEvictCache: "Drop all the cached metadata and contents",
+	control.ActionFlush:      "Upload the contents of all files open for writing",
+	control.ActionPrefetch:   "Download the objects matching the paths into the file cache in the background",
+}
+
+// newCtlCmd returns the command inspecting and controlling a running mount
+// through its control socket.
+func newCtlCmd() *cobra.Command {
+	var socketPath string
+	ctlCmd := &cobra.Command{
+		Use:   ctlCmdName,
+		Short: "Inspect and control a running mount through its control socket",
+		Long: `Inspect and control a mount through the control socket it serves when
+mounted with --control-socket. Queries print a JSON document.`,
+		SilenceUsage: true,
+	}
+	ctlCmd.PersistentFlags().StringVar(&socketPath, "socket", "", "The path of the control socket of the mount.")
+	_ = ctlCmd.MarkPersistentFlagRequired("socket")
+
+	for _, query := range control.Queries {
+		ctlCmd.AddCommand(&cobra.Command{
+			Use:   query,
+			Short: fmt.Sprintf("Print the %s of the mount", strings.ReplaceAll(query, "-", " ")),
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, _ []string) error {
+				body, err := control.NewClient(socketPath).Query(cmd.Context(), query)
+				if err != nil {
+					return err
+				}
+				var out bytes.Buffer
+				if err := json.Indent(&out, body, "", "  "); err != nil {
+					return fmt.Errorf("malformed response: %w", err)
+				}
+				_, err = out.WriteTo(cmd.OutOrStdout())
+				return err
+			},
+		})
+	}
+
+	for _, action := range control.Actions {
+		var manifest string
+		c := &cobra.Command{
+			Use:   action,
+			Short: ctlActionUsage[action],
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				params := url.Values{}
+				if len(args) > 0 {
+					params["path"] = args
+				}
+				if manifest != "" {
+					// The mount may run in another directory.
+					abs, err := filepath.Abs(manifest)
+					if err != nil {
+						return err
+					}
+					params.Set("manifest", abs)
+				}
+				return control.NewClient(socketPath).Act(cmd.Context(), action, params)
+			},
+		}
+		switch action {
+		case control.ActionInvalidate:
+			c.Use = action + " path"
+			c.Long = `Drop the cached metadata and contents of the objects whose names start
+with the path, relative to the mount point, so that they are fetched from
+GCS when next used. End the path with a slash to only match a directory.`
+			c.Args = cobra.ExactArgs(1)
+		case control.ActionPrefetch:
+			c.Use = action + " [path...]"
+			c.Long = `Download the objects matching the paths, relative to the mount point, and
+those listed in the manifest file into the file cache, in the background.
+A path matches the objects whose names start with it, unless it contains
+glob characters (*, ? or [), in which case it is matched as a shell
+pattern. Progress is reported through the file_cache/prefetch_* metrics.`
+			c.Args = cobra.ArbitraryArgs
+			c.Flags().StringVar(&manifest, "manifest", "", "A file listing paths to prefetch, one per line.")
+		}
+		ctlCmd.AddCommand(c)
+	}
+	return ctlCmd
+}
+
+// isCtlCommand returns true if the supplied command line arguments, including
+// the program name, run the ctl command rather than mount a bucket named
+// "ctl".
+func isCtlCommand(args []string) bool {
+	if len(args) < 2 || args[1] != ctlCmdName {
+		return false
+	}
+	if len(args) == 2 || strings.HasPrefix(args[2], "-") || args[2] == "help" {
+		return true
+	}
+	return slices.Contains(control.Queries, args[2]) || slices.Contains(control.Actions, args[2])
+}
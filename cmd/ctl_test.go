@@ -0,0 +1,87 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsCtlCommand(t *testing.T) {
+	testCases := []struct {
+		args     []string
+		expected bool
+	}{
+		{args: []string{"gcsfuse", "ctl"}, expected: true},
+		{args: []string{"gcsfuse", "ctl", "--socket=/run/gcsfuse.sock", "inodes"}, expected: true},
+		{args: []string{"gcsfuse", "ctl", "invalidate", "a/b"}, expected: true},
+		{args: []string{"gcsfuse", "ctl", "help"}, expected: true},
+		// Mounting a bucket named ctl.
+		{args: []string{"gcsfuse", "ctl", "/mnt/ctl"}, expected: false},
+		{args: []string{"gcsfuse", "--implicit-dirs", "ctl", "/mnt/ctl"}, expected: false},
+		{args: []string{"gcsfuse", "bucket", "/mnt"}, expected: false},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, isCtlCommand(tc.args), tc.args)
+	}
+}
+
+func TestCtlCommandCallsControlSocket(t *testing.T) {
+	socketPath := path.Join(t.TempDir(), "ctl.sock")
+	listener, err := net.Listen("unix", socketPath)
+	require.NoError(t, err)
+	var requests []string
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.RequestURI())
+		if r.Method == http.MethodGet {
+			_, _ = io.WriteString(w, `[{"ID":1}]`)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	server.Listener = listener
+	server.Start()
+	defer server.Close()
+	run := func(args ...string) (string, error) {
+		var out bytes.Buffer
+		ctlCmd := newCtlCmd()
+		ctlCmd.SetOut(&out)
+		ctlCmd.SetArgs(append([]string{"--socket", socketPath}, args...))
+		err := ctlCmd.Execute()
+		return out.String(), err
+	}
+
+	out, err := run("inodes")
+	require.NoError(t, err)
+	assert.Equal(t, "[\n  {\n    \"ID\": 1\n  }\n]", out)
+	_, err = run("invalidate", "a/b c")
+	require.NoError(t, err)
+	_, err = run("flush")
+	require.NoError(t, err)
+	_, err = run("invalidate")
+	assert.Error(t, err)
+	_, err = run("prefetch", "a/", "b/*.idx", "--manifest", "/some/manifest")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"GET /inodes", "POST /invalidate?path=a%2Fb+c", "POST /flush", "POST /prefetch?manifest=%2Fsome%2Fmanifest&path=a%2F&path=b%2F%2A.idx"}, requests)
+}
@@ -22,7 +22,6 @@ package cmd
 import (
 	"errors"
 	"fmt"
-	"io/fs"
 	"os"
 	"os/exec"
 	"os/signal"
@@ -39,6 +38,9 @@ import (
 	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
 	"github.com/googlecloudplatform/gcsfuse/v3/common"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/canned"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/changefeed"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/control"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/fs"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/locker"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/logger"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/monitor"
@@ -107,6 +109,54 @@ func registerTerminatingSignalHandler(mountPoint string) {
 	}()
 }
 
+// registerReloadSignalHandler reloads the config on SIGHUP, and applies the
+// settings that can be changed while mounted to the file system controlled by
+// ctl. The reload is rejected as a whole if any other setting has changed.
+func registerReloadSignalHandler(mountInfo *mountInfo, ctl *fs.Controller) {
+	signalChan := make(chan os.Signal, 1)
+	signal.Notify(signalChan, unix.SIGHUP)
+
+	go func() {
+		current := mountInfo.config
+		for range signalChan {
+			logger.Infof("Received SIGHUP, reloading the config...")
+			newConfig, err := mountInfo.reloadConfig()
+			if err != nil {
+				logger.Errorf("Failed to reload the config: %v", err)
+				continue
+			}
+
+			changes := cfg.DiffConfigs(current, newConfig)
+			var rejected []string
+			for _, c := range changes {
+				if c.RequiresRemount {
+					rejected = append(rejected, c.String())
+				}
+			}
+			if len(rejected) > 0 {
+				logger.Errorf("Rejected the reloaded config, as these changes require a remount:\n%s", strings.Join(rejected, "\n"))
+				continue
+			}
+			if len(changes) == 0 {
+				logger.Infof("The reloaded config has no changes.")
+				continue
+			}
+
+			// Leave the log severity alone too if the file system rejects the
+			// config, so that the reload has no effect at all.
+			if err := ctl.Reconfigure(newConfig); err != nil {
+				logger.Errorf("Failed to apply the reloaded config: %v", err)
+				continue
+			}
+			logger.SetLogSeverity(newConfig.Logging.Severity)
+			for _, c := range changes {
+				logger.Infof("Reloaded config: %s", c)
+			}
+			current = newConfig
+		}
+	}()
+}
+
 func getUserAgent(appName, config, mountInstanceID string) string {
 	var userAgent string
 	gcsfuseMetadataImageType := os.Getenv("GCSFUSE_METADATA_IMAGE_TYPE")
@@ -141,6 +191,14 @@ func getConfigForUserAgent(mountConfig *cfg.Config) string {
 	return strings.Join(parts, ":")
 }
 func createStorageHandle(newConfig *cfg.Config, userAgent string, metricHandle metrics.MetricHandle) (storageHandle storage.StorageHandle, err error) {
+	customerSuppliedKeys, err := storageutil.LoadCustomerSuppliedKeys(
+		string(newConfig.GcsConnection.CustomerSuppliedKeyFile),
+		string(newConfig.GcsConnection.CustomerSuppliedKeyMap))
+	if err != nil {
+		err = fmt.Errorf("LoadCustomerSuppliedKeys: %w", err)
+		return
+	}
+
 	storageClientConfig := storageutil.StorageClientConfig{
 		ClientProtocol:             newConfig.GcsConnection.ClientProtocol,
 		MaxConnsPerHost:            int(newConfig.GcsConnection.MaxConnsPerHost),
@@ -164,6 +222,7 @@ func createStorageHandle(newConfig *cfg.Config, userAgent string, metricHandle m
 		TracingEnabled:             cfg.IsTracingEnabled(newConfig),
 		EnableHTTPDNSCache:         newConfig.GcsConnection.EnableHttpDnsCache,
 		LocalSocketAddress:         newConfig.GcsConnection.ExperimentalLocalSocketAddress,
+		CustomerSuppliedKeys:       customerSuppliedKeys,
 	}
 	logger.Infof("UserAgent = %s\n", storageClientConfig.UserAgent)
 	storageHandle, err = storage.NewStorageHandle(context.Background(), storageClientConfig, newConfig.GcsConnection.BillingProject)
@@ -175,7 +234,7 @@ func createStorageHandle(newConfig *cfg.Config, userAgent string, metricHandle m
 ////////////////////////////////////////////////////////////////////////
 
 // Mount the file system according to arguments in the supplied context.
-func mountWithArgs(bucketName string, mountPoint string, newConfig *cfg.Config, metricHandle metrics.MetricHandle) (mfs *fuse.MountedFileSystem, err error) {
+func mountWithArgs(bucketName string, mountPoint string, newConfig *cfg.Config, metricHandle metrics.MetricHandle, ctl *fs.Controller) (mfs *fuse.MountedFileSystem, err error) {
 	// Enable invariant checking if requested.
 	if newConfig.Debug.ExitOnInvariantViolation {
 		locker.EnableInvariantsCheck()
@@ -207,7 +266,8 @@ func mountWithArgs(bucketName string, mountPoint string, newConfig *cfg.Config,
 		mountPoint,
 		newConfig,
 		storageHandle,
-		metricHandle)
+		metricHandle,
+		ctl)
 
 	if err != nil {
 		err = fmt.Errorf("mountWithStorageHandle: %w", err)
@@ -301,7 +361,7 @@ func populateArgs(args []string) (
 func callListRecursive(mountPoint string) (err error) {
 	logger.Debugf("Started recursive metadata-prefetch of directory: \"%s\" ...", mountPoint)
 	numItems := 0
-	err = filepath.WalkDir(mountPoint, func(path string, d fs.DirEntry, err error) error {
+	err = filepath.WalkDir(mountPoint, func(path string, d os.DirEntry, err error) error {
 		if err == nil {
 			numItems++
 			return err
@@ -451,6 +511,16 @@ func Mount(mountInfo *mountInfo, bucketName, mountPoint string) (err error) {
 		logger.Warnf("Deprecated flag stat-cache-ttl and/or type-cache-ttl used! Please switch to config parameter 'metadata-cache: ttl-secs' .")
 	}
 
+	// Reject a malformed change feed before daemonizing, so that the user sees
+	// the error.
+	var changeFeed changefeed.Source
+	if spec := newConfig.MetadataCache.ChangeFeed; spec != "" {
+		if changeFeed, err = changefeed.NewSource(spec); err != nil {
+			err = fmt.Errorf("invalid change-feed: %w", err)
+			return
+		}
+	}
+
 	// If we haven't been asked to run in foreground mode, we should run a daemon
 	// with the foreground flag set and wait for it to mount.
 	if !newConfig.Foreground {
@@ -506,8 +576,9 @@ func Mount(mountInfo *mountInfo, bucketName, mountPoint string) (err error) {
 	// Mount, writing information about our progress to the writer that package
 	// daemonize gives us and telling it about the outcome.
 	var mfs *fuse.MountedFileSystem
+	ctl := fs.NewController()
 	{
-		mfs, err = mountWithArgs(bucketName, mountPoint, newConfig, metricHandle)
+		mfs, err = mountWithArgs(bucketName, mountPoint, newConfig, metricHandle, ctl)
 
 		// This utility is to absorb the error
 		// returned by daemonize.SignalOutcome calls by simply
@@ -568,6 +639,37 @@ func Mount(mountInfo *mountInfo, bucketName, mountPoint string) (err error) {
 	// Let the user unmount with Ctrl-C (SIGINT).
 	registerTerminatingSignalHandler(mfs.Dir())
 
+	// Let the user change some settings without remounting with SIGHUP.
+	registerReloadSignalHandler(mountInfo, ctl)
+
+	if socketPath := string(newConfig.Debug.ControlSocket); socketPath != "" {
+		if shutdownControlFn, err := control.Serve(socketPath, ctl); err != nil {
+			logger.Errorf("Failed to serve the control API: %v", err)
+		} else {
+			shutdownFn = common.JoinShutdownFunc(shutdownControlFn, shutdownFn)
+		}
+	}
+
+	// Keep the caches coherent with changes made elsewhere, as notified.
+	if changeFeed != nil {
+		feedCtx, cancelFeed := context.WithCancel(ctx)
+		go func() {
+			err := changeFeed.Run(feedCtx, func(e changefeed.Event) {
+				logger.Tracef("Change feed: %s of %q in bucket %q", e.Type, e.ObjectName, e.BucketName)
+				if err := ctl.InvalidateObject(e.BucketName, e.ObjectName); err != nil {
+					logger.Warnf("Invalidating %q after a change notification: %v", e.ObjectName, err)
+				}
+			})
+			if err != nil {
+				logger.Errorf("Change feed stopped, caches are now only refreshed when they expire: %v", err)
+			}
+		}()
+		shutdownFn = common.JoinShutdownFunc(func(context.Context) error {
+			cancelFeed()
+			return nil
+		}, shutdownFn)
+	}
+
 	// Wait for the file system to be unmounted.
 	if err = mfs.Join(ctx); err != nil {
 		err = fmt.Errorf("MountedFileSystem.Join: %w", err)
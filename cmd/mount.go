@@ -16,6 +16,7 @@ package cmd
 
 import (
 	"fmt"
+	"math"
 	"os"
 	"time"
 
@@ -42,7 +43,8 @@ func mountWithStorageHandle(
 	mountPoint string,
 	newConfig *cfg.Config,
 	storageHandle storage.StorageHandle,
-	metricHandle metrics.MetricHandle) (mfs *fuse.MountedFileSystem, err error) {
+	metricHandle metrics.MetricHandle,
+	ctl *fs.Controller) (mfs *fuse.MountedFileSystem, err error) {
 	// Sanity check: make sure the temporary directory exists and is writable
 	// currently. This gives a better user experience than harder to debug EIO
 	// errors when reading files in the future.
@@ -86,12 +88,28 @@ be interacting with the file system.`)
 		gid = uint32(newConfig.FileSystem.Gid)
 	}
 
+	// Pin the objects to a snapshot, if requested.
+	var snapshotTime time.Time
+	if newConfig.SnapshotTime != "" {
+		// The time was validated with the config.
+		snapshotTime, _ = time.Parse(time.RFC3339, newConfig.SnapshotTime)
+	}
+	var generationManifest gcsx.GenerationManifest
+	if manifestPath := string(newConfig.GenerationManifest); manifestPath != "" {
+		generationManifest, err = gcsx.LoadGenerationManifest(manifestPath)
+		if err != nil {
+			err = fmt.Errorf("LoadGenerationManifest: %w", err)
+			return
+		}
+	}
+
 	bucketCfg := gcsx.BucketConfig{
 		BillingProject:                     newConfig.GcsConnection.BillingProject,
 		OnlyDir:                            newConfig.OnlyDir,
 		EgressBandwidthLimitBytesPerSecond: newConfig.GcsConnection.LimitBytesPerSec,
 		OpRateLimitHz:                      newConfig.GcsConnection.LimitOpsPerSec,
 		StatCacheMaxSizeMB:                 uint64(newConfig.MetadataCache.StatCacheMaxSizeMb),
+		StatCacheEvictionPolicy:            newConfig.MetadataCache.StatCacheEvictionPolicy,
 		StatCacheTTL:                       time.Duration(newConfig.MetadataCache.TtlSecs) * time.Second,
 		NegativeStatCacheTTL:               time.Duration(newConfig.MetadataCache.NegativeTtlSecs) * time.Second,
 		EnableMonitoring:                   cfg.IsMetricsEnabled(&newConfig.Metrics),
@@ -100,14 +118,39 @@ be interacting with the file system.`)
 		ChunkTransferTimeoutSecs:           newConfig.GcsRetries.ChunkTransferTimeoutSecs,
 		TmpObjectPrefix:                    ".gcsfuse_tmp/",
 		FinalizeFileForRapid:               newConfig.Write.FinalizeFileForRapid,
+		SnapshotTime:                       snapshotTime,
+		GenerationManifest:                 generationManifest,
+	}
+	if newConfig.Trash.Enable {
+		bucketCfg.TrashPrefix = newConfig.Trash.Prefix
+		bucketCfg.TrashRetention = newConfig.Trash.Retention
 	}
-	bm := gcsx.NewBucketManager(bucketCfg, storageHandle)
+	if newConfig.GcsConnection.CustomerSuppliedKeyMap != "" {
+		// The temporary objects appended to files are composed with them, so
+		// must have the same key, which the key map may not give them.
+		bucketCfg.AppendThreshold = math.MaxInt64
+	}
+	if keyFile := string(newConfig.Encryption.KeyFile); keyFile != "" {
+		bucketCfg.KeyEncryptionKey, err = gcsx.NewKeyFileKEK(keyFile)
+		if err != nil {
+			err = fmt.Errorf("NewKeyFileKEK: %w", err)
+			return
+		}
+	} else if plugin := string(newConfig.Encryption.KeyPlugin); plugin != "" {
+		bucketCfg.KeyEncryptionKey, err = gcsx.NewPluginKEK(ctx, plugin)
+		if err != nil {
+			err = fmt.Errorf("NewPluginKEK: %w", err)
+			return
+		}
+	}
+	bm := gcsx.NewBucketManager(bucketCfg, storageHandle, metricHandle)
 
 	// Create a file system server.
 	serverCfg := &fs.ServerConfig{
 		CacheClock:                 timeutil.RealClock(),
 		BucketManager:              bm,
 		BucketName:                 bucketName,
+		MountPoint:                 mountPoint,
 		LocalFileCache:             false,
 		TempDir:                    string(newConfig.FileSystem.TempDir),
 		ImplicitDirectories:        newConfig.ImplicitDirs,
@@ -122,8 +165,11 @@ be interacting with the file system.`)
 		EnableNonexistentTypeCache: newConfig.MetadataCache.EnableNonexistentTypeCache,
 		NewConfig:                  newConfig,
 		MetricHandle:               metricHandle,
+		Controller:                 ctl,
 	}
-	if serverCfg.NewConfig.FileSystem.ExperimentalEnableDentryCache {
+	// The notifier is used to invalidate kernel dentries with the dentry cache,
+	// and the kernel caches of stale inodes and invalidated paths if enabled.
+	if newConfig.FileSystem.ExperimentalEnableDentryCache || newConfig.FileSystem.InvalidateKernelCaches {
 		serverCfg.Notifier = fuse.NewNotifier()
 	}
 
@@ -156,6 +202,12 @@ func getFuseMountConfig(fsName string, newConfig *cfg.Config) *fuse.MountConfig
 		mount.ParseOptions(parsedOptions, o)
 	}
 
+	// Snapshots can't be modified.
+	if newConfig.SnapshotTime != "" || newConfig.GenerationManifest != "" {
+		delete(parsedOptions, "rw")
+		parsedOptions["ro"] = ""
+	}
+
 	mountCfg := &fuse.MountConfig{
 		FSName:     fsName,
 		Subtype:    "gcsfuse",
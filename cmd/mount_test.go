@@ -67,6 +67,35 @@ func TestGetFuseMountConfig_MountOptionsFormattedCorrectly(t *testing.T) {
 	}
 }
 
+func TestGetFuseMountConfig_SnapshotsMountedReadOnly(t *testing.T) {
+	testCases := []struct {
+		name      string
+		newConfig *cfg.Config
+	}{
+		{
+			name: "snapshot time",
+			newConfig: &cfg.Config{
+				FileSystem:   cfg.FileSystemConfig{FuseOptions: []string{"rw", "nodev"}},
+				SnapshotTime: "2026-01-02T15:04:05Z",
+			},
+		},
+		{
+			name: "generation manifest",
+			newConfig: &cfg.Config{
+				FileSystem:         cfg.FileSystemConfig{FuseOptions: []string{"rw", "nodev"}},
+				GenerationManifest: "/tmp/manifest",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fuseMountCfg := getFuseMountConfig("mybucket", tc.newConfig)
+
+			assert.Equal(t, map[string]string{"nodev": "", "ro": ""}, fuseMountCfg.Options)
+		})
+	}
+}
+
 func TestGetFuseMountConfig_LoggerInitializationInFuse(t *testing.T) {
 	testCases := []struct {
 		name                  string
@@ -45,6 +45,9 @@ type mountInfo struct {
 	// optimizedFlags contains the flags that were optimized
 	// based on either machine-type or profile.
 	optimizedFlags map[string]any
+	// reloadConfig reads the config file again, and returns the config that
+	// results from it and the original command line flags.
+	reloadConfig func() (*cfg.Config, error)
 }
 
 type mountFn func(mountInfo *mountInfo, bucketName, mountPoint string) error
@@ -113,6 +116,34 @@ func getConfigFileFlags(v *viper.Viper) map[string]any {
 	return configOnlyViper.AllSettings()
 }
 
+// loadConfig decodes the flags and the config file read by v into c, and
+// validates, optimizes and rationalizes the result. It returns the flags that
+// were optimized.
+func loadConfig(v *viper.Viper, isSet *pflagAsIsValueSet, c *cfg.Config) (map[string]cfg.OptimizationResult, error) {
+	if err := v.Unmarshal(c, viper.DecodeHook(cfg.DecodeHook()), func(decoderConfig *mapstructure.DecoderConfig) {
+		// By default, viper supports mapstructure tags for unmarshalling. Override that to support yaml tag.
+		decoderConfig.TagName = "yaml"
+		// Reject the config file if any of the fields in the YAML don't map to the struct.
+		decoderConfig.ErrorUnused = true
+	},
+	); err != nil {
+		return nil, fmt.Errorf("error while unmarshalling config: %w", err)
+	}
+	if err := cfg.ValidateConfig(v, c); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	optimizedFlags := c.ApplyOptimizations(isSet)
+	optimizedFlagNames := slices.Collect(maps.Keys(optimizedFlags))
+	for k := range optimizedFlags {
+		optimizedFlagNames = append(optimizedFlagNames, k)
+	}
+	if err := cfg.Rationalize(v, c, optimizedFlagNames); err != nil {
+		return nil, fmt.Errorf("error rationalizing config: %w", err)
+	}
+	return optimizedFlags, nil
+}
+
 // newRootCmd accepts the mountFn that it executes with the parsed configuration
 func newRootCmd(m mountFn) (*cobra.Command, error) {
 	var (
@@ -143,27 +174,23 @@ of Cloud Storage FUSE, see https://cloud.google.com/storage/docs/gcs-fuse.`,
 				}
 			}
 
-			if err := v.Unmarshal(mountInfo.config, viper.DecodeHook(cfg.DecodeHook()), func(decoderConfig *mapstructure.DecoderConfig) {
-				// By default, viper supports mapstructure tags for unmarshalling. Override that to support yaml tag.
-				decoderConfig.TagName = "yaml"
-				// Reject the config file if any of the fields in the YAML don't map to the struct.
-				decoderConfig.ErrorUnused = true
-			},
-			); err != nil {
-				return fmt.Errorf("error while unmarshalling config: %w", err)
-			}
-			if err := cfg.ValidateConfig(v, mountInfo.config); err != nil {
-				return fmt.Errorf("invalid config: %w", err)
-			}
-
 			isSet := &pflagAsIsValueSet{fs: cmd.PersistentFlags()}
-			optimizedFlags := mountInfo.config.ApplyOptimizations(isSet)
-			optimizedFlagNames := slices.Collect(maps.Keys(optimizedFlags))
-			for k := range optimizedFlags {
-				optimizedFlagNames = append(optimizedFlagNames, k)
+			optimizedFlags, err := loadConfig(v, isSet, mountInfo.config)
+			if err != nil {
+				return err
 			}
-			if err := cfg.Rationalize(v, mountInfo.config, optimizedFlagNames); err != nil {
-				return fmt.Errorf("error rationalizing config: %w", err)
+			mountInfo.reloadConfig = func() (*cfg.Config, error) {
+				if cfgFile == "" {
+					return nil, fmt.Errorf("no config file to reload, pass --%s", cfg.ConfigFileFlagName)
+				}
+				if err := v.ReadInConfig(); err != nil {
+					return nil, fmt.Errorf("error while reading the config: %w", err)
+				}
+				newConfig := &cfg.Config{}
+				if _, err := loadConfig(v, isSet, newConfig); err != nil {
+					return nil, err
+				}
+				return newConfig, nil
 			}
 			mountInfo.cliFlags = getCliFlags(cmd.PersistentFlags())
 			mountInfo.configFileFlags = getConfigFileFlags(v)
@@ -243,6 +270,15 @@ func convertToPosixArgs(args []string, c *cobra.Command) []string {
 }
 
 var ExecuteMountCmd = func() {
+	if isCtlCommand(os.Args) {
+		ctlCmd := newCtlCmd()
+		ctlCmd.SetArgs(os.Args[2:])
+		if err := ctlCmd.Execute(); err != nil {
+			os.Exit(1)
+		}
+		return
+	}
+
 	rootCmd, err := newRootCmd(Mount)
 	if err != nil {
 		log.Fatalf("Error occurred while creating the root command on gcsfuse/%s: %v", common.GetVersion(), err)
@@ -444,6 +444,7 @@ func TestArgsParsing_ReadConfigFlags(t *testing.T) {
 		expectedReadMaxBlocksPerHandle   int64
 		expectedReadStartBlocksPerHandle int64
 		expectedReadMinBlocksPerHandle   int64
+		expectedReadAdaptivePrefetch     bool
 	}{
 		{
 			name:                             "Test default flags.",
@@ -517,6 +518,16 @@ func TestArgsParsing_ReadConfigFlags(t *testing.T) {
 			expectedReadStartBlocksPerHandle: 1,
 			expectedReadMinBlocksPerHandle:   10,
 		},
+		{
+			name:                             "Test read-adaptive-prefetch flag.",
+			args:                             []string{"gcsfuse", "--read-adaptive-prefetch", "abc", "pqr"},
+			expectedReadBlockSizeMB:          16,
+			expectedReadGlobalMaxBlocks:      40,
+			expectedReadMaxBlocksPerHandle:   20,
+			expectedReadStartBlocksPerHandle: 1,
+			expectedReadMinBlocksPerHandle:   4,
+			expectedReadAdaptivePrefetch:     true,
+		},
 	}
 
 	for _, tc := range tests {
@@ -537,6 +548,7 @@ func TestArgsParsing_ReadConfigFlags(t *testing.T) {
 				assert.Equal(t, tc.expectedReadMaxBlocksPerHandle, rc.MaxBlocksPerHandle)
 				assert.Equal(t, tc.expectedReadStartBlocksPerHandle, rc.StartBlocksPerHandle)
 				assert.Equal(t, tc.expectedReadMinBlocksPerHandle, rc.MinBlocksPerHandle)
+				assert.Equal(t, tc.expectedReadAdaptivePrefetch, rc.AdaptivePrefetch)
 			}
 		})
 	}
@@ -550,20 +562,25 @@ func TestArgsParsing_FileCacheFlags(t *testing.T) {
 	}{
 		{
 			name: "Test file cache flags.",
-			args: []string{"gcsfuse", "--file-cache-cache-file-for-range-read", "--file-cache-download-chunk-size-mb=20", "--file-cache-enable-crc", "--cache-dir=/some/valid/dir", "--file-cache-exclude-regex=.*", "--file-cache-include-regex=.*", "--file-cache-enable-parallel-downloads", "--file-cache-max-parallel-downloads=40", "--file-cache-max-size-mb=100", "--file-cache-parallel-downloads-per-file=2", "--file-cache-enable-o-direct=false", "abc", "pqr"},
+			args: []string{"gcsfuse", "--file-cache-cache-file-for-range-read", "--file-cache-download-chunk-size-mb=20", "--file-cache-enable-crc", "--cache-dir=/some/valid/dir", "--file-cache-exclude-regex=.*", "--file-cache-include-regex=.*", "--file-cache-enable-parallel-downloads", "--file-cache-max-parallel-downloads=40", "--file-cache-max-size-mb=100", "--file-cache-parallel-downloads-per-file=2", "--file-cache-enable-o-direct=false", "--file-cache-eviction-policy=tinylfu", "--file-cache-prefetch-concurrency=4", "--file-cache-prefetch-paths=a/,b/*.bin", "--file-cache-prefetch-manifest=/some/manifest", "--file-cache-dir-readahead-files=8", "abc", "pqr"},
 			expectedConfig: &cfg.Config{
 				CacheDir: "/some/valid/dir",
 				FileCache: cfg.FileCacheConfig{
 					CacheFileForRangeRead:                  true,
+					DirReadaheadFiles:                      8,
 					DownloadChunkSizeMb:                    20,
 					EnableCrc:                              true,
 					EnableParallelDownloads:                true,
+					EvictionPolicy:                         "tinylfu",
 					ExcludeRegex:                           ".*",
 					IncludeRegex:                           ".*",
 					ExperimentalParallelDownloadsDefaultOn: true,
 					MaxParallelDownloads:                   40,
 					MaxSizeMb:                              100,
 					ParallelDownloadsPerFile:               2,
+					PrefetchConcurrency:                    4,
+					PrefetchManifest:                       "/some/manifest",
+					PrefetchPaths:                          []string{"a/", "b/*.bin"},
 					WriteBufferSize:                        4 * 1024 * 1024,
 					EnableODirect:                          false,
 				},
@@ -578,12 +595,15 @@ func TestArgsParsing_FileCacheFlags(t *testing.T) {
 					DownloadChunkSizeMb:                    200,
 					EnableCrc:                              false,
 					EnableParallelDownloads:                false,
+					EvictionPolicy:                         "lru",
 					ExcludeRegex:                           "",
 					IncludeRegex:                           "",
 					ExperimentalParallelDownloadsDefaultOn: true,
 					MaxParallelDownloads:                   int64(max(16, 2*runtime.NumCPU())),
 					MaxSizeMb:                              -1,
 					ParallelDownloadsPerFile:               16,
+					PrefetchConcurrency:                    16,
+					PrefetchPaths:                          []string{},
 					WriteBufferSize:                        4 * 1024 * 1024,
 					EnableODirect:                          false,
 				},
@@ -1576,7 +1596,7 @@ func TestArgsParsing_MetadataCacheFlags(t *testing.T) {
 	}{
 		{
 			name: "normal",
-			args: []string{"gcsfuse", "--stat-cache-capacity=2000", "--stat-cache-ttl=2m", "--type-cache-ttl=1m20s", "--enable-nonexistent-type-cache", "--experimental-metadata-prefetch-on-mount=async", "--stat-cache-max-size-mb=15", "--metadata-cache-ttl-secs=25", "--metadata-cache-negative-ttl-secs=20", "--type-cache-max-size-mb=30", "abc", "pqr"},
+			args: []string{"gcsfuse", "--stat-cache-capacity=2000", "--stat-cache-ttl=2m", "--type-cache-ttl=1m20s", "--enable-nonexistent-type-cache", "--experimental-metadata-prefetch-on-mount=async", "--stat-cache-max-size-mb=15", "--metadata-cache-ttl-secs=25", "--metadata-cache-negative-ttl-secs=20", "--type-cache-max-size-mb=30", "--stat-cache-eviction-policy=2q", "abc", "pqr"},
 			expectedConfig: &cfg.Config{
 				MetadataCache: cfg.MetadataCacheConfig{
 					DeprecatedStatCacheCapacity:         2000,
@@ -1584,6 +1604,7 @@ func TestArgsParsing_MetadataCacheFlags(t *testing.T) {
 					DeprecatedTypeCacheTtl:              80 * time.Second,
 					EnableNonexistentTypeCache:          true,
 					ExperimentalMetadataPrefetchOnMount: "async",
+					StatCacheEvictionPolicy:             "2q",
 					StatCacheMaxSizeMb:                  15,
 					TtlSecs:                             25,
 					NegativeTtlSecs:                     20,
@@ -1601,6 +1622,7 @@ func TestArgsParsing_MetadataCacheFlags(t *testing.T) {
 					DeprecatedTypeCacheTtl:              60 * time.Second,
 					EnableNonexistentTypeCache:          false,
 					ExperimentalMetadataPrefetchOnMount: "disabled",
+					StatCacheEvictionPolicy:             "lru",
 					StatCacheMaxSizeMb:                  33,
 					TtlSecs:                             60,
 					NegativeTtlSecs:                     5,
@@ -1618,6 +1640,7 @@ func TestArgsParsing_MetadataCacheFlags(t *testing.T) {
 					DeprecatedTypeCacheTtl:              60 * time.Second,
 					EnableNonexistentTypeCache:          false,
 					ExperimentalMetadataPrefetchOnMount: "disabled",
+					StatCacheEvictionPolicy:             "lru",
 					StatCacheMaxSizeMb:                  33,
 					TtlSecs:                             60,
 					NegativeTtlSecs:                     5,
@@ -1635,6 +1658,7 @@ func TestArgsParsing_MetadataCacheFlags(t *testing.T) {
 					DeprecatedTypeCacheTtl:              60 * time.Second,
 					EnableNonexistentTypeCache:          false,
 					ExperimentalMetadataPrefetchOnMount: "disabled",
+					StatCacheEvictionPolicy:             "lru",
 					StatCacheMaxSizeMb:                  1024,
 					TtlSecs:                             9223372036,
 					NegativeTtlSecs:                     0,
@@ -1652,6 +1676,7 @@ func TestArgsParsing_MetadataCacheFlags(t *testing.T) {
 					DeprecatedTypeCacheTtl:              80 * time.Second,
 					EnableNonexistentTypeCache:          true,
 					ExperimentalMetadataPrefetchOnMount: "async",
+					StatCacheEvictionPolicy:             "lru",
 					StatCacheMaxSizeMb:                  15,
 					TtlSecs:                             25,
 					NegativeTtlSecs:                     20,
@@ -1669,6 +1694,7 @@ func TestArgsParsing_MetadataCacheFlags(t *testing.T) {
 					DeprecatedTypeCacheTtl:              4 * time.Minute,
 					EnableNonexistentTypeCache:          true,
 					ExperimentalMetadataPrefetchOnMount: "async",
+					StatCacheEvictionPolicy:             "lru",
 					StatCacheMaxSizeMb:                  4,
 					TtlSecs:                             120,
 					NegativeTtlSecs:                     20,
@@ -2217,3 +2243,40 @@ func TestGetConfigFileFlags(t *testing.T) {
 		})
 	}
 }
+
+func TestReloadConfigReadsConfigFileAgain(t *testing.T) {
+	cfgFile := path.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(cfgFile, []byte("metadata-cache:\n  ttl-secs: 30\n"), 0644))
+	var mi *mountInfo
+	cmd, err := newRootCmd(func(mountInfo *mountInfo, _, _ string) error {
+		mi = mountInfo
+		return nil
+	})
+	require.NoError(t, err)
+	cmd.SetArgs(convertToPosixArgs([]string{"gcsfuse", "--config-file=" + cfgFile, "--limit-ops-per-sec=5", "abc", "pqr"}, cmd))
+	require.NoError(t, cmd.Execute())
+	require.NoError(t, os.WriteFile(cfgFile, []byte("metadata-cache:\n  ttl-secs: 60\n"), 0644))
+
+	newConfig, err := mi.reloadConfig()
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(30), mi.config.MetadataCache.TtlSecs)
+	assert.Equal(t, int64(60), newConfig.MetadataCache.TtlSecs)
+	// Flags passed on the command line still apply.
+	assert.Equal(t, float64(5), newConfig.GcsConnection.LimitOpsPerSec)
+}
+
+func TestReloadConfigWithoutConfigFile(t *testing.T) {
+	var mi *mountInfo
+	cmd, err := newRootCmd(func(mountInfo *mountInfo, _, _ string) error {
+		mi = mountInfo
+		return nil
+	})
+	require.NoError(t, err)
+	cmd.SetArgs(convertToPosixArgs([]string{"gcsfuse", "abc", "pqr"}, cmd))
+	require.NoError(t, cmd.Execute())
+
+	_, err = mi.reloadConfig()
+
+	assert.ErrorContains(t, err, "no config file to reload")
+}
@@ -17,6 +17,7 @@ package bufferedread
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/block"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/logger"
@@ -31,6 +32,13 @@ type blockQueueEntry struct {
 	// wasEvicted is true if the block has been removed from the block queue but
 	// still has outstanding references.
 	wasEvicted bool
+
+	// consumed is true once data has been read from the block.
+	consumed bool
+
+	// downloadTime is the time the download of the block took, set by the
+	// download task before it notifies that the block is ready.
+	downloadTime time.Duration
 }
 
 // cancelAndWait cancels the download context for the entry and waits for the
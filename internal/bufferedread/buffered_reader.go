@@ -46,6 +46,7 @@ type BufferedReadConfig struct {
 	InitialPrefetchBlockCnt int64 // Number of blocks to prefetch initially.
 	MinBlocksPerHandle      int64 // Minimum number of blocks available in block-pool to start buffered-read.
 	RandomSeekThreshold     int64 // Seek count threshold to switch another reader
+	AdaptivePrefetch        bool  // Whether the number of blocks prefetched adapts to the read rate and download latency.
 }
 
 const (
@@ -71,6 +72,17 @@ type BufferedReader struct {
 	// prefetching operation.
 	numPrefetchBlocks int64
 
+	// window limits the number of blocks in the queue when prefetching is
+	// adaptive, and is nil otherwise.
+	window *prefetchWindow
+
+	// blockShares caps window to a fair share of the global blocks, if not nil.
+	blockShares *BlockShares
+
+	// reservedBlocks is the number of blocks reserved by the block pool, which
+	// can always be used whatever the share.
+	reservedBlocks int64
+
 	metricHandle metrics.MetricHandle
 
 	readHandle []byte // For zonal bucket.
@@ -117,6 +129,10 @@ type BufferedReaderOptions struct {
 	GlobalMaxBlocksSem *semaphore.Weighted
 	WorkerPool         workerpool.WorkerPool
 	MetricHandle       metrics.MetricHandle
+
+	// BlockShares, if not nil, divides the blocks of GlobalMaxBlocksSem among
+	// the readers with adaptive prefetching.
+	BlockShares *BlockShares
 }
 
 // NewBufferedReader returns a new bufferedReader instance.
@@ -149,6 +165,14 @@ func NewBufferedReader(opts *BufferedReaderOptions) (*BufferedReader, error) {
 		metricHandle:             opts.MetricHandle,
 		prefetchMultiplier:       defaultPrefetchMultiplier,
 		randomReadsThreshold:     opts.Config.RandomSeekThreshold,
+		reservedBlocks:           numBlocksToReserve,
+	}
+	if opts.Config.AdaptivePrefetch {
+		reader.window = newPrefetchWindow(opts.Config.PrefetchBlockSizeBytes, opts.Config.MinBlocksPerHandle, opts.Config.MaxPrefetchBlockCnt)
+		if opts.BlockShares != nil {
+			reader.blockShares = opts.BlockShares
+			reader.blockShares.join()
+		}
 	}
 
 	reader.ctx, reader.cancelFunc = context.WithCancel(context.Background())
@@ -178,10 +202,12 @@ func (p *BufferedReader) handleRandomRead(offset int64, handleID int64) error {
 	// When a random seek is detected, the prefetched blocks in the queue become
 	// irrelevant. We must clear the queue, cancel any ongoing downloads, and
 	// release the blocks back to the pool.
+	evictedUnused := false
 	for !p.blockQueue.IsEmpty() {
-		entry := p.blockQueue.Pop()
-		entry.cancelAndWait()
-		p.releaseOrMarkEvicted(entry)
+		evictedUnused = p.discard(p.blockQueue.Pop()) || evictedUnused
+	}
+	if evictedUnused && p.window != nil {
+		p.window.evictedUnused()
 	}
 
 	if p.randomSeekCount > p.randomReadsThreshold {
@@ -216,6 +242,13 @@ func (p *BufferedReader) isRandomSeek(offset int64) bool {
 // the block pool.
 // LOCKS_REQUIRED(p.mu)
 func (p *BufferedReader) prepareQueueForOffset(offset int64) {
+	evictedUnused := false
+	defer func() {
+		if evictedUnused && p.window != nil {
+			p.window.evictedUnused()
+		}
+	}()
+
 	for !p.blockQueue.IsEmpty() {
 		entry := p.blockQueue.Peek()
 		block := entry.block
@@ -225,8 +258,7 @@ func (p *BufferedReader) prepareQueueForOffset(offset int64) {
 		if offset < blockStart || offset >= blockEnd {
 			// Offset is either before or beyond this block – discard.
 			p.blockQueue.Pop()
-			entry.cancelAndWait()
-			p.releaseOrMarkEvicted(entry)
+			evictedUnused = p.discard(entry) || evictedUnused
 		} else {
 			break
 		}
@@ -302,7 +334,8 @@ func (p *BufferedReader) ReadAt(ctx context.Context, inputBuf []byte, off int64)
 	for bytesRead < len(inputBuf) {
 		p.prepareQueueForOffset(off)
 
-		if p.blockQueue.IsEmpty() {
+		freshStart := p.blockQueue.IsEmpty()
+		if freshStart {
 			if err = p.freshStart(off); err != nil {
 				logger.Warnf("Fallback to another reader for object %q, handle %d, due to freshStart failure: %v", p.object.Name, handleID, err)
 				p.metricHandle.BufferedReadFallbackTriggerCount(1, "insufficient_memory")
@@ -314,6 +347,7 @@ func (p *BufferedReader) ReadAt(ctx context.Context, inputBuf []byte, off int64)
 		entry := p.blockQueue.Peek()
 		blk := entry.block
 
+		waitStart := time.Now()
 		status, waitErr := blk.AwaitReady(ctx)
 		if waitErr != nil {
 			err = fmt.Errorf("BufferedReader.ReadAt: AwaitReady: %w", waitErr)
@@ -334,6 +368,17 @@ func (p *BufferedReader) ReadAt(ctx context.Context, inputBuf []byte, off int64)
 			break
 		}
 
+		if !entry.consumed {
+			entry.consumed = true
+			if p.window != nil {
+				p.window.blockDownloaded(entry.downloadTime)
+				// The first block after a fresh start is always waited for.
+				if !freshStart && time.Since(waitStart) > starvationThreshold {
+					p.window.starved()
+				}
+			}
+		}
+
 		relOff := off - blk.AbsStartOff()
 		bytesToRead := len(inputBuf) - bytesRead
 		dataSlice, readErr := blk.ReadAtSlice(relOff, bytesToRead)
@@ -360,6 +405,9 @@ func (p *BufferedReader) ReadAt(ctx context.Context, inputBuf []byte, off int64)
 		if off >= blk.AbsStartOff()+blk.Size() {
 			entry := p.blockQueue.Pop()
 			p.releaseOrMarkEvicted(entry)
+			if p.window != nil {
+				p.window.blockConsumed(time.Now())
+			}
 
 			if !prefetchTriggered {
 				prefetchTriggered = true
@@ -400,8 +448,15 @@ func (p *BufferedReader) callback(entries []*blockQueueEntry) {
 // LOCKS_REQUIRED(p.mu)
 func (p *BufferedReader) prefetch() error {
 	// Determine the number of blocks to prefetch in this cycle, respecting the
-	// MaxPrefetchBlockCnt and the number of blocks remaining in the file.
-	availableSlots := p.config.MaxPrefetchBlockCnt - int64(p.blockQueue.Len())
+	// queue limit and the number of blocks remaining in the file.
+	limit := p.queueLimit()
+	if p.window != nil && p.blockPool.TotalFreeBlocks() > 0 && int64(p.blockQueue.Len()+p.blockPool.TotalFreeBlocks()) > limit {
+		// Hand the blocks above the limit back to the other readers.
+		if err := p.blockPool.ClearFreeBlockChannel(false); err != nil {
+			return fmt.Errorf("prefetch: releasing free blocks: %w", err)
+		}
+	}
+	availableSlots := limit - int64(p.blockQueue.Len())
 	if availableSlots <= 0 {
 		return nil
 	}
@@ -442,6 +497,19 @@ func (p *BufferedReader) prefetch() error {
 	return nil
 }
 
+// queueLimit returns the maximum number of blocks in the queue: the configured
+// maximum, or the adaptive window capped by the fair share of the reader.
+// LOCKS_REQUIRED(p.mu)
+func (p *BufferedReader) queueLimit() int64 {
+	if p.window == nil {
+		return p.config.MaxPrefetchBlockCnt
+	}
+	if p.blockShares == nil {
+		return p.window.size
+	}
+	return min(p.window.size, max(p.blockShares.share(), p.reservedBlocks))
+}
+
 // freshStart resets the prefetching state and schedules the initial set of
 // blocks starting from the given offset.
 // LOCKS_REQUIRED(p.mu)
@@ -496,6 +564,10 @@ func (p *BufferedReader) scheduleBlockWithIndex(b block.PrefetchBlock, blockInde
 	}
 
 	ctx, cancel := context.WithCancel(p.ctx)
+	entry := &blockQueueEntry{
+		block:  b,
+		cancel: cancel,
+	}
 	task := &downloadTask{
 		ctx:          ctx,
 		object:       p.object,
@@ -503,13 +575,11 @@ func (p *BufferedReader) scheduleBlockWithIndex(b block.PrefetchBlock, blockInde
 		block:        b,
 		readHandle:   p.readHandle,
 		metricHandle: p.metricHandle,
+		downloadTime: &entry.downloadTime,
 	}
 
 	logger.Tracef("Scheduling block: (%s, %d, %t).", p.object.Name, blockIndex, urgent)
-	p.blockQueue.Push(&blockQueueEntry{
-		block:  b,
-		cancel: cancel,
-	})
+	p.blockQueue.Push(entry)
 	p.workerPool.Schedule(urgent, task)
 	return nil
 }
@@ -518,9 +588,11 @@ func (p *BufferedReader) scheduleBlockWithIndex(b block.PrefetchBlock, blockInde
 func (p *BufferedReader) Destroy() {
 	p.mu.Lock()
 	for !p.blockQueue.IsEmpty() {
-		bqe := p.blockQueue.Pop()
-		bqe.cancelAndWait()
-		p.releaseOrMarkEvicted(bqe)
+		p.discard(p.blockQueue.Pop())
+	}
+	if p.blockShares != nil {
+		p.blockShares.leave()
+		p.blockShares = nil
 	}
 	p.mu.Unlock()
 
@@ -554,6 +626,15 @@ func (p *BufferedReader) Destroy() {
 	p.blockPool = nil
 }
 
+// discard cancels the download of an entry removed from the queue and releases
+// its block. It returns true if no data was read from the block.
+// LOCKS_REQUIRED(p.mu)
+func (p *BufferedReader) discard(entry *blockQueueEntry) bool {
+	entry.cancelAndWait()
+	p.releaseOrMarkEvicted(entry)
+	return !entry.consumed
+}
+
 // releaseOrMarkEvicted handles the release of a block that has been removed
 // from the prefetch queue. If the block has no outstanding references (i.e.,
 // it has not been returned to a FUSE read), it is immediately returned to the
@@ -592,6 +673,11 @@ func (p *BufferedReader) CheckInvariants() {
 		panic(fmt.Sprintf("BufferedReader: blockQueue length %d exceeds limit %d", p.blockQueue.Len(), p.config.MaxPrefetchBlockCnt))
 	}
 
+	// The adaptive window must stay within its bounds.
+	if p.window != nil && (p.window.size < p.window.min || p.window.size > p.window.max) {
+		panic(fmt.Sprintf("BufferedReader: prefetch window %d out of bounds [%d, %d]", p.window.size, p.window.min, p.window.max))
+	}
+
 	// The random seek count should never exceed randomReadsThreshold.
 	if p.randomSeekCount > p.randomReadsThreshold {
 		panic(fmt.Sprintf("BufferedReader: randomSeekCount %d exceeds threshold %d", p.randomSeekCount, p.randomReadsThreshold))
@@ -1825,3 +1825,92 @@ func (t *BufferedReaderTest) TestEvictedBlockIsReleasedOnlyAfterCallback() {
 	assert.Equal(t.T(), 1, reader.blockPool.TotalFreeBlocks(), "Evicted block should be released after its callback.")
 	resp2.Callback()
 }
+
+func (t *BufferedReaderTest) TestPrefetchLimitedByAdaptiveWindow() {
+	t.config.AdaptivePrefetch = true
+	t.config.InitialPrefetchBlockCnt = 4
+	reader, err := NewBufferedReader(&BufferedReaderOptions{
+		Object:             t.object,
+		Bucket:             t.bucket,
+		Config:             t.config,
+		GlobalMaxBlocksSem: t.globalMaxBlocksSem,
+		WorkerPool:         t.workerPool,
+		MetricHandle:       t.metricHandle,
+	})
+	require.NoError(t.T(), err)
+	defer reader.Destroy()
+	t.bucket.On("NewReaderWithReadHandle", mock.Anything, mock.MatchedBy(func(r *gcs.ReadObjectRequest) bool { return r.Range.Start == 0 })).Return(createFakeReaderWithOffset(t.T(), int(testPrefetchBlockSizeBytes), 0), nil).Once()
+	t.bucket.On("NewReaderWithReadHandle", mock.Anything, mock.MatchedBy(func(r *gcs.ReadObjectRequest) bool { return r.Range.Start == 1024 })).Return(createFakeReaderWithOffset(t.T(), int(testPrefetchBlockSizeBytes), 1024), nil).Once()
+
+	err = reader.prefetch()
+
+	require.NoError(t.T(), err)
+	// The window starts at MinBlocksPerHandle (2) blocks.
+	assert.Equal(t.T(), 2, reader.blockQueue.Len())
+	assert.Equal(t.T(), int64(2), reader.queueLimit())
+}
+
+func (t *BufferedReaderTest) TestReadAtForwardSeekShrinksAdaptiveWindow() {
+	t.config.AdaptivePrefetch = true
+	reader, err := NewBufferedReader(&BufferedReaderOptions{
+		Object:             t.object,
+		Bucket:             t.bucket,
+		Config:             t.config,
+		GlobalMaxBlocksSem: t.globalMaxBlocksSem,
+		WorkerPool:         t.workerPool,
+		MetricHandle:       t.metricHandle,
+	})
+	require.NoError(t.T(), err)
+	defer reader.Destroy()
+	reader.window.size = 4
+	for i := range int64(3) {
+		b, poolErr := reader.blockPool.Get()
+		require.NoError(t.T(), poolErr)
+		require.NoError(t.T(), b.SetAbsStartOff(i*testPrefetchBlockSizeBytes))
+		_, writeErr := b.Write(make([]byte, testPrefetchBlockSizeBytes))
+		require.NoError(t.T(), writeErr)
+		b.NotifyReady(block.BlockStatus{State: block.BlockStateDownloaded})
+		reader.blockQueue.Push(&blockQueueEntry{block: b, cancel: func() {}})
+	}
+	reader.nextBlockIndexToPrefetch = 3
+	t.bucket.On("NewReaderWithReadHandle", mock.Anything, mock.Anything).Return(createFakeReaderWithOffset(t.T(), int(testPrefetchBlockSizeBytes), 3*testPrefetchBlockSizeBytes), nil).Maybe()
+	t.bucket.On("Name").Return("test-bucket").Maybe()
+
+	// Skipping blocks 0 and 1 discards them unread.
+	resp, err := reader.ReadAt(t.ctx, make([]byte, 10), 2*testPrefetchBlockSizeBytes)
+
+	require.NoError(t.T(), err)
+	resp.Callback()
+	assert.Equal(t.T(), int64(2), reader.window.size)
+}
+
+func (t *BufferedReaderTest) TestAdaptiveWindowCappedByBlockShare() {
+	t.config.AdaptivePrefetch = true
+	shares := NewBlockShares(testGlobalMaxBlocks)
+	newReader := func() *BufferedReader {
+		reader, err := NewBufferedReader(&BufferedReaderOptions{
+			Object:             t.object,
+			Bucket:             t.bucket,
+			Config:             t.config,
+			GlobalMaxBlocksSem: t.globalMaxBlocksSem,
+			WorkerPool:         t.workerPool,
+			MetricHandle:       t.metricHandle,
+			BlockShares:        shares,
+		})
+		require.NoError(t.T(), err)
+		return reader
+	}
+	hot := newReader()
+	defer hot.Destroy()
+	hot.window.size = testMaxPrefetchBlockCnt
+	require.Equal(t.T(), testMaxPrefetchBlockCnt, hot.queueLimit())
+
+	others := []*BufferedReader{newReader(), newReader(), newReader()}
+
+	// 20 blocks are shared by 4 readers.
+	assert.Equal(t.T(), int64(5), hot.queueLimit())
+	for _, r := range others {
+		r.Destroy()
+	}
+	assert.Equal(t.T(), testMaxPrefetchBlockCnt, hot.queueLimit())
+}
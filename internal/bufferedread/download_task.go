@@ -43,6 +43,9 @@ type downloadTask struct {
 
 	// Used for zonal bucket to bypass the auth & metadata checks.
 	readHandle []byte
+
+	// If not nil, set to the time the download took when it succeeds.
+	downloadTime *time.Duration
 }
 
 // Execute implements the workerpool.Task interface. It downloads the data from
@@ -62,6 +65,9 @@ func (p *downloadTask) Execute() {
 		dur := time.Since(stime)
 		if err == nil {
 			logger.Tracef("Download: -> block (%s, %v) Ok(%v).", p.object.Name, blockId, dur)
+			if p.downloadTime != nil {
+				*p.downloadTime = dur
+			}
 			p.block.NotifyReady(block.BlockStatus{State: block.BlockStateDownloaded})
 		} else if errors.Is(err, context.Canceled) && p.ctx.Err() == context.Canceled {
 			logger.Tracef("Download: -> block (%s, %v) cancelled: %v.", p.object.Name, blockId, err)
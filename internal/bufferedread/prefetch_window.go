@@ -0,0 +1,156 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufferedread
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+const (
+	// The weight of the latest sample in the moving averages of the consumption
+	// rate and the download latency.
+	ewmaWeight = 0.25
+
+	// A read waiting longer than this for the block at the head of the queue is
+	// considered starved: the block wasn't prefetched early enough.
+	starvationThreshold = time.Millisecond
+)
+
+// prefetchWindow sizes the prefetch window of a BufferedReader, i.e. the
+// number of blocks it keeps in its queue, from what it observes: the window
+// grows when reads wait for blocks to be downloaded, to at least the number
+// of blocks consumed while one block is downloaded, and halves when blocks
+// are discarded without having been read.
+//
+// Not safe for concurrent use; the BufferedReader guards it with its mutex.
+type prefetchWindow struct {
+	blockSize int64
+
+	// The bounds of the window size.
+	min int64
+	max int64
+
+	// The current window size.
+	size int64
+
+	// The moving averages of the rate at which blocks are consumed, in bytes
+	// per second, and of the time it takes to download a block, in seconds.
+	rate    float64
+	latency float64
+
+	// When the last block was fully consumed, if any.
+	lastConsumed time.Time
+}
+
+func newPrefetchWindow(blockSize, initialSize, maxSize int64) *prefetchWindow {
+	return &prefetchWindow{
+		blockSize: blockSize,
+		min:       1,
+		max:       maxSize,
+		size:      min(max(initialSize, 1), maxSize),
+	}
+}
+
+func ewma(avg, sample float64) float64 {
+	if avg == 0 {
+		return sample
+	}
+	return avg + ewmaWeight*(sample-avg)
+}
+
+// blockConsumed records that a block was fully read at the supplied time.
+func (w *prefetchWindow) blockConsumed(now time.Time) {
+	if !w.lastConsumed.IsZero() {
+		if elapsed := now.Sub(w.lastConsumed).Seconds(); elapsed > 0 {
+			w.rate = ewma(w.rate, float64(w.blockSize)/elapsed)
+		}
+	}
+	w.lastConsumed = now
+}
+
+// blockDownloaded records the time it took to download a block.
+func (w *prefetchWindow) blockDownloaded(d time.Duration) {
+	if d > 0 {
+		w.latency = ewma(w.latency, d.Seconds())
+	}
+}
+
+// neededBlocks returns the number of blocks that keeps the consumer busy while
+// the next block is downloaded: those consumed meanwhile and the one being
+// read.
+func (w *prefetchWindow) neededBlocks() int64 {
+	return int64(math.Ceil(w.rate*w.latency/float64(w.blockSize))) + 1
+}
+
+// starved records that a read had to wait for a block to be downloaded.
+func (w *prefetchWindow) starved() {
+	w.size = min(max(2*w.size, w.neededBlocks()), w.max)
+}
+
+// evictedUnused records that blocks were discarded without having been read.
+func (w *prefetchWindow) evictedUnused() {
+	w.size = max(w.size/2, w.min)
+}
+
+////////////////////////////////////////////////////////////////////////
+// BlockShares
+////////////////////////////////////////////////////////////////////////
+
+// BlockShares divides the blocks available for buffered reads across all file
+// handles, i.e. read-global-max-blocks, evenly among the BufferedReaders with
+// adaptive prefetching, so that the window of a reader can't grow beyond its
+// share and starve the others. It is safe for concurrent use.
+type BlockShares struct {
+	mu sync.Mutex
+
+	// GUARDED_BY(mu)
+	limit   int64
+	readers int64
+}
+
+// NewBlockShares returns shares of the supplied number of blocks.
+func NewBlockShares(limit int64) *BlockShares {
+	return &BlockShares{limit: limit}
+}
+
+// SetLimit changes the number of blocks shared, when read-global-max-blocks
+// is reconfigured. Readers above their new share shrink their window on their
+// next prefetch.
+func (s *BlockShares) SetLimit(limit int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.limit = limit
+}
+
+func (s *BlockShares) join() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.readers++
+}
+
+func (s *BlockShares) leave() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.readers--
+}
+
+// share returns the number of blocks each reader may use, at least one.
+func (s *BlockShares) share() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return max(s.limit/max(s.readers, 1), 1)
+}
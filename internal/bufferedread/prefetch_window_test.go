@@ -0,0 +1,83 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufferedread
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrefetchWindowStartsWithinBounds(t *testing.T) {
+	assert.Equal(t, int64(4), newPrefetchWindow(1024, 4, 10).size)
+	assert.Equal(t, int64(10), newPrefetchWindow(1024, 20, 10).size)
+	assert.Equal(t, int64(1), newPrefetchWindow(1024, 0, 10).size)
+}
+
+func TestPrefetchWindowDoublesWhenStarved(t *testing.T) {
+	w := newPrefetchWindow(1024, 2, 10)
+
+	w.starved()
+	assert.Equal(t, int64(4), w.size)
+	w.starved()
+	assert.Equal(t, int64(8), w.size)
+	w.starved()
+	assert.Equal(t, int64(10), w.size)
+}
+
+func TestPrefetchWindowGrowsToCoverDownloadLatency(t *testing.T) {
+	w := newPrefetchWindow(1024, 1, 20)
+	// A block consumed every 10ms, and downloaded in 45ms.
+	start := time.Now()
+	for i := range 5 {
+		w.blockConsumed(start.Add(time.Duration(i) * 10 * time.Millisecond))
+		w.blockDownloaded(45 * time.Millisecond)
+	}
+
+	w.starved()
+
+	// 4.5 blocks, rounded up, are consumed while one is downloaded, plus the
+	// one being read.
+	assert.Equal(t, int64(6), w.size)
+}
+
+func TestPrefetchWindowHalvesWhenBlocksAreEvictedUnused(t *testing.T) {
+	w := newPrefetchWindow(1024, 8, 10)
+
+	w.evictedUnused()
+	assert.Equal(t, int64(4), w.size)
+	w.evictedUnused()
+	w.evictedUnused()
+	w.evictedUnused()
+	assert.Equal(t, int64(1), w.size)
+}
+
+func TestBlockSharesDividesLimitAmongReaders(t *testing.T) {
+	s := NewBlockShares(10)
+	assert.Equal(t, int64(10), s.share())
+
+	s.join()
+	s.join()
+	s.join()
+	assert.Equal(t, int64(3), s.share())
+
+	s.SetLimit(2)
+	assert.Equal(t, int64(1), s.share())
+
+	s.leave()
+	s.leave()
+	assert.Equal(t, int64(2), s.share())
+}
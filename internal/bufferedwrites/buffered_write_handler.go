@@ -97,6 +97,8 @@ type CreateBWHandlerRequest struct {
 	MaxBlocksPerFile         int64
 	GlobalMaxBlocksSem       *semaphore.Weighted
 	ChunkTransferTimeoutSecs int64
+	// Where the upload is journaled, or nil if uploads aren't.
+	Journal *UploadJournal
 }
 
 // NewBWHandler creates the bufferedWriteHandler struct.
@@ -121,6 +123,7 @@ func NewBWHandler(req *CreateBWHandlerRequest) (bwh BufferedWriteHandler, err er
 			MaxBlocksPerFile:         req.MaxBlocksPerFile,
 			BlockSize:                req.BlockSize,
 			ChunkTransferTimeoutSecs: req.ChunkTransferTimeoutSecs,
+			Journal:                  req.Journal,
 		}),
 		totalSize:     size,
 		mtime:         time.Now(),
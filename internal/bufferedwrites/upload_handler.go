@@ -24,10 +24,12 @@ import (
 	"io"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/block"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/logger"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
 )
 
 // UploadHandler is responsible for synchronized uploads of the filled blocks
@@ -57,6 +59,9 @@ type UploadHandler struct {
 	obj                  *gcs.Object
 	chunkTransferTimeout int64
 	blockSize            int64
+
+	// Where the upload is journaled, or nil if uploads aren't.
+	journal *UploadJournal
 }
 
 type CreateUploadHandlerRequest struct {
@@ -67,6 +72,7 @@ type CreateUploadHandlerRequest struct {
 	MaxBlocksPerFile         int64
 	BlockSize                int64
 	ChunkTransferTimeoutSecs int64
+	Journal                  *UploadJournal
 }
 
 // newUploadHandler creates the UploadHandler struct.
@@ -80,6 +86,7 @@ func newUploadHandler(req *CreateUploadHandlerRequest) *UploadHandler {
 		obj:                  req.Object,
 		blockSize:            req.BlockSize,
 		chunkTransferTimeout: req.ChunkTransferTimeoutSecs,
+		journal:              req.Journal,
 	}
 	return uh
 }
@@ -107,15 +114,43 @@ func (uh *UploadHandler) createObjectWriter() (err error) {
 	// (and context will be cancelled) by the time complete upload is done.
 	var ctx context.Context
 	ctx, uh.cancelFunc = context.WithCancel(context.Background())
+	var progress func(bytesUploadedSoFar int64)
+	if uh.journal != nil {
+		progress = func(bytesUploadedSoFar int64) {
+			uh.journal.progressed(uh.objectName, bytesUploadedSoFar)
+		}
+	}
 	if uh.bucket.BucketType().Zonal && (uh.obj != nil && uh.obj.Finalized.IsZero()) {
+		req.CallBack = progress
 		chunkWriterReq := gcs.CreateObjectChunkWriterRequest{
 			CreateObjectRequest: *req,
 			ChunkSize:           int(uh.blockSize),
 			Offset:              int64(uh.obj.Size),
 		}
 		uh.writer, err = uh.bucket.CreateAppendableObjectWriter(ctx, &chunkWriterReq)
+		if err == nil && uh.journal != nil {
+			uh.journal.started(UploadRecord{
+				ObjectName:  uh.objectName,
+				Appendable:  true,
+				Generation:  uh.obj.Generation,
+				StartOffset: int64(uh.obj.Size),
+				Started:     time.Now(),
+			})
+		}
 	} else {
-		uh.writer, err = uh.bucket.CreateObjectChunkWriter(ctx, req, int(uh.blockSize), nil)
+		if uh.journal != nil && !uh.bucket.BucketType().Zonal {
+			ctx = storageutil.WithResumableSessionCallback(ctx, func(uri string) {
+				uh.journal.sessionStarted(uh.objectName, uri)
+			})
+		}
+		uh.writer, err = uh.bucket.CreateObjectChunkWriter(ctx, req, int(uh.blockSize), progress)
+		if err == nil && uh.journal != nil {
+			uh.journal.started(UploadRecord{
+				ObjectName: uh.objectName,
+				Appendable: uh.bucket.BucketType().Zonal,
+				Started:    time.Now(),
+			})
+		}
 	}
 	return
 }
@@ -195,6 +230,9 @@ func (uh *UploadHandler) Finalize() (*gcs.MinObject, error) {
 		logger.Errorf("FinalizeUpload failed for object %s: %v", uh.objectName, err)
 		return nil, err
 	}
+	if uh.journal != nil {
+		uh.journal.done(uh.objectName)
+	}
 	return obj, nil
 }
 
@@ -225,6 +263,9 @@ func (uh *UploadHandler) FlushPendingWrites() (*gcs.MinObject, error) {
 		logger.Errorf("FlushUpload failed for object %s: %v", uh.objectName, err)
 		return nil, err
 	}
+	if uh.journal != nil && o != nil {
+		uh.journal.flushed(uh.objectName, o.Generation)
+	}
 	return o, nil
 }
 
@@ -235,6 +276,9 @@ func (uh *UploadHandler) CancelUpload() {
 	}
 	// Wait for all in progress buffers to be added to the free channel.
 	uh.wg.Wait()
+	if uh.journal != nil {
+		uh.journal.done(uh.objectName)
+	}
 }
 
 func (uh *UploadHandler) AwaitBlocksUpload() {
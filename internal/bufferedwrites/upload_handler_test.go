@@ -17,6 +17,7 @@ package bufferedwrites
 import (
 	"errors"
 	"fmt"
+	"path"
 	"strconv"
 	"testing"
 	"time"
@@ -448,6 +449,73 @@ func (t *UploadHandlerTest) TestUploadHandlerCancelUpload() {
 	assert.True(t.T(), cancelCalled)
 }
 
+func (t *UploadHandlerTest) journalUploads() []UploadRecord {
+	uploads, err := ReadUploadJournal(t.uh.journal.path)
+	require.NoError(t.T(), err)
+	return uploads
+}
+
+func (t *UploadHandlerTest) TestJournalRecordsUploadUntilFinalized() {
+	var err error
+	t.uh.journal, err = NewUploadJournal(path.Join(t.T().TempDir(), "journal"), 0600, nil)
+	require.NoError(t.T(), err)
+	writer := &storagemock.Writer{}
+	t.mockBucket.On("BucketType").Return(gcs.BucketType{Zonal: true})
+	t.mockBucket.On("CreateObjectChunkWriter", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(writer, nil)
+	t.mockBucket.On("FlushPendingWrites", mock.Anything, writer).Return(&gcs.MinObject{Generation: 5}, nil)
+	t.mockBucket.On("FinalizeUpload", mock.Anything, writer).Return(&gcs.MinObject{}, nil)
+
+	_, err = t.uh.FlushPendingWrites()
+	require.NoError(t.T(), err)
+
+	uploads := t.journalUploads()
+	require.Len(t.T(), uploads, 1)
+	assert.Equal(t.T(), objectName, uploads[0].ObjectName)
+	assert.True(t.T(), uploads[0].Appendable)
+	assert.Equal(t.T(), int64(5), uploads[0].Generation)
+	assert.False(t.T(), uploads[0].Started.IsZero())
+	_, err = t.uh.Finalize()
+	require.NoError(t.T(), err)
+	assert.Empty(t.T(), t.journalUploads())
+}
+
+func (t *UploadHandlerTest) TestJournalRecordsTakeoverOfUnfinalizedObject() {
+	t.createUploadHandlerWithObjectOfGivenSize(objectSize, time.Time{})
+	t.uh.obj.Generation = 3
+	var err error
+	t.uh.journal, err = NewUploadJournal(path.Join(t.T().TempDir(), "journal"), 0600, nil)
+	require.NoError(t.T(), err)
+	var progress func(int64)
+	t.mockBucket.On("BucketType").Return(gcs.BucketType{Zonal: true})
+	t.mockBucket.On("CreateAppendableObjectWriter", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { progress = args.Get(1).(*gcs.CreateObjectChunkWriterRequest).CallBack }).
+		Return(&storagemock.Writer{}, nil)
+
+	require.NoError(t.T(), t.uh.ensureWriter())
+	progress(100)
+
+	uploads := t.journalUploads()
+	require.Len(t.T(), uploads, 1)
+	assert.True(t.T(), uploads[0].Appendable)
+	assert.Equal(t.T(), int64(3), uploads[0].Generation)
+	assert.Equal(t.T(), int64(objectSize), uploads[0].StartOffset)
+	assert.Equal(t.T(), int64(100), uploads[0].Offset)
+}
+
+func (t *UploadHandlerTest) TestJournalForgetsCancelledUpload() {
+	var err error
+	t.uh.journal, err = NewUploadJournal(path.Join(t.T().TempDir(), "journal"), 0600, nil)
+	require.NoError(t.T(), err)
+	t.mockBucket.On("BucketType").Return(gcs.BucketType{})
+	t.mockBucket.On("CreateObjectChunkWriter", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&storagemock.Writer{}, nil)
+	require.NoError(t.T(), t.uh.ensureWriter())
+	require.Len(t.T(), t.journalUploads(), 1)
+
+	t.uh.CancelUpload()
+
+	assert.Empty(t.T(), t.journalUploads())
+}
+
 func (t *UploadHandlerTest) TestCreateObjectChunkWriterIsCalledWithCorrectRequestParametersForEmptyGCSObject() {
 	t.uh.obj = &gcs.Object{
 		Name:            t.uh.objectName,
@@ -0,0 +1,296 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufferedwrites
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/logger"
+)
+
+// UploadRecord describes a streaming upload in progress.
+type UploadRecord struct {
+	ObjectName string
+
+	// Whether the upload appends to an unfinalized object in a zonal bucket,
+	// whose data is visible as soon as it is flushed, as opposed to uploading
+	// the object in a resumable upload session.
+	Appendable bool
+
+	// The generation of the unfinalized object appended to, or zero until it
+	// is known, i.e. until the first flush of a new object.
+	Generation int64 `json:",omitempty"`
+
+	// The size of the unfinalized object taken over by the upload, or zero if
+	// the upload writes the object from the start.
+	StartOffset int64 `json:",omitempty"`
+
+	// The number of bytes the upload last reported as sent.
+	Offset int64 `json:",omitempty"`
+
+	// The URI of the resumable upload session of an upload that isn't
+	// appendable, once the session is started.
+	SessionURI string `json:",omitempty"`
+
+	Started time.Time
+}
+
+// The journal is compacted once it holds more records than
+// uploadJournalCompactionFactor per upload in progress plus
+// minUploadJournalRecords, since uploads append a record per chunk sent.
+const (
+	uploadJournalCompactionFactor = 4
+	minUploadJournalRecords       = 1000
+)
+
+// A record in the journal. Each record either adds or updates the entry for
+// an upload, or removes the entry for the completed upload of the object with
+// the given name.
+type uploadJournalRecord struct {
+	Done   bool `json:",omitempty"`
+	Upload UploadRecord
+}
+
+// UploadJournal is an append-only log of the streaming uploads in progress,
+// from which those interrupted by a crash can be found after a restart. It is
+// rewritten with just the uploads in progress whenever it grows too long.
+//
+// UploadJournal is safe for concurrent use.
+type UploadJournal struct {
+	path string
+	perm os.FileMode
+
+	mu sync.Mutex
+
+	// GUARDED_BY(mu)
+	uploads map[string]UploadRecord
+	// The number of records in the journal.
+	//
+	// GUARDED_BY(mu)
+	records int
+	// GUARDED_BY(mu)
+	f *os.File
+	// GUARDED_BY(mu)
+	w *bufio.Writer
+}
+
+// ReadUploadJournal replays the journal at the supplied path and returns the
+// uploads it leaves in progress, in the order they started. A missing journal
+// is treated as an empty one. Replay stops at the first corrupt record, which
+// is expected if gcsfuse crashed while appending it.
+func ReadUploadJournal(path string) ([]UploadRecord, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Open: %w", err)
+	}
+	defer f.Close()
+
+	uploads := make(map[string]UploadRecord)
+	d := json.NewDecoder(bufio.NewReader(f))
+	for {
+		var r uploadJournalRecord
+		err := d.Decode(&r)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			logger.Warnf("ReadUploadJournal: ignoring the rest of %s: %v", path, err)
+			break
+		}
+
+		if r.Done {
+			delete(uploads, r.Upload.ObjectName)
+		} else {
+			uploads[r.Upload.ObjectName] = r.Upload
+		}
+	}
+	return sortedUploads(uploads), nil
+}
+
+func sortedUploads(uploads map[string]UploadRecord) []UploadRecord {
+	var records []UploadRecord
+	for _, r := range uploads {
+		records = append(records, r)
+	}
+	slices.SortFunc(records, func(a, b UploadRecord) int {
+		return a.Started.Compare(b.Started)
+	})
+	return records
+}
+
+// NewUploadJournal replaces the journal at the supplied path with one
+// containing just the supplied uploads, and opens it for appending.
+func NewUploadJournal(path string, perm os.FileMode, uploads []UploadRecord) (*UploadJournal, error) {
+	j := &UploadJournal{path: path, perm: perm, uploads: make(map[string]UploadRecord)}
+	for _, r := range uploads {
+		j.uploads[r.ObjectName] = r
+	}
+	if err := j.rewrite(); err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+// LOCKS_REQUIRED(j.mu) if j is visible to others.
+func (j *UploadJournal) rewrite() (err error) {
+	tmpPath := j.path + ".tmp"
+	f, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, j.perm)
+	if err != nil {
+		return fmt.Errorf("OpenFile: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			f.Close()
+			os.Remove(tmpPath)
+		}
+	}()
+
+	w := bufio.NewWriter(f)
+	e := json.NewEncoder(w)
+	for _, r := range sortedUploads(j.uploads) {
+		if err = e.Encode(uploadJournalRecord{Upload: r}); err != nil {
+			return fmt.Errorf("Encode: %w", err)
+		}
+	}
+	if err = w.Flush(); err != nil {
+		return fmt.Errorf("Flush: %w", err)
+	}
+	if err = os.Rename(tmpPath, j.path); err != nil {
+		return fmt.Errorf("Rename: %w", err)
+	}
+
+	if j.f != nil {
+		j.f.Close()
+	}
+	j.f = f
+	j.w = w
+	j.records = len(j.uploads)
+	return nil
+}
+
+// LOCKS_REQUIRED(j.mu)
+func (j *UploadJournal) append(r uploadJournalRecord) {
+	if j.f == nil {
+		return
+	}
+
+	// Records are flushed one at a time, so that a crash loses at most the
+	// last one.
+	err := json.NewEncoder(j.w).Encode(r)
+	if err == nil {
+		err = j.w.Flush()
+	}
+	if err != nil {
+		logger.Warnf("UploadJournal: failed to append to %s: %v", j.path, err)
+		return
+	}
+
+	j.records++
+	if j.records > uploadJournalCompactionFactor*len(j.uploads)+minUploadJournalRecords {
+		if err := j.rewrite(); err != nil {
+			logger.Warnf("UploadJournal: failed to compact %s: %v", j.path, err)
+		}
+	}
+}
+
+// LOCKS_EXCLUDED(j.mu)
+func (j *UploadJournal) update(objectName string, f func(r *UploadRecord)) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	r, ok := j.uploads[objectName]
+	if !ok {
+		return
+	}
+	f(&r)
+	j.uploads[objectName] = r
+	j.append(uploadJournalRecord{Upload: r})
+}
+
+// started records the start of an upload, replacing any earlier upload of the
+// same object.
+//
+// LOCKS_EXCLUDED(j.mu)
+func (j *UploadJournal) started(r UploadRecord) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.uploads[r.ObjectName] = r
+	j.append(uploadJournalRecord{Upload: r})
+}
+
+// progressed records the number of bytes sent by the upload of the named
+// object.
+//
+// LOCKS_EXCLUDED(j.mu)
+func (j *UploadJournal) progressed(objectName string, offset int64) {
+	j.update(objectName, func(r *UploadRecord) { r.Offset = offset })
+}
+
+// flushed records the generation of the unfinalized object appended to by the
+// upload of the named object.
+//
+// LOCKS_EXCLUDED(j.mu)
+func (j *UploadJournal) flushed(objectName string, generation int64) {
+	j.update(objectName, func(r *UploadRecord) { r.Generation = generation })
+}
+
+// sessionStarted records the URI of the resumable upload session of the
+// upload of the named object.
+//
+// LOCKS_EXCLUDED(j.mu)
+func (j *UploadJournal) sessionStarted(objectName string, uri string) {
+	j.update(objectName, func(r *UploadRecord) { r.SessionURI = uri })
+}
+
+// done records that the upload of the named object completed, or was
+// cancelled because the file was deleted.
+//
+// LOCKS_EXCLUDED(j.mu)
+func (j *UploadJournal) done(objectName string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if _, ok := j.uploads[objectName]; !ok {
+		return
+	}
+	delete(j.uploads, objectName)
+	j.append(uploadJournalRecord{Done: true, Upload: UploadRecord{ObjectName: objectName}})
+}
+
+// Close compacts the journal down to the uploads still in progress, which are
+// those that failed if all files were closed, and closes it. Further records
+// are dropped.
+//
+// LOCKS_EXCLUDED(j.mu)
+func (j *UploadJournal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	err := j.rewrite()
+	if j.f != nil {
+		j.f.Close()
+	}
+	j.f, j.w = nil, nil
+	return err
+}
@@ -0,0 +1,133 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufferedwrites
+
+import (
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var started = time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestUploadJournal(t *testing.T, pending ...UploadRecord) (*UploadJournal, string) {
+	t.Helper()
+	p := path.Join(t.TempDir(), "bucket.jsonl")
+	j, err := NewUploadJournal(p, 0600, pending)
+	require.NoError(t, err)
+	return j, p
+}
+
+func TestReadUploadJournalOfMissingFile(t *testing.T) {
+	uploads, err := ReadUploadJournal(path.Join(t.TempDir(), "missing"))
+
+	require.NoError(t, err)
+	assert.Empty(t, uploads)
+}
+
+func TestUploadJournalReplaysUploadsInProgress(t *testing.T) {
+	j, p := newTestUploadJournal(t)
+
+	j.started(UploadRecord{ObjectName: "a", Appendable: true, Started: started})
+	j.started(UploadRecord{ObjectName: "b", Started: started.Add(time.Second)})
+	j.started(UploadRecord{ObjectName: "c", Started: started.Add(2 * time.Second)})
+	j.progressed("a", 100)
+	j.flushed("a", 7)
+	j.progressed("b", 50)
+	j.sessionStarted("b", "https://storage.googleapis.com/upload/b")
+	j.done("c")
+	// Ignored: no upload of the object is in progress.
+	j.progressed("c", 10)
+
+	uploads, err := ReadUploadJournal(p)
+	require.NoError(t, err)
+	assert.Equal(t, []UploadRecord{
+		{ObjectName: "a", Appendable: true, Generation: 7, Offset: 100, Started: started},
+		{ObjectName: "b", Offset: 50, SessionURI: "https://storage.googleapis.com/upload/b", Started: started.Add(time.Second)},
+	}, uploads)
+}
+
+func TestUploadJournalReplacesEarlierUploadOfSameObject(t *testing.T) {
+	j, p := newTestUploadJournal(t)
+
+	j.started(UploadRecord{ObjectName: "a", Started: started})
+	j.progressed("a", 100)
+	j.started(UploadRecord{ObjectName: "a", Started: started.Add(time.Second)})
+
+	uploads, err := ReadUploadJournal(p)
+	require.NoError(t, err)
+	assert.Equal(t, []UploadRecord{{ObjectName: "a", Started: started.Add(time.Second)}}, uploads)
+}
+
+func TestReadUploadJournalStopsAtCorruptRecord(t *testing.T) {
+	j, p := newTestUploadJournal(t)
+	j.started(UploadRecord{ObjectName: "a", Started: started})
+	f, err := os.OpenFile(p, os.O_APPEND|os.O_WRONLY, 0)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"Upload":{"ObjectName":"b"`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	uploads, err := ReadUploadJournal(p)
+
+	require.NoError(t, err)
+	assert.Equal(t, []UploadRecord{{ObjectName: "a", Started: started}}, uploads)
+}
+
+func TestNewUploadJournalKeepsPendingUploads(t *testing.T) {
+	pending := UploadRecord{ObjectName: "a", Appendable: true, Offset: 10, Started: started}
+	j, p := newTestUploadJournal(t, pending)
+
+	j.started(UploadRecord{ObjectName: "b", Started: started.Add(time.Second)})
+
+	uploads, err := ReadUploadJournal(p)
+	require.NoError(t, err)
+	assert.Equal(t, []UploadRecord{pending, {ObjectName: "b", Started: started.Add(time.Second)}}, uploads)
+}
+
+func TestUploadJournalCloseCompactsAndDropsFurtherRecords(t *testing.T) {
+	j, p := newTestUploadJournal(t)
+	j.started(UploadRecord{ObjectName: "a", Started: started})
+	j.started(UploadRecord{ObjectName: "b", Started: started})
+	j.done("b")
+
+	require.NoError(t, j.Close())
+	j.started(UploadRecord{ObjectName: "c", Started: started})
+
+	contents, err := os.ReadFile(p)
+	require.NoError(t, err)
+	assert.Equal(t, "{\"Upload\":{\"ObjectName\":\"a\",\"Appendable\":false,\"Started\":\"2026-03-01T12:00:00Z\"}}\n", string(contents))
+}
+
+func TestUploadJournalCompactsWhenRecordsOutnumberUploads(t *testing.T) {
+	j, p := newTestUploadJournal(t)
+	j.started(UploadRecord{ObjectName: "a", Started: started})
+
+	for i := 1; i <= 2*minUploadJournalRecords; i++ {
+		j.progressed("a", int64(i))
+	}
+
+	contents, err := os.ReadFile(p)
+	require.NoError(t, err)
+	assert.Less(t, strings.Count(string(contents), "\n"), minUploadJournalRecords+uploadJournalCompactionFactor+2)
+	uploads, err := ReadUploadJournal(p)
+	require.NoError(t, err)
+	assert.Equal(t, []UploadRecord{{ObjectName: "a", Offset: 2 * minUploadJournalRecords, Started: started}}, uploads)
+}
@@ -0,0 +1,193 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufferedwrites
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/logger"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+)
+
+// The outcomes of the recovery of an interrupted upload.
+const (
+	// The unfinalized object written by the upload was kept: writing can resume
+	// where it stopped by opening the file for appending.
+	UploadOutcomeResumable = "resumable"
+
+	// The unfinalized object created by the upload was deleted, or the
+	// resumable upload session of the upload was cancelled.
+	UploadOutcomeAborted = "aborted"
+
+	// None of the data written can be recovered: it was sent in a resumable
+	// upload session, whose remaining data was only in the memory of the
+	// crashed process and which GCS discards when it expires, or the object
+	// written has since been replaced. The object, if any, is as it was before
+	// the upload or as replaced.
+	UploadOutcomeLost = "lost"
+)
+
+// IncompleteUpload reports a streaming upload interrupted by a crash or a
+// failure, and what was done about it at mount.
+type IncompleteUpload struct {
+	ObjectName string
+	Started    time.Time
+
+	// The number of bytes the upload last reported as sent.
+	SentBytes int64
+
+	// The size of the unfinalized object written by the upload, for
+	// UploadOutcomeResumable.
+	PersistedBytes int64
+
+	// One of the UploadOutcome constants.
+	Outcome string
+}
+
+// RecoverUploads resolves the supplied uploads, left in progress in a journal,
+// against the bucket: the unfinalized objects written by those appending to
+// one are kept for writing to resume, or deleted if abort is set and they were
+// created by the upload. The resumable upload sessions of the others are
+// cancelled if abort is set, and otherwise left to expire. The uploads that couldn't be resolved, because GCS
+// couldn't be reached, are returned as pending, to be kept in the journal.
+func RecoverUploads(ctx context.Context, bucket gcs.Bucket, uploads []UploadRecord, abort bool) (incomplete []IncompleteUpload, pending []UploadRecord) {
+	for _, r := range uploads {
+		u, err := recoverUpload(ctx, bucket, r, abort)
+		if err != nil {
+			logger.Warnf("Failed to recover the interrupted upload of %s, will retry on next mount: %v", r.ObjectName, err)
+			pending = append(pending, r)
+			continue
+		}
+		if u == nil {
+			continue
+		}
+		logger.Warnf("Upload of %s started at %v was interrupted after sending %d bytes: %s, %d bytes kept", u.ObjectName, u.Started, u.SentBytes, u.Outcome, u.PersistedBytes)
+		incomplete = append(incomplete, *u)
+	}
+	return
+}
+
+// recoverUpload returns nil if the upload turns out to have completed.
+func recoverUpload(ctx context.Context, bucket gcs.Bucket, r UploadRecord, abort bool) (*IncompleteUpload, error) {
+	u := &IncompleteUpload{
+		ObjectName: r.ObjectName,
+		Started:    r.Started,
+		SentBytes:  r.Offset,
+		Outcome:    UploadOutcomeLost,
+	}
+	if !r.Appendable {
+		return recoverUploadSession(ctx, u, r.SessionURI, abort)
+	}
+
+	o, _, err := bucket.StatObject(ctx, &gcs.StatObjectRequest{Name: r.ObjectName, ForceFetchFromGcs: true})
+	var notFound *gcs.NotFoundError
+	if errors.As(err, &notFound) {
+		return u, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("StatObject: %w", err)
+	}
+
+	switch {
+	case r.Generation != 0 && o.Generation != r.Generation, r.Generation == 0 && o.Updated.Before(r.Started):
+		// Replaced since, or not written since the upload started.
+		return u, nil
+	case !o.Finalized.IsZero():
+		// Finalized, by the upload before the journal recorded it, or since.
+		return nil, nil
+	}
+
+	if abort && r.StartOffset == 0 {
+		err = bucket.DeleteObject(ctx, &gcs.DeleteObjectRequest{Name: r.ObjectName, Generation: o.Generation})
+		if err != nil && !errors.As(err, &notFound) {
+			return nil, fmt.Errorf("DeleteObject: %w", err)
+		}
+		u.Outcome = UploadOutcomeAborted
+		return u, nil
+	}
+	if abort {
+		logger.Warnf("Not deleting %s: the interrupted upload appended to an existing object", r.ObjectName)
+	}
+	u.Outcome = UploadOutcomeResumable
+	u.PersistedBytes = int64(o.Size)
+	return u, nil
+}
+
+// recoverUploadSession resolves an upload that sent its data in the resumable
+// upload session with the supplied URI, if known. Sessions can't be resumed,
+// since the data left to send was only in the memory of the crashed process,
+// but can be cancelled.
+func recoverUploadSession(ctx context.Context, u *IncompleteUpload, uri string, abort bool) (*IncompleteUpload, error) {
+	if uri == "" {
+		return u, nil
+	}
+
+	status, err := uploadSessionRequest(ctx, http.MethodPut, uri, "bytes */*")
+	if err != nil {
+		return nil, err
+	}
+	switch status {
+	case http.StatusOK, http.StatusCreated:
+		// The upload completed before the journal recorded it.
+		return nil, nil
+	case http.StatusNotFound, http.StatusGone:
+		// Expired or already cancelled.
+		return u, nil
+	case http.StatusPermanentRedirect:
+	default:
+		return nil, fmt.Errorf("upload session status query: unexpected status %d", status)
+	}
+	if !abort {
+		return u, nil
+	}
+
+	status, err = uploadSessionRequest(ctx, http.MethodDelete, uri, "")
+	if err != nil {
+		return nil, err
+	}
+	switch status {
+	// GCS answers cancellations with the non-standard 499.
+	case 499, http.StatusNoContent, http.StatusNotFound, http.StatusGone:
+		u.Outcome = UploadOutcomeAborted
+		return u, nil
+	default:
+		return nil, fmt.Errorf("upload session cancellation: unexpected status %d", status)
+	}
+}
+
+// uploadSessionRequest sends an empty request to the resumable upload session
+// with the supplied URI, and returns the status of the response. The URI
+// authorizes the request by itself.
+func uploadSessionRequest(ctx context.Context, method string, uri string, contentRange string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, method, uri, nil)
+	if err != nil {
+		return 0, fmt.Errorf("NewRequest: %w", err)
+	}
+	if contentRange != "" {
+		req.Header.Set("Content-Range", contentRange)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", method, err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,187 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufferedwrites
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	storagemock "github.com/googlecloudplatform/gcsfuse/v3/internal/storage/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func statReturns(bucket *storagemock.TestifyMockBucket, name string, o *gcs.MinObject, err error) {
+	bucket.On("StatObject", mock.Anything, &gcs.StatObjectRequest{Name: name, ForceFetchFromGcs: true}).
+		Return(o, (*gcs.ExtendedObjectAttributes)(nil), err)
+}
+
+func TestRecoverUploadsReportsResumableUploadSessionsAsLost(t *testing.T) {
+	bucket := new(storagemock.TestifyMockBucket)
+	r := UploadRecord{ObjectName: "a", Offset: 100, Started: started}
+
+	incomplete, pending := RecoverUploads(context.Background(), bucket, []UploadRecord{r}, false)
+
+	assert.Equal(t, []IncompleteUpload{{ObjectName: "a", Started: started, SentBytes: 100, Outcome: UploadOutcomeLost}}, incomplete)
+	assert.Empty(t, pending)
+	bucket.AssertNotCalled(t, "StatObject", mock.Anything, mock.Anything)
+}
+
+// uploadSessionServer serves a resumable upload session answering status
+// queries with the supplied status, and records the methods of the requests.
+func uploadSessionServer(t *testing.T, status int, methods *[]string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*methods = append(*methods, r.Method)
+		switch r.Method {
+		case http.MethodPut:
+			assert.Equal(t, "bytes */*", r.Header.Get("Content-Range"))
+			w.WriteHeader(status)
+		case http.MethodDelete:
+			w.WriteHeader(499)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRecoverUploadsLeavesResumableUploadSessionsToExpire(t *testing.T) {
+	var methods []string
+	server := uploadSessionServer(t, http.StatusPermanentRedirect, &methods)
+	r := UploadRecord{ObjectName: "a", Offset: 100, SessionURI: server.URL, Started: started}
+
+	incomplete, pending := RecoverUploads(context.Background(), new(storagemock.TestifyMockBucket), []UploadRecord{r}, false)
+
+	assert.Equal(t, []IncompleteUpload{{ObjectName: "a", Started: started, SentBytes: 100, Outcome: UploadOutcomeLost}}, incomplete)
+	assert.Empty(t, pending)
+	assert.Equal(t, []string{http.MethodPut}, methods)
+}
+
+func TestRecoverUploadsCancelsResumableUploadSessions(t *testing.T) {
+	var methods []string
+	server := uploadSessionServer(t, http.StatusPermanentRedirect, &methods)
+	r := UploadRecord{ObjectName: "a", Offset: 100, SessionURI: server.URL, Started: started}
+
+	incomplete, pending := RecoverUploads(context.Background(), new(storagemock.TestifyMockBucket), []UploadRecord{r}, true)
+
+	assert.Equal(t, []IncompleteUpload{{ObjectName: "a", Started: started, SentBytes: 100, Outcome: UploadOutcomeAborted}}, incomplete)
+	assert.Empty(t, pending)
+	assert.Equal(t, []string{http.MethodPut, http.MethodDelete}, methods)
+}
+
+func TestRecoverUploadsIgnoresCompletedResumableUploadSessions(t *testing.T) {
+	var methods []string
+	server := uploadSessionServer(t, http.StatusOK, &methods)
+	r := UploadRecord{ObjectName: "a", Offset: 100, SessionURI: server.URL, Started: started}
+
+	incomplete, pending := RecoverUploads(context.Background(), new(storagemock.TestifyMockBucket), []UploadRecord{r}, true)
+
+	assert.Empty(t, incomplete)
+	assert.Empty(t, pending)
+	assert.Equal(t, []string{http.MethodPut}, methods)
+}
+
+func TestRecoverUploadsKeepsPendingUploadSessionsOnError(t *testing.T) {
+	var methods []string
+	server := uploadSessionServer(t, http.StatusServiceUnavailable, &methods)
+	r := UploadRecord{ObjectName: "a", Offset: 100, SessionURI: server.URL, Started: started}
+
+	incomplete, pending := RecoverUploads(context.Background(), new(storagemock.TestifyMockBucket), []UploadRecord{r}, true)
+
+	assert.Empty(t, incomplete)
+	assert.Equal(t, []UploadRecord{r}, pending)
+}
+
+func TestRecoverUploadsKeepsUnfinalizedObjects(t *testing.T) {
+	bucket := new(storagemock.TestifyMockBucket)
+	statReturns(bucket, "a", &gcs.MinObject{Name: "a", Size: 80, Generation: 7}, nil)
+	r := UploadRecord{ObjectName: "a", Appendable: true, Generation: 7, Offset: 100, Started: started}
+
+	incomplete, pending := RecoverUploads(context.Background(), bucket, []UploadRecord{r}, false)
+
+	assert.Equal(t, []IncompleteUpload{{ObjectName: "a", Started: started, SentBytes: 100, PersistedBytes: 80, Outcome: UploadOutcomeResumable}}, incomplete)
+	assert.Empty(t, pending)
+}
+
+func TestRecoverUploadsAbortsUnfinalizedObjectsCreatedByUpload(t *testing.T) {
+	bucket := new(storagemock.TestifyMockBucket)
+	// Never flushed, so the generation wasn't journaled.
+	statReturns(bucket, "a", &gcs.MinObject{Name: "a", Size: 80, Generation: 7, Updated: started.Add(time.Second)}, nil)
+	bucket.On("DeleteObject", mock.Anything, &gcs.DeleteObjectRequest{Name: "a", Generation: 7}).Return(nil)
+	r := UploadRecord{ObjectName: "a", Appendable: true, Offset: 100, Started: started}
+
+	incomplete, _ := RecoverUploads(context.Background(), bucket, []UploadRecord{r}, true)
+
+	assert.Equal(t, []IncompleteUpload{{ObjectName: "a", Started: started, SentBytes: 100, Outcome: UploadOutcomeAborted}}, incomplete)
+	bucket.AssertExpectations(t)
+}
+
+func TestRecoverUploadsDoesNotAbortTakeovers(t *testing.T) {
+	bucket := new(storagemock.TestifyMockBucket)
+	statReturns(bucket, "a", &gcs.MinObject{Name: "a", Size: 80, Generation: 7}, nil)
+	r := UploadRecord{ObjectName: "a", Appendable: true, Generation: 7, StartOffset: 50, Offset: 30, Started: started}
+
+	incomplete, _ := RecoverUploads(context.Background(), bucket, []UploadRecord{r}, true)
+
+	assert.Equal(t, []IncompleteUpload{{ObjectName: "a", Started: started, SentBytes: 30, PersistedBytes: 80, Outcome: UploadOutcomeResumable}}, incomplete)
+	bucket.AssertNotCalled(t, "DeleteObject", mock.Anything, mock.Anything)
+}
+
+func TestRecoverUploadsReportsReplacedObjectsAsLost(t *testing.T) {
+	bucket := new(storagemock.TestifyMockBucket)
+	statReturns(bucket, "a", &gcs.MinObject{Name: "a", Generation: 8}, nil)
+	statReturns(bucket, "b", &gcs.MinObject{Name: "b", Generation: 9, Updated: started.Add(-time.Second)}, nil)
+	statReturns(bucket, "c", nil, &gcs.NotFoundError{Err: errors.New("not found")})
+	uploads := []UploadRecord{
+		{ObjectName: "a", Appendable: true, Generation: 7, Started: started},
+		{ObjectName: "b", Appendable: true, Started: started},
+		{ObjectName: "c", Appendable: true, Started: started},
+	}
+
+	incomplete, _ := RecoverUploads(context.Background(), bucket, uploads, true)
+
+	assert.Equal(t, []IncompleteUpload{
+		{ObjectName: "a", Started: started, Outcome: UploadOutcomeLost},
+		{ObjectName: "b", Started: started, Outcome: UploadOutcomeLost},
+		{ObjectName: "c", Started: started, Outcome: UploadOutcomeLost},
+	}, incomplete)
+	bucket.AssertNotCalled(t, "DeleteObject", mock.Anything, mock.Anything)
+}
+
+func TestRecoverUploadsIgnoresFinalizedObjects(t *testing.T) {
+	bucket := new(storagemock.TestifyMockBucket)
+	statReturns(bucket, "a", &gcs.MinObject{Name: "a", Generation: 7, Updated: started, Finalized: started}, nil)
+	r := UploadRecord{ObjectName: "a", Appendable: true, Started: started}
+
+	incomplete, pending := RecoverUploads(context.Background(), bucket, []UploadRecord{r}, false)
+
+	assert.Empty(t, incomplete)
+	assert.Empty(t, pending)
+}
+
+func TestRecoverUploadsKeepsPendingUploadsOnError(t *testing.T) {
+	bucket := new(storagemock.TestifyMockBucket)
+	statReturns(bucket, "a", nil, errors.New("unavailable"))
+	r := UploadRecord{ObjectName: "a", Appendable: true, Started: started}
+
+	incomplete, pending := RecoverUploads(context.Background(), bucket, []UploadRecord{r}, false)
+
+	assert.Empty(t, incomplete)
+	assert.Equal(t, []UploadRecord{r}, pending)
+}
@@ -48,6 +48,11 @@ type FileInfo struct {
 	ObjectGeneration int64
 	Offset           uint64
 	FileSize         uint64
+
+	// Adopted is true if the file was downloaded by another gcsfuse process
+	// sharing the cache directory. That process accounts for the file, and is
+	// responsible for removing it.
+	Adopted bool `json:",omitempty"`
 }
 
 func (fi FileInfo) Size() uint64 {
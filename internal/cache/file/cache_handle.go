@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sync"
 	"sync/atomic"
 
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/data"
@@ -32,6 +33,13 @@ type CacheHandle struct {
 	// fileHandle to a local file which contains locally downloaded data.
 	fileHandle *os.File
 
+	// localFilePath is the path to the local file to open on first read, if
+	// fileHandle is nil. When the cache directory is shared, the download job
+	// replaces the file at that path, so it is only opened once the job has
+	// downloaded to or adopted it.
+	localFilePath string
+	openMu        sync.Mutex
+
 	// fileDownloadJob is a reference to async download Job. It can be nil if
 	// job is already completed.
 	fileDownloadJob *downloader.Job
@@ -66,7 +74,10 @@ func NewCacheHandle(localFileHandle *os.File, fileDownloadJob *downloader.Job,
 }
 
 func (fch *CacheHandle) validateCacheHandle() error {
-	if fch.fileHandle == nil {
+	fch.openMu.Lock()
+	closed := fch.fileHandle == nil && fch.localFilePath == ""
+	fch.openMu.Unlock()
+	if closed {
 		return util.ErrInvalidFileHandle
 	}
 
@@ -241,7 +252,11 @@ func (fch *CacheHandle) Read(ctx context.Context, bucket gcs.Bucket, object *gcs
 	}
 
 	// We are here means, we have the data downloaded which kernel has asked for.
-	n, err = fch.fileHandle.ReadAt(dst, offset)
+	f, err := fch.localFile()
+	if err != nil {
+		return 0, false, fmt.Errorf("%w: while opening the local file: %w", util.ErrInReadingFileHandle, err)
+	}
+	n, err = f.ReadAt(dst, offset)
 	requestedNumBytes := int(requiredOffset - offset)
 	// dst buffer has fixed size of 1 MiB even when the offset is such that
 	// offset + 1 MiB > object size. In that case, io.ErrUnexpectedEOF is thrown
@@ -270,6 +285,20 @@ func (fch *CacheHandle) Read(ctx context.Context, bucket gcs.Bucket, object *gcs
 	return
 }
 
+// localFile returns the handle to the local file, opening it if needed.
+func (fch *CacheHandle) localFile() (*os.File, error) {
+	fch.openMu.Lock()
+	defer fch.openMu.Unlock()
+	if fch.fileHandle == nil {
+		f, err := os.Open(fch.localFilePath)
+		if err != nil {
+			return nil, err
+		}
+		fch.fileHandle = f
+	}
+	return fch.fileHandle, nil
+}
+
 // IsSequential returns true if the sequential read is being performed, false for
 // random read.
 func (fch *CacheHandle) IsSequential(currentOffset int64) bool {
@@ -290,6 +319,9 @@ func (fch *CacheHandle) IsSequential(currentOffset int64) bool {
 
 // Close closes the underlying fileHandle pointing to locally downloaded cache file.
 func (fch *CacheHandle) Close() (err error) {
+	fch.openMu.Lock()
+	defer fch.openMu.Unlock()
+	fch.localFilePath = ""
 	if fch.fileHandle != nil {
 		err = fch.fileHandle.Close()
 		if err != nil {
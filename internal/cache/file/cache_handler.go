@@ -15,10 +15,13 @@
 package file
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path"
 	"regexp"
+	"strings"
 
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/data"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/file/downloader"
@@ -27,6 +30,7 @@ import (
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/locker"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/logger"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"golang.org/x/sync/errgroup"
 )
 
 // CacheHandler is responsible for creating CacheHandle and invalidating file cache
@@ -59,6 +63,17 @@ type CacheHandler struct {
 
 	// includeRegex is the compiled regex for including files from cache
 	includeRegex *regexp.Regexp
+
+	// journal records the fully downloaded files in cache, so that they can be
+	// re-adopted after a restart. It is nil unless RecoverFromJournal has been
+	// called.
+	journal *Journal
+
+	// sharedUsage accounts for the files downloaded into the cache directory by
+	// all the processes sharing it, which together may take up to
+	// sharedMaxSize bytes. It is nil unless ShareCacheDir has been called.
+	sharedUsage   *SharedUsage
+	sharedMaxSize uint64
 }
 
 func NewCacheHandler(fileInfoCache *lru.Cache, jobManager *downloader.JobManager, cacheDir string, filePerm os.FileMode, dirPerm os.FileMode, excludeRegex string, includeRegex string) *CacheHandler {
@@ -114,9 +129,12 @@ func (chr *CacheHandler) cleanUpEvictedFile(fileInfo *data.FileInfo) error {
 	}
 
 	chr.jobManager.InvalidateAndRemoveJob(key.ObjectName, key.BucketName)
+	if chr.journal != nil {
+		chr.journal.Remove(key)
+	}
 
 	localFilePath := util.GetDownloadPath(chr.cacheDir, util.GetObjectPath(key.BucketName, key.ObjectName))
-	err = util.TruncateAndRemoveFile(localFilePath)
+	err = chr.removeCacheFile(fileInfo)
 	if err != nil {
 		if os.IsNotExist(err) {
 			logger.Warnf("cleanUpEvictedFile: file was not present at the time of clean up: %v", err)
@@ -152,12 +170,24 @@ func (chr *CacheHandler) addFileInfoEntryAndCreateDownloadJob(object *gcs.MinObj
 	if fileInfo == nil {
 		addEntryToCache = true
 	} else {
+		fileInfoData := fileInfo.(data.FileInfo)
+		existingJob := chr.jobManager.GetJob(object.Name, bucket.Name())
+		objectPath := util.GetObjectPath(bucket.Name(), object.Name)
+		filePath := util.GetDownloadPath(chr.cacheDir, objectPath)
+
+		// The process an adopted file was downloaded by may have since evicted it,
+		// or be downloading another generation in its place.
+		adoptedFileGone := fileInfoData.Adopted &&
+			!downloader.DownloadedFileIsCurrent(util.GetDownloadLockPath(chr.cacheDir, objectPath), filePath, fileInfoData.ObjectGeneration, fileInfoData.FileSize)
+
 		// Throw an error, if there is an entry in the file-info cache and cache file doesn't
-		// exist locally.
-		filePath := util.GetDownloadPath(chr.cacheDir, util.GetObjectPath(bucket.Name(), object.Name))
-		_, err := os.Stat(filePath)
-		if err != nil && os.IsNotExist(err) {
-			return fmt.Errorf("addFileInfoEntryAndCreateDownloadJob: %w: %s", util.ErrFileNotPresentInCache, filePath)
+		// exist locally. When the cache directory is shared, the file is only
+		// created once the download job holds the download lock.
+		if !adoptedFileGone && (chr.sharedUsage == nil || existingJob == nil) {
+			_, err := os.Stat(filePath)
+			if err != nil && os.IsNotExist(err) {
+				return fmt.Errorf("addFileInfoEntryAndCreateDownloadJob: %w: %s", util.ErrFileNotPresentInCache, filePath)
+			}
 		}
 
 		// Evict object in cache if the generation of object in cache is different
@@ -165,11 +195,9 @@ func (chr *CacheHandler) addFileInfoEntryAndCreateDownloadJob(object *gcs.MinObj
 		// decide to evict or not because generations are not always increasing:
 		// https://cloud.google.com/storage/docs/metadata#generation-number)
 		// Also, invalidate the cache if download job has failed or not invalid.
-		fileInfoData := fileInfo.(data.FileInfo)
 		// If offset in file info cache is less than object size and there is no
 		// reference to download job then it means the job has failed.
-		existingJob := chr.jobManager.GetJob(object.Name, bucket.Name())
-		shouldInvalidate := (existingJob == nil) && (fileInfoData.Offset < fileInfoData.FileSize)
+		shouldInvalidate := adoptedFileGone || ((existingJob == nil) && (fileInfoData.Offset < fileInfoData.FileSize))
 		if (!shouldInvalidate) && (existingJob != nil) {
 			existingJobStatus := existingJob.GetStatus().Name
 			shouldInvalidate = (existingJobStatus == downloader.Failed) || (existingJobStatus == downloader.Invalid)
@@ -208,6 +236,12 @@ func (chr *CacheHandler) addFileInfoEntryAndCreateDownloadJob(object *gcs.MinObj
 				return fmt.Errorf("addFileInfoEntryAndCreateDownloadJob: while performing post eviction of %s object error: %w", fileInfo.Key.ObjectName, err)
 			}
 		}
+		if chr.sharedUsage != nil {
+			err = chr.evictForSharedUsage(fileInfoKeyName)
+			if err != nil {
+				return fmt.Errorf("addFileInfoEntryAndCreateDownloadJob: %w", err)
+			}
+		}
 	} else {
 		// Move this entry on top of LRU.
 		_ = chr.fileInfoCache.LookUp(fileInfoKeyName)
@@ -260,12 +294,64 @@ func (chr *CacheHandler) GetCacheHandle(object *gcs.MinObject, bucket gcs.Bucket
 		return nil, fmt.Errorf("GetCacheHandle: while adding the entry in the cache: %w", err)
 	}
 
+	job := chr.jobManager.GetJob(object.Name, bucket.Name())
+	if chr.sharedUsage != nil {
+		// The download job may replace the file in cache rather than overwrite
+		// it, so it is only opened once read.
+		ch := NewCacheHandle(nil, job, chr.fileInfoCache, cacheForRangeRead, initialOffset)
+		ch.localFilePath = util.GetDownloadPath(chr.cacheDir, util.GetObjectPath(bucket.Name(), object.Name))
+		return ch, nil
+	}
+
 	localFileReadHandle, err := chr.createLocalFileReadHandle(object.Name, bucket.Name())
 	if err != nil {
 		return nil, fmt.Errorf("GetCacheHandle: while creating local-file read handle: %w", err)
 	}
 
-	return NewCacheHandle(localFileReadHandle, chr.jobManager.GetJob(object.Name, bucket.Name()), chr.fileInfoCache, cacheForRangeRead, initialOffset), nil
+	return NewCacheHandle(localFileReadHandle, job, chr.fileInfoCache, cacheForRangeRead, initialOffset), nil
+}
+
+// Prefetch downloads the supplied object into the cache ahead of any read, as
+// GetCacheHandle would for a sequential read from offset 0, and waits until it
+// is fully downloaded or ctx is done. It returns
+// util.ErrFileExcludedFromCacheByRegex if the object is excluded from cache,
+// and nothing if it is already in cache.
+//
+// Acquires and releases LOCK(CacheHandler.mu)
+func (chr *CacheHandler) Prefetch(ctx context.Context, object *gcs.MinObject, bucket gcs.Bucket) error {
+	chr.mu.Lock()
+	if chr.shouldExcludeFromCache(bucket, object) {
+		chr.mu.Unlock()
+		return util.ErrFileExcludedFromCacheByRegex
+	}
+
+	err := chr.addFileInfoEntryAndCreateDownloadJob(object, bucket)
+	if err == nil {
+		// Readers expect the file in cache to exist along with its entry, while
+		// the job creates it only once it starts.
+		var f *os.File
+		if f, err = chr.createLocalFileReadHandle(object.Name, bucket.Name()); err == nil {
+			_ = f.Close()
+		}
+	}
+	// The job is gone if the object was already downloaded.
+	job := chr.jobManager.GetJob(object.Name, bucket.Name())
+	chr.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("Prefetch: while adding the entry in the cache: %w", err)
+	}
+	if job == nil {
+		return nil
+	}
+
+	status, err := job.Download(ctx, int64(object.Size), true)
+	if err != nil {
+		return fmt.Errorf("Prefetch: %w", err)
+	}
+	if status.Name == downloader.Failed || status.Name == downloader.Invalid {
+		return fmt.Errorf("Prefetch: download job is %s: %v", status.Name, status.Err)
+	}
+	return nil
 }
 
 // InvalidateCache removes the file entry from the fileInfoCache and performs clean
@@ -296,9 +382,57 @@ func (chr *CacheHandler) InvalidateCache(objectName string, bucketName string) e
 	return nil
 }
 
+// InvalidateCacheWithGivenPrefix removes the file entries of the given bucket
+// whose object names start with the given prefix from the fileInfoCache, and
+// performs clean up for the removed entries. An empty bucket name matches the
+// entries of all buckets.
+//
+// Acquires and releases LOCK(CacheHandler.mu)
+func (chr *CacheHandler) InvalidateCacheWithGivenPrefix(objectPrefix string, bucketName string) error {
+	chr.mu.Lock()
+	defer chr.mu.Unlock()
+
+	var keys []string
+	chr.fileInfoCache.VisitEntries(func(key string, val lru.ValueType) {
+		fileInfo := val.(data.FileInfo)
+		if (bucketName == "" || fileInfo.Key.BucketName == bucketName) && strings.HasPrefix(fileInfo.Key.ObjectName, objectPrefix) {
+			keys = append(keys, key)
+		}
+	})
+
+	var errs []error
+	for _, key := range keys {
+		erasedVal := chr.fileInfoCache.Erase(key)
+		if erasedVal == nil {
+			continue
+		}
+		fileInfo := erasedVal.(data.FileInfo)
+		if err := chr.cleanUpEvictedFile(&fileInfo); err != nil {
+			errs = append(errs, fmt.Errorf("InvalidateCacheWithGivenPrefix: while performing clean-up for evicted %s object, error: %w", fileInfo.Key.ObjectName, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Entries returns the entries of the fileInfoCache, least recently used first.
+func (chr *CacheHandler) Entries() []data.FileInfo {
+	var infos []data.FileInfo
+	chr.fileInfoCache.VisitEntries(func(_ string, val lru.ValueType) {
+		infos = append(infos, val.(data.FileInfo))
+	})
+	return infos
+}
+
+// Jobs returns a summary of the download jobs in progress.
+func (chr *CacheHandler) Jobs() []downloader.JobSummary {
+	return chr.jobManager.Jobs()
+}
+
 // Destroy destroys the job manager (i.e. invalidate all the jobs).
 // Note: This method is expected to be called at the time of unmounting and
-// because file info cache is in-memory, it is not required to destroy it.
+// because file info cache is in-memory, it is not required to destroy it. If
+// the cache is journaled, the journal is compacted down to the fully
+// downloaded files in cache.
 //
 // Acquires and releases Lock(chr.mu)
 func (chr *CacheHandler) Destroy() (err error) {
@@ -306,6 +440,15 @@ func (chr *CacheHandler) Destroy() (err error) {
 	defer chr.mu.Unlock()
 
 	chr.jobManager.Destroy()
+	if chr.journal != nil {
+		err = chr.journal.Close(chr.downloadedFileInfos())
+		if err != nil {
+			err = fmt.Errorf("Destroy: while compacting the journal: %w", err)
+		}
+	}
+	if chr.sharedUsage != nil {
+		_ = chr.sharedUsage.Close()
+	}
 	return
 }
 
@@ -331,3 +474,206 @@ func (chr *CacheHandler) shouldExcludeFromCache(bucket gcs.Bucket, object *gcs.M
 
 	return false
 }
+
+// The maximum number of objects stated in parallel while recovering the cache
+// from the journal.
+const journalRecoveryParallelism = 16
+
+// RecoverFromJournal re-adopts the files in cache recorded in the journal of
+// the supplied bucket, instead of leaving them to be downloaded again. A file
+// is re-adopted only if it is fully downloaded and its object hasn't changed
+// since; the others are removed from cache. Further changes to the cache are
+// then recorded in the journal. It must be called before the handler is used.
+//
+// Acquires and releases Lock(chr.mu)
+func (chr *CacheHandler) RecoverFromJournal(ctx context.Context, bucket gcs.Bucket) error {
+	journalPath := util.GetJournalPath(chr.cacheDir, bucket.Name())
+	if err := util.CreateCacheDirectoryIfNotPresentAt(path.Dir(journalPath), chr.dirPerm); err != nil {
+		return fmt.Errorf("RecoverFromJournal: while creating journal directory: %w", err)
+	}
+	entries, err := ReadJournal(journalPath)
+	if err != nil {
+		return fmt.Errorf("RecoverFromJournal: while reading journal: %w", err)
+	}
+
+	recoverable := make([]bool, len(entries))
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(journalRecoveryParallelism)
+	for i := range entries {
+		group.Go(func() error {
+			recoverable[i] = chr.isRecoverable(groupCtx, bucket, &entries[i])
+			return nil
+		})
+	}
+	_ = group.Wait()
+
+	chr.mu.Lock()
+	defer chr.mu.Unlock()
+
+	// Entries are inserted least recently used first, so that the recency order
+	// survives.
+	for i := range entries {
+		info := entries[i]
+		if !recoverable[i] {
+			chr.removeLocalFile(&info)
+			continue
+		}
+		key, _ := info.Key.Key()
+		evictedValues, err := chr.fileInfoCache.Insert(key, info)
+		if err != nil {
+			// The file doesn't fit in the cache any more.
+			chr.removeLocalFile(&info)
+			continue
+		}
+		for _, val := range evictedValues {
+			evicted := val.(data.FileInfo)
+			chr.removeLocalFile(&evicted)
+		}
+	}
+
+	recovered := chr.downloadedFileInfos()
+	chr.journal, err = NewJournal(journalPath, chr.filePerm, recovered)
+	if err != nil {
+		return fmt.Errorf("RecoverFromJournal: while creating journal: %w", err)
+	}
+	chr.jobManager.SetJobDoneCallback(chr.journalDownloadedFile)
+
+	logger.Infof("Recovered %d of %d files in cache from the journal %s", len(recovered), len(entries), journalPath)
+	return nil
+}
+
+// isRecoverable returns true if the file in cache for the supplied journal
+// entry is fully downloaded and the object it was downloaded from is unchanged.
+func (chr *CacheHandler) isRecoverable(ctx context.Context, bucket gcs.Bucket, info *data.FileInfo) bool {
+	if info.Key.BucketName != bucket.Name() || info.Offset < info.FileSize {
+		return false
+	}
+
+	localFilePath := util.GetDownloadPath(chr.cacheDir, util.GetObjectPath(info.Key.BucketName, info.Key.ObjectName))
+	fi, err := os.Stat(localFilePath)
+	if err != nil || uint64(fi.Size()) != info.FileSize {
+		return false
+	}
+
+	m, _, err := bucket.StatObject(ctx, &gcs.StatObjectRequest{Name: info.Key.ObjectName})
+	if err != nil {
+		var notFoundErr *gcs.NotFoundError
+		if !errors.As(err, &notFoundErr) {
+			logger.Warnf("isRecoverable: while stating %q: %v", info.Key.ObjectName, err)
+		}
+		return false
+	}
+	return m.Generation == info.ObjectGeneration && m.Size == info.FileSize
+}
+
+// removeLocalFile removes the file in cache for the supplied entry, if any. It
+// is used for entries that aren't in the file info cache.
+func (chr *CacheHandler) removeLocalFile(info *data.FileInfo) {
+	if err := chr.removeCacheFile(info); err != nil && !os.IsNotExist(err) {
+		logger.Warnf("removeLocalFile: %v", err)
+	}
+}
+
+// removeCacheFile removes the file in cache for the supplied entry, unless it
+// was adopted from another process sharing the cache directory, which then
+// remains responsible for it.
+func (chr *CacheHandler) removeCacheFile(info *data.FileInfo) error {
+	if info.Adopted {
+		return nil
+	}
+	localFilePath := util.GetDownloadPath(chr.cacheDir, util.GetObjectPath(info.Key.BucketName, info.Key.ObjectName))
+	if chr.sharedUsage != nil {
+		// Other processes may have adopted the file and be reading it, so it
+		// mustn't be truncated.
+		return os.Remove(localFilePath)
+	}
+	return util.TruncateAndRemoveFile(localFilePath)
+}
+
+// journalDownloadedFile records the entry for the supplied object in the
+// journal, if the object has been fully downloaded. It is called by the job
+// manager whenever a download job is done.
+func (chr *CacheHandler) journalDownloadedFile(objectName string, bucketName string) {
+	fileInfoKey := data.FileInfoKey{
+		BucketName: bucketName,
+		ObjectName: objectName,
+	}
+	fileInfoKeyName, err := fileInfoKey.Key()
+	if err != nil {
+		return
+	}
+	val := chr.fileInfoCache.LookUpWithoutChangingOrder(fileInfoKeyName)
+	if val == nil {
+		return
+	}
+	fileInfo := val.(data.FileInfo)
+	if fileInfo.Offset >= fileInfo.FileSize {
+		chr.journal.Add(fileInfo)
+	}
+}
+
+// downloadedFileInfos returns the entries of the file info cache for fully
+// downloaded files, least recently used first.
+func (chr *CacheHandler) downloadedFileInfos() []data.FileInfo {
+	var infos []data.FileInfo
+	chr.fileInfoCache.VisitEntries(func(_ string, val lru.ValueType) {
+		fileInfo := val.(data.FileInfo)
+		if fileInfo.Offset >= fileInfo.FileSize {
+			infos = append(infos, fileInfo)
+		}
+	})
+	return infos
+}
+
+// ShareCacheDir lets the handler share its cache directory with other gcsfuse
+// processes, which download each object generation only once between them and
+// keep the total size of the files they have downloaded under maxSize bytes.
+// It must be called before the handler is used.
+func (chr *CacheHandler) ShareCacheDir(maxSize uint64) error {
+	sharedUsage, err := NewSharedUsage(path.Join(chr.cacheDir, util.SharedUsageDir), chr.filePerm, chr.dirPerm)
+	if err != nil {
+		return fmt.Errorf("ShareCacheDir: %w", err)
+	}
+	chr.sharedUsage = sharedUsage
+	chr.sharedMaxSize = maxSize
+	return nil
+}
+
+// evictForSharedUsage evicts the least recently used files downloaded by this
+// process, other than the one with the supplied key, until the files
+// downloaded by all the processes sharing the cache directory fit in the
+// shared limit. It then publishes the usage of this process.
+//
+// Requires Lock(chr.mu)
+func (chr *CacheHandler) evictForSharedUsage(keep string) error {
+	var owned uint64
+	var evictable []string
+	chr.fileInfoCache.VisitEntries(func(key string, val lru.ValueType) {
+		fileInfo := val.(data.FileInfo)
+		if fileInfo.Adopted {
+			return
+		}
+		owned += fileInfo.FileSize
+		if key != keep {
+			evictable = append(evictable, key)
+		}
+	})
+
+	others := chr.sharedUsage.Others()
+	for _, key := range evictable {
+		if owned+others <= chr.sharedMaxSize {
+			break
+		}
+		erasedVal := chr.fileInfoCache.Erase(key)
+		if erasedVal == nil {
+			continue
+		}
+		fileInfo := erasedVal.(data.FileInfo)
+		owned -= fileInfo.FileSize
+		if err := chr.cleanUpEvictedFile(&fileInfo); err != nil {
+			return fmt.Errorf("evictForSharedUsage: while performing post eviction of %s object error: %w", fileInfo.Key.ObjectName, err)
+		}
+	}
+
+	return chr.sharedUsage.Publish(owned)
+}
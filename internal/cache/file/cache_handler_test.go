@@ -751,6 +751,48 @@ func Test_GetCacheHandle_ConcurrentDifferentFiles(t *testing.T) {
 	assert.False(t, isEntryInFileInfoCache(t, chTestArgs.cache, chTestArgs.object.Name, chTestArgs.bucket.Name()))
 }
 
+func Test_Prefetch_DownloadsObject(t *testing.T) {
+	cacheDir := path.Join(os.Getenv("HOME"), "CacheHandlerTest/dir")
+	chTestArgs := initializeCacheHandlerTestArgs(t, &cfg.FileCacheConfig{EnableCrc: true}, cacheDir)
+	content := []byte("content of object_1")
+	minObject := createObject(t, chTestArgs.bucket, "object_1", content)
+
+	err := chTestArgs.cacheHandler.Prefetch(context.Background(), minObject, chTestArgs.bucket)
+
+	require.NoError(t, err)
+	downloadPath := util.GetDownloadPath(cacheDir, util.GetObjectPath(chTestArgs.bucket.Name(), minObject.Name))
+	cached, err := os.ReadFile(downloadPath)
+	require.NoError(t, err)
+	assert.Equal(t, content, cached)
+	// Reads are then served from the cache.
+	cacheHandle, err := chTestArgs.cacheHandler.GetCacheHandle(minObject, chTestArgs.bucket, false, 0)
+	require.NoError(t, err)
+	assert.Nil(t, cacheHandle.validateCacheHandle())
+	// Prefetching again is a no-op.
+	assert.NoError(t, chTestArgs.cacheHandler.Prefetch(context.Background(), minObject, chTestArgs.bucket))
+}
+
+func Test_Prefetch_ExcludedObject(t *testing.T) {
+	cacheDir := path.Join(os.Getenv("HOME"), "CacheHandlerTest/dir")
+	chTestArgs := initializeCacheHandlerTestArgs(t, &cfg.FileCacheConfig{EnableCrc: true, ExcludeRegex: ".*object_1"}, cacheDir)
+	minObject := createObject(t, chTestArgs.bucket, "object_1", []byte("content of object_1"))
+
+	err := chTestArgs.cacheHandler.Prefetch(context.Background(), minObject, chTestArgs.bucket)
+
+	assert.ErrorIs(t, err, util.ErrFileExcludedFromCacheByRegex)
+	assert.False(t, isEntryInFileInfoCache(t, chTestArgs.cache, minObject.Name, chTestArgs.bucket.Name()))
+}
+
+func Test_Prefetch_ObjectLargerThanCache(t *testing.T) {
+	cacheDir := path.Join(os.Getenv("HOME"), "CacheHandlerTest/dir")
+	chTestArgs := initializeCacheHandlerTestArgs(t, &cfg.FileCacheConfig{EnableCrc: true}, cacheDir)
+	minObject := createObject(t, chTestArgs.bucket, "object_1", make([]byte, HandlerCacheMaxSize+1))
+
+	err := chTestArgs.cacheHandler.Prefetch(context.Background(), minObject, chTestArgs.bucket)
+
+	assert.Error(t, err)
+}
+
 func Test_InvalidateCache_WhenAlreadyInCache(t *testing.T) {
 	cacheDir := path.Join(os.Getenv("HOME"), "CacheHandlerTest/dir")
 	chTestArgs := initializeCacheHandlerTestArgs(t, &cfg.FileCacheConfig{EnableCrc: true}, cacheDir)
@@ -1118,3 +1160,28 @@ func Test_Destroy(t *testing.T) {
 		})
 	}
 }
+
+func Test_InvalidateCacheWithGivenPrefix(t *testing.T) {
+	cacheDir := path.Join(os.Getenv("HOME"), "CacheHandlerTest/dir")
+	chTestArgs := initializeCacheHandlerTestArgs(t, &cfg.FileCacheConfig{EnableCrc: true}, cacheDir)
+	existingJob := getDownloadJobForTestObject(t, chTestArgs)
+	inDir := createObject(t, chTestArgs.bucket, "dir/a", []byte("a"))
+	addTestFileInfoEntryInCache(t, chTestArgs.cache, inDir, chTestArgs.bucket.Name())
+	otherBucket := createObject(t, chTestArgs.bucket, "dir/b", []byte("b"))
+	addTestFileInfoEntryInCache(t, chTestArgs.cache, otherBucket, "other_bucket")
+	require.Len(t, chTestArgs.cacheHandler.Entries(), 3)
+
+	err := chTestArgs.cacheHandler.InvalidateCacheWithGivenPrefix("dir/", chTestArgs.bucket.Name())
+
+	require.NoError(t, err)
+	assert.False(t, isEntryInFileInfoCache(t, chTestArgs.cache, inDir.Name, chTestArgs.bucket.Name()))
+	assert.True(t, isEntryInFileInfoCache(t, chTestArgs.cache, otherBucket.Name, "other_bucket"))
+	assert.True(t, isEntryInFileInfoCache(t, chTestArgs.cache, chTestArgs.object.Name, chTestArgs.bucket.Name()))
+	assert.Equal(t, downloader.NotStarted, existingJob.GetStatus().Name)
+	// An empty bucket name and prefix match all the entries.
+	err = chTestArgs.cacheHandler.InvalidateCacheWithGivenPrefix("", "")
+	require.NoError(t, err)
+	assert.Empty(t, chTestArgs.cacheHandler.Entries())
+	assert.Equal(t, downloader.Invalid, existingJob.GetStatus().Name)
+	assert.False(t, doesFileExist(t, chTestArgs.downloadPath))
+}
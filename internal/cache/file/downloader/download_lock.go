@@ -0,0 +1,127 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package downloader
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"syscall"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// How often a job waiting for another process to finish downloading an object
+// checks whether the download lock has been released.
+const downloadLockPollInterval = 50 * time.Millisecond
+
+// downloadLock is an exclusive lock on downloading an object into a cache
+// directory shared by several gcsfuse processes. Besides serializing their
+// downloads, the lock file records the generation and size of the object last
+// fully downloaded, so that the processes that wait for the lock can adopt the
+// file instead of downloading it again.
+//
+// The lock is an flock(2) lock, so it is released by the kernel if its holder
+// dies, and works across PID namespaces.
+type downloadLock struct {
+	f *os.File
+}
+
+// acquireDownloadLock blocks until it holds the download lock at the supplied
+// path, or the supplied context is cancelled.
+func acquireDownloadLock(ctx context.Context, lockPath string, filePerm os.FileMode, dirPerm os.FileMode) (*downloadLock, error) {
+	if err := os.MkdirAll(path.Dir(lockPath), dirPerm); err != nil {
+		return nil, fmt.Errorf("MkdirAll: %w", err)
+	}
+	f, err := os.OpenFile(lockPath, os.O_RDWR|os.O_CREATE, filePerm)
+	if err != nil {
+		return nil, fmt.Errorf("OpenFile: %w", err)
+	}
+
+	for {
+		err = syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+		if err == nil {
+			return &downloadLock{f: f}, nil
+		}
+		if !errors.Is(err, syscall.EWOULDBLOCK) && !errors.Is(err, syscall.EINTR) {
+			f.Close()
+			return nil, fmt.Errorf("Flock: %w", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			f.Close()
+			return nil, ctx.Err()
+		case <-time.After(downloadLockPollInterval):
+		}
+	}
+}
+
+// completed returns true if the object was last fully downloaded with the
+// supplied generation and size.
+func (l *downloadLock) completed(generation int64, size uint64) bool {
+	var g int64
+	var s uint64
+	if _, err := l.f.Seek(0, 0); err != nil {
+		return false
+	}
+	if _, err := fmt.Fscanf(l.f, "%d %d", &g, &s); err != nil {
+		return false
+	}
+	return g == generation && s == size
+}
+
+// markDownloading records that no download of the object is complete, before
+// the file in cache is overwritten.
+func (l *downloadLock) markDownloading() error {
+	return l.f.Truncate(0)
+}
+
+// markCompleted records that the object has been fully downloaded with the
+// supplied generation and size.
+func (l *downloadLock) markCompleted(generation int64, size uint64) error {
+	if err := l.f.Truncate(0); err != nil {
+		return err
+	}
+	_, err := l.f.WriteAt(fmt.Appendf(nil, "%d %d\n", generation, size), 0)
+	return err
+}
+
+// DownloadedFileIsCurrent returns true if the file in cache at filePath is
+// still the one fully downloaded with the supplied generation and size by the
+// process that last held the download lock at lockPath, which is false once
+// that process has evicted the file or started downloading into its place.
+func DownloadedFileIsCurrent(lockPath string, filePath string, generation int64, size uint64) bool {
+	f, err := os.Open(lockPath)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	l := downloadLock{f: f}
+	return l.completed(generation, size) && downloadedFileHasSize(filePath, size)
+}
+
+func downloadedFileHasSize(filePath string, size uint64) bool {
+	fi, err := os.Stat(filePath)
+	return err == nil && uint64(fi.Size()) == size
+}
+
+// release releases the lock.
+func (l *downloadLock) release() {
+	// Closing the file releases the flock.
+	l.f.Close()
+}
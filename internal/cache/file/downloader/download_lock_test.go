@@ -0,0 +1,192 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package downloader
+
+import (
+	"context"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/data"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/lru"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/util"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/fake"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"github.com/googlecloudplatform/gcsfuse/v3/metrics"
+	"github.com/jacobsa/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDownloadLockIsExclusive(t *testing.T) {
+	lockPath := path.Join(t.TempDir(), util.DownloadLockDir, "lock")
+	lock, err := acquireDownloadLock(context.Background(), lockPath, util.DefaultFilePerm, util.DefaultDirPerm)
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*downloadLockPollInterval)
+	defer cancel()
+
+	_, err = acquireDownloadLock(ctx, lockPath, util.DefaultFilePerm, util.DefaultDirPerm)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	lock.release()
+	lock, err = acquireDownloadLock(context.Background(), lockPath, util.DefaultFilePerm, util.DefaultDirPerm)
+	require.NoError(t, err)
+	lock.release()
+}
+
+func TestDownloadLockRecordsCompletedDownload(t *testing.T) {
+	lockPath := path.Join(t.TempDir(), "lock")
+	lock, err := acquireDownloadLock(context.Background(), lockPath, util.DefaultFilePerm, util.DefaultDirPerm)
+	require.NoError(t, err)
+	defer lock.release()
+	assert.False(t, lock.completed(1, 4))
+
+	require.NoError(t, lock.markCompleted(1, 4))
+
+	assert.True(t, lock.completed(1, 4))
+	assert.False(t, lock.completed(2, 4))
+	require.NoError(t, lock.markDownloading())
+	assert.False(t, lock.completed(1, 4))
+}
+
+func TestDownloadedFileIsCurrent(t *testing.T) {
+	dir := t.TempDir()
+	lockPath := path.Join(dir, "lock")
+	filePath := path.Join(dir, "file")
+	require.NoError(t, os.WriteFile(filePath, []byte("taco"), util.DefaultFilePerm))
+	lock, err := acquireDownloadLock(context.Background(), lockPath, util.DefaultFilePerm, util.DefaultDirPerm)
+	require.NoError(t, err)
+	defer lock.release()
+	require.NoError(t, lock.markCompleted(1, 4))
+
+	assert.True(t, DownloadedFileIsCurrent(lockPath, filePath, 1, 4))
+	assert.False(t, DownloadedFileIsCurrent(lockPath, filePath, 2, 4))
+	require.NoError(t, lock.markDownloading())
+	assert.False(t, DownloadedFileIsCurrent(lockPath, filePath, 1, 4))
+	require.NoError(t, lock.markCompleted(1, 4))
+	require.NoError(t, os.Remove(filePath))
+	assert.False(t, DownloadedFileIsCurrent(lockPath, filePath, 1, 4))
+}
+
+func TestSharedCacheJobAdoptsFileDownloadedByAnotherProcess(t *testing.T) {
+	ctx := context.Background()
+	cacheDir := t.TempDir()
+	bucket := fake.NewFakeBucket(timeutil.RealClock(), "some_bucket", gcs.BucketType{})
+	o, err := storageutil.CreateObject(ctx, bucket, "foo", []byte("taco"))
+	require.NoError(t, err)
+	object := storageutil.ConvertObjToMinObject(o)
+	objectPath := util.GetObjectPath(bucket.Name(), object.Name)
+	// Another process has downloaded the object.
+	downloadPath := util.GetDownloadPath(cacheDir, objectPath)
+	require.NoError(t, os.MkdirAll(path.Dir(downloadPath), util.DefaultDirPerm))
+	require.NoError(t, os.WriteFile(downloadPath, []byte("taco"), util.DefaultFilePerm))
+	lock, err := acquireDownloadLock(ctx, util.GetDownloadLockPath(cacheDir, objectPath), util.DefaultFilePerm, util.DefaultDirPerm)
+	require.NoError(t, err)
+	require.NoError(t, lock.markCompleted(object.Generation, object.Size))
+	lock.release()
+	cache := lru.NewCache(1024)
+	fileInfoKey := data.FileInfoKey{BucketName: bucket.Name(), ObjectName: object.Name}
+	fileInfoKeyName, err := fileInfoKey.Key()
+	require.NoError(t, err)
+	_, err = cache.Insert(fileInfoKeyName, data.FileInfo{Key: fileInfoKey, ObjectGeneration: object.Generation, FileSize: object.Size})
+	require.NoError(t, err)
+	jm := NewJobManager(cache, util.DefaultFilePerm, util.DefaultDirPerm, cacheDir, 200, &cfg.FileCacheConfig{EnableSharedCache: true}, metrics.NewNoopMetrics())
+	job := jm.CreateJobIfNotExists(object, bucket)
+
+	status, err := job.Download(ctx, int64(object.Size), true)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(object.Size), status.Offset)
+	assert.Eventually(t, func() bool { return job.GetStatus().Name == Completed }, time.Second, time.Millisecond)
+	fileInfo := cache.LookUpWithoutChangingOrder(fileInfoKeyName).(data.FileInfo)
+	assert.True(t, fileInfo.Adopted)
+	assert.Equal(t, object.Size, fileInfo.Offset)
+}
+
+func TestSharedCacheJobWaitsForAnotherProcess(t *testing.T) {
+	ctx := context.Background()
+	cacheDir := t.TempDir()
+	bucket := fake.NewFakeBucket(timeutil.RealClock(), "some_bucket", gcs.BucketType{})
+	o, err := storageutil.CreateObject(ctx, bucket, "foo", []byte("taco"))
+	require.NoError(t, err)
+	object := storageutil.ConvertObjToMinObject(o)
+	// Another process is downloading the object, and dies without completing.
+	lock, err := acquireDownloadLock(ctx, util.GetDownloadLockPath(cacheDir, util.GetObjectPath(bucket.Name(), object.Name)), util.DefaultFilePerm, util.DefaultDirPerm)
+	require.NoError(t, err)
+	cache := lru.NewCache(1024)
+	fileInfoKey := data.FileInfoKey{BucketName: bucket.Name(), ObjectName: object.Name}
+	fileInfoKeyName, err := fileInfoKey.Key()
+	require.NoError(t, err)
+	_, err = cache.Insert(fileInfoKeyName, data.FileInfo{Key: fileInfoKey, ObjectGeneration: object.Generation, FileSize: object.Size})
+	require.NoError(t, err)
+	jm := NewJobManager(cache, util.DefaultFilePerm, util.DefaultDirPerm, cacheDir, 200, &cfg.FileCacheConfig{EnableSharedCache: true}, metrics.NewNoopMetrics())
+	job := jm.CreateJobIfNotExists(object, bucket)
+
+	status, err := job.Download(ctx, 0, false)
+	require.NoError(t, err)
+	time.Sleep(3 * downloadLockPollInterval)
+	assert.Equal(t, int64(0), job.GetStatus().Offset)
+	lock.release()
+	status, err = job.Download(ctx, int64(object.Size), true)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(object.Size), status.Offset)
+	assert.Eventually(t, func() bool { return job.GetStatus().Name == Completed }, time.Second, time.Millisecond)
+	fileInfo := cache.LookUpWithoutChangingOrder(fileInfoKeyName).(data.FileInfo)
+	assert.False(t, fileInfo.Adopted)
+	contents, err := os.ReadFile(util.GetDownloadPath(cacheDir, util.GetObjectPath(bucket.Name(), object.Name)))
+	require.NoError(t, err)
+	assert.Equal(t, "taco", string(contents))
+}
+
+func TestSharedCacheJobReplacesFileInsteadOfOverwritingIt(t *testing.T) {
+	ctx := context.Background()
+	cacheDir := t.TempDir()
+	bucket := fake.NewFakeBucket(timeutil.RealClock(), "some_bucket", gcs.BucketType{})
+	o, err := storageutil.CreateObject(ctx, bucket, "foo", []byte("taco"))
+	require.NoError(t, err)
+	object := storageutil.ConvertObjToMinObject(o)
+	// Another process is reading the file it adopted for an earlier generation.
+	downloadPath := util.GetDownloadPath(cacheDir, util.GetObjectPath(bucket.Name(), object.Name))
+	require.NoError(t, os.MkdirAll(path.Dir(downloadPath), util.DefaultDirPerm))
+	require.NoError(t, os.WriteFile(downloadPath, []byte("burrito"), util.DefaultFilePerm))
+	adopted, err := os.Open(downloadPath)
+	require.NoError(t, err)
+	defer adopted.Close()
+	cache := lru.NewCache(1024)
+	fileInfoKey := data.FileInfoKey{BucketName: bucket.Name(), ObjectName: object.Name}
+	fileInfoKeyName, err := fileInfoKey.Key()
+	require.NoError(t, err)
+	_, err = cache.Insert(fileInfoKeyName, data.FileInfo{Key: fileInfoKey, ObjectGeneration: object.Generation, FileSize: object.Size})
+	require.NoError(t, err)
+	jm := NewJobManager(cache, util.DefaultFilePerm, util.DefaultDirPerm, cacheDir, 200, &cfg.FileCacheConfig{EnableSharedCache: true}, metrics.NewNoopMetrics())
+	job := jm.CreateJobIfNotExists(object, bucket)
+
+	_, err = job.Download(ctx, int64(object.Size), true)
+
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool { return job.GetStatus().Name == Completed }, time.Second, time.Millisecond)
+	contents, err := os.ReadFile(downloadPath)
+	require.NoError(t, err)
+	assert.Equal(t, "taco", string(contents))
+	buf := make([]byte, 7)
+	_, err = adopted.ReadAt(buf, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "burrito", string(buf))
+}
@@ -15,8 +15,10 @@
 package downloader
 
 import (
+	"cmp"
 	"math"
 	"os"
+	"slices"
 
 	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/data"
@@ -62,6 +64,10 @@ type JobManager struct {
 	mu                locker.Locker
 	maxParallelismSem *semaphore.Weighted
 	metricHandle      metrics.MetricHandle
+
+	// jobDoneCallback, if set, is called with the object and bucket name of each
+	// job once it is removed after completion/failure/invalidation.
+	jobDoneCallback func(objectName string, bucketName string)
 }
 
 func NewJobManager(fileInfoCache *lru.Cache, filePerm os.FileMode, dirPerm os.FileMode,
@@ -87,6 +93,13 @@ func NewJobManager(fileInfoCache *lru.Cache, filePerm os.FileMode, dirPerm os.Fi
 	return
 }
 
+// SetJobDoneCallback sets a function to be called with the object and bucket
+// name of each job once it is done. It must be called before any job is
+// created.
+func (jm *JobManager) SetJobDoneCallback(callback func(objectName string, bucketName string)) {
+	jm.jobDoneCallback = callback
+}
+
 // removeJob is a helper function to remove downloader.Job for given object and
 // bucket from jm.jobs if present. It is passed as callback function to job so
 // that job can remove itself after completion/failure/invalidation.
@@ -94,9 +107,13 @@ func NewJobManager(fileInfoCache *lru.Cache, filePerm os.FileMode, dirPerm os.Fi
 // Acquires and releases Lock(jm.mu)
 func (jm *JobManager) removeJob(objectName string, bucketName string) {
 	jm.mu.Lock()
-	defer jm.mu.Unlock()
 	objectPath := util.GetObjectPath(bucketName, objectName)
 	delete(jm.jobs, objectPath)
+	jm.mu.Unlock()
+
+	if jm.jobDoneCallback != nil {
+		jm.jobDoneCallback(objectName, bucketName)
+	}
 }
 
 // CreateJobIfNotExists creates and returns downloader.Job for given object and bucket.
@@ -119,6 +136,9 @@ func (jm *JobManager) CreateJobIfNotExists(object *gcs.MinObject, bucket gcs.Buc
 		jm.removeJob(object.Name, bucket.Name())
 	}
 	job = NewJob(object, bucket, jm.fileInfoCache, jm.sequentialReadSizeMb, fileSpec, removeJobCallback, jm.fileCacheConfig, jm.maxParallelismSem, jm.metricHandle)
+	if jm.fileCacheConfig.EnableSharedCache {
+		job.downloadLockPath = util.GetDownloadLockPath(jm.cacheDir, objectPath)
+	}
 	jm.jobs[objectPath] = job
 	return job
 }
@@ -135,6 +155,53 @@ func (jm *JobManager) GetJob(objectName string, bucketName string) *Job {
 	return job
 }
 
+// JobSummary describes a job managed by JobManager.
+type JobSummary struct {
+	BucketName string
+	ObjectName string
+	Generation int64
+	Size       uint64
+	Status     jobStatusName
+	Offset     int64
+	Err        string `json:",omitempty"`
+}
+
+// Jobs returns a summary of the jobs that job manager is managing, sorted by
+// bucket and object name.
+//
+// Acquires and releases Lock(jm.mu)
+func (jm *JobManager) Jobs() []JobSummary {
+	jm.mu.Lock()
+	jobs := make([]*Job, 0, len(jm.jobs))
+	for _, job := range jm.jobs {
+		jobs = append(jobs, job)
+	}
+	jm.mu.Unlock()
+
+	// Get the statuses without holding Lock(jm.mu), as jobs acquire it to remove
+	// themselves while holding their own lock.
+	summaries := make([]JobSummary, 0, len(jobs))
+	for _, job := range jobs {
+		status := job.GetStatus()
+		summary := JobSummary{
+			BucketName: job.bucket.Name(),
+			ObjectName: job.object.Name,
+			Generation: job.object.Generation,
+			Size:       job.object.Size,
+			Status:     status.Name,
+			Offset:     status.Offset,
+		}
+		if status.Err != nil {
+			summary.Err = status.Err.Error()
+		}
+		summaries = append(summaries, summary)
+	}
+	slices.SortFunc(summaries, func(a, b JobSummary) int {
+		return cmp.Or(cmp.Compare(a.BucketName, b.BucketName), cmp.Compare(a.ObjectName, b.ObjectName))
+	})
+	return summaries
+}
+
 // InvalidateAndRemoveJob invalidates downloader.Job for given object and bucket.
 // If there is no existing job present then this method does nothing.
 // Note: Invalidating a job also removes job from jm.jobs map.
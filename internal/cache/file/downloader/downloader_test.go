@@ -331,3 +331,21 @@ func (dt *downloaderTest) Test_CreateJobIfNotExists_InvalidateAndRemoveJob_Concu
 	}
 	wg.Wait()
 }
+
+func (dt *downloaderTest) Test_Jobs() {
+	AssertEq(0, len(dt.jm.Jobs()))
+	dt.jm.mu.Lock()
+	dt.jm.jobs[util.GetObjectPath(dt.bucket.Name(), dt.object.Name)] = dt.job
+	dt.jm.mu.Unlock()
+
+	jobs := dt.jm.Jobs()
+
+	AssertEq(1, len(jobs))
+	ExpectEq(dt.bucket.Name(), jobs[0].BucketName)
+	ExpectEq(dt.object.Name, jobs[0].ObjectName)
+	ExpectEq(dt.object.Generation, jobs[0].Generation)
+	ExpectEq(dt.object.Size, jobs[0].Size)
+	ExpectEq(NotStarted, jobs[0].Status)
+	ExpectEq(0, jobs[0].Offset)
+	ExpectEq("", jobs[0].Err)
+}
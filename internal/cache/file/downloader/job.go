@@ -97,6 +97,17 @@ type Job struct {
 	rangeChan chan data.ObjectRange
 
 	metricsHandle metrics.MetricHandle
+
+	// downloadLockPath is the path to the download lock of the object if the
+	// cache directory is shared with other gcsfuse processes, and empty
+	// otherwise. See downloadLock.
+	downloadLockPath string
+
+	// adopted is true if the file in cache was downloaded by another gcsfuse
+	// process sharing the cache directory.
+	//
+	// GUARDED_BY(mu)
+	adopted bool
 }
 
 // JobStatus represents the status of job.
@@ -275,6 +286,7 @@ func (job *Job) updateStatusOffset(downloadedOffset int64) (err error) {
 	updatedFileInfo := data.FileInfo{
 		Key: fileInfoKey, ObjectGeneration: job.object.Generation,
 		FileSize: job.object.Size, Offset: uint64(downloadedOffset),
+		Adopted: job.adopted,
 	}
 
 	err = job.fileInfoCache.UpdateWithoutChangingOrder(fileInfoKeyName, updatedFileInfo)
@@ -412,6 +424,33 @@ func (job *Job) downloadObjectAsync() {
 	// Cleanup the async job in all cases - completion/failure/invalidation.
 	defer job.cleanUpDownloadAsyncJob()
 
+	// When the cache directory is shared with other processes, only the holder
+	// of the download lock may write the file in cache.
+	var lock *downloadLock
+	if job.downloadLockPath != "" {
+		var err error
+		lock, err = acquireDownloadLock(job.cancelCtx, job.downloadLockPath, job.fileSpec.FilePerm, job.fileSpec.DirPerm)
+		if err != nil {
+			job.handleError(fmt.Errorf("downloadObjectAsync: error in acquiring download lock: %w", err))
+			return
+		}
+		defer lock.release()
+
+		if job.adoptDownloadedFile(lock) {
+			return
+		}
+		if err = lock.markDownloading(); err != nil {
+			job.handleError(fmt.Errorf("downloadObjectAsync: error in updating download lock: %w", err))
+			return
+		}
+		// Other processes may have adopted the file in cache and be reading it,
+		// so it is replaced rather than overwritten.
+		if err = os.Remove(job.fileSpec.Path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			job.handleError(fmt.Errorf("downloadObjectAsync: error in removing cache file: %w", err))
+			return
+		}
+	}
+
 	cacheFile, err := job.createCacheFile()
 	if err != nil {
 		err = fmt.Errorf("downloadObjectAsync: error in creating cache file: %w", err)
@@ -463,9 +502,49 @@ func (job *Job) downloadObjectAsync() {
 		return
 	}
 
+	if lock != nil {
+		err = lock.markCompleted(job.object.Generation, job.object.Size)
+		if err != nil {
+			// The file is still good for this process; others just won't adopt it.
+			logger.Warnf("downloadObjectAsync: error in updating download lock: %v", err)
+			err = nil
+		}
+	}
+
 	job.updateStatusAndNotifySubscribers(Completed, err)
 }
 
+// adoptDownloadedFile completes the job without downloading anything if
+// another process sharing the cache directory has already fully downloaded the
+// object into the file in cache. It returns true if the job is done.
+//
+// Acquires and releases LOCK(job.mu)
+func (job *Job) adoptDownloadedFile(lock *downloadLock) bool {
+	if !lock.completed(job.object.Generation, job.object.Size) {
+		return false
+	}
+	if !downloadedFileHasSize(job.fileSpec.Path, job.object.Size) {
+		return false
+	}
+
+	job.mu.Lock()
+	job.adopted = true
+	err := job.updateStatusOffset(int64(job.object.Size))
+	job.mu.Unlock()
+	if err != nil {
+		if errors.Is(err, lru.ErrEntryNotExist) {
+			job.updateStatusAndNotifySubscribers(Invalid, err)
+		} else {
+			job.handleError(err)
+		}
+		return true
+	}
+
+	logger.Tracef("Job:%p (%s:/%s) adopted the file downloaded by another process.", job, job.bucket.Name(), job.object.Name)
+	job.updateStatusAndNotifySubscribers(Completed, nil)
+	return true
+}
+
 // Download downloads object till the given offset and returns the status of
 // job. If the object is already downloaded or there was failure in download,
 // then it returns the job status. The caller shouldn't read data from file in
@@ -529,6 +608,11 @@ func (job *Job) validateCRC() (err error) {
 	if !job.fileCacheConfig.EnableCrc || job.bucket.BucketType().Zonal {
 		return
 	}
+	// There is nothing to compare with for objects without a checksum, like
+	// encrypted ones.
+	if job.object.CRC32C == nil {
+		return
+	}
 
 	crc32Val, err := cacheutil.CalculateFileCRC32(job.cancelCtx, job.fileSpec.Path)
 	if err != nil {
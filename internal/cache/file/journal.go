@@ -0,0 +1,263 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package file
+
+import (
+	"bufio"
+	"container/list"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/data"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/logger"
+)
+
+// A record in the journal. Each record either adds an entry for a fully
+// downloaded file, or removes the entry for the file with the given key.
+type journalRecord struct {
+	Removed bool `json:",omitempty"`
+	Info    data.FileInfo
+}
+
+// The journal is compacted once it holds more records than
+// journalCompactionFactor per entry in it plus minJournalRecords, since files
+// that are evicted and downloaded again leave records behind.
+const (
+	journalCompactionFactor = 4
+	minJournalRecords       = 1000
+)
+
+// Journal is an append-only log of the entries of the file info cache, from
+// which the cache can be rebuilt after a restart instead of downloading the
+// files in it again.
+//
+// Journal is safe for concurrent use.
+type Journal struct {
+	path string
+	perm os.FileMode
+
+	mu sync.Mutex
+
+	// The entries left behind by the records in the journal, least recently
+	// added first, and their elements by key.
+	//
+	// GUARDED_BY(mu)
+	entries *list.List
+	// GUARDED_BY(mu)
+	elements map[string]*list.Element
+	// The number of records in the journal.
+	//
+	// GUARDED_BY(mu)
+	records int
+	// GUARDED_BY(mu)
+	f *os.File
+	// GUARDED_BY(mu)
+	w *bufio.Writer
+}
+
+// ReadJournal replays the journal at the supplied path and returns the file
+// info entries it leaves behind, least recently added first. A missing journal
+// is treated as an empty one. Replay stops at the first corrupt record, which
+// is expected if gcsfuse crashed while appending it.
+func ReadJournal(path string) ([]data.FileInfo, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Open: %w", err)
+	}
+	defer f.Close()
+
+	// The position of each key in entries.
+	positions := make(map[string]int)
+	var entries []*data.FileInfo
+	d := json.NewDecoder(bufio.NewReader(f))
+	for {
+		var r journalRecord
+		err := d.Decode(&r)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			logger.Warnf("ReadJournal: ignoring the rest of %s: %v", path, err)
+			break
+		}
+
+		key, err := r.Info.Key.Key()
+		if err != nil {
+			continue
+		}
+		if i, ok := positions[key]; ok {
+			entries[i] = nil
+			delete(positions, key)
+		}
+		if !r.Removed {
+			positions[key] = len(entries)
+			info := r.Info
+			entries = append(entries, &info)
+		}
+	}
+
+	var infos []data.FileInfo
+	for _, e := range entries {
+		if e != nil {
+			infos = append(infos, *e)
+		}
+	}
+	return infos, nil
+}
+
+// NewJournal replaces the journal at the supplied path with one containing
+// just the supplied entries, and opens it for appending.
+func NewJournal(path string, perm os.FileMode, entries []data.FileInfo) (*Journal, error) {
+	j := &Journal{path: path, perm: perm}
+	if err := j.rewrite(entries); err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+// LOCKS_REQUIRED(j.mu) if j is visible to others.
+func (j *Journal) rewrite(entries []data.FileInfo) (err error) {
+	tmpPath := j.path + ".tmp"
+	f, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, j.perm)
+	if err != nil {
+		return fmt.Errorf("OpenFile: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			f.Close()
+			os.Remove(tmpPath)
+		}
+	}()
+
+	w := bufio.NewWriter(f)
+	e := json.NewEncoder(w)
+	for _, info := range entries {
+		if err = e.Encode(journalRecord{Info: info}); err != nil {
+			return fmt.Errorf("Encode: %w", err)
+		}
+	}
+	if err = w.Flush(); err != nil {
+		return fmt.Errorf("Flush: %w", err)
+	}
+	if err = os.Rename(tmpPath, j.path); err != nil {
+		return fmt.Errorf("Rename: %w", err)
+	}
+
+	if j.f != nil {
+		j.f.Close()
+	}
+	j.f = f
+	j.w = w
+	j.entries = list.New()
+	j.elements = make(map[string]*list.Element)
+	j.records = 0
+	for _, info := range entries {
+		j.apply(journalRecord{Info: info})
+	}
+	return nil
+}
+
+// apply updates the entries left behind by the journal with the supplied
+// record, as ReadJournal does.
+//
+// LOCKS_REQUIRED(j.mu) if j is visible to others.
+func (j *Journal) apply(r journalRecord) {
+	j.records++
+	key, err := r.Info.Key.Key()
+	if err != nil {
+		return
+	}
+	if e, ok := j.elements[key]; ok {
+		j.entries.Remove(e)
+		delete(j.elements, key)
+	}
+	if !r.Removed {
+		j.elements[key] = j.entries.PushBack(r.Info)
+	}
+}
+
+// LOCKS_REQUIRED(j.mu)
+func (j *Journal) liveEntries() []data.FileInfo {
+	infos := make([]data.FileInfo, 0, j.entries.Len())
+	for e := j.entries.Front(); e != nil; e = e.Next() {
+		infos = append(infos, e.Value.(data.FileInfo))
+	}
+	return infos
+}
+
+// LOCKS_EXCLUDED(j.mu)
+func (j *Journal) append(r journalRecord) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.f == nil {
+		return
+	}
+
+	// Records are flushed one at a time, so that a crash loses at most the
+	// last one.
+	err := json.NewEncoder(j.w).Encode(r)
+	if err == nil {
+		err = j.w.Flush()
+	}
+	if err != nil {
+		logger.Warnf("Journal: failed to append to %s: %v", j.path, err)
+		return
+	}
+
+	j.apply(r)
+	if j.records > journalCompactionFactor*j.entries.Len()+minJournalRecords {
+		if err := j.rewrite(j.liveEntries()); err != nil {
+			logger.Warnf("Journal: failed to compact %s: %v", j.path, err)
+		}
+	}
+}
+
+// Add records an entry for a fully downloaded file.
+//
+// LOCKS_EXCLUDED(j.mu)
+func (j *Journal) Add(info data.FileInfo) {
+	j.append(journalRecord{Info: info})
+}
+
+// Remove records that the file with the supplied key is no longer in the
+// cache.
+//
+// LOCKS_EXCLUDED(j.mu)
+func (j *Journal) Remove(key data.FileInfoKey) {
+	j.append(journalRecord{Removed: true, Info: data.FileInfo{Key: key}})
+}
+
+// Close compacts the journal down to the supplied entries and closes it.
+// Further records are dropped.
+//
+// LOCKS_EXCLUDED(j.mu)
+func (j *Journal) Close(entries []data.FileInfo) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	err := j.rewrite(entries)
+	if j.f != nil {
+		j.f.Close()
+	}
+	j.f, j.w = nil, nil
+	return err
+}
@@ -0,0 +1,172 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package file
+
+import (
+	"context"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/data"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/file/downloader"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/lru"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/util"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/fake"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"github.com/jacobsa/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func journaledFileInfo(objectName string, generation int64, offset uint64, size uint64) data.FileInfo {
+	return data.FileInfo{
+		Key:              data.FileInfoKey{BucketName: "some_bucket", ObjectName: objectName},
+		ObjectGeneration: generation,
+		Offset:           offset,
+		FileSize:         size,
+	}
+}
+
+func TestReadJournalReplaysRecords(t *testing.T) {
+	journalPath := path.Join(t.TempDir(), "journal")
+	j, err := NewJournal(journalPath, util.DefaultFilePerm, []data.FileInfo{journaledFileInfo("a", 1, 4, 4)})
+	require.NoError(t, err)
+	j.Add(journaledFileInfo("b", 1, 4, 4))
+	j.Remove(journaledFileInfo("a", 1, 4, 4).Key)
+	j.Add(journaledFileInfo("c", 1, 4, 4))
+	j.Add(journaledFileInfo("b", 2, 4, 4))
+	// A record torn by a crash.
+	f, err := os.OpenFile(journalPath, os.O_WRONLY|os.O_APPEND, 0)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"Info":{"Key":`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	entries, err := ReadJournal(journalPath)
+
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "c", entries[0].Key.ObjectName)
+	assert.Equal(t, "b", entries[1].Key.ObjectName)
+	assert.Equal(t, int64(2), entries[1].ObjectGeneration)
+}
+
+func TestJournalCompactsWhenRecordsOutnumberEntries(t *testing.T) {
+	journalPath := path.Join(t.TempDir(), "journal")
+	j, err := NewJournal(journalPath, util.DefaultFilePerm, []data.FileInfo{journaledFileInfo("a", 1, 4, 4)})
+	require.NoError(t, err)
+
+	for i := 1; i <= minJournalRecords; i++ {
+		j.Add(journaledFileInfo("b", int64(i), 4, 4))
+		j.Remove(journaledFileInfo("b", int64(i), 4, 4).Key)
+	}
+	j.Add(journaledFileInfo("c", 1, 4, 4))
+
+	contents, err := os.ReadFile(journalPath)
+	require.NoError(t, err)
+	assert.Less(t, strings.Count(string(contents), "\n"), minJournalRecords+2*journalCompactionFactor+2)
+	entries, err := ReadJournal(journalPath)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "a", entries[0].Key.ObjectName)
+	assert.Equal(t, "c", entries[1].Key.ObjectName)
+}
+
+func TestReadJournalMissing(t *testing.T) {
+	entries, err := ReadJournal(path.Join(t.TempDir(), "journal"))
+
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestRecoverFromJournal(t *testing.T) {
+	ctx := context.Background()
+	cacheDir := t.TempDir()
+	bucket := fake.NewFakeBucket(timeutil.RealClock(), "some_bucket", gcs.BucketType{})
+	createCachedObject := func(name string) *gcs.Object {
+		o, err := storageutil.CreateObject(ctx, bucket, name, []byte("taco"))
+		require.NoError(t, err)
+		localPath := util.GetDownloadPath(cacheDir, util.GetObjectPath(bucket.Name(), name))
+		require.NoError(t, os.MkdirAll(path.Dir(localPath), util.DefaultDirPerm))
+		require.NoError(t, os.WriteFile(localPath, []byte("taco"), util.DefaultFilePerm))
+		return o
+	}
+	valid := createCachedObject("valid")
+	changed := createCachedObject("changed")
+	partial := createCachedObject("partial")
+	createCachedObject("deleted")
+	require.NoError(t, bucket.DeleteObject(ctx, &gcs.DeleteObjectRequest{Name: "deleted"}))
+	journalPath := util.GetJournalPath(cacheDir, bucket.Name())
+	require.NoError(t, os.MkdirAll(path.Dir(journalPath), util.DefaultDirPerm))
+	_, err := NewJournal(journalPath, util.DefaultFilePerm, []data.FileInfo{
+		journaledFileInfo("valid", valid.Generation, 4, 4),
+		journaledFileInfo("changed", changed.Generation+1, 4, 4),
+		journaledFileInfo("partial", partial.Generation, 2, 4),
+		journaledFileInfo("deleted", 1, 4, 4),
+	})
+	require.NoError(t, err)
+	fileInfoCache := lru.NewCache(1024)
+	fileCacheConfig := &cfg.FileCacheConfig{}
+	jobManager := downloader.NewJobManager(fileInfoCache, util.DefaultFilePerm, util.DefaultDirPerm, cacheDir, 200, fileCacheConfig, nil)
+	chr := NewCacheHandler(fileInfoCache, jobManager, cacheDir, util.DefaultFilePerm, util.DefaultDirPerm, "", "")
+
+	err = chr.RecoverFromJournal(ctx, bucket)
+
+	require.NoError(t, err)
+	for _, name := range []string{"changed", "partial", "deleted"} {
+		_, err := os.Stat(util.GetDownloadPath(cacheDir, util.GetObjectPath(bucket.Name(), name)))
+		assert.ErrorIs(t, err, os.ErrNotExist, name)
+	}
+	key, err := data.FileInfoKey{BucketName: bucket.Name(), ObjectName: "valid"}.Key()
+	require.NoError(t, err)
+	val := fileInfoCache.LookUpWithoutChangingOrder(key)
+	require.NotNil(t, val)
+	assert.Equal(t, valid.Generation, val.(data.FileInfo).ObjectGeneration)
+	// Once adopted, the file can be evicted like any other.
+	require.NoError(t, chr.InvalidateCache("valid", bucket.Name()))
+	entries, err := ReadJournal(journalPath)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestDestroyCompactsJournal(t *testing.T) {
+	ctx := context.Background()
+	cacheDir := t.TempDir()
+	bucket := fake.NewFakeBucket(timeutil.RealClock(), "some_bucket", gcs.BucketType{})
+	fileInfoCache := lru.NewCache(1024)
+	jobManager := downloader.NewJobManager(fileInfoCache, util.DefaultFilePerm, util.DefaultDirPerm, cacheDir, 200, &cfg.FileCacheConfig{}, nil)
+	chr := NewCacheHandler(fileInfoCache, jobManager, cacheDir, util.DefaultFilePerm, util.DefaultDirPerm, "", "")
+	require.NoError(t, chr.RecoverFromJournal(ctx, bucket))
+	for _, info := range []data.FileInfo{
+		journaledFileInfo("downloaded", 1, 4, 4),
+		journaledFileInfo("downloading", 1, 2, 4),
+	} {
+		key, err := info.Key.Key()
+		require.NoError(t, err)
+		_, err = fileInfoCache.Insert(key, info)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, chr.Destroy())
+
+	entries, err := ReadJournal(util.GetJournalPath(cacheDir, bucket.Name()))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "downloaded", entries[0].Key.ObjectName)
+}
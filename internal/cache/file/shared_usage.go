@@ -0,0 +1,145 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package file
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/logger"
+)
+
+// The width of the decimal byte count in a usage file. Counts are written in
+// place with a fixed width, so that readers never see a torn one.
+const usageWidth = 20
+
+// SharedUsage accounts for the space taken by the files in a cache directory
+// shared by several gcsfuse processes. Each process publishes the number of
+// bytes it has downloaded into the cache in a usage file of its own, which it
+// holds an flock(2) lock on for as long as it runs. The usage files of
+// processes that have died, and so no longer hold their lock, are ignored and
+// removed.
+type SharedUsage struct {
+	dir string
+
+	// The name and the open usage file of this process.
+	name string
+	f    *os.File
+}
+
+// NewSharedUsage creates a usage file for this process in the supplied
+// directory.
+func NewSharedUsage(dir string, filePerm os.FileMode, dirPerm os.FileMode) (u *SharedUsage, err error) {
+	if err = os.MkdirAll(dir, dirPerm); err != nil {
+		return nil, fmt.Errorf("MkdirAll: %w", err)
+	}
+
+	// The file is locked before it is given its final name, so that other
+	// processes never mistake it for the file of a dead process.
+	f, err := os.CreateTemp(dir, "new-")
+	if err != nil {
+		return nil, fmt.Errorf("CreateTemp: %w", err)
+	}
+	tmpName := f.Name()
+	defer func() {
+		if err != nil {
+			f.Close()
+			os.Remove(tmpName)
+		}
+	}()
+	if err = f.Chmod(filePerm); err != nil {
+		return nil, fmt.Errorf("Chmod: %w", err)
+	}
+	if err = syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		return nil, fmt.Errorf("Flock: %w", err)
+	}
+	u = &SharedUsage{dir: dir, name: "usage-" + strings.TrimPrefix(path.Base(tmpName), "new-"), f: f}
+	if err = u.Publish(0); err != nil {
+		return nil, err
+	}
+	if err = os.Rename(tmpName, path.Join(dir, u.name)); err != nil {
+		return nil, fmt.Errorf("Rename: %w", err)
+	}
+	return u, nil
+}
+
+// Publish sets the number of bytes accounted to this process.
+func (u *SharedUsage) Publish(bytes uint64) error {
+	if _, err := u.f.WriteAt([]byte(fmt.Sprintf("%0*d", usageWidth, bytes)), 0); err != nil {
+		return fmt.Errorf("WriteAt: %w", err)
+	}
+	return nil
+}
+
+// Others returns the number of bytes accounted to the other live processes
+// sharing the cache directory.
+func (u *SharedUsage) Others() uint64 {
+	entries, err := os.ReadDir(u.dir)
+	if err != nil {
+		logger.Warnf("SharedUsage: while listing %s: %v", u.dir, err)
+		return 0
+	}
+
+	var total uint64
+	for _, e := range entries {
+		if e.Name() == u.name || !strings.HasPrefix(e.Name(), "usage-") {
+			continue
+		}
+		bytes, err := readUsageOfLiveProcess(path.Join(u.dir, e.Name()))
+		if err != nil {
+			logger.Warnf("SharedUsage: while reading %s: %v", e.Name(), err)
+			continue
+		}
+		total += bytes
+	}
+	return total
+}
+
+// readUsageOfLiveProcess returns the count in the supplied usage file if the
+// process that owns it is still running, and removes it otherwise.
+func readUsageOfLiveProcess(p string) (uint64, error) {
+	f, err := os.Open(p)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	// If the lock can be taken, the owner is gone.
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_SH|syscall.LOCK_NB); err == nil {
+		os.Remove(p)
+		return 0, nil
+	}
+
+	buf := make([]byte, usageWidth)
+	n, err := f.ReadAt(buf, 0)
+	if n < usageWidth {
+		return 0, fmt.Errorf("ReadAt: %d bytes, %w", n, err)
+	}
+	return strconv.ParseUint(string(buf), 10, 64)
+}
+
+// Close removes the usage file of this process.
+func (u *SharedUsage) Close() error {
+	os.Remove(path.Join(u.dir, u.name))
+	return u.f.Close()
+}
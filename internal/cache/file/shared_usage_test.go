@@ -0,0 +1,151 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package file
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/data"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/file/downloader"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/lru"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/util"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/fake"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"github.com/googlecloudplatform/gcsfuse/v3/metrics"
+	"github.com/jacobsa/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSharedUsageCountsOtherLiveProcesses(t *testing.T) {
+	dir := t.TempDir()
+	u1, err := NewSharedUsage(dir, util.DefaultFilePerm, util.DefaultDirPerm)
+	require.NoError(t, err)
+	u2, err := NewSharedUsage(dir, util.DefaultFilePerm, util.DefaultDirPerm)
+	require.NoError(t, err)
+	// The usage file of a process that died without removing it.
+	require.NoError(t, os.WriteFile(path.Join(dir, "usage-dead"), []byte("00000000000000000100"), util.DefaultFilePerm))
+
+	require.NoError(t, u1.Publish(7))
+	require.NoError(t, u2.Publish(5))
+
+	assert.Equal(t, uint64(5), u1.Others())
+	assert.Equal(t, uint64(7), u2.Others())
+	_, err = os.Stat(path.Join(dir, "usage-dead"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	require.NoError(t, u2.Close())
+	assert.Equal(t, uint64(0), u1.Others())
+	require.NoError(t, u1.Close())
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestEvictForSharedUsage(t *testing.T) {
+	cacheDir := t.TempDir()
+	fileInfoCache := lru.NewCache(1024)
+	jobManager := downloader.NewJobManager(fileInfoCache, util.DefaultFilePerm, util.DefaultDirPerm, cacheDir, 200, &cfg.FileCacheConfig{EnableSharedCache: true}, nil)
+	chr := NewCacheHandler(fileInfoCache, jobManager, cacheDir, util.DefaultFilePerm, util.DefaultDirPerm, "", "")
+	require.NoError(t, chr.ShareCacheDir(10))
+	defer chr.Destroy()
+	other, err := NewSharedUsage(path.Join(cacheDir, util.SharedUsageDir), util.DefaultFilePerm, util.DefaultDirPerm)
+	require.NoError(t, err)
+	defer other.Close()
+	require.NoError(t, other.Publish(4))
+	insert := func(name string, adopted bool) string {
+		info := data.FileInfo{
+			Key:      data.FileInfoKey{BucketName: "some_bucket", ObjectName: name},
+			Offset:   4,
+			FileSize: 4,
+			Adopted:  adopted,
+		}
+		key, err := info.Key.Key()
+		require.NoError(t, err)
+		_, err = fileInfoCache.Insert(key, info)
+		require.NoError(t, err)
+		localPath := util.GetDownloadPath(cacheDir, util.GetObjectPath("some_bucket", name))
+		require.NoError(t, os.MkdirAll(path.Dir(localPath), util.DefaultDirPerm))
+		require.NoError(t, os.WriteFile(localPath, []byte("taco"), util.DefaultFilePerm))
+		return key
+	}
+	adoptedKey := insert("adopted", true)
+	oldKey := insert("old", false)
+	newKey := insert("new", false)
+
+	chr.mu.Lock()
+	err = chr.evictForSharedUsage(newKey)
+	chr.mu.Unlock()
+
+	require.NoError(t, err)
+	// 4 bytes of the other process and 4 of "new" fit in 10; "old" doesn't.
+	assert.Nil(t, fileInfoCache.LookUpWithoutChangingOrder(oldKey))
+	_, err = os.Stat(util.GetDownloadPath(cacheDir, util.GetObjectPath("some_bucket", "old")))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	assert.NotNil(t, fileInfoCache.LookUpWithoutChangingOrder(newKey))
+	// Adopted files are accounted to the process that downloaded them.
+	assert.NotNil(t, fileInfoCache.LookUpWithoutChangingOrder(adoptedKey))
+	assert.Equal(t, uint64(4), other.Others())
+}
+
+func TestSharedCacheDropsAdoptedFileEvictedByItsOwner(t *testing.T) {
+	ctx := context.Background()
+	cacheDir := t.TempDir()
+	bucket := fake.NewFakeBucket(timeutil.RealClock(), "some_bucket", gcs.BucketType{})
+	o, err := storageutil.CreateObject(ctx, bucket, "foo", []byte("taco"))
+	require.NoError(t, err)
+	object := storageutil.ConvertObjToMinObject(o)
+	fileInfoCache := lru.NewCache(1024)
+	jobManager := downloader.NewJobManager(fileInfoCache, util.DefaultFilePerm, util.DefaultDirPerm, cacheDir, 200, &cfg.FileCacheConfig{EnableSharedCache: true}, metrics.NewNoopMetrics())
+	chr := NewCacheHandler(fileInfoCache, jobManager, cacheDir, util.DefaultFilePerm, util.DefaultDirPerm, "", "")
+	require.NoError(t, chr.ShareCacheDir(1024))
+	defer chr.Destroy()
+	// The file was downloaded by another process, and adopted.
+	objectPath := util.GetObjectPath(bucket.Name(), object.Name)
+	localPath := util.GetDownloadPath(cacheDir, objectPath)
+	require.NoError(t, os.MkdirAll(path.Dir(localPath), util.DefaultDirPerm))
+	require.NoError(t, os.WriteFile(localPath, []byte("taco"), util.DefaultFilePerm))
+	lockPath := util.GetDownloadLockPath(cacheDir, objectPath)
+	require.NoError(t, os.MkdirAll(path.Dir(lockPath), util.DefaultDirPerm))
+	require.NoError(t, os.WriteFile(lockPath, fmt.Appendf(nil, "%d %d\n", object.Generation, object.Size), util.DefaultFilePerm))
+	info := data.FileInfo{
+		Key:              data.FileInfoKey{BucketName: bucket.Name(), ObjectName: object.Name},
+		ObjectGeneration: object.Generation,
+		Offset:           object.Size,
+		FileSize:         object.Size,
+		Adopted:          true,
+	}
+	key, err := info.Key.Key()
+	require.NoError(t, err)
+	_, err = fileInfoCache.Insert(key, info)
+	require.NoError(t, err)
+	adopted := func() bool {
+		ch, err := chr.GetCacheHandle(object, bucket, true, 0)
+		require.NoError(t, err)
+		require.NoError(t, ch.Close())
+		return fileInfoCache.LookUpWithoutChangingOrder(key).(data.FileInfo).Adopted
+	}
+	require.True(t, adopted())
+
+	// The other process evicts the file.
+	require.NoError(t, os.Remove(localPath))
+
+	assert.False(t, adopted())
+}
@@ -30,10 +30,12 @@ var (
 	ErrInvalidEntry           = errors.New("nil values are not supported")
 	ErrInvalidUpdateEntrySize = errors.New("size of entry to be updated is not same as existing size")
 	ErrEntryNotExist          = errors.New("entry with given key does not exist")
+	ErrNotAdmitted            = errors.New("entry not admitted by the cache's eviction policy")
 )
 
 // Cache is a LRU cache for any lru.ValueType indexed by string keys.
-// That means entry's value should be a lru.ValueType.
+// That means entry's value should be a lru.ValueType. The entries to evict can
+// be chosen by another eviction Policy instead.
 type Cache struct {
 	/////////////////////////
 	// Constant data
@@ -42,6 +44,9 @@ type Cache struct {
 	// INVARIANT: maxSize > 0
 	maxSize uint64
 
+	// Called with the number of entries evicted by each insertion, if not nil.
+	onEvict func(count int)
+
 	/////////////////////////
 	// Mutable state
 	/////////////////////////
@@ -61,6 +66,10 @@ type Cache struct {
 	// INVARIANT: Contains all and only the elements of entries
 	index map[string]*list.Element
 
+	// Chooses the entries to evict, or nil to evict the least recently used
+	// ones. It knows about all and only the keys in index.
+	policy Policy
+
 	// All public methods of this Cache uses this RW mutex based locker while
 	// accessing/updating Cache's data.
 	mu locker.RWLocker
@@ -78,9 +87,20 @@ type entry struct {
 // NewCache returns the reference of cache object by initialising the cache with
 // the supplied maxSize, which must be greater than zero.
 func NewCache(maxSize uint64) *Cache {
+	return NewCacheWithPolicy(maxSize, nil, nil)
+}
+
+// NewCacheWithPolicy is like NewCache, but evicts the entries chosen by the
+// supplied policy, if not nil, which must not be shared with other caches.
+// If onEvict is
+// not nil, it is called with the number of entries evicted by each insertion
+// that evicts any.
+func NewCacheWithPolicy(maxSize uint64, policy Policy, onEvict func(count int)) *Cache {
 	c := &Cache{
 		maxSize: maxSize,
+		onEvict: onEvict,
 		index:   make(map[string]*list.Element),
+		policy:  policy,
 	}
 
 	// Set up invariant checking.
@@ -125,8 +145,20 @@ func (c *Cache) checkInvariants() {
 	}
 }
 
-func (c *Cache) evictOne() ValueType {
-	e := c.entries.Back()
+// evictOne evicts the entry chosen by the policy, which is never the one with
+// the supplied key.
+func (c *Cache) evictOne(keep string) ValueType {
+	var e *list.Element
+	if c.policy == nil {
+		// The kept entry was just moved to the front, and fits on its own.
+		e = c.entries.Back()
+	} else {
+		key := c.policy.Victim(keep)
+		var ok bool
+		if e, ok = c.index[key]; !ok {
+			panic(fmt.Sprintf("Policy %s chose victim %q not in the cache", c.policy.Name(), key))
+		}
+	}
 	key := e.Value.(entry).Key
 
 	evictedEntry := e.Value.(entry).Value
@@ -145,6 +177,8 @@ func (c *Cache) evictOne() ValueType {
 // Insert the supplied value into the cache, overwriting any previous entry for
 // the given key. The value must be non-nil.
 // Also returns a slice of ValueType evicted by the new inserted entry.
+// Returns ErrNotAdmitted if there is no entry for the key, and the policy
+// doesn't deem the value worth evicting others for.
 func (c *Cache) Insert(
 	key string,
 	value ValueType) ([]ValueType, error) {
@@ -160,6 +194,9 @@ func (c *Cache) Insert(
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	if c.policy != nil {
+		c.policy.Access(key)
+	}
 	e, ok := c.index[key]
 	if ok {
 		// Update an entry if already exist.
@@ -168,16 +205,25 @@ func (c *Cache) Insert(
 		e.Value = entry{key, value}
 		c.entries.MoveToFront(e)
 	} else {
+		if c.policy != nil && c.currentSize+valueSize > c.maxSize && !c.policy.Admit(key) {
+			return nil, ErrNotAdmitted
+		}
 		// Add the entry if already doesn't exist.
 		e := c.entries.PushFront(entry{key, value})
 		c.index[key] = e
 		c.currentSize += valueSize
+		if c.policy != nil {
+			c.policy.Added(key)
+		}
 	}
 
 	var evictedValues []ValueType
 	// Evict until we're at or below maxSize.
 	for c.currentSize > c.maxSize {
-		evictedValues = append(evictedValues, c.evictOne())
+		evictedValues = append(evictedValues, c.evictOne(key))
+	}
+	if len(evictedValues) > 0 && c.onEvict != nil {
+		c.onEvict(len(evictedValues))
 	}
 
 	return evictedValues, nil
@@ -188,6 +234,11 @@ func (c *Cache) Erase(key string) (value ValueType) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	return c.erase(key)
+}
+
+// LOCKS_REQUIRED(c.mu)
+func (c *Cache) erase(key string) (value ValueType) {
 	e, ok := c.index[key]
 	if !ok {
 		return
@@ -198,6 +249,9 @@ func (c *Cache) Erase(key string) (value ValueType) {
 
 	delete(c.index, key)
 	c.entries.Remove(e)
+	if c.policy != nil {
+		c.policy.Removed(key)
+	}
 
 	return deletedEntry
 }
@@ -215,6 +269,9 @@ func (c *Cache) LookUp(key string) (value ValueType) {
 	}
 	// This is now the most recently used entry.
 	c.entries.MoveToFront(e)
+	if c.policy != nil {
+		c.policy.Access(key)
+	}
 
 	// Return the value.
 	return e.Value.(entry).Value
@@ -269,10 +326,27 @@ func (c *Cache) UpdateWithoutChangingOrder(
 	return nil
 }
 
+// VisitEntries calls visit for each entry in the cache, from the least to the
+// most recently used, without changing their order. visit must not call back
+// into the cache.
+func (c *Cache) VisitEntries(visit func(key string, value ValueType)) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for e := c.entries.Back(); e != nil; e = e.Prev() {
+		visit(e.Value.(entry).Key, e.Value.(entry).Value)
+	}
+}
+
+// EraseEntriesWithGivenPrefix erases the entries whose keys start with the
+// given prefix.
 func (c *Cache) EraseEntriesWithGivenPrefix(prefix string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	for key := range c.index {
 		if strings.HasPrefix(key, prefix) {
-			c.Erase(key)
+			c.erase(key)
 		}
 	}
 }
@@ -311,6 +311,30 @@ func (t *CacheTest) TestLookUpWithoutChangingOrder_NotChangeOrder() {
 	t.insertAndAssert(key3, data3, []int64{23}, nil)
 }
 
+func (t *CacheTest) TestVisitEntriesFromLeastRecentlyUsed() {
+	t.insertAndAssert("burrito1", testData{Value: 1, DataSize: 5}, []int64{}, nil)
+	t.insertAndAssert("burrito2", testData{Value: 2, DataSize: 5}, []int64{}, nil)
+	t.insertAndAssert("burrito3", testData{Value: 3, DataSize: 5}, []int64{}, nil)
+	t.cache.LookUp("burrito1")
+
+	var keys []string
+	var values []int64
+	t.cache.VisitEntries(func(key string, value lru.ValueType) {
+		keys = append(keys, key)
+		values = append(values, value.(testData).Value)
+	})
+
+	AssertEq(3, len(keys))
+	ExpectEq("burrito2", keys[0])
+	ExpectEq("burrito3", keys[1])
+	ExpectEq("burrito1", keys[2])
+	ExpectEq(2, values[0])
+	ExpectEq(3, values[1])
+	ExpectEq(1, values[2])
+	// Visiting doesn't change the order.
+	t.insertAndAssert("burrito4", testData{Value: 4, DataSize: 40}, []int64{2}, nil)
+}
+
 // This will detect race if we run the test with `-race` flag.
 // We get the race condition failure if we remove lock from Insert or Erase method.
 func (t *CacheTest) TestRaceCondition() {
@@ -0,0 +1,151 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lru
+
+import (
+	"container/list"
+	"fmt"
+)
+
+// Names of the supported eviction policies, as accepted by NewPolicy.
+const (
+	PolicyLRU     = "lru"
+	PolicyTinyLFU = "tinylfu"
+	Policy2Q      = "2q"
+)
+
+// Policy decides which entries a Cache evicts when it runs out of space, and
+// whether a new entry is worth evicting others for. The nil Policy stands for
+// LRU, which the Cache implements with its own list of entries.
+//
+// A Policy is only ever called with the lock of its Cache held, so
+// implementations need no synchronization of their own.
+type Policy interface {
+	// Name returns the name of the policy, as accepted by NewPolicy.
+	Name() string
+
+	// Access records a hit on the supplied key, or an insertion of it whether
+	// or not it is in the cache.
+	Access(key string)
+
+	// Added records that the supplied key has been added to the cache.
+	Added(key string)
+
+	// Removed records that the supplied key has been removed from the cache
+	// other than by being returned from Victim.
+	Removed(key string)
+
+	// Admit returns true if the supplied key, which is not in the cache, should
+	// be added to it even though that requires evicting other entries.
+	Admit(candidate string) bool
+
+	// Victim forgets and returns the key to be evicted next, which is never the
+	// supplied one. It returns "" if there is no such key.
+	Victim(keep string) string
+}
+
+// NewPolicy returns a new eviction policy with the supplied name. The empty
+// name selects LRU, for which it returns nil.
+func NewPolicy(name string) (Policy, error) {
+	switch name {
+	case "", PolicyLRU:
+		return nil, nil
+	case PolicyTinyLFU:
+		return NewTinyLFUPolicy(), nil
+	case Policy2Q:
+		return New2QPolicy(), nil
+	default:
+		return nil, fmt.Errorf("unsupported eviction policy %q", name)
+	}
+}
+
+// PolicyName returns the name of the supplied policy, as accepted by
+// NewPolicy.
+func PolicyName(p Policy) string {
+	if p == nil {
+		return PolicyLRU
+	}
+	return p.Name()
+}
+
+////////////////////////////////////////////////////////////////////////
+// keyList
+////////////////////////////////////////////////////////////////////////
+
+// keyList is a list of distinct keys indexed by key, with the most recently
+// pushed or moved key at the front.
+type keyList struct {
+	l     list.List
+	index map[string]*list.Element
+}
+
+func newKeyList() *keyList {
+	return &keyList{index: make(map[string]*list.Element)}
+}
+
+func (kl *keyList) len() int {
+	return kl.l.Len()
+}
+
+func (kl *keyList) contains(key string) bool {
+	_, ok := kl.index[key]
+	return ok
+}
+
+func (kl *keyList) pushFront(key string) {
+	if e, ok := kl.index[key]; ok {
+		kl.l.MoveToFront(e)
+		return
+	}
+	kl.index[key] = kl.l.PushFront(key)
+}
+
+// moveToFront moves the supplied key to the front if it is in the list.
+func (kl *keyList) moveToFront(key string) {
+	if e, ok := kl.index[key]; ok {
+		kl.l.MoveToFront(e)
+	}
+}
+
+func (kl *keyList) remove(key string) bool {
+	e, ok := kl.index[key]
+	if !ok {
+		return false
+	}
+	kl.l.Remove(e)
+	delete(kl.index, key)
+	return true
+}
+
+// back returns the key nearest to the back other than the supplied one, or ""
+// if there is none.
+func (kl *keyList) back(keep string) string {
+	for e := kl.l.Back(); e != nil; e = e.Prev() {
+		if key := e.Value.(string); key != keep {
+			return key
+		}
+	}
+	return ""
+}
+
+// popBack removes and returns the key nearest to the back other than the
+// supplied one, or "" if there is none.
+func (kl *keyList) popBack(keep string) string {
+	key := kl.back(keep)
+	if key != "" {
+		kl.remove(key)
+	}
+	return key
+}
@@ -0,0 +1,148 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lru_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/lru"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func hotKey(i int) string  { return fmt.Sprintf("hot%d", i) }
+func coldKey(i int) string { return fmt.Sprintf("cold%d", i) }
+func scanKey(i int) string { return fmt.Sprintf("scan%d", i) }
+
+// newPolicyCache returns a cache for 10 entries of size 1 with the supplied
+// policy, and a pointer to the number of entries it has evicted.
+func newPolicyCache(t *testing.T, name string) (*lru.Cache, *int) {
+	t.Helper()
+	policy, err := lru.NewPolicy(name)
+	require.NoError(t, err)
+	require.Equal(t, name, lru.PolicyName(policy))
+	evicted := new(int)
+	return lru.NewCacheWithPolicy(10, policy, func(count int) { *evicted += count }), evicted
+}
+
+func insertKeys(t *testing.T, c *lru.Cache, key func(int) string, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		_, err := c.Insert(key(i), testData{Value: int64(i), DataSize: 1})
+		if err != nil {
+			require.ErrorIs(t, err, lru.ErrNotAdmitted)
+		}
+	}
+}
+
+// scanAndCountHotKeys inserts a long run of keys used once, and returns how
+// many of the 5 hot keys survive it.
+func scanAndCountHotKeys(t *testing.T, c *lru.Cache) int {
+	t.Helper()
+	insertKeys(t, c, scanKey, 100)
+	var survivors int
+	for i := 0; i < 5; i++ {
+		if c.LookUpWithoutChangingOrder(hotKey(i)) != nil {
+			survivors++
+		}
+	}
+	return survivors
+}
+
+func TestNewPolicyRejectsUnknownName(t *testing.T) {
+	_, err := lru.NewPolicy("mru")
+
+	assert.ErrorContains(t, err, `unsupported eviction policy "mru"`)
+}
+
+func TestNewPolicyDefaultsToLRU(t *testing.T) {
+	policy, err := lru.NewPolicy("")
+
+	require.NoError(t, err)
+	// Implemented by the Cache itself.
+	assert.Nil(t, policy)
+	assert.Equal(t, lru.PolicyLRU, lru.PolicyName(policy))
+}
+
+func TestLRUPolicyLetsScanFlushHotEntries(t *testing.T) {
+	c, evicted := newPolicyCache(t, lru.PolicyLRU)
+	insertKeys(t, c, hotKey, 5)
+	for i := 0; i < 5; i++ {
+		c.LookUp(hotKey(i))
+	}
+
+	assert.Equal(t, 0, scanAndCountHotKeys(t, c))
+	assert.Equal(t, 95, *evicted)
+}
+
+func TestTinyLFUPolicyKeepsFrequentEntriesOnScan(t *testing.T) {
+	c, evicted := newPolicyCache(t, lru.PolicyTinyLFU)
+	insertKeys(t, c, hotKey, 5)
+	for n := 0; n < 3; n++ {
+		for i := 0; i < 5; i++ {
+			c.LookUp(hotKey(i))
+		}
+	}
+
+	assert.Equal(t, 5, scanAndCountHotKeys(t, c))
+	// The first scanned keys fill up the cache, and the others aren't admitted in
+	// place of them, as they have been accessed as often.
+	assert.Equal(t, 0, *evicted)
+}
+
+func TestTinyLFUPolicyAdmitsEntryAccessedMoreOften(t *testing.T) {
+	c, evicted := newPolicyCache(t, lru.PolicyTinyLFU)
+	insertKeys(t, c, coldKey, 10)
+	_, err := c.Insert(hotKey(0), testData{DataSize: 1})
+	require.ErrorIs(t, err, lru.ErrNotAdmitted)
+	// Misses don't count as accesses, but rejected insertions do.
+	require.Nil(t, c.LookUp(hotKey(0)))
+
+	evictedValues, err := c.Insert(hotKey(0), testData{DataSize: 1})
+
+	require.NoError(t, err)
+	require.Len(t, evictedValues, 1)
+	assert.Equal(t, int64(0), evictedValues[0].(testData).Value)
+	assert.Equal(t, 1, *evicted)
+	assert.NotNil(t, c.LookUpWithoutChangingOrder(hotKey(0)))
+}
+
+func Test2QPolicyKeepsReusedEntriesOnScan(t *testing.T) {
+	c, _ := newPolicyCache(t, lru.Policy2Q)
+	insertKeys(t, c, hotKey, 5)
+	// Push the hot keys out of the cache, and add them again while they are
+	// still remembered, which promotes them to the main queue.
+	insertKeys(t, c, coldKey, 10)
+	require.Nil(t, c.LookUpWithoutChangingOrder(hotKey(0)))
+	insertKeys(t, c, hotKey, 5)
+
+	assert.Equal(t, 5, scanAndCountHotKeys(t, c))
+}
+
+func TestPoliciesNeverChooseKeptKeyAsVictim(t *testing.T) {
+	for _, name := range []string{lru.PolicyTinyLFU, lru.Policy2Q} {
+		t.Run(name, func(t *testing.T) {
+			policy, err := lru.NewPolicy(name)
+			require.NoError(t, err)
+			policy.Added("a")
+
+			assert.Equal(t, "", policy.Victim("a"))
+			policy.Added("b")
+			assert.Equal(t, "a", policy.Victim("b"))
+			assert.Equal(t, "", policy.Victim("b"))
+		})
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lru
+
+import (
+	"hash/maphash"
+)
+
+const (
+	// The number of counters a key is hashed to in the sketch.
+	sketchDepth = 4
+
+	// Counters saturate at this value.
+	sketchMaxCount = 15
+
+	// The initial number of counters per row of the sketch. Rows grow with the
+	// number of resident keys.
+	sketchMinWidth = 1024
+
+	// The sketch is aged after this many increments per counter in a row.
+	sketchSamplesPerCounter = 10
+)
+
+// countMinSketch estimates how often keys have been accessed recently, in a
+// fixed amount of memory. Counts are halved periodically, so that keys that
+// were popular long ago don't stay in the cache forever.
+type countMinSketch struct {
+	seed     maphash.Seed
+	rows     [sketchDepth][]uint8
+	mask     uint64
+	samples  int
+	maxCount int
+}
+
+func newCountMinSketch(width int) *countMinSketch {
+	s := &countMinSketch{seed: maphash.MakeSeed()}
+	s.reset(width)
+	return s
+}
+
+// reset clears the sketch, and sizes its rows to the supplied width, which
+// must be a power of two.
+func (s *countMinSketch) reset(width int) {
+	for i := range s.rows {
+		s.rows[i] = make([]uint8, width)
+	}
+	s.mask = uint64(width - 1)
+	s.samples = 0
+	s.maxCount = width * sketchSamplesPerCounter
+}
+
+func (s *countMinSketch) width() int {
+	return len(s.rows[0])
+}
+
+// indexes returns the positions of the counters of the supplied key, one per
+// row, using double hashing.
+func (s *countMinSketch) indexes(key string) (idx [sketchDepth]uint64) {
+	h := maphash.String(s.seed, key)
+	h1, h2 := h, (h>>32)|1
+	for i := range idx {
+		idx[i] = (h1 + uint64(i)*h2) & s.mask
+	}
+	return
+}
+
+func (s *countMinSketch) increment(key string) {
+	for i, j := range s.indexes(key) {
+		if s.rows[i][j] < sketchMaxCount {
+			s.rows[i][j]++
+		}
+	}
+
+	s.samples++
+	if s.samples >= s.maxCount {
+		s.age()
+	}
+}
+
+func (s *countMinSketch) estimate(key string) uint8 {
+	count := uint8(sketchMaxCount)
+	for i, j := range s.indexes(key) {
+		if s.rows[i][j] < count {
+			count = s.rows[i][j]
+		}
+	}
+	return count
+}
+
+// age halves all the counters.
+func (s *countMinSketch) age() {
+	for i := range s.rows {
+		for j := range s.rows[i] {
+			s.rows[i][j] /= 2
+		}
+	}
+	s.samples /= 2
+}
+
+type tinyLFUPolicy struct {
+	// Estimated access frequency of both resident and non-resident keys.
+	sketch *countMinSketch
+
+	// Resident keys, with the least recently used at the back.
+	keys *keyList
+}
+
+// NewTinyLFUPolicy returns a policy that evicts the least recently used
+// entry, but only admits a new entry in place of it if the new entry has been
+// accessed more often recently. This keeps one-off accesses, such as those of
+// a scan, from flushing frequently used entries from the cache.
+func NewTinyLFUPolicy() Policy {
+	return &tinyLFUPolicy{
+		sketch: newCountMinSketch(sketchMinWidth),
+		keys:   newKeyList(),
+	}
+}
+
+func (p *tinyLFUPolicy) Name() string { return PolicyTinyLFU }
+
+func (p *tinyLFUPolicy) Access(key string) {
+	p.sketch.increment(key)
+	p.keys.moveToFront(key)
+}
+
+func (p *tinyLFUPolicy) Added(key string) {
+	p.keys.pushFront(key)
+
+	// Keep the sketch wide enough for the counters of the resident keys not to
+	// collide too often.
+	if width := p.sketch.width(); p.keys.len() > width {
+		p.sketch.reset(2 * width)
+	}
+}
+
+func (p *tinyLFUPolicy) Removed(key string) { p.keys.remove(key) }
+
+func (p *tinyLFUPolicy) Admit(candidate string) bool {
+	victim := p.keys.back("")
+	if victim == "" {
+		return true
+	}
+	return p.sketch.estimate(candidate) > p.sketch.estimate(victim)
+}
+
+func (p *tinyLFUPolicy) Victim(keep string) string { return p.keys.popBack(keep) }
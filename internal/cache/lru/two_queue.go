@@ -0,0 +1,85 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lru
+
+// The share of the resident keys that may be in the first-access queue, and
+// the number of evicted keys remembered relative to the resident ones, as
+// suggested in the 2Q paper.
+const (
+	twoQueueInPercent    = 25
+	twoQueueGhostPercent = 50
+)
+
+// twoQueuePolicy implements the full version of the 2Q algorithm by Johnson
+// and Shasha. New keys enter a FIFO queue, and are only promoted to the main
+// LRU queue if they are added again soon after having been evicted from it. A
+// scan thus only churns the FIFO queue, and leaves the main queue alone.
+type twoQueuePolicy struct {
+	// Resident keys accessed once, with the newest at the front (A1in).
+	in *keyList
+
+	// Non-resident keys recently evicted from in, with the most recently
+	// evicted at the front (A1out).
+	ghost *keyList
+
+	// Resident keys accessed again after their eviction from in, with the
+	// least recently used at the back (Am).
+	main *keyList
+}
+
+// New2QPolicy returns a policy implementing the 2Q algorithm.
+func New2QPolicy() Policy {
+	return &twoQueuePolicy{
+		in:    newKeyList(),
+		ghost: newKeyList(),
+		main:  newKeyList(),
+	}
+}
+
+func (p *twoQueuePolicy) Name() string { return Policy2Q }
+
+// Access only moves keys in the main queue, as repeated accesses of a key in
+// quick succession shouldn't count as reuse.
+func (p *twoQueuePolicy) Access(key string) { p.main.moveToFront(key) }
+
+func (p *twoQueuePolicy) Added(key string) {
+	if p.ghost.remove(key) {
+		p.main.pushFront(key)
+		return
+	}
+	p.in.pushFront(key)
+}
+
+func (p *twoQueuePolicy) Removed(key string) {
+	if !p.in.remove(key) {
+		p.main.remove(key)
+	}
+}
+
+func (p *twoQueuePolicy) Admit(string) bool { return true }
+
+func (p *twoQueuePolicy) Victim(keep string) string {
+	resident := p.in.len() + p.main.len()
+	if p.in.len()*100 > resident*twoQueueInPercent || p.main.back(keep) == "" {
+		if key := p.in.popBack(keep); key != "" {
+			p.ghost.pushFront(key)
+			for p.ghost.len()*100 > resident*twoQueueGhostPercent {
+				p.ghost.popBack("")
+			}
+			return key
+		}
+	}
+	return p.main.popBack(keep)
+}
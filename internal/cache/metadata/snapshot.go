@@ -0,0 +1,161 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/lru"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+)
+
+// The version of the snapshot format. Snapshots of any other version are
+// discarded.
+const snapshotVersion = 1
+
+// Snapshot is the on-disk form of the metadata caches of a mount. It is
+// written on unmount and reloaded on the next mount of the same bucket.
+type Snapshot struct {
+	Version    int
+	BucketName string
+
+	// Identifies the config under which the caches were populated. Snapshots
+	// taken under a different config are discarded.
+	ConfigHash string
+
+	// Least recently used first.
+	StatEntries []StatCacheSnapshotEntry
+
+	// The type cache entries of each directory, keyed by directory name.
+	TypeEntries map[string][]TypeCacheSnapshotEntry
+}
+
+// StatCacheSnapshotEntry is a stat cache entry in a snapshot. An entry with
+// neither an object nor a folder is a negative entry.
+type StatCacheSnapshotEntry struct {
+	Key        string
+	Object     *gcs.MinObject `json:",omitempty"`
+	Folder     *gcs.Folder    `json:",omitempty"`
+	Expiration time.Time
+}
+
+// TypeCacheSnapshotEntry is a type cache entry in a snapshot.
+type TypeCacheSnapshotEntry struct {
+	Name   string
+	Type   Type
+	Expiry time.Time
+}
+
+// SnapshotStatCache returns the entries of the supplied stat cache that
+// haven't expired at the supplied time, least recently used first.
+func SnapshotStatCache(sc *lru.Cache, now time.Time) []StatCacheSnapshotEntry {
+	var entries []StatCacheSnapshotEntry
+	sc.VisitEntries(func(key string, value lru.ValueType) {
+		e := value.(entry)
+		if e.expiration.Before(now) {
+			return
+		}
+		entries = append(entries, StatCacheSnapshotEntry{
+			Key:        key,
+			Object:     e.m,
+			Folder:     e.f,
+			Expiration: e.expiration,
+		})
+	})
+	return entries
+}
+
+// RestoreStatCache inserts the entries of a snapshot that haven't expired at
+// the supplied time into the supplied stat cache, keeping their expiration
+// times and recency order. Entries already in the cache are fresher, and are
+// kept.
+func RestoreStatCache(sc *lru.Cache, entries []StatCacheSnapshotEntry, now time.Time) {
+	for _, se := range entries {
+		if se.Expiration.Before(now) || sc.LookUpWithoutChangingOrder(se.Key) != nil {
+			continue
+		}
+		e := entry{
+			m:          se.Object,
+			f:          se.Folder,
+			expiration: se.Expiration,
+			key:        se.Key,
+		}
+		// Entries that don't fit in the cache any more are dropped.
+		_, _ = sc.Insert(se.Key, e)
+	}
+}
+
+// WriteSnapshot writes the supplied snapshot to a file at the supplied path,
+// replacing any existing one atomically.
+func WriteSnapshot(path string, s *Snapshot) (err error) {
+	s.Version = snapshotVersion
+
+	tmpPath := path + ".tmp"
+	f, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("OpenFile: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			os.Remove(tmpPath)
+		}
+	}()
+
+	w := bufio.NewWriter(f)
+	if err = json.NewEncoder(w).Encode(s); err != nil {
+		f.Close()
+		return fmt.Errorf("Encode: %w", err)
+	}
+	if err = w.Flush(); err != nil {
+		f.Close()
+		return fmt.Errorf("Flush: %w", err)
+	}
+	if err = f.Close(); err != nil {
+		return fmt.Errorf("Close: %w", err)
+	}
+	if err = os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("Rename: %w", err)
+	}
+	return nil
+}
+
+// ReadSnapshot reads the snapshot at the supplied path, failing if it was
+// taken for another bucket or config, or in another format.
+func ReadSnapshot(path string, bucketName string, configHash string) (*Snapshot, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var s Snapshot
+	if err := json.NewDecoder(bufio.NewReader(f)).Decode(&s); err != nil {
+		return nil, fmt.Errorf("Decode: %w", err)
+	}
+	switch {
+	case s.Version != snapshotVersion:
+		return nil, fmt.Errorf("unsupported snapshot version %d", s.Version)
+	case s.BucketName != bucketName:
+		return nil, fmt.Errorf("snapshot is for bucket %q", s.BucketName)
+	case s.ConfigHash != configHash:
+		return nil, errors.New("snapshot was taken with a different config")
+	}
+	return &s, nil
+}
@@ -0,0 +1,138 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata_test
+
+import (
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/lru"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/metadata"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var snapshotTime = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestStatCacheSnapshotRoundTrip(t *testing.T) {
+	src := lru.NewCache(1 << 20)
+	sc := metadata.NewStatCacheBucketView(src, "")
+	sc.Insert(&gcs.MinObject{Name: "foo", Generation: 2, MetaGeneration: 3}, snapshotTime.Add(time.Minute))
+	sc.AddNegativeEntry("bar", snapshotTime.Add(time.Minute))
+	sc.InsertFolder(&gcs.Folder{Name: "dir/"}, snapshotTime.Add(time.Minute))
+	sc.Insert(&gcs.MinObject{Name: "expired", Generation: 1}, snapshotTime.Add(-time.Second))
+
+	entries := metadata.SnapshotStatCache(src, snapshotTime)
+	dst := lru.NewCache(1 << 20)
+	metadata.RestoreStatCache(dst, entries, snapshotTime.Add(time.Second))
+
+	restored := metadata.NewStatCacheBucketView(dst, "")
+	hit, m := restored.LookUp("foo", snapshotTime.Add(time.Second))
+	require.True(t, hit)
+	assert.Equal(t, int64(2), m.Generation)
+	assert.Equal(t, int64(3), m.MetaGeneration)
+	hit, m = restored.LookUp("bar", snapshotTime.Add(time.Second))
+	assert.True(t, hit)
+	assert.Nil(t, m)
+	hit, f := restored.LookUpFolder("dir/", snapshotTime.Add(time.Second))
+	assert.True(t, hit)
+	assert.Equal(t, "dir/", f.Name)
+	hit, _ = restored.LookUp("expired", snapshotTime.Add(time.Second))
+	assert.False(t, hit)
+	// The entries keep their original expiration.
+	hit, _ = restored.LookUp("foo", snapshotTime.Add(time.Minute+time.Second))
+	assert.False(t, hit)
+}
+
+func TestRestoreStatCacheKeepsExistingEntries(t *testing.T) {
+	entries := []metadata.StatCacheSnapshotEntry{
+		{Key: "foo", Object: &gcs.MinObject{Name: "foo", Generation: 1}, Expiration: snapshotTime.Add(time.Minute)},
+	}
+	c := lru.NewCache(1 << 20)
+	sc := metadata.NewStatCacheBucketView(c, "")
+	sc.Insert(&gcs.MinObject{Name: "foo", Generation: 2}, snapshotTime.Add(time.Minute))
+
+	metadata.RestoreStatCache(c, entries, snapshotTime)
+
+	_, m := sc.LookUp("foo", snapshotTime)
+	assert.Equal(t, int64(2), m.Generation)
+}
+
+func TestTypeCacheSnapshotRoundTrip(t *testing.T) {
+	src := metadata.NewTypeCache(1, time.Minute)
+	src.Insert(snapshotTime, "file", metadata.RegularFileType)
+	src.Insert(snapshotTime.Add(-time.Hour), "expired", metadata.ExplicitDirType)
+
+	entries := src.Snapshot(snapshotTime)
+	dst := metadata.NewTypeCache(1, time.Minute)
+	dst.Restore(snapshotTime.Add(time.Second), entries)
+
+	assert.Equal(t, metadata.RegularFileType, dst.Get(snapshotTime.Add(time.Second), "file"))
+	assert.Equal(t, metadata.UnknownType, dst.Get(snapshotTime.Add(time.Second), "expired"))
+	assert.Equal(t, metadata.UnknownType, dst.Get(snapshotTime.Add(time.Minute+time.Second), "file"))
+}
+
+func TestTypeCacheRestoreCapsExpiryAtTTL(t *testing.T) {
+	entries := []metadata.TypeCacheSnapshotEntry{
+		{Name: "file", Type: metadata.RegularFileType, Expiry: snapshotTime.Add(time.Hour)},
+	}
+	tc := metadata.NewTypeCache(1, time.Minute)
+
+	tc.Restore(snapshotTime, entries)
+
+	assert.Equal(t, metadata.RegularFileType, tc.Get(snapshotTime.Add(time.Minute), "file"))
+	assert.Equal(t, metadata.UnknownType, tc.Get(snapshotTime.Add(time.Minute+time.Second), "file"))
+}
+
+func TestWriteAndReadSnapshot(t *testing.T) {
+	p := path.Join(t.TempDir(), "snapshot.json")
+	s := &metadata.Snapshot{
+		BucketName: "some_bucket",
+		ConfigHash: "abc",
+		StatEntries: []metadata.StatCacheSnapshotEntry{
+			{Key: "foo", Object: &gcs.MinObject{Name: "foo", Generation: 1, Metadata: map[string]string{"k": "v"}}, Expiration: snapshotTime},
+		},
+		TypeEntries: map[string][]metadata.TypeCacheSnapshotEntry{
+			"dir/": {{Name: "foo", Type: metadata.RegularFileType, Expiry: snapshotTime}},
+		},
+	}
+	require.NoError(t, metadata.WriteSnapshot(p, s))
+
+	read, err := metadata.ReadSnapshot(p, "some_bucket", "abc")
+
+	require.NoError(t, err)
+	assert.Equal(t, s.StatEntries[0].Key, read.StatEntries[0].Key)
+	assert.Equal(t, *s.StatEntries[0].Object, *read.StatEntries[0].Object)
+	assert.True(t, snapshotTime.Equal(read.StatEntries[0].Expiration))
+	assert.Equal(t, s.TypeEntries["dir/"][0].Name, read.TypeEntries["dir/"][0].Name)
+	assert.Equal(t, metadata.RegularFileType, read.TypeEntries["dir/"][0].Type)
+	_, err = os.Stat(p + ".tmp")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestReadSnapshotRejectsMismatch(t *testing.T) {
+	p := path.Join(t.TempDir(), "snapshot.json")
+	require.NoError(t, metadata.WriteSnapshot(p, &metadata.Snapshot{BucketName: "some_bucket", ConfigHash: "abc"}))
+
+	_, err := metadata.ReadSnapshot(p, "other_bucket", "abc")
+	assert.ErrorContains(t, err, "bucket")
+	_, err = metadata.ReadSnapshot(p, "some_bucket", "def")
+	assert.ErrorContains(t, err, "config")
+	_, err = metadata.ReadSnapshot(path.Join(t.TempDir(), "missing.json"), "some_bucket", "abc")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -15,6 +15,7 @@
 package metadata
 
 import (
+	"errors"
 	"math"
 	"time"
 
@@ -173,6 +174,14 @@ func (sc *statCacheBucketView) key(objectName string) string {
 	return objectName
 }
 
+// insert inserts the supplied entry into the shared cache. Entries rejected by
+// the eviction policy of the cache are dropped, as if evicted right away.
+func (sc *statCacheBucketView) insert(name string, e entry) {
+	if _, err := sc.sharedCache.Insert(name, e); err != nil && !errors.Is(err, lru.ErrNotAdmitted) {
+		panic(err)
+	}
+}
+
 func (sc *statCacheBucketView) Insert(m *gcs.MinObject, expiration time.Time) {
 	name := sc.key(m.Name)
 
@@ -190,9 +199,7 @@ func (sc *statCacheBucketView) Insert(m *gcs.MinObject, expiration time.Time) {
 		key:        name,
 	}
 
-	if _, err := sc.sharedCache.Insert(name, e); err != nil {
-		panic(err)
-	}
+	sc.insert(name, e)
 }
 
 func (sc *statCacheBucketView) AddNegativeEntry(objectName string, expiration time.Time) {
@@ -205,9 +212,7 @@ func (sc *statCacheBucketView) AddNegativeEntry(objectName string, expiration ti
 		key:        name,
 	}
 
-	if _, err := sc.sharedCache.Insert(name, e); err != nil {
-		panic(err)
-	}
+	sc.insert(name, e)
 }
 
 func (sc *statCacheBucketView) AddNegativeEntryForFolder(folderName string, expiration time.Time) {
@@ -220,9 +225,7 @@ func (sc *statCacheBucketView) AddNegativeEntryForFolder(folderName string, expi
 		key:        name,
 	}
 
-	if _, err := sc.sharedCache.Insert(name, e); err != nil {
-		panic(err)
-	}
+	sc.insert(name, e)
 }
 
 func (sc *statCacheBucketView) Erase(objectName string) {
@@ -281,9 +284,7 @@ func (sc *statCacheBucketView) InsertFolder(f *gcs.Folder, expiration time.Time)
 		key:        name,
 	}
 
-	if _, err := sc.sharedCache.Insert(name, e); err != nil {
-		panic(err)
-	}
+	sc.insert(name, e)
 }
 
 // Invalidate cache for all the entries with given prefix.
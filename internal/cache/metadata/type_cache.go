@@ -53,6 +53,9 @@ type TypeCache interface {
 	Insert(now time.Time, name string, it Type)
 	// Erase removes the entry with the given name.
 	Erase(name string)
+	// EraseEntriesWithGivenPrefix removes the entries whose names start with
+	// the given prefix.
+	EraseEntriesWithGivenPrefix(prefix string)
 	// Get returns the entry with given name, and also
 	// records this entry as latest accessed in the cache.
 	// If now > expiration, then entry is removed from cache, and
@@ -60,6 +63,13 @@ type TypeCache interface {
 	// If entry doesn't exist in the cache, then
 	// UnknownType is returned.
 	Get(now time.Time, name string) Type
+	// Snapshot returns the entries that haven't expired at now, least
+	// recently used first.
+	Snapshot(now time.Time) []TypeCacheSnapshotEntry
+	// Restore inserts the entries from a snapshot that haven't expired at now,
+	// keeping their expiry unless it is further away than the ttl. Entries
+	// already in the cache are kept.
+	Restore(now time.Time, entries []TypeCacheSnapshotEntry)
 }
 
 type cacheEntry struct {
@@ -167,6 +177,12 @@ func (tc *typeCache) Erase(name string) {
 	}
 }
 
+func (tc *typeCache) EraseEntriesWithGivenPrefix(prefix string) {
+	if tc.entries != nil { // only if caching is enabled
+		tc.entries.EraseEntriesWithGivenPrefix(prefix)
+	}
+}
+
 func (tc *typeCache) Get(now time.Time, name string) Type {
 	if tc.entries == nil { // if caching is not enabled
 		return UnknownType
@@ -185,3 +201,46 @@ func (tc *typeCache) Get(now time.Time, name string) Type {
 	}
 	return entry.inodeType
 }
+
+func (tc *typeCache) Snapshot(now time.Time) []TypeCacheSnapshotEntry {
+	if tc.entries == nil { // if caching is not enabled
+		return nil
+	}
+
+	var entries []TypeCacheSnapshotEntry
+	tc.entries.VisitEntries(func(key string, value lru.ValueType) {
+		entry := value.(cacheEntry)
+		if entry.expiry.Before(now) {
+			return
+		}
+		entries = append(entries, TypeCacheSnapshotEntry{
+			Name:   key,
+			Type:   entry.inodeType,
+			Expiry: entry.expiry,
+		})
+	})
+	return entries
+}
+
+func (tc *typeCache) Restore(now time.Time, entries []TypeCacheSnapshotEntry) {
+	if tc.entries == nil { // only if caching is enabled
+		return
+	}
+
+	maxExpiry := now.Add(tc.ttl)
+	for _, e := range entries {
+		if e.Expiry.Before(now) || tc.entries.LookUpWithoutChangingOrder(e.Name) != nil {
+			continue
+		}
+		expiry := e.Expiry
+		if expiry.After(maxExpiry) {
+			expiry = maxExpiry
+		}
+		// Entries that don't fit in the cache any more are dropped.
+		_, _ = tc.entries.Insert(e.Name, cacheEntry{
+			expiry:    expiry,
+			inodeType: e.Type,
+			key:       e.Name,
+		})
+	}
+}
@@ -185,6 +185,17 @@ func (t *TypeCacheTest) TestGetErasedEntry() {
 	ExpectEq(UnknownType, t.cache.Get(beforeExpiration, "abcd"))
 }
 
+func (t *TypeCacheTest) TestGetEntriesErasedWithGivenPrefix() {
+	t.cache.Insert(now, "abcd", RegularFileType)
+	t.cache.Insert(now, "abc", ExplicitDirType)
+	t.cache.Insert(now, "abd", RegularFileType)
+	t.cache.EraseEntriesWithGivenPrefix("abc")
+
+	ExpectEq(UnknownType, t.cache.Get(beforeExpiration, "abcd"))
+	ExpectEq(UnknownType, t.cache.Get(beforeExpiration, "abc"))
+	ExpectEq(RegularFileType, t.cache.Get(beforeExpiration, "abd"))
+}
+
 func (t *TypeCacheTest) TestGetReinsertedEntry() {
 	t.cache.Insert(now, "abcd", RegularFileType)
 	t.cache.Erase("abcd")
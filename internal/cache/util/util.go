@@ -16,6 +16,8 @@ package util
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"hash/crc32"
@@ -48,6 +50,11 @@ const (
 	DefaultFilePerm  = os.FileMode(0600)
 	DefaultDirPerm   = os.FileMode(0700)
 	FileCache        = "gcsfuse-file-cache"
+	MetadataCache    = "gcsfuse-metadata-cache"
+	UploadJournalDir = "gcsfuse-upload-journal"
+	JournalDir       = ".journals"
+	DownloadLockDir  = ".locks"
+	SharedUsageDir   = ".usage"
 	BufferSizeForCRC = 65536
 )
 
@@ -94,6 +101,22 @@ func GetDownloadPath(cacheDir string, objectPath string) string {
 	return path.Join(cacheDir, objectPath)
 }
 
+// GetJournalPath gives the path to the journal of the files in cache for the
+// given bucket. Bucket names can't start with a dot, so the journals can't
+// clash with the cache files.
+func GetJournalPath(cacheDir string, bucketName string) string {
+	return path.Join(cacheDir, JournalDir, bucketName)
+}
+
+// GetDownloadLockPath gives the path to the file that gcsfuse processes sharing
+// the cache lock while downloading the given object path. Object paths are
+// hashed so that the lock files of an object and of the objects "under" it
+// can't clash.
+func GetDownloadLockPath(cacheDir string, objectPath string) string {
+	sum := sha256.Sum256([]byte(objectPath))
+	return path.Join(cacheDir, DownloadLockDir, hex.EncodeToString(sum[:]))
+}
+
 // IsCacheHandleInvalid says either the current cacheHandle is invalid or not, based
 // on the error we got while reading with the cacheHandle.
 // If it's invalid then we should close that cacheHandle and create new cacheHandle
@@ -0,0 +1,101 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package changefeed
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// EventType is the type of change of an object, as in the eventType attribute
+// of GCS Pub/Sub notifications.
+type EventType string
+
+const (
+	ObjectFinalize       EventType = "OBJECT_FINALIZE"
+	ObjectMetadataUpdate EventType = "OBJECT_METADATA_UPDATE"
+	ObjectDelete         EventType = "OBJECT_DELETE"
+	ObjectArchive        EventType = "OBJECT_ARCHIVE"
+)
+
+// Event is a change of an object in GCS.
+type Event struct {
+	Type       EventType
+	BucketName string
+	ObjectName string
+
+	// The generation of the object the event is about, or 0 if unknown.
+	Generation int64
+}
+
+// objectResource holds the fields of interest of the JSON_API_V1 payload of
+// GCS Pub/Sub notifications.
+type objectResource struct {
+	Bucket     string `json:"bucket"`
+	Name       string `json:"name"`
+	Generation string `json:"generation"`
+}
+
+// ParseMessage returns the event described by a GCS Pub/Sub notification
+// with the supplied attributes and data. The attributes are used if present,
+// so that notifications without a payload are understood, otherwise the
+// JSON_API_V1 payload is.
+func ParseMessage(attributes map[string]string, data []byte) (Event, error) {
+	e := Event{
+		Type:       EventType(attributes["eventType"]),
+		BucketName: attributes["bucketId"],
+		ObjectName: attributes["objectId"],
+	}
+	generation := attributes["objectGeneration"]
+
+	if e.BucketName == "" || e.ObjectName == "" {
+		var o objectResource
+		if err := json.Unmarshal(data, &o); err != nil {
+			return Event{}, fmt.Errorf("malformed payload: %w", err)
+		}
+		e.BucketName, e.ObjectName, generation = o.Bucket, o.Name, o.Generation
+	}
+	if e.BucketName == "" || e.ObjectName == "" {
+		return Event{}, errors.New("missing bucket or object name")
+	}
+
+	if generation != "" {
+		var err error
+		if e.Generation, err = strconv.ParseInt(generation, 10, 64); err != nil {
+			return Event{}, fmt.Errorf("malformed generation %q: %w", generation, err)
+		}
+	}
+	return e, nil
+}
+
+// pushRequest is the body of the requests made by Pub/Sub push subscriptions.
+type pushRequest struct {
+	Message struct {
+		Attributes map[string]string `json:"attributes"`
+		Data       []byte            `json:"data"`
+	} `json:"message"`
+}
+
+// parsePushRequest returns the event described by the body of a Pub/Sub push
+// request.
+func parsePushRequest(body []byte) (Event, error) {
+	var r pushRequest
+	if err := json.Unmarshal(body, &r); err != nil {
+		return Event{}, fmt.Errorf("malformed push request: %w", err)
+	}
+	return ParseMessage(r.Message.Attributes, r.Message.Data)
+}
@@ -0,0 +1,105 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package changefeed
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/logger"
+)
+
+// How often the file of a file source is checked for new lines. A variable
+// for tests.
+var filePollInterval = time.Second
+
+// fileSource follows a file with one Pub/Sub push request body per line,
+// starting at its end when run, like tail -f.
+type fileSource struct {
+	path string
+}
+
+func (s *fileSource) Run(ctx context.Context, handle func(Event)) error {
+	f, err := os.Open(s.path)
+	if err != nil {
+		return fmt.Errorf("opening change feed file: %w", err)
+	}
+	defer f.Close()
+
+	offset, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return fmt.Errorf("seeking change feed file: %w", err)
+	}
+
+	logger.Infof("Receiving object change notifications from %s", s.path)
+	var partial []byte
+	buf := make([]byte, 64*1024)
+	ticker := time.NewTicker(filePollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		// Start over if the file was truncated.
+		fi, err := f.Stat()
+		if err != nil {
+			return fmt.Errorf("stating change feed file: %w", err)
+		}
+		if fi.Size() < offset {
+			if offset, err = f.Seek(0, io.SeekStart); err != nil {
+				return fmt.Errorf("seeking change feed file: %w", err)
+			}
+			partial = nil
+		}
+
+		for {
+			n, err := f.Read(buf)
+			offset += int64(n)
+			partial = append(partial, buf[:n]...)
+			if err == io.EOF || n == 0 {
+				break
+			}
+			if err != nil {
+				return fmt.Errorf("reading change feed file: %w", err)
+			}
+		}
+
+		// Hold on to the last line until it is complete.
+		for {
+			i := bytes.IndexByte(partial, '\n')
+			if i < 0 {
+				break
+			}
+			line := bytes.TrimSpace(partial[:i])
+			partial = partial[i+1:]
+			if len(line) == 0 {
+				continue
+			}
+			e, err := parsePushRequest(line)
+			if err != nil {
+				logger.Warnf("Ignoring line of %s: %v", s.path, err)
+				continue
+			}
+			handle(e)
+		}
+	}
+}
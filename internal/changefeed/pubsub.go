@@ -0,0 +1,58 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package changefeed
+
+import (
+	"context"
+	"fmt"
+
+	"cloud.google.com/go/pubsub"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/logger"
+	"google.golang.org/api/option"
+)
+
+// pubsubSource pulls notifications from a Pub/Sub subscription, with the
+// application default credentials.
+type pubsubSource struct {
+	project      string
+	subscription string
+
+	// Overrides the endpoint and credentials in tests.
+	clientOptions []option.ClientOption
+}
+
+func (s *pubsubSource) Run(ctx context.Context, handle func(Event)) error {
+	client, err := pubsub.NewClient(ctx, s.project, s.clientOptions...)
+	if err != nil {
+		return fmt.Errorf("creating Pub/Sub client: %w", err)
+	}
+	defer client.Close()
+
+	logger.Infof("Receiving object change notifications from projects/%s/subscriptions/%s", s.project, s.subscription)
+	err = client.Subscription(s.subscription).Receive(ctx, func(_ context.Context, m *pubsub.Message) {
+		e, err := ParseMessage(m.Attributes, m.Data)
+		if err != nil {
+			// Redelivering the message wouldn't help.
+			logger.Warnf("Ignoring Pub/Sub message %s: %v", m.ID, err)
+		} else {
+			handle(e)
+		}
+		m.Ack()
+	})
+	if err != nil {
+		return fmt.Errorf("receiving from Pub/Sub: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package changefeed receives notifications of changes to objects in GCS, in
+// the format of GCS Pub/Sub notifications, so that the caches of a mount can
+// be kept coherent with writers elsewhere without waiting for TTLs.
+package changefeed
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Source delivers object change events.
+type Source interface {
+	// Run calls handle with each event received until ctx is done, and returns
+	// the error that stopped it early, if any. handle may be called
+	// concurrently.
+	Run(ctx context.Context, handle func(Event)) error
+}
+
+// NewSource returns the source described by the supplied spec, one of:
+//
+//   - pubsub://projects/PROJECT/subscriptions/SUBSCRIPTION, pulling
+//     notifications from a Pub/Sub subscription.
+//   - http://HOST:PORT/PATH[?audience=AUDIENCE[&service-account=EMAIL]],
+//     serving a Pub/Sub push endpoint at PATH. With an audience, push requests
+//     must be authenticated with a token for it. Without one, the endpoint is
+//     unauthenticated, and listens on localhost unless HOST is given.
+//   - file:///PATH, following a file with one push request body per line, as
+//     a local stand-in for Pub/Sub.
+func NewSource(spec string) (Source, error) {
+	if rest, ok := strings.CutPrefix(spec, "pubsub://"); ok {
+		parts := strings.Split(rest, "/")
+		if len(parts) != 4 || parts[0] != "projects" || parts[1] == "" || parts[2] != "subscriptions" || parts[3] == "" {
+			return nil, fmt.Errorf("%q is not of the form pubsub://projects/PROJECT/subscriptions/SUBSCRIPTION", spec)
+		}
+		return &pubsubSource{project: parts[1], subscription: parts[3]}, nil
+	}
+
+	u, err := url.Parse(spec)
+	if err != nil {
+		return nil, err
+	}
+	switch u.Scheme {
+	case "http":
+		if u.Host == "" {
+			return nil, fmt.Errorf("%q has no address to listen on", spec)
+		}
+		q := u.Query()
+		s := &webhookSource{
+			address:        u.Host,
+			path:           "/" + strings.TrimPrefix(u.Path, "/"),
+			audience:       q.Get("audience"),
+			serviceAccount: q.Get("service-account"),
+		}
+		if s.serviceAccount != "" && s.audience == "" {
+			return nil, fmt.Errorf("%q sets a service-account without an audience", spec)
+		}
+		// Without authentication, only listen on all interfaces if asked to.
+		if strings.HasPrefix(s.address, ":") && s.audience == "" {
+			s.address = "localhost" + s.address
+		}
+		return s, nil
+	case "file":
+		if u.Path == "" {
+			return nil, fmt.Errorf("%q has no path", spec)
+		}
+		return &fileSource{path: u.Path}, nil
+	default:
+		return nil, fmt.Errorf("unsupported change feed %q, expected a pubsub://, http:// or file:// URL", spec)
+	}
+}
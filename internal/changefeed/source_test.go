@@ -0,0 +1,295 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package changefeed
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"cloud.google.com/go/pubsub/pstest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/idtoken"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+const payload = `{"kind":"storage#object","bucket":"some-bucket","name":"dir/foo","generation":"1234"}`
+
+// events collects the events handled by a source.
+type events struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func (e *events) handle(event Event) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.events = append(e.events, event)
+}
+
+func (e *events) get() []Event {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]Event(nil), e.events...)
+}
+
+func pushRequestBody(attributes string, data string) string {
+	return fmt.Sprintf(`{"message":{"attributes":%s,"data":%q},"subscription":"projects/p/subscriptions/s"}`,
+		attributes, base64.StdEncoding.EncodeToString([]byte(data)))
+}
+
+func TestParseMessage(t *testing.T) {
+	testCases := []struct {
+		name       string
+		attributes map[string]string
+		data       string
+		expected   Event
+	}{
+		{
+			name: "attributes",
+			attributes: map[string]string{
+				"eventType":        "OBJECT_DELETE",
+				"bucketId":         "some-bucket",
+				"objectId":         "dir/bar",
+				"objectGeneration": "42",
+			},
+			expected: Event{Type: ObjectDelete, BucketName: "some-bucket", ObjectName: "dir/bar", Generation: 42},
+		},
+		{
+			name:       "payload",
+			attributes: map[string]string{"eventType": "OBJECT_FINALIZE"},
+			data:       payload,
+			expected:   Event{Type: ObjectFinalize, BucketName: "some-bucket", ObjectName: "dir/foo", Generation: 1234},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e, err := ParseMessage(tc.attributes, []byte(tc.data))
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, e)
+		})
+	}
+}
+
+func TestParseMessageRejectsMalformedMessages(t *testing.T) {
+	testCases := []struct {
+		name       string
+		attributes map[string]string
+		data       string
+	}{
+		{name: "empty"},
+		{name: "not JSON", data: "foo"},
+		{name: "missing name", data: `{"bucket":"some-bucket"}`},
+		{name: "malformed generation", attributes: map[string]string{"bucketId": "b", "objectId": "o", "objectGeneration": "x"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseMessage(tc.attributes, []byte(tc.data))
+
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestNewSource(t *testing.T) {
+	testCases := []struct {
+		spec     string
+		expected Source
+	}{
+		{spec: "pubsub://projects/p/subscriptions/s", expected: &pubsubSource{project: "p", subscription: "s"}},
+		{spec: "http://localhost:8080/notify", expected: &webhookSource{address: "localhost:8080", path: "/notify"}},
+		{spec: "http://:8080", expected: &webhookSource{address: "localhost:8080", path: "/"}},
+		{spec: "http://:8080/notify?audience=aud&service-account=sa@p.iam.gserviceaccount.com", expected: &webhookSource{address: ":8080", path: "/notify", audience: "aud", serviceAccount: "sa@p.iam.gserviceaccount.com"}},
+		{spec: "file:///var/run/feed", expected: &fileSource{path: "/var/run/feed"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.spec, func(t *testing.T) {
+			s, err := NewSource(tc.spec)
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, s)
+		})
+	}
+}
+
+func TestNewSourceRejectsInvalidSpecs(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"some-subscription",
+		"pubsub://projects/p",
+		"pubsub://projects//subscriptions/s",
+		"http:///notify",
+		"http://:8080/notify?service-account=sa@p.iam.gserviceaccount.com",
+		"file://",
+		"https://example.com/notify",
+	} {
+		t.Run(spec, func(t *testing.T) {
+			_, err := NewSource(spec)
+
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestWebhookSourceHandlesPushRequests(t *testing.T) {
+	var got events
+	s := &webhookSource{path: "/notify"}
+	server := httptest.NewServer(s.handler(got.handle))
+	defer server.Close()
+
+	resp, err := http.Post(server.URL+"/notify", "application/json",
+		strings.NewReader(pushRequestBody(`{"eventType":"OBJECT_FINALIZE"}`, payload)))
+
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.Equal(t, []Event{{Type: ObjectFinalize, BucketName: "some-bucket", ObjectName: "dir/foo", Generation: 1234}}, got.get())
+}
+
+func TestWebhookSourceRejectsMalformedPushRequests(t *testing.T) {
+	var got events
+	s := &webhookSource{path: "/notify"}
+	server := httptest.NewServer(s.handler(got.handle))
+	defer server.Close()
+
+	resp, err := http.Post(server.URL+"/notify", "application/json", strings.NewReader("foo"))
+
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Empty(t, got.get())
+}
+
+func TestWebhookSourceAuthenticatesPushRequests(t *testing.T) {
+	const serviceAccount = "sa@p.iam.gserviceaccount.com"
+	validate := func(ctx context.Context, token string, audience string) (*idtoken.Payload, error) {
+		if audience != "aud" {
+			return nil, fmt.Errorf("unexpected audience %q", audience)
+		}
+		switch token {
+		case "pubsub":
+			return &idtoken.Payload{Claims: map[string]any{"email": serviceAccount, "email_verified": true}}, nil
+		case "other":
+			return &idtoken.Payload{Claims: map[string]any{"email": "other@p.iam.gserviceaccount.com", "email_verified": true}}, nil
+		default:
+			return nil, fmt.Errorf("invalid token")
+		}
+	}
+	testCases := []struct {
+		name          string
+		authorization string
+		expected      int
+	}{
+		{name: "valid token", authorization: "Bearer pubsub", expected: http.StatusNoContent},
+		{name: "missing token", expected: http.StatusUnauthorized},
+		{name: "invalid token", authorization: "Bearer forged", expected: http.StatusUnauthorized},
+		{name: "other service account", authorization: "Bearer other", expected: http.StatusUnauthorized},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got events
+			s := &webhookSource{path: "/notify", audience: "aud", serviceAccount: serviceAccount, validate: validate}
+			server := httptest.NewServer(s.handler(got.handle))
+			defer server.Close()
+			req, err := http.NewRequest(http.MethodPost, server.URL+"/notify",
+				strings.NewReader(pushRequestBody(`{"eventType":"OBJECT_FINALIZE"}`, payload)))
+			require.NoError(t, err)
+			if tc.authorization != "" {
+				req.Header.Set("Authorization", tc.authorization)
+			}
+
+			resp, err := http.DefaultClient.Do(req)
+
+			require.NoError(t, err)
+			resp.Body.Close()
+			assert.Equal(t, tc.expected, resp.StatusCode)
+			if tc.expected != http.StatusNoContent {
+				assert.Empty(t, got.get())
+			}
+		})
+	}
+}
+
+func TestFileSourceFollowsFile(t *testing.T) {
+	defer func(d time.Duration) { filePollInterval = d }(filePollInterval)
+	filePollInterval = 10 * time.Millisecond
+	path := filepath.Join(t.TempDir(), "feed")
+	// Lines written before the source runs are skipped.
+	require.NoError(t, os.WriteFile(path, []byte(pushRequestBody(`{"bucketId":"b","objectId":"old"}`, "")+"\n"), 0600))
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	var got events
+	go func() { done <- (&fileSource{path: path}).Run(ctx, got.handle) }()
+	// Let the source seek to the end of the file.
+	time.Sleep(50 * time.Millisecond)
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	require.NoError(t, err)
+	defer f.Close()
+
+	line := pushRequestBody(`{"eventType":"OBJECT_DELETE"}`, payload)
+	_, err = f.WriteString("foo\n" + line[:10])
+	require.NoError(t, err)
+	time.Sleep(50 * time.Millisecond)
+	_, err = f.WriteString(line[10:] + "\n")
+	require.NoError(t, err)
+
+	expected := []Event{{Type: ObjectDelete, BucketName: "some-bucket", ObjectName: "dir/foo", Generation: 1234}}
+	assert.Eventually(t, func() bool { return len(got.get()) > 0 }, 5*time.Second, 10*time.Millisecond)
+	cancel()
+	assert.NoError(t, <-done)
+	assert.Equal(t, expected, got.get())
+}
+
+func TestPubsubSourceReceivesMessages(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	server := pstest.NewServer()
+	defer server.Close()
+	conn, err := grpc.NewClient(server.Addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	client, err := pubsub.NewClient(ctx, "p", option.WithGRPCConn(conn))
+	require.NoError(t, err)
+	topic, err := client.CreateTopic(ctx, "t")
+	require.NoError(t, err)
+	_, err = client.CreateSubscription(ctx, "s", pubsub.SubscriptionConfig{Topic: topic})
+	require.NoError(t, err)
+	s := &pubsubSource{project: "p", subscription: "s", clientOptions: []option.ClientOption{option.WithGRPCConn(conn)}}
+	var got events
+	done := make(chan error)
+	go func() { done <- s.Run(ctx, got.handle) }()
+
+	server.Publish("projects/p/topics/t", []byte("foo"), nil)
+	server.Publish("projects/p/topics/t", []byte(payload), map[string]string{"eventType": "OBJECT_METADATA_UPDATE"})
+
+	expected := []Event{{Type: ObjectMetadataUpdate, BucketName: "some-bucket", ObjectName: "dir/foo", Generation: 1234}}
+	assert.Eventually(t, func() bool { return len(got.get()) > 0 }, 10*time.Second, 10*time.Millisecond)
+	cancel()
+	assert.NoError(t, <-done)
+	assert.Equal(t, expected, got.get())
+}
@@ -0,0 +1,131 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package changefeed
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/logger"
+	"google.golang.org/api/idtoken"
+)
+
+// The largest push request accepted. Notifications are a few KiB.
+const maxPushRequestBytes = 1 << 20
+
+// webhookSource serves a Pub/Sub push endpoint.
+type webhookSource struct {
+	address string
+	path    string
+
+	// If set, push requests must carry an OIDC token that Pub/Sub signed for
+	// this audience, and, if serviceAccount is set too, on behalf of that
+	// service account.
+	audience       string
+	serviceAccount string
+
+	// Validates the tokens. Defaults to idtoken.Validate.
+	validate func(ctx context.Context, token string, audience string) (*idtoken.Payload, error)
+}
+
+// authenticate checks the token of a push request, if the source requires one.
+func (s *webhookSource) authenticate(r *http.Request) error {
+	if s.audience == "" {
+		return nil
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return fmt.Errorf("missing bearer token")
+	}
+	validate := s.validate
+	if validate == nil {
+		validate = idtoken.Validate
+	}
+	payload, err := validate(r.Context(), token, s.audience)
+	if err != nil {
+		return fmt.Errorf("validating token: %w", err)
+	}
+	if s.serviceAccount == "" {
+		return nil
+	}
+	if email, _ := payload.Claims["email"].(string); email != s.serviceAccount {
+		return fmt.Errorf("token is for %q, not %q", email, s.serviceAccount)
+	}
+	if verified, _ := payload.Claims["email_verified"].(bool); !verified {
+		return fmt.Errorf("email of token is not verified")
+	}
+	return nil
+}
+
+// handler returns the handler of push requests, acknowledging each with a
+// 204 once handled.
+func (s *webhookSource) handler(handle func(Event)) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST "+s.path, func(w http.ResponseWriter, r *http.Request) {
+		if err := s.authenticate(r); err != nil {
+			logger.Warnf("Rejecting push request: %v", err)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxPushRequestBytes))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		e, err := parsePushRequest(body)
+		if err != nil {
+			logger.Warnf("Ignoring push request: %v", err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		handle(e)
+		w.WriteHeader(http.StatusNoContent)
+	})
+	return mux
+}
+
+func (s *webhookSource) Run(ctx context.Context, handle func(Event)) error {
+	listener, err := net.Listen("tcp", s.address)
+	if err != nil {
+		return fmt.Errorf("listening for push requests: %w", err)
+	}
+	server := &http.Server{
+		Handler:           s.handler(handle),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		_ = server.Close()
+	}()
+
+	logger.Infof("Receiving object change notifications at http://%s%s", listener.Addr(), s.path)
+	if s.audience == "" && !isLoopback(listener.Addr()) {
+		logger.Warnf("Push requests to http://%s%s are not authenticated, so anyone who can reach it can make gcsfuse drop its caches; set an audience to require Pub/Sub tokens", listener.Addr(), s.path)
+	}
+	if err := server.Serve(listener); err != http.ErrServerClosed {
+		return fmt.Errorf("serving push requests: %w", err)
+	}
+	return nil
+}
+
+func isLoopback(addr net.Addr) bool {
+	tcpAddr, ok := addr.(*net.TCPAddr)
+	return ok && tcpAddr.IP.IsLoopback()
+}
@@ -0,0 +1,82 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package control
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+)
+
+// Client calls the API served on a control socket.
+type Client struct {
+	httpClient *http.Client
+}
+
+// NewClient returns a client for the control socket at the supplied path.
+func NewClient(socketPath string) *Client {
+	return &Client{
+		httpClient: &http.Client{
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					var d net.Dialer
+					return d.DialContext(ctx, "unix", socketPath)
+				},
+			},
+		},
+	}
+}
+
+// Query returns the JSON document returned by the named query.
+func (c *Client) Query(ctx context.Context, name string) (json.RawMessage, error) {
+	return c.do(ctx, http.MethodGet, name, nil)
+}
+
+// Act runs the named action with the supplied parameters.
+func (c *Client) Act(ctx context.Context, name string, params url.Values) error {
+	_, err := c.do(ctx, http.MethodPost, name, params)
+	return err
+}
+
+func (c *Client) do(ctx context.Context, method string, name string, params url.Values) (json.RawMessage, error) {
+	// The host is ignored, as the client always dials the socket.
+	u := url.URL{Scheme: "http", Host: "gcsfuse", Path: "/" + name, RawQuery: params.Encode()}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response: %w", err)
+	}
+	if resp.StatusCode >= 300 {
+		var errResp errorResponse
+		if json.Unmarshal(body, &errResp) == nil && errResp.Error != "" {
+			return nil, fmt.Errorf("%s %s: %s", method, name, errResp.Error)
+		}
+		return nil, fmt.Errorf("%s %s: %s", method, name, resp.Status)
+	}
+	return body, nil
+}
@@ -0,0 +1,206 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package control serves an HTTP/JSON API on a Unix domain socket, through
+// which a running mount can be inspected and controlled, e.g. with
+// 'gcsfuse ctl'.
+package control
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	"github.com/googlecloudplatform/gcsfuse/v3/common"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/bufferedwrites"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/data"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/file/downloader"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/metadata"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/fs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/logger"
+)
+
+// Target is the mount inspected and controlled through the API. It is
+// implemented by fs.Controller.
+type Target interface {
+	Config() (*cfg.Config, error)
+	Inodes() ([]fs.InodeInfo, error)
+	Handles() ([]fs.HandleInfo, error)
+	StatCacheEntries() ([]metadata.StatCacheSnapshotEntry, error)
+	FileCacheEntries() ([]data.FileInfo, error)
+	Downloads() ([]downloader.JobSummary, error)
+	IncompleteUploads() ([]bufferedwrites.IncompleteUpload, error)
+	InvalidatePath(p string) error
+	EvictCaches() error
+	FlushAll(ctx context.Context) error
+	Prefetch(ctx context.Context, paths []string, manifest string) error
+}
+
+// The names of the queries, served on GET /<name>, each returning a JSON
+// document.
+const (
+	QueryConfig            = "config"
+	QueryInodes            = "inodes"
+	QueryHandles           = "handles"
+	QueryStatCache         = "stat-cache"
+	QueryFileCache         = "file-cache"
+	QueryDownloads         = "downloads"
+	QueryIncompleteUploads = "incomplete-uploads"
+)
+
+// The names of the actions, served on POST /<name>, each returning no content
+// on success.
+const (
+	// Takes the path to invalidate, relative to the mount point, in the "path"
+	// query parameter. See fs.Controller.InvalidatePath.
+	ActionInvalidate = "invalidate"
+	ActionEvictCache = "evict-cache"
+	ActionFlush      = "flush"
+	// Takes the paths to prefetch into the file cache, relative to the mount
+	// point, in "path" query parameters and/or the path of a manifest file
+	// listing them in the "manifest" query parameter. It returns once the
+	// prefetching has started. See fs.Controller.Prefetch.
+	ActionPrefetch = "prefetch"
+)
+
+// Queries and Actions list the names above.
+var (
+	Queries = []string{QueryConfig, QueryInodes, QueryHandles, QueryStatCache, QueryFileCache, QueryDownloads, QueryIncompleteUploads}
+	Actions = []string{ActionInvalidate, ActionEvictCache, ActionFlush, ActionPrefetch}
+)
+
+var errMissingPath = errors.New("missing path")
+
+// errorResponse is the body of the responses to failed requests.
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logger.Warnf("Control socket: failed to write response: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
+
+func handleQuery[T any](query func() (T, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		result, err := query()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, result)
+	}
+}
+
+func handleAction(action func(r *http.Request) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := action(r); err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, errMissingPath) {
+				status = http.StatusBadRequest
+			}
+			writeError(w, status, err)
+			return
+		}
+		logger.Infof("Control socket: %s %s done", r.Method, r.URL.RequestURI())
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// NewHandler returns the handler serving the API for the supplied target.
+func NewHandler(t Target) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("GET /"+QueryConfig, handleQuery(t.Config))
+	mux.Handle("GET /"+QueryInodes, handleQuery(t.Inodes))
+	mux.Handle("GET /"+QueryHandles, handleQuery(t.Handles))
+	mux.Handle("GET /"+QueryStatCache, handleQuery(t.StatCacheEntries))
+	mux.Handle("GET /"+QueryFileCache, handleQuery(t.FileCacheEntries))
+	mux.Handle("GET /"+QueryDownloads, handleQuery(t.Downloads))
+	mux.Handle("GET /"+QueryIncompleteUploads, handleQuery(t.IncompleteUploads))
+	mux.Handle("POST /"+ActionInvalidate, handleAction(func(r *http.Request) error {
+		p := r.URL.Query().Get("path")
+		if p == "" {
+			return errMissingPath
+		}
+		return t.InvalidatePath(p)
+	}))
+	mux.Handle("POST /"+ActionEvictCache, handleAction(func(*http.Request) error { return t.EvictCaches() }))
+	mux.Handle("POST /"+ActionFlush, handleAction(func(r *http.Request) error { return t.FlushAll(r.Context()) }))
+	mux.Handle("POST /"+ActionPrefetch, handleAction(func(r *http.Request) error {
+		paths, manifest := r.URL.Query()["path"], r.URL.Query().Get("manifest")
+		if len(paths) == 0 && manifest == "" {
+			return errMissingPath
+		}
+		return t.Prefetch(r.Context(), paths, manifest)
+	}))
+	return mux
+}
+
+// Serve serves the API for the supplied target on a Unix domain socket at the
+// supplied path, which only the user running gcsfuse can connect to. A stale
+// socket left behind at the path is replaced. The returned function shuts the
+// server down and removes the socket.
+func Serve(socketPath string, t Target) (common.ShutdownFn, error) {
+	if fi, err := os.Lstat(socketPath); err == nil && fi.Mode()&os.ModeSocket != 0 {
+		if err := os.Remove(socketPath); err != nil {
+			return nil, fmt.Errorf("removing stale control socket: %w", err)
+		}
+	}
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return nil, fmt.Errorf("listening on control socket: %w", err)
+	}
+	if err := os.Chmod(socketPath, 0600); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("restricting access to control socket: %w", err)
+	}
+
+	server := &http.Server{
+		Handler: NewHandler(t),
+		// Actions such as flushing all files can take long, so only the headers
+		// are timed.
+		ReadHeaderTimeout: 10 * time.Second,
+		MaxHeaderBytes:    1 << 20,
+	}
+	go func() {
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			logger.Errorf("Control socket server failed: %v", err)
+		}
+	}()
+	logger.Infof("Serving the control API at %s", socketPath)
+
+	return func(ctx context.Context) error {
+		err := server.Shutdown(ctx)
+		// Shutdown misses the listener if Serve hasn't started yet. Closing it
+		// removes the socket.
+		_ = listener.Close()
+		if err != nil {
+			return fmt.Errorf("shutting down control socket server: %w", err)
+		}
+		return nil
+	}, nil
+}
@@ -0,0 +1,163 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package control
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/bufferedwrites"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/data"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/file/downloader"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/metadata"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/fs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeTarget struct {
+	invalidated []string
+	evicted     bool
+	flushErr    error
+	prefetched  []string
+	manifest    string
+}
+
+func (t *fakeTarget) Config() (*cfg.Config, error) {
+	return &cfg.Config{AppName: "app"}, nil
+}
+
+func (t *fakeTarget) Inodes() ([]fs.InodeInfo, error) {
+	return []fs.InodeInfo{{ID: 1, Name: "", Type: "dir"}, {ID: 2, Name: "a", Type: "file"}}, nil
+}
+
+func (t *fakeTarget) Handles() ([]fs.HandleInfo, error) {
+	return []fs.HandleInfo{{ID: 3, InodeID: 2, Name: "a", Type: "file"}}, nil
+}
+
+func (t *fakeTarget) StatCacheEntries() ([]metadata.StatCacheSnapshotEntry, error) {
+	return nil, nil
+}
+
+func (t *fakeTarget) FileCacheEntries() ([]data.FileInfo, error) {
+	return nil, errors.New("taco")
+}
+
+func (t *fakeTarget) Downloads() ([]downloader.JobSummary, error) {
+	return []downloader.JobSummary{{ObjectName: "a", Status: downloader.Downloading, Offset: 5}}, nil
+}
+
+func (t *fakeTarget) IncompleteUploads() ([]bufferedwrites.IncompleteUpload, error) {
+	return []bufferedwrites.IncompleteUpload{{ObjectName: "b", SentBytes: 10, PersistedBytes: 8, Outcome: bufferedwrites.UploadOutcomeResumable}}, nil
+}
+
+func (t *fakeTarget) InvalidatePath(p string) error {
+	t.invalidated = append(t.invalidated, p)
+	return nil
+}
+
+func (t *fakeTarget) EvictCaches() error {
+	t.evicted = true
+	return nil
+}
+
+func (t *fakeTarget) FlushAll(context.Context) error {
+	return t.flushErr
+}
+
+func (t *fakeTarget) Prefetch(_ context.Context, paths []string, manifest string) error {
+	t.prefetched = append(t.prefetched, paths...)
+	t.manifest = manifest
+	return nil
+}
+
+func serve(t *testing.T, target Target) *Client {
+	t.Helper()
+	socketPath := path.Join(t.TempDir(), "ctl.sock")
+	shutdown, err := Serve(socketPath, target)
+	require.NoError(t, err)
+	t.Cleanup(func() { assert.NoError(t, shutdown(context.Background())) })
+	return NewClient(socketPath)
+}
+
+func TestServeRestrictsAccessToSocket(t *testing.T) {
+	socketPath := path.Join(t.TempDir(), "ctl.sock")
+	shutdown, err := Serve(socketPath, &fakeTarget{})
+	require.NoError(t, err)
+
+	fi, err := os.Stat(socketPath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), fi.Mode().Perm())
+	require.NoError(t, shutdown(context.Background()))
+	_, err = os.Stat(socketPath)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestQueries(t *testing.T) {
+	c := serve(t, &fakeTarget{})
+	ctx := context.Background()
+
+	inodes, err := c.Query(ctx, QueryInodes)
+	require.NoError(t, err)
+	var got []fs.InodeInfo
+	require.NoError(t, json.Unmarshal(inodes, &got))
+	assert.Equal(t, []fs.InodeInfo{{ID: 1, Name: "", Type: "dir"}, {ID: 2, Name: "a", Type: "file"}}, got)
+
+	downloads, err := c.Query(ctx, QueryDownloads)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"BucketName":"","ObjectName":"a","Generation":0,"Size":0,"Status":"Downloading","Offset":5}]`, string(downloads))
+
+	uploads, err := c.Query(ctx, QueryIncompleteUploads)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"ObjectName":"b","Started":"0001-01-01T00:00:00Z","SentBytes":10,"PersistedBytes":8,"Outcome":"resumable"}]`, string(uploads))
+
+	config, err := c.Query(ctx, QueryConfig)
+	require.NoError(t, err)
+	assert.Contains(t, string(config), `"AppName":"app"`)
+
+	_, err = c.Query(ctx, QueryFileCache)
+	assert.EqualError(t, err, "GET file-cache: taco")
+
+	_, err = c.Query(ctx, "unknown")
+	assert.EqualError(t, err, "GET unknown: 404 Not Found")
+}
+
+func TestActions(t *testing.T) {
+	target := &fakeTarget{flushErr: errors.New("taco")}
+	c := serve(t, target)
+	ctx := context.Background()
+
+	require.NoError(t, c.Act(ctx, ActionInvalidate, url.Values{"path": {"a/b"}}))
+	require.NoError(t, c.Act(ctx, ActionEvictCache, nil))
+	err := c.Act(ctx, ActionFlush, nil)
+
+	assert.EqualError(t, err, "POST flush: taco")
+	assert.Equal(t, []string{"a/b"}, target.invalidated)
+	assert.True(t, target.evicted)
+	assert.EqualError(t, c.Act(ctx, ActionInvalidate, nil), "POST invalidate: missing path")
+	require.NoError(t, c.Act(ctx, ActionPrefetch, url.Values{"path": {"a/", "b/*.idx"}, "manifest": {"/some/manifest"}}))
+	assert.Equal(t, []string{"a/", "b/*.idx"}, target.prefetched)
+	assert.Equal(t, "/some/manifest", target.manifest)
+	assert.EqualError(t, c.Act(ctx, ActionPrefetch, nil), "POST prefetch: missing path")
+	// Actions can't be run with GET.
+	_, err = c.Query(ctx, ActionEvictCache)
+	assert.EqualError(t, err, "GET evict-cache: 405 Method Not Allowed")
+}
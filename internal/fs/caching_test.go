@@ -23,6 +23,7 @@ import (
 	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/lru"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/metadata"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/fs"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/fs/inode"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/caching"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/fake"
@@ -67,9 +68,11 @@ func (t *cachingTestCommon) SetUpTestSuite() {
 		uncachedBucket,
 		negativeCacheTTL,
 	)
+	t.statCache = statCache
 
 	// Enable directory type caching.
 	t.serverCfg.DirTypeCacheTTL = ttl
+	t.serverCfg.Controller = fs.NewController()
 
 	// Call through.
 	t.fsTest.SetUpTestSuite()
@@ -205,6 +208,108 @@ func (t *CachingTest) DirectoryRemovedRemotely() {
 	ExpectTrue(os.IsNotExist(err), "err: %v", err)
 }
 
+func (t *CachingTest) FileChangedRemotely_PathInvalidated() {
+	const name = "foo"
+	var fi os.FileInfo
+	var err error
+
+	// Create a file via the file system.
+	err = os.WriteFile(path.Join(mntDir, name), []byte("taco"), 0500)
+	AssertEq(nil, err)
+
+	// Overwrite the object in GCS.
+	_, err = storageutil.CreateObject(
+		ctx,
+		uncachedBucket,
+		name,
+		[]byte("burrito"))
+
+	AssertEq(nil, err)
+
+	// Because we are caching, the file should still appear to be the local
+	// version.
+	fi, err = os.Stat(path.Join(mntDir, name))
+	AssertEq(nil, err)
+	ExpectEq(len("taco"), fi.Size())
+
+	// After invalidating the path, we should see the new version without
+	// waiting for the TTL.
+	err = t.serverCfg.Controller.InvalidatePath(name)
+	AssertEq(nil, err)
+
+	fi, err = os.Stat(path.Join(mntDir, name))
+	AssertEq(nil, err)
+	ExpectEq(len("burrito"), fi.Size())
+
+	b, err := os.ReadFile(path.Join(mntDir, name))
+	AssertEq(nil, err)
+	ExpectEq("burrito", string(b))
+}
+
+func (t *CachingTest) DirectoryRemovedRemotely_ParentPathInvalidated() {
+	var err error
+
+	// Create a directory with a file via the file system.
+	err = os.MkdirAll(path.Join(mntDir, "foo/bar"), 0700)
+	AssertEq(nil, err)
+
+	// Remove the backing object in GCS.
+	err = uncachedBucket.DeleteObject(
+		ctx,
+		&gcs.DeleteObjectRequest{Name: "foo/bar/"})
+
+	AssertEq(nil, err)
+
+	// Because we are caching, the directory should still appear to exist.
+	_, err = os.Stat(path.Join(mntDir, "foo/bar"))
+	AssertEq(nil, err)
+
+	// Invalidating the subtree should make it disappear, while its parent
+	// stays.
+	err = t.serverCfg.Controller.InvalidatePath("foo/")
+	AssertEq(nil, err)
+
+	_, err = os.Stat(path.Join(mntDir, "foo/bar"))
+	ExpectTrue(os.IsNotExist(err), "err: %v", err)
+	_, err = os.Stat(path.Join(mntDir, "foo"))
+	ExpectEq(nil, err)
+}
+
+func (t *CachingTest) FileCreatedRemotely_ObjectInvalidated() {
+	const name = "foo/bar"
+	var err error
+
+	// Look up the file before it exists, caching its absence.
+	err = os.Mkdir(path.Join(mntDir, "foo"), 0700)
+	AssertEq(nil, err)
+	_, err = os.Stat(path.Join(mntDir, name))
+	AssertTrue(os.IsNotExist(err), "err: %v", err)
+
+	// Create the object in GCS.
+	_, err = storageutil.CreateObject(
+		ctx,
+		uncachedBucket,
+		name,
+		[]byte("taco"))
+
+	AssertEq(nil, err)
+
+	// Because we are caching, the file should still appear not to exist.
+	_, err = os.Stat(path.Join(mntDir, name))
+	ExpectTrue(os.IsNotExist(err), "err: %v", err)
+
+	// After being told of the change, the file system should see it without
+	// waiting for the TTL. Changes to other buckets are ignored.
+	err = t.serverCfg.Controller.InvalidateObject("other-bucket", name)
+	AssertEq(nil, err)
+	err = t.serverCfg.Controller.InvalidateObject(bucket.Name(), name)
+	AssertEq(nil, err)
+
+	b, err := os.ReadFile(path.Join(mntDir, name))
+	AssertEq(nil, err)
+	ExpectEq("taco", string(b))
+}
+
 func (t *CachingTest) ConflictingNames_RemoteModifier() {
 	const name = "foo"
 	var fi os.FileInfo
@@ -0,0 +1,603 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fs
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"math"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/bufferedwrites"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/data"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/file/downloader"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/metadata"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/fs/handle"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/fs/inode"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/logger"
+	"github.com/jacobsa/fuse"
+	"github.com/jacobsa/fuse/fuseops"
+	"golang.org/x/sync/semaphore"
+)
+
+// Controller gives access to a file system from outside of the FUSE
+// operations, e.g. from signal handlers and the control socket. It is bound to
+// the file system created with it in ServerConfig.
+type Controller struct {
+	fs *fileSystem
+
+	// The name of the mounted bucket, or empty if all accessible buckets are
+	// mounted.
+	bucketName string
+
+	// The directory of the bucket that is mounted, with a trailing slash, or
+	// empty if the whole bucket is.
+	onlyDir string
+
+	mu sync.Mutex
+
+	// The config last applied with Reconfigure, if any.
+	//
+	// GUARDED_BY(mu)
+	config *cfg.Config
+}
+
+// NewController returns a controller to be passed in ServerConfig.
+func NewController() *Controller {
+	return &Controller{}
+}
+
+func (c *Controller) fileSystem() (*fileSystem, error) {
+	if c.fs == nil {
+		return nil, errors.New("the file system hasn't been created yet")
+	}
+	return c.fs, nil
+}
+
+// Reconfigure applies the settings of the supplied config that can be changed
+// while mounted, as listed by cfg.ReloadableConfigPaths, other than the log
+// severity. The caller must have checked that no other setting has changed
+// since the file system was created, with cfg.DiffConfigs.
+func (c *Controller) Reconfigure(newConfig *cfg.Config) error {
+	fs, err := c.fileSystem()
+	if err != nil {
+		return err
+	}
+
+	ttl := time.Duration(newConfig.MetadataCache.TtlSecs) * time.Second
+	negativeTTL := time.Duration(newConfig.MetadataCache.NegativeTtlSecs) * time.Second
+	fs.inodeAttributeCacheTTL.Store(int64(ttl))
+	fs.dirTypeCacheTTL.Store(int64(ttl))
+	fs.bucketManager.SetStatCacheTTLs(ttl, negativeTTL)
+
+	fs.bucketManager.SetRateLimits(newConfig.GcsConnection.LimitOpsPerSec, newConfig.GcsConnection.LimitBytesPerSec)
+
+	fs.blockLimits.setMax(newConfig.Read.GlobalMaxBlocks, newConfig.Write.GlobalMaxBlocks)
+
+	c.mu.Lock()
+	c.config = newConfig
+	c.mu.Unlock()
+	return nil
+}
+
+// Config returns the config in effect, i.e. the one the file system was
+// created with, updated by Reconfigure.
+func (c *Controller) Config() (*cfg.Config, error) {
+	fs, err := c.fileSystem()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.config != nil {
+		return c.config, nil
+	}
+	return fs.newConfig, nil
+}
+
+// InodeInfo describes a live inode.
+type InodeInfo struct {
+	ID fuseops.InodeID
+
+	// The path of the inode relative to the mount point, ending with a slash
+	// for directories.
+	Name string
+
+	// One of "file", "dir" and "symlink".
+	Type string
+}
+
+// HandleInfo describes an open handle.
+type HandleInfo struct {
+	ID      fuseops.HandleID
+	InodeID fuseops.InodeID
+	Name    string
+	Type    string
+}
+
+func inodeType(in inode.Inode) string {
+	switch in.(type) {
+	case *inode.FileInode, *inode.VersionInode:
+		return "file"
+	case *inode.SymlinkInode:
+		return "symlink"
+	case inode.DirInode:
+		return "dir"
+	default:
+		return "unknown"
+	}
+}
+
+// Inodes returns the live inodes, sorted by ID.
+func (c *Controller) Inodes() ([]InodeInfo, error) {
+	fs, err := c.fileSystem()
+	if err != nil {
+		return nil, err
+	}
+
+	fs.mu.Lock()
+	infos := make([]InodeInfo, 0, len(fs.inodes))
+	for id, in := range fs.inodes {
+		infos = append(infos, InodeInfo{ID: id, Name: in.Name().LocalName(), Type: inodeType(in)})
+	}
+	fs.mu.Unlock()
+
+	slices.SortFunc(infos, func(a, b InodeInfo) int { return cmp.Compare(a.ID, b.ID) })
+	return infos, nil
+}
+
+// Handles returns the open handles, sorted by ID.
+func (c *Controller) Handles() ([]HandleInfo, error) {
+	fs, err := c.fileSystem()
+	if err != nil {
+		return nil, err
+	}
+
+	fs.mu.Lock()
+	infos := make([]HandleInfo, 0, len(fs.handles))
+	for id, h := range fs.handles {
+		var in inode.Inode
+		switch h := h.(type) {
+		case *handle.FileHandle:
+			in = h.Inode()
+		case *handle.DirHandle:
+			in = h.Inode()
+		case *inode.VersionInode:
+			in = h
+		default:
+			continue
+		}
+		infos = append(infos, HandleInfo{ID: id, InodeID: in.ID(), Name: in.Name().LocalName(), Type: inodeType(in)})
+	}
+	fs.mu.Unlock()
+
+	slices.SortFunc(infos, func(a, b HandleInfo) int { return cmp.Compare(a.ID, b.ID) })
+	return infos, nil
+}
+
+// StatCacheEntries returns the unexpired entries of the stat cache, least
+// recently used first. It returns nil if the stat cache is disabled.
+func (c *Controller) StatCacheEntries() ([]metadata.StatCacheSnapshotEntry, error) {
+	fs, err := c.fileSystem()
+	if err != nil {
+		return nil, err
+	}
+	return fs.bucketManager.SnapshotStatCache(fs.cacheClock.Now()), nil
+}
+
+// FileCacheEntries returns the entries of the file cache, least recently used
+// first. It returns nil if the file cache is disabled.
+func (c *Controller) FileCacheEntries() ([]data.FileInfo, error) {
+	fs, err := c.fileSystem()
+	if err != nil {
+		return nil, err
+	}
+	if fs.fileCacheHandler == nil {
+		return nil, nil
+	}
+	return fs.fileCacheHandler.Entries(), nil
+}
+
+// Downloads returns the jobs downloading objects into the file cache. It
+// returns nil if the file cache is disabled.
+func (c *Controller) Downloads() ([]downloader.JobSummary, error) {
+	fs, err := c.fileSystem()
+	if err != nil {
+		return nil, err
+	}
+	if fs.fileCacheHandler == nil {
+		return nil, nil
+	}
+	return fs.fileCacheHandler.Jobs(), nil
+}
+
+// IncompleteUploads returns the streaming uploads that the previous mount
+// left incomplete, as found in the upload journal at mount, and what was done
+// about them. It returns nil if the upload journal is disabled.
+func (c *Controller) IncompleteUploads() ([]bufferedwrites.IncompleteUpload, error) {
+	fs, err := c.fileSystem()
+	if err != nil {
+		return nil, err
+	}
+	return fs.incompleteUploads, nil
+}
+
+// splitPath splits a path relative to the mount point into the name of its
+// bucket and object.
+func (c *Controller) splitPath(p string) (bucketName string, objectName string, err error) {
+	p = strings.TrimPrefix(p, "/")
+	if c.bucketName != "" {
+		return c.bucketName, p, nil
+	}
+	bucketName, objectName, _ = strings.Cut(p, "/")
+	if bucketName == "" {
+		return "", "", errors.New("the path must start with a bucket name, as all buckets are mounted")
+	}
+	return bucketName, objectName, nil
+}
+
+// InvalidatePath drops the stat cache, type cache and file cache entries of
+// the supplied path, relative to the mount point, so that they are fetched
+// from GCS when next used. The path is treated as a prefix of object names:
+// "a/b" also matches "a/bc" and everything under "a/b/", while "a/b/" only
+// matches the latter. If the file system can notify the kernel, the kernel's
+// attributes, contents and directory entries of the matching inodes are
+// invalidated too.
+func (c *Controller) InvalidatePath(p string) error {
+	fs, err := c.fileSystem()
+	if err != nil {
+		return err
+	}
+	bucketName, objectName, err := c.splitPath(p)
+	if err != nil {
+		return err
+	}
+
+	fs.bucketManager.EraseStatCacheEntries(bucketName, objectName)
+
+	// Inode locks must not be acquired while holding fs.mu, so collect the
+	// inodes of the bucket first.
+	var dirs []inode.DirInode
+	var matches []inode.Inode
+	fs.mu.Lock()
+	for _, in := range fs.inodes {
+		if b, ok := in.(inode.BucketOwnedInode); !ok || b.Bucket().Name() != bucketName {
+			continue
+		}
+		if d, ok := in.(inode.DirInode); ok {
+			dirs = append(dirs, d)
+		}
+		if strings.HasPrefix(in.Name().GcsObjectName(), objectName) {
+			matches = append(matches, in)
+		}
+	}
+	fs.mu.Unlock()
+
+	for _, d := range dirs {
+		d.Lock()
+		eraseFromTypeCache(d, objectName)
+		d.Unlock()
+	}
+
+	if fs.fileCacheHandler != nil {
+		if err := fs.fileCacheHandler.InvalidateCacheWithGivenPrefix(objectName, bucketName); err != nil {
+			return err
+		}
+	}
+
+	if fs.invalidateKernelCaches {
+		notifyKernelOfInvalidation(fs.notifier, dirs, matches)
+	}
+	return nil
+}
+
+// eraseFromTypeCache erases the names matching the supplied object name
+// prefix from the type cache of the directory: all of them if the directory
+// is under the prefix, else those of its children starting with the last
+// component of the prefix, if the prefix is within the directory.
+//
+// LOCKS_REQUIRED(d)
+func eraseFromTypeCache(d inode.DirInode, objectName string) {
+	dirName := d.Name().GcsObjectName()
+	if strings.HasPrefix(dirName, objectName) {
+		d.EraseFromTypeCacheWithGivenPrefix("")
+		return
+	}
+	rest, ok := strings.CutPrefix(objectName, dirName)
+	if !ok {
+		return
+	}
+	if child, isDir := strings.CutSuffix(rest, "/"); isDir {
+		// Only the directory itself matches.
+		if !strings.Contains(child, "/") {
+			d.EraseFromTypeCache(child)
+		}
+	} else if !strings.Contains(rest, "/") {
+		d.EraseFromTypeCacheWithGivenPrefix(rest)
+	}
+}
+
+// notifyKernelOfInvalidation asks the kernel to drop the attributes and
+// contents it cached for the matching inodes, and their entries in the
+// directories, so that they are looked up again. Failures are logged, as the
+// kernel's caches expire anyway.
+//
+// LOCKS_EXCLUDED(fs.mu)
+// LOCKS_EXCLUDED(in) for each of dirs and matches
+func notifyKernelOfInvalidation(notifier *fuse.Notifier, dirs []inode.DirInode, matches []inode.Inode) {
+	dirIDs := make(map[string][]fuseops.InodeID, len(dirs))
+	for _, d := range dirs {
+		dirIDs[d.Name().GcsObjectName()] = append(dirIDs[d.Name().GcsObjectName()], d.ID())
+	}
+
+	for _, in := range matches {
+		if err := notifier.InvalidateInode(in.ID(), 0, 0); !ignored(err) {
+			logger.Warnf("Invalidating inode %d (%q) in the kernel: %v", in.ID(), in.Name().LocalName(), err)
+		}
+
+		objectName := strings.TrimSuffix(in.Name().GcsObjectName(), "/")
+		if objectName == "" {
+			// The root of the bucket.
+			continue
+		}
+		parent, child := path.Split(objectName)
+		for _, parentID := range dirIDs[parent] {
+			if err := notifier.InvalidateEntry(parentID, child); !ignored(err) {
+				logger.Warnf("Invalidating entry %q of inode %d in the kernel: %v", child, parentID, err)
+			}
+		}
+	}
+}
+
+// ignored returns true if the supplied error of a kernel notification can be
+// ignored: ENOENT means that the kernel didn't cache anything, and ENOSYS that
+// it doesn't support invalidations.
+func ignored(err error) bool {
+	return err == nil || errors.Is(err, syscall.ENOENT) || errors.Is(err, syscall.ENOSYS)
+}
+
+// InvalidateObject drops the stat cache, type cache and file cache entries of
+// the named object, which was created, updated or deleted in GCS by someone
+// else, so that it is fetched from GCS when next used. The stat cache and type
+// cache entries of its ancestor directories, which it may have implicitly
+// created or removed, are dropped too. If the file system can notify the
+// kernel, the kernel's attributes, contents and directory entries of the
+// object and its ancestors are invalidated too, including negative entries.
+// Objects of buckets and directories that aren't mounted are ignored.
+func (c *Controller) InvalidateObject(bucketName string, objectName string) error {
+	fs, err := c.fileSystem()
+	if err != nil {
+		return err
+	}
+	if c.bucketName != "" && bucketName != c.bucketName {
+		return nil
+	}
+	if c.onlyDir != "" {
+		var ok bool
+		if objectName, ok = strings.CutPrefix(objectName, c.onlyDir); !ok {
+			return nil
+		}
+	}
+	if objectName == "" {
+		return nil
+	}
+
+	// The names of the ancestor directories, e.g. "", "a/" and "a/b/" for
+	// "a/b/c".
+	ancestors := []string{""}
+	for i := 0; i < len(objectName)-1; i++ {
+		if objectName[i] == '/' {
+			ancestors = append(ancestors, objectName[:i+1])
+		}
+	}
+
+	fs.bucketManager.EraseStatCacheEntry(bucketName, objectName)
+	for _, a := range ancestors[1:] {
+		fs.bucketManager.EraseStatCacheEntry(bucketName, a)
+	}
+
+	// Inode locks must not be acquired while holding fs.mu, so collect the
+	// inodes of the object and its ancestors first.
+	var dirs []inode.DirInode
+	var matches []inode.Inode
+	fs.mu.Lock()
+	for _, in := range fs.inodes {
+		if b, ok := in.(inode.BucketOwnedInode); !ok || b.Bucket().Name() != bucketName {
+			continue
+		}
+		name := in.Name().GcsObjectName()
+		if d, ok := in.(inode.DirInode); ok && slices.Contains(ancestors, name) {
+			dirs = append(dirs, d)
+			matches = append(matches, in)
+		} else if name == objectName {
+			matches = append(matches, in)
+		}
+	}
+	fs.mu.Unlock()
+
+	// The name of the child of each directory that leads to the object.
+	childOf := func(d inode.DirInode) string {
+		child, _, _ := strings.Cut(strings.TrimPrefix(objectName, d.Name().GcsObjectName()), "/")
+		return child
+	}
+	for _, d := range dirs {
+		d.Lock()
+		d.EraseFromTypeCache(childOf(d))
+		d.Unlock()
+	}
+
+	if fs.fileCacheHandler != nil {
+		if err := fs.fileCacheHandler.InvalidateCache(objectName, bucketName); err != nil {
+			return err
+		}
+	}
+
+	if fs.invalidateKernelCaches {
+		for _, in := range matches {
+			if err := fs.notifier.InvalidateInode(in.ID(), 0, 0); !ignored(err) {
+				logger.Warnf("Invalidating inode %d (%q) in the kernel: %v", in.ID(), in.Name().LocalName(), err)
+			}
+		}
+		for _, d := range dirs {
+			if err := fs.notifier.InvalidateEntry(d.ID(), childOf(d)); !ignored(err) {
+				logger.Warnf("Invalidating entry %q of inode %d in the kernel: %v", childOf(d), d.ID(), err)
+			}
+		}
+	}
+	return nil
+}
+
+// EvictCaches drops all the entries of the stat cache and the file cache of
+// the buckets.
+func (c *Controller) EvictCaches() error {
+	fs, err := c.fileSystem()
+	if err != nil {
+		return err
+	}
+
+	fs.bucketManager.EraseStatCacheEntries("", "")
+	if fs.fileCacheHandler != nil {
+		return fs.fileCacheHandler.InvalidateCacheWithGivenPrefix("", "")
+	}
+	return nil
+}
+
+// FlushAll uploads the contents of all files open for writing to GCS, like
+// syncfs(2).
+func (c *Controller) FlushAll(ctx context.Context) error {
+	fs, err := c.fileSystem()
+	if err != nil {
+		return err
+	}
+	return fs.SyncFS(ctx, &fuseops.SyncFSOp{})
+}
+
+// Prefetch starts downloading the objects matching the supplied paths,
+// relative to the mount point, and those listed in the supplied manifest file,
+// if any, into the file cache in the background. A path matches the objects
+// whose names start with it, unless it holds glob characters, in which case
+// it is matched with path.Match. Progress is reported through metrics and a
+// summary is logged once done.
+func (c *Controller) Prefetch(ctx context.Context, paths []string, manifest string) error {
+	fs, err := c.fileSystem()
+	if err != nil {
+		return err
+	}
+	if manifest != "" {
+		manifestPaths, err := readPrefetchManifest(manifest)
+		if err != nil {
+			return err
+		}
+		paths = append(slices.Clip(paths), manifestPaths...)
+	}
+	return fs.startPrefetch(ctx, paths)
+}
+
+////////////////////////////////////////////////////////////////////////
+// blockLimit
+////////////////////////////////////////////////////////////////////////
+
+// blockLimit is a limit on the number of blocks that can be allocated across
+// the file system, enforced by a semaphore that can be resized.
+//
+// semaphore.Weighted has a fixed size, so the semaphore is as large as can be
+// and the limit is enforced by holding back the weight above it.
+type blockLimit struct {
+	sem *semaphore.Weighted
+
+	mu sync.Mutex
+
+	// The weight of sem held back, and the weight to be held back for the
+	// current limit.
+	//
+	// GUARDED_BY(mu)
+	reserved int64
+	target   int64
+
+	// Whether a goroutine is waiting for blocks to be released to lower the
+	// limit, and the function to make it stop waiting when the target changes.
+	//
+	// GUARDED_BY(mu)
+	lowering       bool
+	cancelLowering context.CancelFunc
+}
+
+func newBlockLimit(limit int64) *blockLimit {
+	l := &blockLimit{
+		sem:      semaphore.NewWeighted(math.MaxInt64),
+		reserved: math.MaxInt64 - limit,
+		target:   math.MaxInt64 - limit,
+	}
+	// The semaphore is unused yet, so this doesn't block.
+	_ = l.sem.Acquire(context.Background(), l.reserved)
+	return l
+}
+
+// set changes the limit. Raising it takes effect right away. Lowering it holds
+// back the blocks released until the number of blocks allocated is below the
+// new limit, without blocking the caller.
+func (l *blockLimit) set(limit int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.target = math.MaxInt64 - limit
+	if l.cancelLowering != nil {
+		l.cancelLowering()
+	}
+	if l.target < l.reserved {
+		l.sem.Release(l.reserved - l.target)
+		l.reserved = l.target
+	} else if l.target > l.reserved && !l.lowering {
+		l.lowering = true
+		go l.lower()
+	}
+}
+
+// lower holds back weight until the target is reached, following the changes
+// of the target made meanwhile.
+func (l *blockLimit) lower() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for l.reserved < l.target {
+		need := l.target - l.reserved
+		ctx, cancel := context.WithCancel(context.Background())
+		l.cancelLowering = cancel
+		l.mu.Unlock()
+		err := l.sem.Acquire(ctx, need)
+		cancel()
+		l.mu.Lock()
+		l.cancelLowering = nil
+
+		// Start over if the target has changed meanwhile.
+		if err != nil {
+			continue
+		}
+		l.reserved += need
+		if l.reserved > l.target {
+			l.sem.Release(l.reserved - l.target)
+			l.reserved = l.target
+		}
+	}
+	l.lowering = false
+}
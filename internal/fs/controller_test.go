@@ -0,0 +1,60 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockLimitRaise(t *testing.T) {
+	l := newBlockLimit(2)
+	require.True(t, l.sem.TryAcquire(2))
+	require.False(t, l.sem.TryAcquire(1))
+
+	l.set(3)
+
+	assert.True(t, l.sem.TryAcquire(1))
+	assert.False(t, l.sem.TryAcquire(1))
+}
+
+func TestBlockLimitLowerWaitsForRelease(t *testing.T) {
+	l := newBlockLimit(3)
+	require.True(t, l.sem.TryAcquire(2))
+
+	l.set(1)
+
+	// New blocks can't be allocated while more than the limit are.
+	assert.Never(t, func() bool { return l.sem.TryAcquire(1) }, 50*time.Millisecond, time.Millisecond)
+	l.sem.Release(2)
+	assert.Eventually(t, func() bool { return l.sem.TryAcquire(1) }, time.Second, time.Millisecond)
+	assert.False(t, l.sem.TryAcquire(1))
+}
+
+func TestBlockLimitRaiseWhileLowering(t *testing.T) {
+	l := newBlockLimit(3)
+	require.True(t, l.sem.TryAcquire(3))
+	l.set(1)
+	// Let the limit start waiting for blocks to be released.
+	time.Sleep(10 * time.Millisecond)
+
+	l.set(4)
+
+	assert.Eventually(t, func() bool { return l.sem.TryAcquire(1) }, time.Second, time.Millisecond)
+	assert.False(t, l.sem.TryAcquire(1))
+}
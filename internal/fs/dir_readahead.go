@@ -0,0 +1,247 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fs
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/logger"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/metrics"
+	"golang.org/x/sync/semaphore"
+)
+
+// The number of files of a directory that must be opened one after the other
+// in lexical order before the following ones are prefetched.
+const dirReadaheadTrigger = 2
+
+// The maximum number of directories whose opens are tracked at a time. The
+// least recently used one is forgotten to make room for another.
+const dirReadaheadMaxDirs = 64
+
+type dirReadaheadKey struct {
+	bucketName string
+
+	// The name of the directory, ending with a slash unless it is the root.
+	dirName string
+}
+
+// dirReadaheadState tracks the opens of the files of a directory.
+type dirReadaheadState struct {
+	lastUse uint64
+
+	// The name of the file last opened, relative to the directory, and the
+	// number of files opened in lexical order up to it.
+	lastOpened string
+	run        int
+
+	// The files of the directory in lexical order, as last listed.
+	files []*gcs.MinObject
+
+	// The name of the last file queued for prefetching during the run, if any.
+	prefetchedUpTo string
+
+	// Whether a goroutine is listing the directory.
+	listing bool
+}
+
+// dirReadahead prefetches the next files of a directory into the file cache
+// when its files are opened one after the other in lexical order, the order
+// in which directories are listed, as data loaders reading shards do.
+type dirReadahead struct {
+	// Cancelled on unmount.
+	ctx context.Context
+
+	// Counts the goroutines listing and prefetching, waited for on unmount.
+	wg *sync.WaitGroup
+
+	// The number of files to prefetch ahead of the last one opened.
+	files int
+
+	// Limits the number of files prefetched at a time.
+	sem *semaphore.Weighted
+
+	// Downloads an object, counted as pending, into the file cache.
+	prefetch     func(context.Context, prefetchObject) metrics.Status
+	metricHandle metrics.MetricHandle
+
+	mu sync.Mutex
+
+	// GUARDED_BY(mu)
+	uses uint64
+	dirs map[dirReadaheadKey]*dirReadaheadState
+}
+
+func newDirReadahead(
+	ctx context.Context,
+	wg *sync.WaitGroup,
+	files int,
+	concurrency int64,
+	prefetch func(context.Context, prefetchObject) metrics.Status,
+	metricHandle metrics.MetricHandle) *dirReadahead {
+	return &dirReadahead{
+		ctx:          ctx,
+		wg:           wg,
+		files:        files,
+		sem:          semaphore.NewWeighted(concurrency),
+		prefetch:     prefetch,
+		metricHandle: metricHandle,
+		dirs:         make(map[dirReadaheadKey]*dirReadaheadState),
+	}
+}
+
+// fileOpened records that the supplied object was opened for reading. If it
+// continues a run of files of its directory opened in lexical order, the files
+// following it are prefetched in the background.
+func (r *dirReadahead) fileOpened(bucket gcs.Bucket, object *gcs.MinObject) {
+	dirName, name := path.Split(object.Name)
+	key := dirReadaheadKey{bucketName: bucket.Name(), dirName: dirName}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s := r.state(key)
+	if s.lastOpened != "" && name > s.lastOpened {
+		s.run++
+	} else {
+		s.run = 1
+		s.prefetchedUpTo = ""
+	}
+	s.lastOpened = name
+	if s.run < dirReadaheadTrigger || s.listing {
+		return
+	}
+
+	s.listing = true
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		r.readahead(bucket, key, s, object)
+	}()
+}
+
+// state returns the state of the supplied directory, tracking it if it isn't.
+//
+// LOCKS_REQUIRED(r.mu)
+func (r *dirReadahead) state(key dirReadaheadKey) *dirReadaheadState {
+	r.uses++
+	s, ok := r.dirs[key]
+	if !ok {
+		if len(r.dirs) >= dirReadaheadMaxDirs {
+			var lru dirReadaheadKey
+			var lruUse uint64
+			for k, other := range r.dirs {
+				if lruUse == 0 || other.lastUse < lruUse {
+					lru, lruUse = k, other.lastUse
+				}
+			}
+			delete(r.dirs, lru)
+		}
+		s = &dirReadaheadState{}
+		r.dirs[key] = s
+	}
+	s.lastUse = r.uses
+	return s
+}
+
+// readahead queues the files following the supplied object in its directory
+// for prefetching, up to r.files of them. The directory is listed again if the
+// object isn't among its files as last listed.
+//
+// LOCKS_EXCLUDED(r.mu)
+func (r *dirReadahead) readahead(bucket gcs.Bucket, key dirReadaheadKey, s *dirReadaheadState, object *gcs.MinObject) {
+	r.mu.Lock()
+	files := s.files
+	r.mu.Unlock()
+
+	after, listed := fileIndexAfter(files, object)
+	if !listed {
+		var err error
+		files, err = listDirFiles(r.ctx, bucket, key.dirName)
+		if err != nil {
+			logger.Warnf("Directory readahead: %v", err)
+			r.mu.Lock()
+			s.listing = false
+			r.mu.Unlock()
+			return
+		}
+		after, _ = fileIndexAfter(files, object)
+	}
+
+	r.mu.Lock()
+	s.files = files
+	s.listing = false
+	start, end := after, min(len(files), after+r.files)
+	for start < end && path.Base(files[start].Name) <= s.prefetchedUpTo {
+		start++
+	}
+	next := files[start:end]
+	if len(next) > 0 {
+		s.prefetchedUpTo = path.Base(next[len(next)-1].Name)
+	}
+	r.mu.Unlock()
+
+	for _, o := range next {
+		r.metricHandle.FileCachePrefetchPendingCount(1)
+		r.wg.Add(1)
+		go func() {
+			defer r.wg.Done()
+			if err := r.sem.Acquire(r.ctx, 1); err != nil {
+				r.metricHandle.FileCachePrefetchPendingCount(-1)
+				return
+			}
+			defer r.sem.Release(1)
+			r.prefetch(r.ctx, prefetchObject{bucket: bucket, object: o})
+		}()
+	}
+}
+
+// fileIndexAfter returns the index of the first of the supplied files, sorted
+// by name, that comes after the supplied object, and whether the object is
+// among them with the same generation.
+func fileIndexAfter(files []*gcs.MinObject, object *gcs.MinObject) (int, bool) {
+	i := sort.Search(len(files), func(i int) bool { return files[i].Name >= object.Name })
+	if i < len(files) && files[i].Name == object.Name {
+		return i + 1, files[i].Generation == object.Generation
+	}
+	return i, false
+}
+
+// listDirFiles returns the files directly in the named directory, in lexical
+// order.
+func listDirFiles(ctx context.Context, bucket gcs.Bucket, dirName string) ([]*gcs.MinObject, error) {
+	req := &gcs.ListObjectsRequest{Prefix: dirName, Delimiter: "/"}
+	var files []*gcs.MinObject
+	for {
+		listing, err := bucket.ListObjects(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("listing %q in bucket %q: %w", dirName, bucket.Name(), err)
+		}
+		for _, o := range listing.MinObjects {
+			if !strings.HasSuffix(o.Name, "/") {
+				files = append(files, o)
+			}
+		}
+		if listing.ContinuationToken == "" {
+			return files, nil
+		}
+		req.ContinuationToken = listing.ContinuationToken
+	}
+}
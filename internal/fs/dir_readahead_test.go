@@ -0,0 +1,175 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fs
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/fake"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"github.com/googlecloudplatform/gcsfuse/v3/metrics"
+	"github.com/jacobsa/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type prefetchRecorder struct {
+	mu    sync.Mutex
+	names []string
+}
+
+func (p *prefetchRecorder) prefetch(_ context.Context, o prefetchObject) metrics.Status {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.names = append(p.names, o.object.Name)
+	return metrics.StatusCompletedAttr
+}
+
+func (p *prefetchRecorder) prefetched() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	names := slices.Clone(p.names)
+	slices.Sort(names)
+	return names
+}
+
+type dirReadaheadTest struct {
+	bucket    gcs.Bucket
+	recorder  *prefetchRecorder
+	wg        *sync.WaitGroup
+	readahead *dirReadahead
+}
+
+func newDirReadaheadTest(t *testing.T, files int) *dirReadaheadTest {
+	t.Helper()
+	bucket := fake.NewFakeBucket(timeutil.RealClock(), "some-bucket", gcs.BucketType{})
+	contents := map[string][]byte{"shards/": nil, "shards/sub/a": []byte("taco"), "other": []byte("taco")}
+	for i := range 10 {
+		contents[fmt.Sprintf("shards/%02d", i)] = []byte("taco")
+	}
+	require.NoError(t, storageutil.CreateObjects(context.Background(), bucket, contents))
+	recorder := &prefetchRecorder{}
+	wg := &sync.WaitGroup{}
+	return &dirReadaheadTest{
+		bucket:    bucket,
+		recorder:  recorder,
+		wg:        wg,
+		readahead: newDirReadahead(context.Background(), wg, files, 4, recorder.prefetch, metrics.NewNoopMetrics()),
+	}
+}
+
+// open records the opening of the named object, and waits for the directory
+// listing it may trigger.
+func (d *dirReadaheadTest) open(t *testing.T, name string) {
+	t.Helper()
+	m, _, err := d.bucket.StatObject(context.Background(), &gcs.StatObjectRequest{Name: name})
+	require.NoError(t, err)
+	d.readahead.fileOpened(d.bucket, m)
+	require.Eventually(t, func() bool {
+		d.readahead.mu.Lock()
+		defer d.readahead.mu.Unlock()
+		for _, s := range d.readahead.dirs {
+			if s.listing {
+				return false
+			}
+		}
+		return true
+	}, time.Second, time.Millisecond)
+}
+
+func (d *dirReadaheadTest) assertPrefetched(t *testing.T, expected ...string) {
+	t.Helper()
+	assert.EventuallyWithT(t, func(c *assert.CollectT) {
+		assert.Equal(c, expected, d.recorder.prefetched())
+	}, time.Second, time.Millisecond)
+}
+
+func TestDirReadaheadPrefetchesFollowingFiles(t *testing.T) {
+	d := newDirReadaheadTest(t, 3)
+
+	d.open(t, "shards/00")
+	assert.Empty(t, d.recorder.prefetched())
+	d.open(t, "shards/01")
+	d.assertPrefetched(t, "shards/02", "shards/03", "shards/04")
+	// The window moves along, without prefetching files twice.
+	d.open(t, "shards/02")
+	d.assertPrefetched(t, "shards/02", "shards/03", "shards/04", "shards/05")
+}
+
+func TestDirReadaheadGoroutinesCanBeWaitedFor(t *testing.T) {
+	d := newDirReadaheadTest(t, 3)
+	m, _, err := d.bucket.StatObject(context.Background(), &gcs.StatObjectRequest{Name: "shards/00"})
+	require.NoError(t, err)
+	d.readahead.fileOpened(d.bucket, m)
+	m, _, err = d.bucket.StatObject(context.Background(), &gcs.StatObjectRequest{Name: "shards/01"})
+	require.NoError(t, err)
+	d.readahead.fileOpened(d.bucket, m)
+
+	d.wg.Wait()
+
+	assert.Equal(t, []string{"shards/02", "shards/03", "shards/04"}, d.recorder.prefetched())
+}
+
+func TestDirReadaheadStopsAtEndOfDirectory(t *testing.T) {
+	d := newDirReadaheadTest(t, 3)
+
+	d.open(t, "shards/07")
+	d.open(t, "shards/08")
+
+	d.assertPrefetched(t, "shards/09")
+}
+
+func TestDirReadaheadIgnoresOpensOutOfOrder(t *testing.T) {
+	d := newDirReadaheadTest(t, 3)
+
+	d.open(t, "shards/05")
+	d.open(t, "shards/02")
+	d.open(t, "other")
+
+	time.Sleep(10 * time.Millisecond)
+	assert.Empty(t, d.recorder.prefetched())
+}
+
+func TestDirReadaheadListsNewFiles(t *testing.T) {
+	d := newDirReadaheadTest(t, 1)
+	d.open(t, "shards/08")
+	d.open(t, "shards/09")
+	_, err := storageutil.CreateObject(context.Background(), d.bucket, "shards/10", []byte("taco"))
+	require.NoError(t, err)
+	_, err = storageutil.CreateObject(context.Background(), d.bucket, "shards/11", []byte("taco"))
+	require.NoError(t, err)
+
+	d.open(t, "shards/10")
+
+	d.assertPrefetched(t, "shards/11")
+}
+
+func TestDirReadaheadForgetsLeastRecentlyUsedDirs(t *testing.T) {
+	d := newDirReadaheadTest(t, 1)
+	d.open(t, "shards/00")
+
+	for i := range dirReadaheadMaxDirs {
+		d.readahead.fileOpened(d.bucket, &gcs.MinObject{Name: fmt.Sprintf("dir%d/a", i)})
+	}
+
+	assert.Len(t, d.readahead.dirs, dirReadaheadMaxDirs)
+	assert.NotContains(t, d.readahead.dirs, dirReadaheadKey{bucketName: "some-bucket", dirName: "shards/"})
+}
@@ -0,0 +1,102 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// A collection of tests for fallocate on files staged in a local temp file.
+package fs_test
+
+import (
+	"os"
+	"path"
+	"syscall"
+	"testing"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/sys/unix"
+)
+
+type FallocateTest struct {
+	suite.Suite
+	fsTest
+}
+
+func TestFallocateTestSuite(t *testing.T) {
+	suite.Run(t, new(FallocateTest))
+}
+
+func (t *FallocateTest) SetupSuite() {
+	t.fsTest.SetUpTestSuite()
+}
+
+func (t *FallocateTest) TearDownTest() {
+	t.fsTest.TearDown()
+}
+
+func (t *FallocateTest) TearDownSuite() {
+	t.fsTest.TearDownTestSuite()
+}
+
+func (t *FallocateTest) openFile(name string) *os.File {
+	_, err := storageutil.CreateObject(ctx, bucket, name, []byte("taco"))
+	require.NoError(t.T(), err)
+	f, err := os.OpenFile(path.Join(mntDir, name), os.O_RDWR, 0)
+	require.NoError(t.T(), err)
+	t.T().Cleanup(func() { f.Close() })
+	return f
+}
+
+func (t *FallocateTest) TestFallocateExtendsFileWithZeros() {
+	f := t.openFile("foo")
+
+	err := unix.Fallocate(int(f.Fd()), 0, 0, 6)
+
+	require.NoError(t.T(), err)
+	require.NoError(t.T(), f.Close())
+	contents, err := storageutil.ReadObject(ctx, bucket, "foo")
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), "taco\x00\x00", string(contents))
+}
+
+func (t *FallocateTest) TestFallocateKeepSize() {
+	f := t.openFile("foo")
+
+	err := unix.Fallocate(int(f.Fd()), unix.FALLOC_FL_KEEP_SIZE, 0, 100)
+
+	require.NoError(t.T(), err)
+	fi, err := f.Stat()
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), int64(4), fi.Size())
+}
+
+func (t *FallocateTest) TestPunchHole() {
+	f := t.openFile("foo")
+
+	err := unix.Fallocate(int(f.Fd()), unix.FALLOC_FL_PUNCH_HOLE|unix.FALLOC_FL_KEEP_SIZE, 1, 2)
+
+	require.NoError(t.T(), err)
+	require.NoError(t.T(), f.Close())
+	contents, err := storageutil.ReadObject(ctx, bucket, "foo")
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), "t\x00\x00o", string(contents))
+}
+
+func (t *FallocateTest) TestUnsupportedMode() {
+	f := t.openFile("foo")
+
+	err := unix.Fallocate(int(f.Fd()), unix.FALLOC_FL_ZERO_RANGE, 0, 2)
+
+	assert.ErrorIs(t.T(), err, syscall.EOPNOTSUPP)
+}
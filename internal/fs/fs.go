@@ -25,6 +25,8 @@ import (
 	"path"
 	"reflect"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -33,11 +35,15 @@ import (
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/workerpool"
 	"github.com/googlecloudplatform/gcsfuse/v3/metrics"
 
+	"golang.org/x/sync/errgroup"
 	"golang.org/x/sync/semaphore"
+	"golang.org/x/sys/unix"
 
 	"go.opentelemetry.io/otel/trace"
 
 	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/bufferedread"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/bufferedwrites"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/file"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/file/downloader"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/lru"
@@ -68,6 +74,10 @@ type ServerConfig struct {
 	// all accessible GCS buckets are mounted as subdirectories of the FS root.
 	BucketName string
 
+	// The directory the file system is mounted on, which distinguishes the
+	// state it keeps under the cache directory from that of other mounts.
+	MountPoint string
+
 	// LocalFileCache
 	LocalFileCache bool
 
@@ -141,6 +151,10 @@ type ServerConfig struct {
 	// when underlying content changes, improving consistency while still leveraging
 	// kernel caching.
 	Notifier *fuse.Notifier
+
+	// If set, Controller is bound to the file system, so that it can be
+	// inspected and settings can be changed while it is mounted.
+	Controller *Controller
 }
 
 // Create a fuse file system server according to the supplied configuration.
@@ -186,8 +200,6 @@ func NewFileSystem(ctx context.Context, serverCfg *ServerConfig) (fuseutil.FileS
 		contentCache:               contentCache,
 		implicitDirs:               serverCfg.ImplicitDirectories,
 		enableNonexistentTypeCache: serverCfg.EnableNonexistentTypeCache,
-		inodeAttributeCacheTTL:     serverCfg.InodeAttributeCacheTTL,
-		dirTypeCacheTTL:            serverCfg.DirTypeCacheTTL,
 		kernelListCacheTTL:         cfg.ListCacheTTLSecsToDuration(serverCfg.NewConfig.FileSystem.KernelListCacheTtlSecs),
 		renameDirLimit:             serverCfg.RenameDirLimit,
 		sequentialReadSizeMb:       serverCfg.SequentialReadSizeMb,
@@ -201,6 +213,7 @@ func NewFileSystem(ctx context.Context, serverCfg *ServerConfig) (fuseutil.FileS
 		implicitDirInodes:          make(map[inode.Name]inode.DirInode),
 		folderInodes:               make(map[inode.Name]inode.DirInode),
 		localFileInodes:            make(map[inode.Name]inode.Inode),
+		versionInodes:              make(map[inode.Name]inode.Inode),
 		handles:                    make(map[fuseops.HandleID]any),
 		newConfig:                  serverCfg.NewConfig,
 		fileCacheHandler:           fileCacheHandler,
@@ -208,11 +221,32 @@ func NewFileSystem(ctx context.Context, serverCfg *ServerConfig) (fuseutil.FileS
 		metricHandle:               serverCfg.MetricHandle,
 		enableAtomicRenameObject:   serverCfg.NewConfig.EnableAtomicRenameObject,
 		isTracingEnabled:           cfg.IsTracingEnabled(serverCfg.NewConfig),
-		globalMaxWriteBlocksSem:    semaphore.NewWeighted(serverCfg.NewConfig.Write.GlobalMaxBlocks),
-		globalMaxReadBlocksSem:     semaphore.NewWeighted(serverCfg.NewConfig.Read.GlobalMaxBlocks),
+		blockLimits:                newBlockLimits(serverCfg.NewConfig.Read.GlobalMaxBlocks, serverCfg.NewConfig.Write.GlobalMaxBlocks, serverCfg.MetricHandle),
 	}
-	if serverCfg.Notifier != nil {
-		fs.notifier = serverCfg.Notifier
+	if fs.fileCacheHandler != nil {
+		fs.prefetchCtx, fs.stopPrefetching = context.WithCancel(context.Background())
+		fs.prefetchConcurrency = serverCfg.NewConfig.FileCache.PrefetchConcurrency
+		if files := serverCfg.NewConfig.FileCache.DirReadaheadFiles; files > 0 {
+			fs.dirReadahead = newDirReadahead(fs.prefetchCtx, &fs.prefetches, int(files), fs.prefetchConcurrency, fs.prefetchObject, fs.metricHandle)
+		}
+	}
+	fs.watchMemoryPressure(&serverCfg.NewConfig.MemoryPressure)
+	if serverCfg.NewConfig.Trash.Enable {
+		fs.trash = gcsx.NewTrash(serverCfg.NewConfig.Trash.Prefix, fs.enableAtomicRenameObject, fs.mtimeClock)
+	}
+	fs.inodeAttributeCacheTTL.Store(int64(serverCfg.InodeAttributeCacheTTL))
+	fs.dirTypeCacheTTL.Store(int64(serverCfg.DirTypeCacheTTL))
+	fs.globalMaxWriteBlocksSem = fs.blockLimits.write.sem
+	fs.globalMaxReadBlocksSem = fs.blockLimits.read.sem
+	fs.readBlockShares = fs.blockLimits.readShares
+	fs.notifier = serverCfg.Notifier
+	fs.invalidateKernelCaches = fs.notifier != nil && serverCfg.NewConfig.FileSystem.InvalidateKernelCaches
+	if fs.invalidateKernelCaches {
+		// Check the objects fetched from GCS for lookups and listings against
+		// their inodes, to invalidate the kernel's caches of stale ones.
+		fs.generationChecks = make(chan generationCheck, generationCheckQueueLen)
+		fs.stopGenerationChecks = make(chan struct{})
+		fs.bucketManager.SetStatCacheObserver(fs.queueGenerationCheck)
 	}
 
 	if serverCfg.NewConfig.Read.EnableBufferedRead {
@@ -235,6 +269,27 @@ func NewFileSystem(ctx context.Context, serverCfg *ServerConfig) (fuseutil.FileS
 			return nil, fmt.Errorf("SetUpBucket: %w", err)
 		}
 		root = makeRootForBucket(fs, syncerBucket)
+
+		if fs.fileCacheHandler != nil && serverCfg.NewConfig.FileCache.EnableJournal {
+			if err := fs.fileCacheHandler.RecoverFromJournal(ctx, syncerBucket); err != nil {
+				logger.Warnf("Not journaling the file cache: %v", err)
+			}
+		}
+
+		if serverCfg.NewConfig.Write.EnableUploadJournal {
+			if err := fs.recoverUploads(ctx, syncerBucket, serverCfg.NewConfig, serverCfg.MountPoint); err != nil {
+				logger.Warnf("Not journaling streaming uploads: %v", err)
+			}
+		}
+
+		if serverCfg.NewConfig.MetadataCache.EnablePersistence {
+			fs.metadataCachePersister = &metadataCachePersister{
+				path:       path.Join(string(serverCfg.NewConfig.CacheDir), cacheutil.MetadataCache, mountStateFileName(serverCfg.BucketName, serverCfg.NewConfig.OnlyDir, serverCfg.MountPoint, ".json")),
+				bucketName: serverCfg.BucketName,
+				configHash: metadataCacheConfigHash(serverCfg.NewConfig),
+			}
+			fs.restoreMetadataCaches(root)
+		}
 	}
 	root.Lock()
 	root.IncrementLookupCount()
@@ -245,6 +300,24 @@ func NewFileSystem(ctx context.Context, serverCfg *ServerConfig) (fuseutil.FileS
 
 	// Set up invariant checking.
 	fs.mu = locker.New("FS", fs.checkInvariants)
+
+	if fs.generationChecks != nil {
+		go fs.checkGenerations()
+	}
+
+	if err := fs.prefetchOnMount(ctx); err != nil {
+		return nil, fmt.Errorf("prefetching into the file cache: %w", err)
+	}
+
+	if serverCfg.Controller != nil {
+		serverCfg.Controller.fs = fs
+		if serverCfg.BucketName != "_" {
+			serverCfg.Controller.bucketName = serverCfg.BucketName
+		}
+		if serverCfg.NewConfig.OnlyDir != "" {
+			serverCfg.Controller.onlyDir = path.Clean(serverCfg.NewConfig.OnlyDir) + "/"
+		}
+	}
 	return fs, nil
 }
 
@@ -257,12 +330,17 @@ func createFileCacheHandler(serverCfg *ServerConfig) (fileCacheHandler *file.Cac
 	} else {
 		sizeInBytes = uint64(serverCfg.NewConfig.FileCache.MaxSizeMb) * cacheutil.MiB
 	}
-	fileInfoCache := lru.NewCache(sizeInBytes)
+	policy, err := lru.NewPolicy(serverCfg.NewConfig.FileCache.EvictionPolicy)
+	if err != nil {
+		return nil, fmt.Errorf("createFileCacheHandler: %w", err)
+	}
+	fileInfoCache := lru.NewCacheWithPolicy(sizeInBytes, policy, func(count int) {
+		serverCfg.MetricHandle.CacheEvictionCount(int64(count), metrics.CacheNameFileAttr, metrics.EvictionPolicy(lru.PolicyName(policy)))
+	})
 
 	cacheDir := string(serverCfg.NewConfig.CacheDir)
 	// Adding a new directory inside cacheDir to keep file-cache separate from
-	// metadata cache if and when we support storing metadata cache on disk in
-	// the future.
+	// the persisted metadata cache.
 	cacheDir = path.Join(cacheDir, cacheutil.FileCache)
 
 	filePerm := cacheutil.DefaultFilePerm
@@ -275,6 +353,11 @@ func createFileCacheHandler(serverCfg *ServerConfig) (fileCacheHandler *file.Cac
 
 	jobManager := downloader.NewJobManager(fileInfoCache, filePerm, dirPerm, cacheDir, serverCfg.SequentialReadSizeMb, &serverCfg.NewConfig.FileCache, serverCfg.MetricHandle)
 	fileCacheHandler = file.NewCacheHandler(fileInfoCache, jobManager, cacheDir, filePerm, dirPerm, serverCfg.NewConfig.FileCache.ExcludeRegex, serverCfg.NewConfig.FileCache.IncludeRegex)
+	if serverCfg.NewConfig.FileCache.EnableSharedCache {
+		if err = fileCacheHandler.ShareCacheDir(sizeInBytes); err != nil {
+			return nil, fmt.Errorf("createFileCacheHandler: %w", err)
+		}
+	}
 	return
 }
 
@@ -297,7 +380,7 @@ func makeRootForBucket(
 		fs.implicitDirs,
 		fs.newConfig.List.EnableEmptyManagedFolders,
 		fs.enableNonexistentTypeCache,
-		fs.dirTypeCacheTTL,
+		time.Duration(fs.dirTypeCacheTTL.Load()),
 		&syncerBucket,
 		fs.mtimeClock,
 		fs.cacheClock,
@@ -371,8 +454,12 @@ type fileSystem struct {
 	contentCache               *contentcache.ContentCache
 	implicitDirs               bool
 	enableNonexistentTypeCache bool
-	inodeAttributeCacheTTL     time.Duration
-	dirTypeCacheTTL            time.Duration
+
+	// The TTLs of the attributes cached by the kernel and of the type caches of
+	// new directory inodes, as time.Durations. They can be changed while
+	// mounted, through Controller.
+	inodeAttributeCacheTTL atomic.Int64
+	dirTypeCacheTTL        atomic.Int64
 
 	// kernelListCacheTTL specifies the duration to keep the readdir response cached
 	// in kernel. After ttl, gcsfuse, (filesystem) on next opendir call (just before as part
@@ -457,7 +544,8 @@ type fileSystem struct {
 	// INVARIANT: For each value v, inodes[v.ID()] == v
 	// INVARIANT: For each value v, v is not ExplicitDirInode
 	// INVARIANT: For each in in inodes such that in is DirInode but not
-	//            ExplicitDirInode, implicitDirInodes[d.Name()] == d
+	//            ExplicitDirInode or VersionsDirInode,
+	//            implicitDirInodes[d.Name()] == d
 	//
 	// GUARDED_BY(mu)
 	implicitDirInodes map[inode.Name]inode.DirInode
@@ -485,9 +573,21 @@ type fileSystem struct {
 	// GUARDED_BY(mu)
 	localFileInodes map[inode.Name]inode.Inode
 
+	// A map from name to the versions directory inode or version inode that
+	// represents that name, if any. Both are only created when versions
+	// directories are enabled.
+	//
+	// INVARIANT: For each k/v, v.Name() == k
+	// INVARIANT: For each value v, inodes[v.ID()] == v
+	// INVARIANT: Each value is *inode.VersionsDirInode or *inode.VersionInode
+	//
+	// GUARDED_BY(mu)
+	versionInodes map[inode.Name]inode.Inode
+
 	// The collection of live handles, keyed by handle ID.
 	//
-	// INVARIANT: All values are of type *dirHandle or *handle.FileHandle
+	// INVARIANT: All values are of type *dirHandle, *handle.FileHandle or
+	//            *inode.VersionInode
 	//
 	// GUARDED_BY(mu)
 	handles map[fuseops.HandleID]any
@@ -499,6 +599,18 @@ type fileSystem struct {
 	// GUARDED_BY(mu)
 	nextHandleID fuseops.HandleID
 
+	// Saves the stat and type caches on unmount for the next mount of the
+	// bucket to reload, or nil if metadata cache persistence is disabled or all
+	// buckets are mounted.
+	metadataCachePersister *metadataCachePersister
+
+	// Type cache entries reloaded from a snapshot for directories that haven't
+	// been looked up yet, keyed by directory name. Each is consumed when the
+	// inode for its directory is minted.
+	//
+	// GUARDED_BY(mu)
+	pendingTypeCacheEntries map[string][]metadata.TypeCacheSnapshotEntry
+
 	// newConfig specified by the user using config-file flag and CLI flags.
 	newConfig *cfg.Config
 
@@ -510,21 +622,65 @@ type fileSystem struct {
 	// random file access.
 	cacheFileForRangeRead bool
 
+	// Objects are prefetched into the file cache, prefetchConcurrency at a
+	// time, until prefetchCtx is cancelled on unmount. Both are unset unless
+	// fileCacheHandler is.
+	prefetchCtx         context.Context
+	stopPrefetching     context.CancelFunc
+	prefetchConcurrency int64
+
+	// Counts the goroutines prefetching into the file cache, which are waited
+	// for on unmount before the file cache is destroyed.
+	prefetches sync.WaitGroup
+
+	// Prefetches the files of directories read in lexical order into the file
+	// cache, or nil if directory readahead is disabled.
+	dirReadahead *dirReadahead
+
 	metricHandle metrics.MetricHandle
 
 	enableAtomicRenameObject bool
 
+	// Where unlinked objects are moved instead of being deleted, or nil if
+	// trash is disabled.
+	trash *gcsx.Trash
+
 	isTracingEnabled bool
 
 	// Limits the max number of blocks that can be created across file system when
 	// streaming writes are enabled.
 	globalMaxWriteBlocksSem *semaphore.Weighted
 
+	// The limits behind globalMaxWriteBlocksSem, globalMaxReadBlocksSem and
+	// readBlockShares, which can be changed while mounted and are lowered under
+	// memory pressure.
+	blockLimits *blockLimits
+
+	// Stops watching the memory pressure, if it is watched.
+	stopWatchingMemory context.CancelFunc
+
+	// Where streaming uploads are journaled, or nil if they aren't.
+	uploadJournal *bufferedwrites.UploadJournal
+
+	// The uploads found interrupted in the journal at mount, and what was done
+	// about them. Set before the file system is used.
+	incompleteUploads []bufferedwrites.IncompleteUpload
+
 	// notifier allows sending invalidation messages to the FUSE kernel module.
 	// It is used to invalidate the kernel's dentry cache,
 	// providing feedback to the kernel about dynamic content changes.
 	notifier *fuse.Notifier
 
+	// Whether the kernel's caches of stale inodes and invalidated paths are
+	// dropped through notifier.
+	invalidateKernelCaches bool
+
+	// Batches of objects fetched from GCS, to be checked against the
+	// generations of their inodes by checkGenerations until
+	// stopGenerationChecks is closed. Nil unless invalidateKernelCaches.
+	generationChecks     chan generationCheck
+	stopGenerationChecks chan struct{}
+
 	// bufferedReadWorkerPool is used for asynchronous prefetching of data for buffered reads.
 	// It executes download tasks associated with prefetch blocks.
 	bufferedReadWorkerPool workerpool.WorkerPool
@@ -533,6 +689,10 @@ type fileSystem struct {
 	// that can be allocated for buffered read across all file-handles in the file system.
 	// This helps control the overall memory usage for buffered reads.
 	globalMaxReadBlocksSem *semaphore.Weighted
+
+	// readBlockShares divides the blocks of globalMaxReadBlocksSem among the
+	// buffered readers with adaptive prefetching.
+	readBlockShares *bufferedread.BlockShares
 }
 
 ////////////////////////////////////////////////////////////////////////
@@ -644,12 +804,14 @@ func (fs *fileSystem) checkInvariantsForImplicitDirs() {
 	}
 
 	// INVARIANT: For each in in inodes such that in is DirInode but not
-	//            ExplicitDirInode, implicitDirInodes[d.Name()] == d
+	//            ExplicitDirInode or VersionsDirInode,
+	//            implicitDirInodes[d.Name()] == d
 	for _, in := range fs.inodes {
 		_, dir := in.(inode.DirInode)
 		_, edir := in.(inode.ExplicitDirInode)
+		_, vdir := in.(*inode.VersionsDirInode)
 
-		if dir && !edir {
+		if dir && !edir && !vdir {
 			if !(fs.implicitDirInodes[in.Name()] == in) {
 				panic(fmt.Sprintf(
 					"implicitDirInodes mismatch: %q %v %v",
@@ -661,6 +823,39 @@ func (fs *fileSystem) checkInvariantsForImplicitDirs() {
 	}
 }
 
+func (fs *fileSystem) checkInvariantsForVersionInodes() {
+	// INVARIANT: For each k/v, v.Name() == k
+	for k, v := range fs.versionInodes {
+		if !(v.Name() == k) {
+			panic(fmt.Sprintf(
+				"Unexpected name: \"%s\" vs. \"%s\"",
+				v.Name(),
+				k))
+		}
+	}
+
+	// INVARIANT: For each value v, inodes[v.ID()] == v
+	for _, v := range fs.versionInodes {
+		if fs.inodes[v.ID()] != v {
+			panic(fmt.Sprintf(
+				"Mismatch for ID %v: %v %v",
+				v.ID(),
+				fs.inodes[v.ID()],
+				v))
+		}
+	}
+
+	// INVARIANT: Each value is *inode.VersionsDirInode or *inode.VersionInode
+	for _, v := range fs.versionInodes {
+		switch v.(type) {
+		case *inode.VersionsDirInode:
+		case *inode.VersionInode:
+		default:
+			panic(fmt.Sprintf("Unexpected version inode type: %T", v))
+		}
+	}
+}
+
 func (fs *fileSystem) checkInvariantsForGenerationBackedInodes() {
 	// INVARIANT: For each k/v, v.Name() == k
 	for k, v := range fs.generationBackedInodes {
@@ -731,16 +926,19 @@ func (fs *fileSystem) checkInvariants() {
 	fs.checkInvariantsForImplicitDirs()
 	fs.checkInvariantsForFolderInodes()
 	fs.checkInvariantsForLocalFileInodes()
+	fs.checkInvariantsForVersionInodes()
 
 	//////////////////////////////////
 	// handles
 	//////////////////////////////////
 
-	// INVARIANT: All values are of type *dirHandle or *handle.FileHandle
+	// INVARIANT: All values are of type *dirHandle, *handle.FileHandle or
+	//            *inode.VersionInode
 	for _, h := range fs.handles {
 		switch h.(type) {
 		case *handle.DirHandle:
 		case *handle.FileHandle:
+		case *inode.VersionInode:
 		default:
 			panic(fmt.Sprintf("Unexpected handle type: %T", h))
 		}
@@ -776,7 +974,7 @@ func (fs *fileSystem) createExplicitDirInode(inodeID fuseops.InodeID, ic inode.C
 		fs.implicitDirs,
 		fs.newConfig.List.EnableEmptyManagedFolders,
 		fs.enableNonexistentTypeCache,
-		fs.dirTypeCacheTTL,
+		time.Duration(fs.dirTypeCacheTTL.Load()),
 		ic.Bucket,
 		fs.mtimeClock,
 		fs.cacheClock,
@@ -820,7 +1018,7 @@ func (fs *fileSystem) mintInode(ic inode.Core) (in inode.Inode) {
 			fs.implicitDirs,
 			fs.newConfig.List.EnableEmptyManagedFolders,
 			fs.enableNonexistentTypeCache,
-			fs.dirTypeCacheTTL,
+			time.Duration(fs.dirTypeCacheTTL.Load()),
 			ic.Bucket,
 			fs.mtimeClock,
 			fs.cacheClock,
@@ -857,7 +1055,12 @@ func (fs *fileSystem) mintInode(ic inode.Core) (in inode.Inode) {
 			fs.mtimeClock,
 			ic.Local,
 			fs.newConfig,
-			fs.globalMaxWriteBlocksSem)
+			fs.globalMaxWriteBlocksSem,
+			fs.uploadJournal)
+	}
+
+	if d, ok := in.(inode.DirInode); ok {
+		fs.restorePendingTypeCache(d)
 	}
 
 	// Place it in our map of IDs to inodes.
@@ -1144,6 +1347,145 @@ func (fs *fileSystem) lookUpLocalFileInode(parent inode.DirInode, childName stri
 	return
 }
 
+// Return the inode indexed under the given name in versionInodes, minting one
+// with mint if there is none. Generations are immutable, so an existing inode
+// never goes stale.
+//
+// Return the inode locked, incrementing its lookup count.
+//
+// LOCKS_EXCLUDED(fs.mu)
+// LOCK_FUNCTION(in)
+func (fs *fileSystem) lookUpOrCreateVersionInode(
+	name inode.Name,
+	mint func(id fuseops.InodeID) inode.Inode) (in inode.Inode) {
+	fs.mu.Lock()
+	defer func() {
+		in.IncrementLookupCount()
+		fs.mu.Unlock()
+	}()
+
+	for {
+		existing, ok := fs.versionInodes[name]
+		if !ok {
+			in = mint(fs.nextInodeID)
+			fs.nextInodeID++
+			fs.inodes[in.ID()] = in
+			fs.versionInodes[name] = in
+			in.Lock()
+			return
+		}
+
+		// Follow the lock ordering rules, then check that the inode wasn't
+		// destroyed in the meantime.
+		fs.mu.Unlock()
+		existing.Lock()
+		fs.mu.Lock()
+		if fs.versionInodes[name] == existing {
+			in = existing
+			return
+		}
+		existing.Unlock()
+	}
+}
+
+// Look up the versions directory with the given name, ending with
+// inode.VersionsDirSuffix, within the parent. Return ENOENT if the object it
+// is named after has no noncurrent generations.
+//
+// Return the child locked, incrementing its lookup count.
+//
+// LOCKS_EXCLUDED(fs.mu)
+// LOCKS_EXCLUDED(parent)
+// LOCK_FUNCTION(child)
+func (fs *fileSystem) lookUpVersionsDirInode(
+	ctx context.Context,
+	parent inode.DirInode,
+	childName string) (child inode.Inode, err error) {
+	bucketOwned, ok := parent.(inode.BucketOwnedDirInode)
+	if !ok {
+		return nil, fuse.ENOENT
+	}
+	bucket := bucketOwned.Bucket()
+	objectName := inode.NewFileName(parent.Name(), strings.TrimSuffix(childName, inode.VersionsDirSuffix)).GcsObjectName()
+
+	versions, err := inode.NoncurrentVersions(ctx, bucket, objectName)
+	if err != nil {
+		return nil, fmt.Errorf("NoncurrentVersions: %w", err)
+	}
+	if len(versions) == 0 {
+		return nil, fuse.ENOENT
+	}
+
+	name := inode.NewDirName(parent.Name(), childName)
+	child = fs.lookUpOrCreateVersionInode(name, func(id fuseops.InodeID) inode.Inode {
+		return inode.NewVersionsDirInode(
+			id,
+			name,
+			objectName,
+			bucket,
+			fuseops.InodeAttributes{
+				Uid:   fs.uid,
+				Gid:   fs.gid,
+				Mode:  fs.dirMode &^ 0222,
+				Atime: fs.mtimeClock.Now(),
+				Ctime: fs.mtimeClock.Now(),
+				Mtime: fs.mtimeClock.Now(),
+			})
+	})
+	return
+}
+
+// Look up the noncurrent generation with the given name within the versions
+// directory, returning ENOENT if there is none.
+//
+// Return the child locked, incrementing its lookup count.
+//
+// LOCKS_EXCLUDED(fs.mu)
+// LOCKS_EXCLUDED(parent)
+// LOCK_FUNCTION(child)
+func (fs *fileSystem) lookUpVersionInode(
+	ctx context.Context,
+	parent *inode.VersionsDirInode,
+	childName string) (child inode.Inode, err error) {
+	parent.RLock()
+	m, err := parent.LookUpVersion(ctx, childName)
+	parent.RUnlock()
+	if err != nil {
+		return nil, fmt.Errorf("LookUpVersion: %w", err)
+	}
+	if m == nil {
+		return nil, fuse.ENOENT
+	}
+
+	child = fs.lookUpOrCreateVersionFileInode(inode.NewFileName(parent.Name(), childName), parent.ObjectBucket(), m)
+	return
+}
+
+// Return the inode of the noncurrent generation m, named name within its
+// versions directory, minting one if necessary.
+//
+// Return the inode locked, incrementing its lookup count.
+//
+// LOCKS_EXCLUDED(fs.mu)
+// LOCK_FUNCTION(in)
+func (fs *fileSystem) lookUpOrCreateVersionFileInode(
+	name inode.Name,
+	bucket *gcsx.SyncerBucket,
+	m *gcs.MinObject) (in inode.Inode) {
+	return fs.lookUpOrCreateVersionInode(name, func(id fuseops.InodeID) inode.Inode {
+		return inode.NewVersionInode(
+			id,
+			name,
+			bucket,
+			m,
+			fuseops.InodeAttributes{
+				Uid:  fs.uid,
+				Gid:  fs.gid,
+				Mode: fs.fileMode,
+			})
+	})
+}
+
 // Look up the child directory with the given name within the parent, then
 // return an existing dir inode for that child or create a new one if necessary.
 // Return ENOENT if the child doesn't exist.
@@ -1258,6 +1600,24 @@ func (fs *fileSystem) syncFile(
 	return nil
 }
 
+// Makes sure the object backing the supplied file inode can have its metadata
+// patched in place. Local files don't have a backing object yet, and streaming
+// writes capture the object's metadata when the upload starts, so both are
+// flushed to GCS first. Dirty temp files need no flush since the metadata is
+// carried over when they are synced.
+//
+// LOCKS_EXCLUDED(fs.mu)
+// LOCKS_REQUIRED(f)
+func (fs *fileSystem) flushFileForMetadataUpdate(ctx context.Context, f *inode.FileInode) error {
+	if !f.IsLocal() && !f.IsUsingBWH() {
+		return nil
+	}
+	if f.IsUnlinked() {
+		return syscall.ENOENT
+	}
+	return fs.flushFile(ctx, f)
+}
+
 // Initializes Buffered Write Handler if Eligible and synchronizes the file inode to GCS if initialization succeeds.
 // Otherwise creates an empty temp writer if temp file nil.
 //
@@ -1332,6 +1692,9 @@ func (fs *fileSystem) unlockAndDecrementLookupCount(in inode.Inode, N uint64) {
 		if fs.folderInodes[name] == in {
 			delete(fs.folderInodes, name)
 		}
+		if fs.versionInodes[name] == in {
+			delete(fs.versionInodes, name)
+		}
 		fs.mu.Unlock()
 	}
 
@@ -1398,8 +1761,8 @@ func (fs *fileSystem) getAttributes(
 	}
 
 	// Set up the expiration time.
-	if fs.inodeAttributeCacheTTL > 0 {
-		expiration = time.Now().Add(fs.inodeAttributeCacheTTL)
+	if ttl := time.Duration(fs.inodeAttributeCacheTTL.Load()); ttl > 0 {
+		expiration = time.Now().Add(ttl)
 	}
 
 	return
@@ -1485,9 +1848,15 @@ func (fs *fileSystem) invalidateChildFileCacheIfExist(parentInode inode.DirInode
 
 // coreToDirentPlus creates a fuseutil.DirentPlus entry from an inode core.
 // LOCKS_EXCLUDED(fs.mu)
-func (fs *fileSystem) coreToDirentPlus(ctx context.Context, fullName inode.Name, core inode.Core) (entryPlus *fuseutil.DirentPlus, err error) {
-	// Look up or create the inode for the core.
-	child := fs.lookUpOrCreateInodeIfNotStale(core)
+func (fs *fileSystem) coreToDirentPlus(ctx context.Context, parent inode.DirInode, fullName inode.Name, core inode.Core) (entryPlus *fuseutil.DirentPlus, err error) {
+	// Look up or create the inode for the core. The cores of versions are named
+	// after them rather than their objects.
+	var child inode.Inode
+	if _, ok := parent.(*inode.VersionsDirInode); ok {
+		child = fs.lookUpOrCreateVersionFileInode(fullName, core.Bucket, core.MinObject)
+	} else {
+		child = fs.lookUpOrCreateInodeIfNotStale(core)
+	}
 	if child == nil {
 		return nil, fmt.Errorf("coreToDirentPlus: stale record for %s", path.Base(fullName.LocalName()))
 	}
@@ -1500,7 +1869,7 @@ func (fs *fileSystem) coreToDirentPlus(ctx context.Context, fullName inode.Name,
 		return nil, fmt.Errorf("coreToDirentPlus: unable to fetch attributes for %s: %w", path.Base(fullName.LocalName()), err)
 	}
 
-	expiration := time.Now().Add(fs.inodeAttributeCacheTTL)
+	expiration := time.Now().Add(time.Duration(fs.inodeAttributeCacheTTL.Load()))
 	entryPlus = &fuseutil.DirentPlus{
 		Dirent: fuseutil.Dirent{
 			Name:  path.Base(fullName.LocalName()),
@@ -1581,7 +1950,7 @@ func (fs *fileSystem) lookupAndFetchAttributesForLocalFileEntriesPlus(parentName
 		// Unlock the inode after retrieving its attributes.
 		child.Unlock()
 
-		expiration := time.Now().Add(fs.inodeAttributeCacheTTL)
+		expiration := time.Now().Add(time.Duration(fs.inodeAttributeCacheTTL.Load()))
 		childInodeEntry := fuseops.ChildInodeEntry{
 			Child:                child.ID(),
 			Attributes:           attrs,
@@ -1643,18 +2012,122 @@ func (fs *fileSystem) invalidateCachedEntry(childID fuseops.InodeID) error {
 	return fs.notifier.InvalidateEntry(parentInodeID, childBase)
 }
 
+// The number of batches of objects that can be queued for checkGenerations.
+// Batches beyond it are dropped, leaving the kernel's caches to expire.
+const generationCheckQueueLen = 256
+
+// generationCheck is a batch of objects fetched from GCS by a bucket for a
+// lookup or listing.
+type generationCheck struct {
+	// The name of the bucket, or empty if a single bucket is mounted.
+	bucketName string
+	objects    []*gcs.MinObject
+}
+
+// queueGenerationCheck is called by the buckets with the objects they fetch
+// from GCS. It doesn't block, as the buckets may be called with inode locks
+// held.
+func (fs *fileSystem) queueGenerationCheck(bucketName string, objs []*gcs.MinObject) {
+	select {
+	case fs.generationChecks <- generationCheck{bucketName: bucketName, objects: objs}:
+	default:
+		logger.Tracef("Dropping the generation check of %d objects, as too many are queued", len(objs))
+	}
+}
+
+// checkGenerations invalidates the kernel's caches of the inodes found to be
+// backed by older generations than those of the queued objects, until the
+// file system is destroyed. The kernel may be serving their attributes,
+// contents and directory entries long after the stat cache learns of newer
+// generations, e.g. with the dentry cache or the kernel list cache enabled.
+//
+// LOCKS_EXCLUDED(fs.mu)
+func (fs *fileSystem) checkGenerations() {
+	for {
+		select {
+		case c := <-fs.generationChecks:
+			fs.invalidateStaleInodes(c)
+		case <-fs.stopGenerationChecks:
+			return
+		}
+	}
+}
+
+// LOCKS_EXCLUDED(fs.mu)
+func (fs *fileSystem) invalidateStaleInodes(c generationCheck) {
+	type candidate struct {
+		in  inode.GenerationBackedInode
+		gen inode.Generation
+	}
+
+	// Inode locks must not be acquired while holding fs.mu, so collect the
+	// inodes first.
+	var candidates []candidate
+	root := inode.NewRootName(c.bucketName)
+	fs.mu.Lock()
+	for _, o := range c.objects {
+		in, ok := fs.generationBackedInodes[inode.NewDescendantName(root, o.Name)]
+		if !ok {
+			continue
+		}
+		candidates = append(candidates, candidate{
+			in:  in,
+			gen: inode.Generation{Object: o.Generation, Metadata: o.MetaGeneration, Size: o.Size},
+		})
+	}
+	fs.mu.Unlock()
+
+	for _, cand := range candidates {
+		cand.in.Lock()
+		stale := cand.gen.Compare(cand.in.SourceGeneration()) > 0
+		cand.in.Unlock()
+		if !stale {
+			continue
+		}
+
+		// ENOENT means that the kernel didn't cache anything, and ENOSYS that it
+		// doesn't support invalidations.
+		id := cand.in.ID()
+		logger.Debugf("Invalidating the kernel's caches of stale inode %d (%q)", id, cand.in.Name().LocalName())
+		if err := fs.notifier.InvalidateInode(id, 0, 0); err != nil && !errors.Is(err, syscall.ENOENT) && !errors.Is(err, syscall.ENOSYS) {
+			logger.Warnf("Invalidating inode %d in the kernel: %v", id, err)
+		}
+		if err := fs.invalidateCachedEntry(id); err != nil && !errors.Is(err, syscall.ENOENT) && !errors.Is(err, syscall.ENOSYS) {
+			logger.Debugf("Invalidating the entry of inode %d in the kernel: %v", id, err)
+		}
+	}
+}
+
 ////////////////////////////////////////////////////////////////////////
 // fuse.FileSystem methods
 ////////////////////////////////////////////////////////////////////////
 
 func (fs *fileSystem) Destroy() {
+	if fs.metadataCachePersister != nil {
+		fs.persistMetadataCaches()
+	}
+	if fs.stopPrefetching != nil {
+		fs.stopPrefetching()
+		fs.prefetches.Wait()
+	}
+	if fs.stopWatchingMemory != nil {
+		fs.stopWatchingMemory()
+	}
 	fs.bucketManager.ShutDown()
 	if fs.fileCacheHandler != nil {
 		_ = fs.fileCacheHandler.Destroy()
 	}
+	if fs.uploadJournal != nil {
+		if err := fs.uploadJournal.Close(); err != nil {
+			logger.Warnf("Failed to compact the upload journal: %v", err)
+		}
+	}
 	if fs.bufferedReadWorkerPool != nil {
 		fs.bufferedReadWorkerPool.Stop()
 	}
+	if fs.stopGenerationChecks != nil {
+		close(fs.stopGenerationChecks)
+	}
 }
 
 func (fs *fileSystem) StatFS(
@@ -1703,6 +2176,12 @@ func (fs *fileSystem) getInterruptlessContext(ctx context.Context) context.Conte
 	return ctx
 }
 
+// isVersionsDirName returns true if the name is that of a versions directory,
+// i.e. an object name followed by inode.VersionsDirSuffix.
+func isVersionsDirName(name string) bool {
+	return len(name) > len(inode.VersionsDirSuffix) && strings.HasSuffix(name, inode.VersionsDirSuffix)
+}
+
 // LOCKS_EXCLUDED(fs.mu)
 func (fs *fileSystem) LookUpInode(
 	ctx context.Context,
@@ -1714,7 +2193,14 @@ func (fs *fileSystem) LookUpInode(
 	fs.mu.Unlock()
 
 	// Find or create the child inode.
-	child, err := fs.lookUpOrCreateChildInode(ctx, parent, op.Name)
+	var child inode.Inode
+	if versionsDir, ok := parent.(*inode.VersionsDirInode); ok {
+		child, err = fs.lookUpVersionInode(ctx, versionsDir, op.Name)
+	} else if fs.newConfig.FileSystem.EnableVersionsDirs && isVersionsDirName(op.Name) {
+		child, err = fs.lookUpVersionsDirInode(ctx, parent, op.Name)
+	} else {
+		child, err = fs.lookUpOrCreateChildInode(ctx, parent, op.Name)
+	}
 	if err != nil {
 		return err
 	}
@@ -1772,6 +2258,11 @@ func (fs *fileSystem) SetInodeAttributes(
 	defer in.Unlock()
 	file, isFile := in.(*inode.FileInode)
 
+	// Noncurrent generations are read-only.
+	if _, ok := in.(*inode.VersionInode); ok && (op.Mtime != nil || op.Size != nil) {
+		return syscall.EROFS
+	}
+
 	// Set file mtimes.
 	if isFile && op.Mtime != nil {
 		err = file.SetMtime(ctx, *op.Mtime)
@@ -2042,7 +2533,7 @@ func (fs *fileSystem) CreateFile(
 
 	// CreateFile() invoked to create new files, can be safely considered as filehandle
 	// opened in append mode.
-	fs.handles[handleID] = handle.NewFileHandle(child.(*inode.FileInode), fs.fileCacheHandler, fs.cacheFileForRangeRead, fs.metricHandle, util.Append, fs.newConfig, fs.bufferedReadWorkerPool, fs.globalMaxReadBlocksSem)
+	fs.handles[handleID] = handle.NewFileHandle(child.(*inode.FileInode), fs.fileCacheHandler, fs.cacheFileForRangeRead, fs.metricHandle, util.Append, fs.newConfig, fs.bufferedReadWorkerPool, fs.globalMaxReadBlocksSem, fs.readBlockShares)
 	op.Handle = handleID
 
 	fs.mu.Unlock()
@@ -2112,6 +2603,102 @@ func (fs *fileSystem) CreateSymlink(
 	return
 }
 
+// LOCKS_EXCLUDED(fs.mu)
+func (fs *fileSystem) CreateLink(
+	ctx context.Context,
+	op *fuseops.CreateLinkOp) (err error) {
+	if !fs.newConfig.FileSystem.EnableHardLinks {
+		return syscall.ENOSYS
+	}
+
+	ctx = fs.getInterruptlessContext(ctx)
+	// Find the parent and the target.
+	fs.mu.Lock()
+	parent := fs.dirInodeOrDie(op.Parent)
+	target := fs.inodeOrDie(op.Target)
+	// A local file with the new name has no object yet for the precondition
+	// below to fail on.
+	local, ok := fs.localFileInodes[inode.NewFileName(parent.Name(), op.Name)]
+	fs.mu.Unlock()
+	if ok && !local.(*inode.FileInode).IsUnlinked() {
+		return fuse.EEXIST
+	}
+
+	// Only regular files can be linked.
+	file, ok := target.(*inode.FileInode)
+	if !ok {
+		return syscall.EPERM
+	}
+
+	// Make sure the backing object has the latest contents before cloning it.
+	file.Lock()
+	if file.IsUnlinked() {
+		file.Unlock()
+		return syscall.ENOENT
+	}
+	err = fs.flushFile(ctx, file)
+	src := file.Source()
+	name := inode.NewFileName(parent.Name(), op.Name)
+	links := withLink(file.Links(), name.GcsObjectName())
+	file.Unlock()
+	if err != nil {
+		return err
+	}
+
+	// Clone the object in GCS, failing if the name already exists.
+	parent.Lock()
+	result, err := parent.CreateChildLink(ctx, op.Name, src, links)
+	parent.Unlock()
+
+	// Special case: *gcs.PreconditionError means the name already exists.
+	var preconditionErr *gcs.PreconditionError
+	if errors.As(err, &preconditionErr) {
+		err = fuse.EEXIST
+		return
+	}
+
+	// Propagate other errors.
+	if err != nil {
+		err = fmt.Errorf("CreateChildLink: %w", err)
+		return err
+	}
+
+	// Record the new link on the target and its other links as well. The link
+	// has already been created, so failing to do so is not fatal.
+	file.Lock()
+	if file.SourceGeneration().Object == src.Generation {
+		if err := file.SetLinks(ctx, links); err != nil {
+			logger.Warnf("CreateLink: failed to update the hard links of %q: %v", file.Name(), err)
+		}
+	}
+	file.Unlock()
+	others := withoutLink(withoutLink(links, src.Name), name.GcsObjectName())
+	fs.updateLinks(ctx, file.Bucket(), name, others, src.Name, links)
+
+	// Attempt to create a child inode using the object we created. If we fail to
+	// do so, it means someone beat us to the punch with a newer generation
+	// (unlikely, so we're probably okay with failing here).
+	child := fs.lookUpOrCreateInodeIfNotStale(*result)
+	if child == nil {
+		err = fmt.Errorf("newly-created record is already stale")
+		return err
+	}
+
+	defer fs.unlockAndMaybeDisposeOfInode(child, &err)
+
+	// Fill out the response.
+	e := &op.Entry
+	e.Child = child.ID()
+	e.Attributes, e.AttributesExpiration, err = fs.getAttributes(ctx, child)
+
+	if err != nil {
+		err = fmt.Errorf("getAttributes: %w", err)
+		return err
+	}
+
+	return
+}
+
 // LOCKS_EXCLUDED(fs.mu)
 func (fs *fileSystem) RmDir(
 	// When rm -r or os.RemoveAll call is made, the following calls are made in order
@@ -2222,16 +2809,22 @@ func (fs *fileSystem) RmDir(
 	// We are done with the child.
 	cleanUpAndUnlockChild()
 
-	// Delete the backing object.
+	// Delete the backing object, or move it to the trash.
 	fs.mu.Lock()
 	_, isImplicitDir := fs.implicitDirInodes[child.Name()]
 	fs.mu.Unlock()
 	parent.Lock()
-	err = parent.DeleteChildDir(ctx, op.Name, isImplicitDir, childDir)
+	if !isImplicitDir && fs.shouldTrashChildDir(parent, child.Name()) {
+		err = fs.trashChildDir(ctx, parent, op.Name)
+	} else {
+		err = parent.DeleteChildDir(ctx, op.Name, isImplicitDir, childDir)
+		if err != nil {
+			err = fmt.Errorf("DeleteChildDir: %w", err)
+		}
+	}
 	parent.Unlock()
 
 	if err != nil {
-		err = fmt.Errorf("DeleteChildDir: %w", err)
 		return err
 	}
 
@@ -2249,6 +2842,14 @@ func (fs *fileSystem) Rename(
 	newParent := fs.dirInodeOrDie(op.NewParent)
 	fs.mu.Unlock()
 
+	// Versions are read-only snapshots, not objects that can be moved. EXDEV
+	// lets tools like mv(1) copy them instead.
+	_, oldIsVersions := oldParent.(*inode.VersionsDirInode)
+	_, newIsVersions := newParent.(*inode.VersionsDirInode)
+	if oldIsVersions || newIsVersions {
+		return syscall.EXDEV
+	}
+
 	if oldParentInode, ok := oldParent.(inode.BucketOwnedInode); !ok {
 		// The old parent is not owned by any bucket, which means it's the base
 		// directory that holds all the buckets' root directories. So, this op
@@ -2293,6 +2894,7 @@ func (fs *fileSystem) Rename(
 // LOCKS_EXCLUDED(newParent)
 func (fs *fileSystem) renameFile(ctx context.Context, op *fuseops.RenameOp, child inode.BucketOwnedInode, oldParent, newParent inode.DirInode) error {
 	var updatedMinObject *gcs.MinObject
+	var links []string
 	var err error
 
 	switch c := child.(type) {
@@ -2301,15 +2903,31 @@ func (fs *fileSystem) renameFile(ctx context.Context, op *fuseops.RenameOp, chil
 		if err != nil {
 			return fmt.Errorf("flushPendingWrites: %w", err)
 		}
+		if fs.newConfig.FileSystem.EnableHardLinks {
+			links = inode.Links(updatedMinObject)
+		}
 	case *inode.SymlinkInode:
 		updatedMinObject = c.Source()
 	default:
 		return fmt.Errorf("child inode (id %v) is not a file or symlink inode", child.ID())
 	}
 	if fs.enableAtomicRenameObject || child.Bucket().BucketType().Zonal {
-		return fs.atomicRename(ctx, oldParent, op.OldName, updatedMinObject, newParent, op.NewName)
+		err = fs.atomicRename(ctx, oldParent, op.OldName, updatedMinObject, newParent, op.NewName)
+	} else {
+		err = fs.nonAtomicRename(ctx, oldParent, op.OldName, updatedMinObject, newParent, op.NewName)
 	}
-	return fs.nonAtomicRename(ctx, oldParent, op.OldName, updatedMinObject, newParent, op.NewName)
+	if err != nil {
+		return err
+	}
+
+	// Replace the old name with the new one in the hard links to the file,
+	// including the renamed object, which carries the links of the old one.
+	if len(links) > 1 {
+		newName := inode.NewFileName(newParent.Name(), op.NewName)
+		newLinks := withLink(withoutLink(links, updatedMinObject.Name), newName.GcsObjectName())
+		fs.updateLinks(ctx, child.Bucket(), newName, newLinks, updatedMinObject.Name, newLinks)
+	}
+	return nil
 }
 
 // LOCKS_EXCLUDED(fileInode)
@@ -2607,6 +3225,63 @@ func (fs *fileSystem) renameNonHierarchicalDir(
 	return nil
 }
 
+// Move the object backing the named file within the parent to the trash.
+//
+// LOCKS_REQUIRED(parent)
+func (fs *fileSystem) trashChildFile(ctx context.Context, parent inode.DirInode, name string) error {
+	bucketOwned, ok := parent.(inode.BucketOwnedDirInode)
+	if !ok {
+		return fuse.ENOSYS
+	}
+
+	parent.EraseFromTypeCache(name)
+	objectName := inode.NewFileName(parent.Name(), name).GcsObjectName()
+	trashName, err := fs.trash.MoveObject(ctx, bucketOwned.Bucket(), objectName)
+	if err != nil {
+		return fmt.Errorf("trash: %w", err)
+	}
+	logger.Debugf("Moved %q to the trash as %q", objectName, trashName)
+
+	return nil
+}
+
+// shouldTrashChildDir returns true if the placeholder object of the named
+// child directory of parent is to be moved to the trash rather than deleted.
+// In buckets with hierarchical namespace, directories are folders, which are
+// deleted.
+func (fs *fileSystem) shouldTrashChildDir(parent inode.DirInode, childName inode.Name) bool {
+	if fs.trash == nil || fs.trash.Contains(childName.GcsObjectName()) {
+		return false
+	}
+	bucketOwned, ok := parent.(inode.BucketOwnedDirInode)
+	return ok && !bucketOwned.Bucket().BucketType().Hierarchical
+}
+
+// Move the placeholder object of the named directory within the parent to the
+// trash, if it still exists.
+//
+// LOCKS_REQUIRED(parent)
+func (fs *fileSystem) trashChildDir(ctx context.Context, parent inode.DirInode, name string) error {
+	bucketOwned, ok := parent.(inode.BucketOwnedDirInode)
+	if !ok {
+		return fuse.ENOSYS
+	}
+
+	parent.EraseFromTypeCache(name)
+	objectName := inode.NewDirName(parent.Name(), name).GcsObjectName()
+	trashName, err := fs.trash.MoveObject(ctx, bucketOwned.Bucket(), objectName)
+	var notFoundErr *gcs.NotFoundError
+	if errors.As(err, &notFoundErr) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("trash: %w", err)
+	}
+	logger.Debugf("Moved %q to the trash as %q", objectName, trashName)
+
+	return nil
+}
+
 // LOCKS_EXCLUDED(fs.mu)
 func (fs *fileSystem) Unlink(
 	ctx context.Context,
@@ -2632,9 +3307,14 @@ func (fs *fileSystem) Unlink(
 
 	fs.mu.Unlock()
 
+	var file *inode.FileInode
+	var links []string
 	if in != nil {
 		// Perform the unlink operation on the inode.
 		in.Lock()
+		if f, ok := in.(*inode.FileInode); ok && fs.newConfig.FileSystem.EnableHardLinks && !isLocalFile {
+			file, links = f, f.Links()
+		}
 		in.Unlink()
 		in.Unlock()
 	}
@@ -2645,19 +3325,36 @@ func (fs *fileSystem) Unlink(
 		return
 	}
 
-	// Delete the backing object present on GCS.
+	// Drop the name from the other hard links to the file once it's gone. This
+	// runs after the parent is unlocked below, as it locks their inodes.
+	if len(links) > 1 {
+		defer func() {
+			if err == nil {
+				remaining := withoutLink(links, fileName.GcsObjectName())
+				fs.updateLinks(ctx, file.Bucket(), fileName, remaining, fileName.GcsObjectName(), remaining)
+			}
+		}()
+	}
+
+	// Delete the backing object present on GCS, or move it to the trash.
 	parent.Lock()
 	defer parent.Unlock()
 
-	err = parent.DeleteChildFile(
-		ctx,
-		op.Name,
-		0,   // Latest generation
-		nil) // No meta-generation precondition
+	if fs.trash != nil && !fs.trash.Contains(fileName.GcsObjectName()) {
+		if err = fs.trashChildFile(ctx, parent, op.Name); err != nil {
+			return err
+		}
+	} else {
+		err = parent.DeleteChildFile(
+			ctx,
+			op.Name,
+			0,   // Latest generation
+			nil) // No meta-generation precondition
 
-	if err != nil {
-		err = fmt.Errorf("DeleteChildFile: %w", err)
-		return err
+		if err != nil {
+			err = fmt.Errorf("DeleteChildFile: %w", err)
+			return err
+		}
 	}
 
 	if err := fs.invalidateChildFileCacheIfExist(parent, fileName.GcsObjectName()); err != nil {
@@ -2753,7 +3450,7 @@ func (fs *fileSystem) ReadDirPlus(ctx context.Context, op *fuseops.ReadDirPlusOp
 	// lock here has no performance overhead.
 	var entriesPlus []fuseutil.DirentPlus
 	for fullName, core := range cores {
-		entry, err := fs.coreToDirentPlus(ctx, fullName, *core)
+		entry, err := fs.coreToDirentPlus(ctx, in, fullName, *core)
 		if err != nil {
 			return err
 		}
@@ -2794,6 +3491,21 @@ func (fs *fileSystem) OpenFile(
 
 	fs.mu.Lock()
 
+	// Noncurrent generations are read through the inode, which serves as the
+	// handle.
+	if v, ok := fs.inodes[op.Inode].(*inode.VersionInode); ok {
+		defer fs.mu.Unlock()
+		if util.FileOpenMode(op) != util.Read {
+			return syscall.EROFS
+		}
+		handleID := fs.nextHandleID
+		fs.nextHandleID++
+		fs.handles[handleID] = v
+		op.Handle = handleID
+		op.KeepPageCache = true
+		return
+	}
+
 	// Find the inode.
 	in := fs.fileInodeOrDie(op.Inode)
 	// Follow lock ordering rules to get inode lock.
@@ -2812,9 +3524,13 @@ func (fs *fileSystem) OpenFile(
 
 	// Figure out the mode in which the file is being opened.
 	openMode := util.FileOpenMode(op)
-	fs.handles[handleID] = handle.NewFileHandle(in, fs.fileCacheHandler, fs.cacheFileForRangeRead, fs.metricHandle, openMode, fs.newConfig, fs.bufferedReadWorkerPool, fs.globalMaxReadBlocksSem)
+	fs.handles[handleID] = handle.NewFileHandle(in, fs.fileCacheHandler, fs.cacheFileForRangeRead, fs.metricHandle, openMode, fs.newConfig, fs.bufferedReadWorkerPool, fs.globalMaxReadBlocksSem, fs.readBlockShares)
 	op.Handle = handleID
 
+	if fs.dirReadahead != nil && openMode == util.Read && !in.IsLocal() {
+		fs.dirReadahead.fileOpened(in.Bucket(), in.Source())
+	}
+
 	// When we observe object generations that we didn't create, we assign them
 	// new inode IDs. So for a given inode, all modifications go through the
 	// kernel. Therefore it's safe to tell the kernel to keep the page cache from
@@ -2834,9 +3550,19 @@ func (fs *fileSystem) ReadFile(
 
 	// Find the handle and lock it.
 	fs.mu.Lock()
-	fh := fs.handles[op.Handle].(*handle.FileHandle)
+	h := fs.handles[op.Handle]
 	fs.mu.Unlock()
 
+	if v, ok := h.(*inode.VersionInode); ok {
+		op.BytesRead, err = v.Read(ctx, op.Dst, op.Offset)
+		// As required by fuse, we don't treat EOF as an error.
+		if err == io.EOF {
+			err = nil
+		}
+		return
+	}
+	fh := h.(*handle.FileHandle)
+
 	fh.Inode().Lock()
 	if fh.Inode().IsUsingBWH() {
 		// Flush/Sync Pending streaming writes and issue read within same inode lock.
@@ -2957,6 +3683,41 @@ func (fs *fileSystem) WriteFile(
 	return
 }
 
+// Fallocate supports preallocating (optionally without changing the file
+// size) and punching holes in files staged in a local temp file. Files being
+// written with streaming writes, and other modes, fail with EOPNOTSUPP.
+//
+// LOCKS_EXCLUDED(fs.mu)
+func (fs *fileSystem) Fallocate(
+	ctx context.Context,
+	op *fuseops.FallocateOp) (err error) {
+	ctx = fs.getInterruptlessContext(ctx)
+
+	// Find the inode.
+	fs.mu.Lock()
+	in := fs.fileInodeOrDie(op.Inode)
+	fs.mu.Unlock()
+
+	in.Lock()
+	defer in.Unlock()
+
+	offset, length := int64(op.Offset), int64(op.Length)
+	switch op.Mode {
+	case 0:
+		err = in.Fallocate(ctx, offset, length, false)
+	case unix.FALLOC_FL_KEEP_SIZE:
+		err = in.Fallocate(ctx, offset, length, true)
+	case unix.FALLOC_FL_PUNCH_HOLE | unix.FALLOC_FL_KEEP_SIZE:
+		err = in.PunchHole(ctx, offset, length)
+	default:
+		return syscall.EOPNOTSUPP
+	}
+	if err != nil {
+		err = fmt.Errorf("fallocate: %w", err)
+	}
+	return
+}
+
 // LOCKS_EXCLUDED(fs.mu)
 func (fs *fileSystem) SyncFile(
 	ctx context.Context,
@@ -2991,6 +3752,11 @@ func (fs *fileSystem) FlushFile(
 	ctx = fs.getInterruptlessContext(ctx)
 	// Find the inode.
 	fs.mu.Lock()
+	if _, ok := fs.inodes[op.Inode].(*inode.VersionInode); ok {
+		// Nothing to flush.
+		fs.mu.Unlock()
+		return
+	}
 	in := fs.fileInodeOrDie(op.Inode)
 	fs.mu.Unlock()
 
@@ -3011,6 +3777,11 @@ func (fs *fileSystem) ReleaseFileHandle(
 	op *fuseops.ReleaseFileHandleOp) (err error) {
 	fs.mu.Lock()
 
+	if _, ok := fs.handles[op.Handle].(*inode.VersionInode); ok {
+		delete(fs.handles, op.Handle)
+		fs.mu.Unlock()
+		return
+	}
 	fileHandle := fs.handles[op.Handle].(*handle.FileHandle)
 	// Update the map. We are okay updating the map before destroy is called
 	// since destroy is doing only internal cleanup.
@@ -3025,20 +3796,209 @@ func (fs *fileSystem) ReleaseFileHandle(
 	return
 }
 
+// Copy the supplied xattr value or name list into dst following the
+// getxattr(2)/listxattr(2) conventions: an empty dst is a request for the
+// required size, and a dst that is too small results in ERANGE.
+func copyXattrValue(dst []byte, value []byte) (bytesRead int, err error) {
+	bytesRead = len(value)
+	if len(dst) == 0 {
+		return
+	}
+	if len(dst) < len(value) {
+		err = syscall.ERANGE
+		return
+	}
+	copy(dst, value)
+	return
+}
+
+// LOCKS_EXCLUDED(fs.mu)
 func (fs *fileSystem) GetXattr(
 	ctx context.Context,
 	op *fuseops.GetXattrOp) (err error) {
-	return syscall.ENOSYS
+	if !fs.newConfig.FileSystem.EnableXattrs {
+		return syscall.ENOSYS
+	}
+	ctx = fs.getInterruptlessContext(ctx)
+
+	fs.mu.Lock()
+	in := fs.inodeOrDie(op.Inode)
+	fs.mu.Unlock()
+
+	// Only files carry extended attributes.
+	file, ok := in.(*inode.FileInode)
+	if !ok {
+		return fuse.ENOATTR
+	}
+
+	file.Lock()
+	defer file.Unlock()
+
+	value, err := file.GetXattr(ctx, op.Name)
+	if err != nil {
+		return err
+	}
+	op.BytesRead, err = copyXattrValue(op.Dst, value)
+	return
 }
 
+// LOCKS_EXCLUDED(fs.mu)
 func (fs *fileSystem) ListXattr(
 	ctx context.Context,
-	op *fuseops.ListXattrOp) error {
-	return syscall.ENOSYS
+	op *fuseops.ListXattrOp) (err error) {
+	if !fs.newConfig.FileSystem.EnableXattrs {
+		return syscall.ENOSYS
+	}
+
+	fs.mu.Lock()
+	in := fs.inodeOrDie(op.Inode)
+	fs.mu.Unlock()
+
+	file, ok := in.(*inode.FileInode)
+	if !ok {
+		return
+	}
+
+	file.Lock()
+	names := file.ListXattr()
+	file.Unlock()
+
+	var list []byte
+	for _, name := range names {
+		list = append(list, name...)
+		list = append(list, 0)
+	}
+	op.BytesRead, err = copyXattrValue(op.Dst, list)
+	return
 }
 
+// LOCKS_EXCLUDED(fs.mu)
+func (fs *fileSystem) SetXattr(
+	ctx context.Context,
+	op *fuseops.SetXattrOp) (err error) {
+	if !fs.newConfig.FileSystem.EnableXattrs {
+		return syscall.ENOSYS
+	}
+	ctx = fs.getInterruptlessContext(ctx)
+
+	fs.mu.Lock()
+	in := fs.inodeOrDie(op.Inode)
+	fs.mu.Unlock()
+
+	file, ok := in.(*inode.FileInode)
+	if !ok {
+		return syscall.ENOTSUP
+	}
+
+	file.Lock()
+	defer file.Unlock()
+
+	if err = fs.flushFileForMetadataUpdate(ctx, file); err != nil {
+		return err
+	}
+	return file.SetXattr(ctx, op.Name, op.Value, op.Flags)
+}
+
+// LOCKS_EXCLUDED(fs.mu)
+func (fs *fileSystem) RemoveXattr(
+	ctx context.Context,
+	op *fuseops.RemoveXattrOp) (err error) {
+	if !fs.newConfig.FileSystem.EnableXattrs {
+		return syscall.ENOSYS
+	}
+	ctx = fs.getInterruptlessContext(ctx)
+
+	fs.mu.Lock()
+	in := fs.inodeOrDie(op.Inode)
+	fs.mu.Unlock()
+
+	file, ok := in.(*inode.FileInode)
+	if !ok {
+		return syscall.ENOTSUP
+	}
+
+	file.Lock()
+	defer file.Unlock()
+
+	if err = fs.flushFileForMetadataUpdate(ctx, file); err != nil {
+		return err
+	}
+	return file.RemoveXattr(ctx, op.Name)
+}
+
+// pinInode increments the lookup count of the supplied inode, found without
+// holding its lock, unless it has been forgotten and destroyed since. The
+// caller must release the count with unlockAndDecrementLookupCount.
+//
+// LOCKS_REQUIRED(in)
+// LOCKS_EXCLUDED(fs.mu)
+func (fs *fileSystem) pinInode(in inode.Inode) bool {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	// Inodes are removed from the index, under their lock, before they are
+	// destroyed.
+	if fs.inodes[in.ID()] != in {
+		return false
+	}
+	in.IncrementLookupCount()
+	return true
+}
+
+// The maximum number of files flushed to GCS concurrently by SyncFS.
+const syncFSMaxParallelism = 16
+
+// SyncFS flushes every file that has an open handle to GCS. Files with
+// streaming writes in progress are finalized, as they would be on close. All
+// files are attempted even if some of them fail, and the failures are
+// reported together.
+//
+// LOCKS_EXCLUDED(fs.mu)
 func (fs *fileSystem) SyncFS(
 	ctx context.Context,
 	op *fuseops.SyncFSOp) error {
-	return syscall.ENOSYS
+	ctx = fs.getInterruptlessContext(ctx)
+
+	// Find the distinct file inodes with open handles. Their handles may be
+	// released, and the inodes forgotten, once fs.mu is released, so each is
+	// pinned with a lookup count of its own while it is flushed.
+	fs.mu.Lock()
+	var files []*inode.FileInode
+	seen := make(map[*inode.FileInode]struct{})
+	for _, h := range fs.handles {
+		fh, ok := h.(*handle.FileHandle)
+		if !ok {
+			continue
+		}
+		if _, ok := seen[fh.Inode()]; ok {
+			continue
+		}
+		seen[fh.Inode()] = struct{}{}
+		files = append(files, fh.Inode())
+	}
+	fs.mu.Unlock()
+
+	// Flush them in parallel, collecting the errors rather than stopping at the
+	// first one.
+	errs := make([]error, len(files))
+	var group errgroup.Group
+	group.SetLimit(syncFSMaxParallelism)
+	for i, f := range files {
+		group.Go(func() error {
+			f.Lock()
+			if !fs.pinInode(f) {
+				// Forgotten since, after its last handle was closed and flushed.
+				f.Unlock()
+				return nil
+			}
+			if err := fs.flushFile(ctx, f); err != nil {
+				errs[i] = fmt.Errorf("%q: %w", f.Name().GcsObjectName(), err)
+			}
+			fs.unlockAndDecrementLookupCount(f, 1)
+			return nil
+		})
+	}
+	_ = group.Wait()
+
+	return errors.Join(errs...)
 }
@@ -30,6 +30,7 @@ import (
 	"time"
 
 	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/metadata"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/fs"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/gcsx"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/locker"
@@ -83,6 +84,10 @@ type fsTest struct {
 	serverCfg fs.ServerConfig
 	mountCfg  fuse.MountConfig
 
+	// The stat cache wrapping the bucket, if any, whose entries are erased
+	// through the bucket manager.
+	statCache metadata.StatCache
+
 	// Files to close when tearing down. Nil entries are skipped.
 	f1 *os.File
 	f2 *os.File
@@ -155,6 +160,7 @@ func (t *fsTest) SetUpTestSuite() {
 		appendThreshold:          0,
 		chunkTransferTimeoutSecs: 10,
 		tmpObjectPrefix:          ".gcsfuse_tmp/",
+		statCache:                t.statCache,
 	}
 	if t.serverCfg.RenameDirLimit == 0 {
 		t.serverCfg.RenameDirLimit = RenameDirLimit
@@ -370,8 +376,33 @@ type fakeBucketManager struct {
 	appendThreshold          int64
 	chunkTransferTimeoutSecs int64
 	tmpObjectPrefix          string
+	statCache                metadata.StatCache
+}
+
+func (bm *fakeBucketManager) SnapshotStatCache(time.Time) []metadata.StatCacheSnapshotEntry {
+	return nil
+}
+
+func (bm *fakeBucketManager) RestoreStatCache([]metadata.StatCacheSnapshotEntry, time.Time) {}
+
+func (bm *fakeBucketManager) SetRateLimits(float64, float64) {}
+
+func (bm *fakeBucketManager) EraseStatCacheEntries(_ string, prefix string) {
+	if bm.statCache != nil {
+		bm.statCache.EraseEntriesWithGivenPrefix(prefix)
+	}
 }
 
+func (bm *fakeBucketManager) EraseStatCacheEntry(_ string, objectName string) {
+	if bm.statCache != nil {
+		bm.statCache.Erase(objectName)
+	}
+}
+
+func (bm *fakeBucketManager) SetStatCacheTTLs(time.Duration, time.Duration) {}
+
+func (bm *fakeBucketManager) SetStatCacheObserver(func(string, []*gcs.MinObject)) {}
+
 func (bm *fakeBucketManager) ShutDown() {}
 
 func (bm *fakeBucketManager) SetUpBucket(
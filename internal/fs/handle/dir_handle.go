@@ -112,6 +112,11 @@ func NewDirHandle(
 	return
 }
 
+// Inode returns the inode backing this handle.
+func (dh *DirHandle) Inode() inode.DirInode {
+	return dh.in
+}
+
 ////////////////////////////////////////////////////////////////////////
 // Helpers
 ////////////////////////////////////////////////////////////////////////
@@ -143,6 +143,7 @@ func createFileInode(
 		false,
 		config,
 		semaphore.NewWeighted(100),
+		nil,
 	)
 }
 
@@ -154,7 +155,7 @@ func (t *fileTest) TestFileHandleWrite() {
 	parent := createDirInode(&t.bucket, &t.clock)
 	config := &cfg.Config{Write: cfg.WriteConfig{EnableStreamingWrites: false}}
 	in := createFileInode(t.T(), &t.bucket, &t.clock, config, parent, "test_obj", nil, false)
-	fh := NewFileHandle(in, nil, false, nil, util.Write, &cfg.Config{}, nil, nil)
+	fh := NewFileHandle(in, nil, false, nil, util.Write, &cfg.Config{}, nil, nil, nil)
 	data := []byte("hello")
 
 	_, err := fh.Write(t.ctx, data, 0)
@@ -178,7 +179,7 @@ func (t *fileTest) Test_IsValidReadManager_NilReadManager() {
 	const objectName = "test_obj"
 	const objectContent = "some data"
 	in := createFileInode(t.T(), &t.bucket, &t.clock, config, parent, objectName, []byte(objectContent), false)
-	fh := NewFileHandle(in, nil, false, nil, util.Read, config, nil, nil)
+	fh := NewFileHandle(in, nil, false, nil, util.Read, config, nil, nil, nil)
 	fh.inode.Lock()
 	defer fh.inode.Unlock()
 	fh.readManager = nil
@@ -194,7 +195,7 @@ func (t *fileTest) Test_IsValidReadManager_GenerationValidation() {
 	const objectName = "test_obj"
 	const objectContent = "some data"
 	in := createFileInode(t.T(), &t.bucket, &t.clock, config, parent, objectName, []byte(objectContent), false)
-	fh := NewFileHandle(in, nil, false, nil, util.Read, config, nil, nil)
+	fh := NewFileHandle(in, nil, false, nil, util.Read, config, nil, nil, nil)
 	fh.inode.Lock()
 	defer fh.inode.Unlock()
 
@@ -234,7 +235,7 @@ func (t *fileTest) Test_IsValidReader_NilReader() {
 	const objectName = "test_obj"
 	const objectContent = "some data"
 	in := createFileInode(t.T(), &t.bucket, &t.clock, config, parent, objectName, []byte(objectContent), false)
-	fh := NewFileHandle(in, nil, false, nil, util.Read, config, nil, nil)
+	fh := NewFileHandle(in, nil, false, nil, util.Read, config, nil, nil, nil)
 	fh.inode.Lock()
 	defer fh.inode.Unlock()
 	fh.reader = nil
@@ -250,7 +251,7 @@ func (t *fileTest) Test_IsValidReader_GenerationValidation() {
 	const objectName = "test_obj"
 	const objectContent = "some data"
 	in := createFileInode(t.T(), &t.bucket, &t.clock, config, parent, objectName, []byte(objectContent), false)
-	fh := NewFileHandle(in, nil, false, nil, util.Read, config, nil, nil)
+	fh := NewFileHandle(in, nil, false, nil, util.Read, config, nil, nil, nil)
 	fh.inode.Lock()
 	defer fh.inode.Unlock()
 
@@ -289,7 +290,7 @@ func (t *fileTest) Test_Read_Success() {
 	expectedData := []byte("hello from reader")
 	parent := createDirInode(&t.bucket, &t.clock)
 	in := createFileInode(t.T(), &t.bucket, &t.clock, nil, parent, "test_obj_reader", expectedData, false)
-	fh := NewFileHandle(in, nil, false, metrics.NewNoopMetrics(), util.Read, &cfg.Config{}, nil, nil)
+	fh := NewFileHandle(in, nil, false, metrics.NewNoopMetrics(), util.Read, &cfg.Config{}, nil, nil, nil)
 	buf := make([]byte, len(expectedData))
 	fh.inode.Lock()
 
@@ -305,7 +306,7 @@ func (t *fileTest) Test_ReadWithReadManager_Success() {
 	expectedData := []byte("hello from readManager")
 	parent := createDirInode(&t.bucket, &t.clock)
 	in := createFileInode(t.T(), &t.bucket, &t.clock, nil, parent, "test_obj_readManager", expectedData, false)
-	fh := NewFileHandle(in, nil, false, metrics.NewNoopMetrics(), util.Read, &cfg.Config{}, nil, nil)
+	fh := NewFileHandle(in, nil, false, metrics.NewNoopMetrics(), util.Read, &cfg.Config{}, nil, nil, nil)
 	buf := make([]byte, len(expectedData))
 	fh.inode.Lock()
 
@@ -331,7 +332,7 @@ func (t *fileTest) Test_ReadWithReadManager_Concurrent() {
 
 	parent := createDirInode(&t.bucket, &t.clock)
 	in := createFileInode(t.T(), &t.bucket, &t.clock, &cfg.Config{}, parent, "concurrent_read_obj", objectContent, false)
-	fh := NewFileHandle(in, nil, false, metrics.NewNoopMetrics(), util.Read, &cfg.Config{}, nil, nil)
+	fh := NewFileHandle(in, nil, false, metrics.NewNoopMetrics(), util.Read, &cfg.Config{}, nil, nil, nil)
 
 	var wg sync.WaitGroup
 	wg.Add(numReaders)
@@ -387,7 +388,7 @@ func (t *fileTest) Test_Read_Concurrent() {
 
 	parent := createDirInode(&t.bucket, &t.clock)
 	in := createFileInode(t.T(), &t.bucket, &t.clock, &cfg.Config{}, parent, "concurrent_read_obj", objectContent, false)
-	fh := NewFileHandle(in, nil, false, metrics.NewNoopMetrics(), util.Read, &cfg.Config{}, nil, nil)
+	fh := NewFileHandle(in, nil, false, metrics.NewNoopMetrics(), util.Read, &cfg.Config{}, nil, nil, nil)
 
 	var wg sync.WaitGroup
 	wg.Add(numReaders)
@@ -449,7 +450,7 @@ func (t *fileTest) Test_ReadWithReadManager_ErrorScenarios() {
 			t.SetupTest()
 			parent := createDirInode(&t.bucket, &t.clock)
 			testInode := createFileInode(t.T(), &t.bucket, &t.clock, nil, parent, object.Name, []byte("data"), false)
-			fh := NewFileHandle(testInode, nil, false, metrics.NewNoopMetrics(), util.Read, &cfg.Config{}, nil, nil)
+			fh := NewFileHandle(testInode, nil, false, metrics.NewNoopMetrics(), util.Read, &cfg.Config{}, nil, nil, nil)
 			fh.inode.Lock()
 			mockRM := new(read_manager.MockReadManager)
 			mockRM.On("ReadAt", t.ctx, dst, int64(0)).Return(gcsx.ReadResponse{}, tc.returnErr)
@@ -486,7 +487,7 @@ func (t *fileTest) Test_Read_ErrorScenarios() {
 			t.SetupTest()
 			parent := createDirInode(&t.bucket, &t.clock)
 			testInode := createFileInode(t.T(), &t.bucket, &t.clock, nil, parent, object.Name, []byte("data"), false)
-			fh := NewFileHandle(testInode, nil, false, metrics.NewNoopMetrics(), util.Read, &cfg.Config{}, nil, nil)
+			fh := NewFileHandle(testInode, nil, false, metrics.NewNoopMetrics(), util.Read, &cfg.Config{}, nil, nil, nil)
 			fh.inode.Lock()
 			mockReader := new(gcsx.MockRandomReader)
 			mockReader.On("ReadAt", t.ctx, dst, int64(0)).Return(gcsx.ObjectData{}, tc.returnErr)
@@ -511,7 +512,7 @@ func (t *fileTest) Test_ReadWithReadManager_FallbackToInode() {
 	object := gcs.MinObject{Name: "test_obj"}
 	parent := createDirInode(&t.bucket, &t.clock)
 	in := createFileInode(t.T(), &t.bucket, &t.clock, nil, parent, object.Name, objectData, true)
-	fh := NewFileHandle(in, nil, false, metrics.NewNoopMetrics(), util.Read, &cfg.Config{}, nil, nil)
+	fh := NewFileHandle(in, nil, false, metrics.NewNoopMetrics(), util.Read, &cfg.Config{}, nil, nil, nil)
 	fh.inode.Lock()
 	mockRM := new(read_manager.MockReadManager)
 	fh.readManager = mockRM
@@ -532,7 +533,7 @@ func (t *fileTest) Test_Read_FallbackToInode() {
 	object := gcs.MinObject{Name: "test_obj"}
 	parent := createDirInode(&t.bucket, &t.clock)
 	in := createFileInode(t.T(), &t.bucket, &t.clock, nil, parent, object.Name, objectData, true)
-	fh := NewFileHandle(in, nil, false, metrics.NewNoopMetrics(), util.Read, &cfg.Config{}, nil, nil)
+	fh := NewFileHandle(in, nil, false, metrics.NewNoopMetrics(), util.Read, &cfg.Config{}, nil, nil, nil)
 	fh.inode.Lock()
 	mockR := new(gcsx.MockRandomReader)
 	fh.reader = mockR
@@ -553,7 +554,7 @@ func (t *fileTest) Test_ReadWithReadManager_ReadManagerInvalidatedByGenerationCh
 
 	parent := createDirInode(&t.bucket, &t.clock)
 	in := createFileInode(t.T(), &t.bucket, &t.clock, &cfg.Config{}, parent, objectName, content1, false)
-	fh := NewFileHandle(in, nil, false, metrics.NewNoopMetrics(), util.Read, &cfg.Config{}, nil, nil)
+	fh := NewFileHandle(in, nil, false, metrics.NewNoopMetrics(), util.Read, &cfg.Config{}, nil, nil, nil)
 
 	// First read, to create a readManager.
 	fh.inode.Lock()
@@ -593,7 +594,7 @@ func (t *fileTest) Test_Read_ReaderInvalidatedByGenerationChange() {
 
 	parent := createDirInode(&t.bucket, &t.clock)
 	in := createFileInode(t.T(), &t.bucket, &t.clock, &cfg.Config{}, parent, objectName, content1, false)
-	fh := NewFileHandle(in, nil, false, metrics.NewNoopMetrics(), util.Read, &cfg.Config{}, nil, nil)
+	fh := NewFileHandle(in, nil, false, metrics.NewNoopMetrics(), util.Read, &cfg.Config{}, nil, nil, nil)
 
 	// First read, to create a reader.
 	fh.inode.Lock()
@@ -647,7 +648,7 @@ func (t *fileTest) TestOpenMode() {
 		parent := createDirInode(&t.bucket, &t.clock)
 		config := &cfg.Config{Write: cfg.WriteConfig{EnableStreamingWrites: false}}
 		in := createFileInode(t.T(), &t.bucket, &t.clock, config, parent, "test_obj", nil, false)
-		fh := NewFileHandle(in, nil, false, nil, tc.openMode, &cfg.Config{}, nil, nil)
+		fh := NewFileHandle(in, nil, false, nil, tc.openMode, &cfg.Config{}, nil, nil, nil)
 
 		openMode := fh.OpenMode()
 
@@ -666,7 +667,7 @@ func (t *fileTest) TestFileHandle_Destroy_WithReaderAndReadManager() {
 	mockReader.On("Destroy").Once()
 	mockReadManager.On("Destroy").Once()
 	// Construct file handle with mocks
-	fh := NewFileHandle(fileInode, nil, false, nil, util.Read, config, nil, nil)
+	fh := NewFileHandle(fileInode, nil, false, nil, util.Read, config, nil, nil, nil)
 	fh.reader = mockReader
 	fh.readManager = mockReadManager
 
@@ -682,7 +683,7 @@ func (t *fileTest) TestFileHandle_Destroy_WithNilReaderAndReadManager() {
 	config := &cfg.Config{}
 	fileInode := createFileInode(t.T(), &t.bucket, &t.clock, config, parent, "destroy_test_nil_obj", nil, false)
 	// Construct file handle with nils
-	fh := NewFileHandle(fileInode, nil, false, nil, util.Read, config, nil, nil)
+	fh := NewFileHandle(fileInode, nil, false, nil, util.Read, config, nil, nil, nil)
 	fh.reader = nil
 	fh.readManager = nil
 
@@ -702,7 +703,7 @@ func (t *fileTest) TestFileHandle_CheckInvariants_WithNonNilReaderAndManager() {
 	// Expectations
 	mockReader.On("CheckInvariants").Once()
 	mockRM.On("CheckInvariants").Once()
-	fh := NewFileHandle(fileInode, nil, false, nil, util.Read, config, nil, nil)
+	fh := NewFileHandle(fileInode, nil, false, nil, util.Read, config, nil, nil, nil)
 	fh.reader = mockReader
 	fh.readManager = mockRM
 
@@ -719,7 +720,7 @@ func (t *fileTest) TestFileHandle_CheckInvariants_WithNilReaderAndManager() {
 	config := &cfg.Config{}
 	in := createFileInode(t.T(), &t.bucket, &t.clock, config, parent, "test_check_invariants_nil", nil, false)
 
-	fh := NewFileHandle(in, nil, false, nil, util.Read, config, nil, nil)
+	fh := NewFileHandle(in, nil, false, nil, util.Read, config, nil, nil, nil)
 
 	// Should not panic even if both are nil
 	assert.NotPanics(t.T(), func() {
@@ -731,7 +732,7 @@ func (t *fileTest) Test_LockHandleAndRelockInode_Lock_NoDeadlockWithContention()
 	parent := createDirInode(&t.bucket, &t.clock)
 	config := &cfg.Config{}
 	in := createFileInode(t.T(), &t.bucket, &t.clock, config, parent, "test_obj_deadlock", []byte("content"), false)
-	fh := NewFileHandle(in, nil, false, nil, util.Read, config, nil, nil)
+	fh := NewFileHandle(in, nil, false, nil, util.Read, config, nil, nil, nil)
 	var wg sync.WaitGroup
 	const numContenders = 10
 	wg.Add(2 * numContenders)
@@ -777,7 +778,7 @@ func (t *fileTest) Test_LockHandleAndRelockInode_RLock_NoDeadlockWithContention(
 	parent := createDirInode(&t.bucket, &t.clock)
 	config := &cfg.Config{}
 	in := createFileInode(t.T(), &t.bucket, &t.clock, config, parent, "test_obj_deadlock", []byte("content"), false)
-	fh := NewFileHandle(in, nil, false, nil, util.Read, config, nil, nil)
+	fh := NewFileHandle(in, nil, false, nil, util.Read, config, nil, nil, nil)
 	var wg sync.WaitGroup
 	const numContenders = 10
 	wg.Add(2 * numContenders)
@@ -823,7 +824,7 @@ func (t *fileTest) Test_LockHandleAndRelockInode_Mixed_NoDeadlockWithContention(
 	parent := createDirInode(&t.bucket, &t.clock)
 	config := &cfg.Config{}
 	in := createFileInode(t.T(), &t.bucket, &t.clock, config, parent, "test_obj_deadlock", []byte("content"), false)
-	fh := NewFileHandle(in, nil, false, nil, util.Read, config, nil, nil)
+	fh := NewFileHandle(in, nil, false, nil, util.Read, config, nil, nil, nil)
 	var wg sync.WaitGroup
 	const numRContenders = 10
 	const numWContenders = 10
@@ -869,7 +870,7 @@ func (t *fileTest) Test_UnlockHandleAndInode() {
 	parent := createDirInode(&t.bucket, &t.clock)
 	config := &cfg.Config{}
 	in := createFileInode(t.T(), &t.bucket, &t.clock, config, parent, "test_obj_deadlock", []byte("content"), false)
-	fh := NewFileHandle(in, nil, false, nil, util.Read, config, nil, nil)
+	fh := NewFileHandle(in, nil, false, nil, util.Read, config, nil, nil, nil)
 
 	var wg sync.WaitGroup
 	const numContenders = 10
@@ -937,7 +938,7 @@ func (t *fileTest) Test_ReadWithReadManager_FullReadSuccessWithBufferedRead() {
 	globalSemaphore := semaphore.NewWeighted(20) // Sufficient blocks for the test
 	parent := createDirInode(&t.bucket, &t.clock)
 	in := createFileInode(t.T(), &t.bucket, &t.clock, config, parent, "read_obj", expectedData, false)
-	fh := NewFileHandle(in, nil, false, metrics.NewNoopMetrics(), util.Read, config, workerPool, globalSemaphore)
+	fh := NewFileHandle(in, nil, false, metrics.NewNoopMetrics(), util.Read, config, workerPool, globalSemaphore, nil)
 	fh.inode.Lock()
 	buf := make([]byte, fileSize)
 
@@ -973,7 +974,7 @@ func (t *fileTest) Test_ReadWithReadManager_ConcurrentReadsWithBufferedReader()
 	// Create mock inode and file handle.
 	parent := createDirInode(&t.bucket, &t.clock)
 	in := createFileInode(t.T(), &t.bucket, &t.clock, config, parent, "read_obj", expectedData, false)
-	fh := NewFileHandle(in, nil, false, metrics.NewNoopMetrics(), util.Read, config, workerPool, globalSemaphore)
+	fh := NewFileHandle(in, nil, false, metrics.NewNoopMetrics(), util.Read, config, workerPool, globalSemaphore, nil)
 	// Use a WaitGroup to synchronize goroutines.
 	var wg sync.WaitGroup
 	wg.Add(numGoroutines)
@@ -1026,7 +1027,7 @@ func (t *fileTest) Test_ReadWithReadManager_WorkloadInsightVisual() {
 	parent := createDirInode(&t.bucket, &t.clock)
 	in := createFileInode(t.T(), &t.bucket, &t.clock, config, parent, "test_obj_visual", content, false)
 	in.Lock()
-	fh := NewFileHandle(in, nil, false, metrics.NewNoopMetrics(), util.Read, config, nil, nil)
+	fh := NewFileHandle(in, nil, false, metrics.NewNoopMetrics(), util.Read, config, nil, nil, nil)
 	in.Unlock()
 
 	// Perform multiple reads and destroy the file-handle.
@@ -0,0 +1,210 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// A collection of tests for hard links made by copying the backing object.
+package fs_test
+
+import (
+	"context"
+	"os"
+	"path"
+	"strconv"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/fs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/fs/inode"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/fake"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"github.com/jacobsa/fuse/fuseops"
+	"github.com/jacobsa/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type HardLinkTest struct {
+	suite.Suite
+	fsTest
+}
+
+func TestHardLinkTestSuite(t *testing.T) {
+	suite.Run(t, new(HardLinkTest))
+}
+
+func (t *HardLinkTest) SetupSuite() {
+	t.serverCfg.NewConfig = &cfg.Config{
+		FileSystem: cfg.FileSystemConfig{
+			EnableHardLinks: true,
+		},
+	}
+	t.fsTest.SetUpTestSuite()
+}
+
+func (t *HardLinkTest) TearDownTest() {
+	t.fsTest.TearDown()
+}
+
+func (t *HardLinkTest) TearDownSuite() {
+	t.fsTest.TearDownTestSuite()
+}
+
+func (t *HardLinkTest) TestLinkCopiesObjectAndRecordsLinkCount() {
+	_, err := storageutil.CreateObject(ctx, bucket, "foo", []byte("taco"))
+	require.NoError(t.T(), err)
+
+	err = os.Link(path.Join(mntDir, "foo"), path.Join(mntDir, "bar"))
+
+	require.NoError(t.T(), err)
+	contents, err := storageutil.ReadObject(ctx, bucket, "bar")
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), "taco", string(contents))
+	for _, name := range []string{"foo", "bar"} {
+		m, _, err := bucket.StatObject(ctx, &gcs.StatObjectRequest{Name: name, ForceFetchFromGcs: true})
+		require.NoError(t.T(), err)
+		assert.Equal(t.T(), "2", m.Metadata[inode.LinkCountMetadataKey], name)
+		fi, err := os.Stat(path.Join(mntDir, name))
+		require.NoError(t.T(), err)
+		assert.Equal(t.T(), uint64(2), uint64(fi.Sys().(*syscall.Stat_t).Nlink), name)
+	}
+}
+
+func (t *HardLinkTest) TestLinkFlushesDirtyContents() {
+	filePath := path.Join(mntDir, "foo")
+	f, err := os.Create(filePath)
+	require.NoError(t.T(), err)
+	defer f.Close()
+	_, err = f.WriteString("burrito")
+	require.NoError(t.T(), err)
+
+	err = os.Link(filePath, path.Join(mntDir, "bar"))
+
+	require.NoError(t.T(), err)
+	contents, err := storageutil.ReadObject(ctx, bucket, "bar")
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), "burrito", string(contents))
+}
+
+func (t *HardLinkTest) TestLinkOntoExistingNameFails() {
+	_, err := storageutil.CreateObject(ctx, bucket, "foo", []byte("taco"))
+	require.NoError(t.T(), err)
+	_, err = storageutil.CreateObject(ctx, bucket, "bar", []byte("burrito"))
+	require.NoError(t.T(), err)
+
+	err = os.Link(path.Join(mntDir, "foo"), path.Join(mntDir, "bar"))
+
+	assert.ErrorIs(t.T(), err, syscall.EEXIST)
+	contents, err := storageutil.ReadObject(ctx, bucket, "bar")
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), "burrito", string(contents))
+}
+
+func (t *HardLinkTest) TestLinkOntoLocalFileFails() {
+	_, err := storageutil.CreateObject(ctx, bucket, "foo", []byte("taco"))
+	require.NoError(t.T(), err)
+	f, err := os.Create(path.Join(mntDir, "bar"))
+	require.NoError(t.T(), err)
+	defer f.Close()
+
+	err = os.Link(path.Join(mntDir, "foo"), path.Join(mntDir, "bar"))
+
+	assert.ErrorIs(t.T(), err, syscall.EEXIST)
+	_, _, err = bucket.StatObject(ctx, &gcs.StatObjectRequest{Name: "bar", ForceFetchFromGcs: true})
+	var notFoundErr *gcs.NotFoundError
+	assert.ErrorAs(t.T(), err, &notFoundErr)
+}
+
+func (t *HardLinkTest) TestLinkToDirectoryFails() {
+	require.NoError(t.T(), os.Mkdir(path.Join(mntDir, "dir"), dirPerms))
+
+	err := os.Link(path.Join(mntDir, "dir"), path.Join(mntDir, "bar"))
+
+	assert.ErrorIs(t.T(), err, syscall.EPERM)
+}
+
+func (t *HardLinkTest) TestUnlinkDecrementsLinkCountOfRemainingLinks() {
+	_, err := storageutil.CreateObject(ctx, bucket, "foo", []byte("taco"))
+	require.NoError(t.T(), err)
+	require.NoError(t.T(), os.Link(path.Join(mntDir, "foo"), path.Join(mntDir, "bar")))
+	require.NoError(t.T(), os.Link(path.Join(mntDir, "foo"), path.Join(mntDir, "baz")))
+
+	err = os.Remove(path.Join(mntDir, "foo"))
+
+	require.NoError(t.T(), err)
+	for _, name := range []string{"bar", "baz"} {
+		m, _, err := bucket.StatObject(ctx, &gcs.StatObjectRequest{Name: name, ForceFetchFromGcs: true})
+		require.NoError(t.T(), err)
+		assert.Equal(t.T(), "2", m.Metadata[inode.LinkCountMetadataKey], name)
+		assert.Equal(t.T(), []string{"bar", "baz"}, inode.Links(m), name)
+		fi, err := os.Stat(path.Join(mntDir, name))
+		require.NoError(t.T(), err)
+		assert.Equal(t.T(), uint64(2), uint64(fi.Sys().(*syscall.Stat_t).Nlink), name)
+	}
+}
+
+func TestUnlinkAndRenameUpdateRemainingLinks(t *testing.T) {
+	ctx := context.Background()
+	bucket := fake.NewFakeBucket(timeutil.RealClock(), "test-bucket", gcs.BucketType{})
+	createWithContents(ctx, t, bucket, "foo", "taco")
+	server, err := fs.NewFileSystem(ctx, &fs.ServerConfig{
+		NewConfig: &cfg.Config{
+			FileSystem: cfg.FileSystemConfig{EnableHardLinks: true},
+			Write:      cfg.WriteConfig{GlobalMaxBlocks: 1},
+			Read:       cfg.ReadConfig{GlobalMaxBlocks: 1},
+		},
+		CacheClock: &timeutil.SimulatedClock{},
+		BucketName: bucket.Name(),
+		BucketManager: &fakeBucketManager{
+			buckets: map[string]gcs.Bucket{bucket.Name(): bucket},
+		},
+		SequentialReadSizeMb: 200,
+		DirTypeCacheTTL:      time.Hour,
+	})
+	require.NoError(t, err, "NewFileSystem")
+	defer server.Destroy()
+	lookUp := &fuseops.LookUpInodeOp{Parent: fuseops.RootInodeID, Name: "foo"}
+	require.NoError(t, server.LookUpInode(ctx, lookUp))
+	for _, name := range []string{"bar", "baz"} {
+		require.NoError(t, server.CreateLink(ctx, &fuseops.CreateLinkOp{Parent: fuseops.RootInodeID, Name: name, Target: lookUp.Entry.Child}))
+	}
+	assertLinks := func(names ...string) {
+		t.Helper()
+		for _, name := range names {
+			m, _, err := bucket.StatObject(ctx, &gcs.StatObjectRequest{Name: name, ForceFetchFromGcs: true})
+			require.NoError(t, err)
+			assert.Equal(t, strconv.Itoa(len(names)), m.Metadata[inode.LinkCountMetadataKey], name)
+			assert.Equal(t, names, inode.Links(m), name)
+		}
+	}
+	assertLinks("bar", "baz", "foo")
+	// An inode the file system knows about adopts the update as well.
+	lookUpBaz := &fuseops.LookUpInodeOp{Parent: fuseops.RootInodeID, Name: "baz"}
+	require.NoError(t, server.LookUpInode(ctx, lookUpBaz))
+
+	require.NoError(t, server.Unlink(ctx, &fuseops.UnlinkOp{Parent: fuseops.RootInodeID, Name: "foo"}))
+	assertLinks("bar", "baz")
+	getAttrs := &fuseops.GetInodeAttributesOp{Inode: lookUpBaz.Entry.Child}
+	require.NoError(t, server.GetInodeAttributes(ctx, getAttrs))
+	assert.Equal(t, uint32(2), getAttrs.Attributes.Nlink)
+
+	require.NoError(t, server.LookUpInode(ctx, &fuseops.LookUpInodeOp{Parent: fuseops.RootInodeID, Name: "bar"}))
+	require.NoError(t, server.Rename(ctx, &fuseops.RenameOp{OldParent: fuseops.RootInodeID, OldName: "bar", NewParent: fuseops.RootInodeID, NewName: "qux"}))
+	assertLinks("baz", "qux")
+
+	require.NoError(t, server.Unlink(ctx, &fuseops.UnlinkOp{Parent: fuseops.RootInodeID, Name: "baz"}))
+	assertLinks("qux")
+}
@@ -0,0 +1,75 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fs
+
+import (
+	"context"
+	"slices"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/fs/inode"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/logger"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+)
+
+// withLink returns a copy of links that includes name.
+func withLink(links []string, name string) []string {
+	if slices.Contains(links, name) {
+		return slices.Clone(links)
+	}
+	return append(slices.Clone(links), name)
+}
+
+// withoutLink returns a copy of links that doesn't include name.
+func withoutLink(links []string, name string) []string {
+	return slices.DeleteFunc(slices.Clone(links), func(l string) bool { return l == name })
+}
+
+// Record the supplied hard links on each of the named objects that still lists
+// member as one of its links. The objects are updated through their inodes
+// where the file system knows them, so that the inodes adopt the new
+// meta-generation. The link count of the objects is advisory, so failures are
+// logged rather than returned.
+//
+// LOCKS_EXCLUDED(fs.mu)
+func (fs *fileSystem) updateLinks(
+	ctx context.Context,
+	bucket gcs.Bucket,
+	base inode.Name,
+	names []string,
+	member string,
+	links []string) {
+	for _, name := range names {
+		fs.mu.Lock()
+		in := fs.generationBackedInodes[inode.NewDescendantName(base, name)]
+		fs.mu.Unlock()
+
+		if f, ok := in.(*inode.FileInode); ok {
+			f.Lock()
+			updated := false
+			if !f.IsLocal() && !f.IsUnlinked() && slices.Contains(f.Links(), member) {
+				updated = f.SetLinks(ctx, links) == nil
+			}
+			f.Unlock()
+			if updated {
+				continue
+			}
+		}
+
+		// The inode may be missing or stale, so go by the latest object.
+		if err := inode.UpdateLinks(ctx, bucket, name, member, links); err != nil {
+			logger.Warnf("Failed to update the hard links of %q: %v", name, err)
+		}
+	}
+}
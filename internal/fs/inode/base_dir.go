@@ -18,6 +18,7 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/metadata"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/locker"
 	"github.com/googlecloudplatform/gcsfuse/v3/metrics"
 
@@ -218,6 +219,14 @@ func (d *baseDirInode) InsertFileIntoTypeCache(_ string) {}
 
 func (d *baseDirInode) EraseFromTypeCache(_ string) {}
 
+func (d *baseDirInode) EraseFromTypeCacheWithGivenPrefix(_ string) {}
+
+func (d *baseDirInode) SnapshotTypeCache() []metadata.TypeCacheSnapshotEntry {
+	return nil
+}
+
+func (d *baseDirInode) RestoreTypeCache(_ []metadata.TypeCacheSnapshotEntry) {}
+
 func (d *baseDirInode) CreateLocalChildFileCore(_ string) (Core, error) {
 	return Core{}, fuse.ENOSYS
 }
@@ -226,6 +235,10 @@ func (d *baseDirInode) CloneToChildFile(ctx context.Context, name string, src *g
 	return nil, fuse.ENOSYS
 }
 
+func (d *baseDirInode) CreateChildLink(ctx context.Context, name string, src *gcs.MinObject, links []string) (*Core, error) {
+	return nil, fuse.ENOSYS
+}
+
 func (d *baseDirInode) CreateChildSymlink(ctx context.Context, name string, target string) (*Core, error) {
 	return nil, fuse.ENOSYS
 }
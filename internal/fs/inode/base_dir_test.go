@@ -105,6 +105,22 @@ func (bm *fakeBucketManager) SetUpBucket(
 	return
 }
 
+func (bm *fakeBucketManager) SnapshotStatCache(time.Time) []metadata.StatCacheSnapshotEntry {
+	return nil
+}
+
+func (bm *fakeBucketManager) RestoreStatCache([]metadata.StatCacheSnapshotEntry, time.Time) {}
+
+func (bm *fakeBucketManager) SetRateLimits(float64, float64) {}
+
+func (bm *fakeBucketManager) EraseStatCacheEntries(string, string) {}
+
+func (bm *fakeBucketManager) EraseStatCacheEntry(string, string) {}
+
+func (bm *fakeBucketManager) SetStatCacheTTLs(time.Duration, time.Duration) {}
+
+func (bm *fakeBucketManager) SetStatCacheObserver(func(string, []*gcs.MinObject)) {}
+
 func (bm *fakeBucketManager) ShutDown() {}
 
 func (bm *fakeBucketManager) SetUpTimes() int {
@@ -116,11 +116,28 @@ type DirInode interface {
 	// EraseFromTypeCache removes the given name from type-cache
 	EraseFromTypeCache(name string)
 
+	// EraseFromTypeCacheWithGivenPrefix removes the names starting with the
+	// given prefix from type-cache.
+	EraseFromTypeCacheWithGivenPrefix(prefix string)
+
+	// SnapshotTypeCache returns the unexpired entries of the type-cache, for
+	// persisting across mounts.
+	SnapshotTypeCache() []metadata.TypeCacheSnapshotEntry
+
+	// RestoreTypeCache seeds the type-cache with persisted entries.
+	RestoreTypeCache(entries []metadata.TypeCacheSnapshotEntry)
+
 	// Like CreateChildFile, except clone the supplied source object instead of
 	// creating an empty object.
 	// Return the full name of the child and the GCS object it backs up.
 	CloneToChildFile(ctx context.Context, name string, src *gcs.MinObject) (*Core, error)
 
+	// Like CloneToChildFile, except fail with *gcs.PreconditionError if a backing
+	// object already exists in GCS, and record the supplied hard links, which
+	// include the child, in the metadata of the clone.
+	// Return the full name of the child and the GCS object it backs up.
+	CreateChildLink(ctx context.Context, name string, src *gcs.MinObject, links []string) (*Core, error)
+
 	// Create a symlink object with the supplied (relative) name and the supplied
 	// target, failing with *gcs.PreconditionError if a backing object already
 	// exists in GCS.
@@ -883,6 +900,21 @@ func (d *dirInode) EraseFromTypeCache(name string) {
 	d.cache.Erase(name)
 }
 
+// LOCKS_REQUIRED(d)
+func (d *dirInode) EraseFromTypeCacheWithGivenPrefix(prefix string) {
+	d.cache.EraseEntriesWithGivenPrefix(prefix)
+}
+
+// LOCKS_REQUIRED(d)
+func (d *dirInode) SnapshotTypeCache() []metadata.TypeCacheSnapshotEntry {
+	return d.cache.Snapshot(d.cacheClock.Now())
+}
+
+// LOCKS_REQUIRED(d)
+func (d *dirInode) RestoreTypeCache(entries []metadata.TypeCacheSnapshotEntry) {
+	d.cache.Restore(d.cacheClock.Now(), entries)
+}
+
 // LOCKS_REQUIRED(d)
 func (d *dirInode) CloneToChildFile(ctx context.Context, name string, src *gcs.MinObject) (*Core, error) {
 	// Erase any existing type information for this name.
@@ -912,6 +944,59 @@ func (d *dirInode) CloneToChildFile(ctx context.Context, name string, src *gcs.M
 	return c, nil
 }
 
+// LOCKS_REQUIRED(d)
+func (d *dirInode) CreateChildLink(ctx context.Context, name string, src *gcs.MinObject, links []string) (*Core, error) {
+	fullName := NewFileName(d.Name(), name)
+	linkMetadata, err := LinkMetadata(links)
+	if err != nil {
+		return nil, err
+	}
+
+	// Clone the source, failing if anything already exists for the name.
+	var precond int64
+	clone, err := d.bucket.CopyObject(
+		ctx,
+		&gcs.CopyObjectRequest{
+			SrcName:                       src.Name,
+			SrcGeneration:                 src.Generation,
+			SrcMetaGenerationPrecondition: &src.MetaGeneration,
+			DstName:                       fullName.GcsObjectName(),
+			DstGenerationPrecondition:     &precond,
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	// Record the links on the clone.
+	o, err := d.bucket.UpdateObject(
+		ctx,
+		&gcs.UpdateObjectRequest{
+			Name:                       clone.Name,
+			Generation:                 clone.Generation,
+			MetaGenerationPrecondition: &clone.MetaGeneration,
+			Metadata:                   linkMetadata,
+		})
+	if err != nil {
+		// Don't leave behind a clone claiming the links of its source.
+		if deleteErr := d.bucket.DeleteObject(ctx, &gcs.DeleteObjectRequest{
+			Name:                       clone.Name,
+			Generation:                 clone.Generation,
+			MetaGenerationPrecondition: &clone.MetaGeneration,
+		}); deleteErr != nil {
+			logger.Warnf("CreateChildLink: failed to delete clone %q: %v", clone.Name, deleteErr)
+		}
+		return nil, fmt.Errorf("UpdateObject: %w", err)
+	}
+	m := storageutil.ConvertObjToMinObject(o)
+
+	d.cache.Insert(d.cacheClock.Now(), name, metadata.RegularFileType)
+	return &Core{
+		Bucket:    d.Bucket(),
+		FullName:  fullName,
+		MinObject: m,
+	}, nil
+}
+
 // LOCKS_REQUIRED(d)
 func (d *dirInode) CreateChildSymlink(ctx context.Context, name string, target string) (*Core, error) {
 	fullName := NewFileName(d.Name(), name)
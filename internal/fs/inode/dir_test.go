@@ -234,7 +234,8 @@ func (t *DirTest) createLocalFileInode(parent Name, name string, id fuseops.Inod
 		&t.clock,
 		true, //localFile
 		&cfg.Config{},
-		semaphore.NewWeighted(math.MaxInt64))
+		semaphore.NewWeighted(math.MaxInt64),
+		nil)
 	return
 }
 
@@ -1332,6 +1333,57 @@ func (t *DirTest) CloneToChildFile_TypeCaching() {
 	ExpectEq(dirObjName, result.MinObject.Name)
 }
 
+func (t *DirTest) CreateChildLink_DestinationDoesntExist() {
+	const srcName = "blah/baz"
+	dstName := path.Join(dirInodeName, "qux")
+
+	// Create the source.
+	src, err := storageutil.CreateObject(t.ctx, t.bucket, srcName, []byte("taco"))
+	AssertEq(nil, err)
+
+	// Call the inode.
+	srcMinObject := storageutil.ConvertObjToMinObject(src)
+	result, err := t.in.CreateChildLink(t.ctx, path.Base(dstName), srcMinObject, []string{srcName, dstName})
+	AssertEq(nil, err)
+	AssertNe(nil, result)
+	AssertNe(nil, result.MinObject)
+	ExpectEq(metadata.RegularFileType, t.getTypeFromCache("qux"))
+
+	ExpectEq(t.bucket.Name(), result.Bucket.Name())
+	ExpectEq(dstName, result.MinObject.Name)
+	ExpectEq("2", result.MinObject.Metadata[LinkCountMetadataKey])
+	ExpectThat(Links(result.MinObject), ElementsAre(srcName, dstName))
+
+	// Check resulting contents.
+	contents, err := storageutil.ReadObject(t.ctx, t.bucket, dstName)
+	AssertEq(nil, err)
+	ExpectEq("taco", string(contents))
+}
+
+func (t *DirTest) CreateChildLink_DestinationExists() {
+	const srcName = "blah/baz"
+	dstName := path.Join(dirInodeName, "qux")
+
+	// Create the source.
+	src, err := storageutil.CreateObject(t.ctx, t.bucket, srcName, []byte("taco"))
+	AssertEq(nil, err)
+
+	// And a destination object that must not be overwritten.
+	_, err = storageutil.CreateObject(t.ctx, t.bucket, dstName, []byte("burrito"))
+	AssertEq(nil, err)
+
+	// Call the inode.
+	srcMinObject := storageutil.ConvertObjToMinObject(src)
+	_, err = t.in.CreateChildLink(t.ctx, path.Base(dstName), srcMinObject, []string{srcName, dstName})
+	var preconditionErr *gcs.PreconditionError
+	ExpectTrue(errors.As(err, &preconditionErr))
+
+	// Check the destination is intact.
+	contents, err := storageutil.ReadObject(t.ctx, t.bucket, dstName)
+	AssertEq(nil, err)
+	ExpectEq("burrito", string(contents))
+}
+
 func (t *DirTest) CreateChildSymlink_DoesntExist() {
 	const name = "qux"
 	const target = "taco"
@@ -1640,6 +1692,19 @@ func (t *DirTest) EraseFromTypeCache() {
 	AssertEq(0, tp)
 }
 
+func (t *DirTest) EraseFromTypeCacheWithGivenPrefix() {
+	t.in.InsertFileIntoTypeCache("abc")
+	t.in.InsertFileIntoTypeCache("abd")
+	t.in.InsertFileIntoTypeCache("b")
+
+	t.in.EraseFromTypeCacheWithGivenPrefix("ab")
+
+	d := t.in.(*dirInode)
+	AssertEq(0, d.cache.Get(d.cacheClock.Now(), "abc"))
+	AssertEq(0, d.cache.Get(d.cacheClock.Now(), "abd"))
+	AssertEq(2, d.cache.Get(d.cacheClock.Now(), "b"))
+}
+
 func (t *DirTest) TestDeleteObjects() {
 	// Arrange
 	parentDirGcsName := t.in.Name().GcsObjectName() // e.g., "foo/bar/"
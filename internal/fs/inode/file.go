@@ -20,6 +20,7 @@ import (
 	"io"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
@@ -43,6 +44,9 @@ import (
 // the format defined by time.RFC3339Nano.
 const (
 	FileMtimeMetadataKey = gcs.MtimeMetadataKey
+	// A GCS object metadata key for the number of hard links to a file, which
+	// is recorded when hard links are enabled. A missing key means one link.
+	LinkCountMetadataKey = "gcsfuse_link_count"
 	// TODO(b/447991081): Update streaming writes semantic message once semantics for ZB are updated on semantics doc.
 	StreamingWritesSemantics = "Streaming writes is supported for sequential writes to new/empty files. " +
 		"For more details, see: https://github.com/GoogleCloudPlatform/gcsfuse/blob/master/docs/semantics.md#writes"
@@ -123,6 +127,9 @@ type FileInode struct {
 	// Limits the max number of blocks that can be created across file system when
 	// streaming writes are enabled.
 	globalMaxWriteBlocksSem *semaphore.Weighted
+
+	// Where streaming uploads are journaled, or nil if they aren't.
+	uploadJournal *bufferedwrites.UploadJournal
 }
 
 var _ Inode = &FileInode{}
@@ -146,7 +153,8 @@ func NewFileInode(
 	mtimeClock timeutil.Clock,
 	localFile bool,
 	cfg *cfg.Config,
-	globalMaxBlocksSem *semaphore.Weighted) (f *FileInode) {
+	globalMaxBlocksSem *semaphore.Weighted,
+	uploadJournal *bufferedwrites.UploadJournal) (f *FileInode) {
 	// Set up the basic struct.
 	var minObj gcs.MinObject
 	if m != nil {
@@ -165,6 +173,7 @@ func NewFileInode(
 		unlinked:                false,
 		config:                  cfg,
 		globalMaxWriteBlocksSem: globalMaxBlocksSem,
+		uploadJournal:           uploadJournal,
 	}
 	var err error
 	f.MRDWrapper, err = gcsx.NewMultiRangeDownloaderWrapper(bucket, &minObj, cfg)
@@ -536,7 +545,7 @@ func (f *FileInode) Attributes(
 		}
 	}
 
-	attrs.Nlink = 1
+	attrs.Nlink = f.LinkCount()
 
 	// For local files, also checking if file is unlinked locally.
 	if f.IsLocal() && f.IsUnlinked() {
@@ -787,6 +796,42 @@ func (f *FileInode) SetMtime(
 	return
 }
 
+// LinkCount returns the number of hard links to this file recorded in the
+// metadata of the backing object.
+//
+// LOCKS_REQUIRED(f.mu)
+func (f *FileInode) LinkCount() uint32 {
+	if _, ok := f.src.Metadata[LinksMetadataKey]; ok {
+		return uint32(len(f.Links()))
+	}
+	n, err := strconv.ParseUint(f.src.Metadata[LinkCountMetadataKey], 10, 32)
+	if err != nil || n == 0 {
+		return 1
+	}
+	return uint32(n)
+}
+
+// Links returns the names of the objects recorded as hard links to this file,
+// including its own.
+//
+// LOCKS_REQUIRED(f.mu)
+func (f *FileInode) Links() []string {
+	return Links(&f.src)
+}
+
+// SetLinks records the supplied hard links to this file, and their number, in
+// the metadata of the backing object.
+//
+// REQUIRES: !f.IsLocal()
+// LOCKS_REQUIRED(f.mu)
+func (f *FileInode) SetLinks(ctx context.Context, links []string) error {
+	metadata, err := LinkMetadata(links)
+	if err != nil {
+		return err
+	}
+	return f.updateMetadata(ctx, metadata)
+}
+
 func (f *FileInode) fetchLatestGcsObject(ctx context.Context) (*gcs.Object, error) {
 	// When listObjects call is made, we fetch data with projection set as noAcl
 	// which means acls and owner properties are not returned. So the f.src object
@@ -989,6 +1034,67 @@ func (f *FileInode) Truncate(
 	return false, f.truncateUsingTempFile(ctx, size)
 }
 
+// Make sure f.content != nil, for operations that only work on the temp file.
+//
+// LOCKS_REQUIRED(f.mu)
+func (f *FileInode) ensureTempFile(ctx context.Context) error {
+	// Local files that haven't been written yet have no object to read from.
+	if f.local {
+		return f.CreateEmptyTempFile(ctx)
+	}
+	if err := f.ensureContent(ctx); err != nil {
+		return fmt.Errorf("ensureContent: %w", err)
+	}
+	return nil
+}
+
+// Fallocate preallocates the range [offset, offset+length) of the file, with
+// semantics matching fallocate(2). Unless keepSize is set, the file is extended
+// with zeros to cover the range. Space is never reserved in GCS, so the range
+// is only ever allocated in the temp file. Files being written with buffered
+// writes are not supported.
+//
+// LOCKS_REQUIRED(f.mu)
+func (f *FileInode) Fallocate(ctx context.Context, offset int64, length int64, keepSize bool) error {
+	if f.bwh != nil {
+		return fmt.Errorf("fallocate with buffered writes: %w", syscall.EOPNOTSUPP)
+	}
+	if keepSize {
+		// Nothing to allocate up front.
+		return nil
+	}
+
+	err := f.ensureTempFile(ctx)
+	if err != nil {
+		return err
+	}
+	sr, err := f.content.Stat()
+	if err != nil {
+		return fmt.Errorf("Stat: %w", err)
+	}
+	if end := offset + length; end > sr.Size {
+		return f.content.Truncate(end)
+	}
+	return nil
+}
+
+// PunchHole zeroes the range [offset, offset+length) of the file without
+// changing its size, as for fallocate(2) with FALLOC_FL_PUNCH_HOLE. Files
+// being written with buffered writes are not supported.
+//
+// LOCKS_REQUIRED(f.mu)
+func (f *FileInode) PunchHole(ctx context.Context, offset int64, length int64) error {
+	if f.bwh != nil {
+		return fmt.Errorf("punch hole with buffered writes: %w", syscall.EOPNOTSUPP)
+	}
+
+	err := f.ensureTempFile(ctx)
+	if err != nil {
+		return err
+	}
+	return f.content.PunchHole(offset, length)
+}
+
 // Ensures cache content on read if content cache enabled
 func (f *FileInode) CacheEnsureContent(ctx context.Context) (err error) {
 	if f.localFileCache {
@@ -1053,6 +1159,7 @@ func (f *FileInode) InitBufferedWriteHandlerIfEligible(ctx context.Context, open
 			MaxBlocksPerFile:         f.config.Write.MaxBlocksPerFile,
 			GlobalMaxBlocksSem:       f.globalMaxWriteBlocksSem,
 			ChunkTransferTimeoutSecs: f.config.GcsRetries.ChunkTransferTimeoutSecs,
+			Journal:                  f.uploadJournal,
 		})
 		if errors.Is(err, block.CantAllocateAnyBlockError) {
 			logger.Warnf("File %s will use legacy staged writes because concurrent streaming write "+
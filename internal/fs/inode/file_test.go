@@ -22,6 +22,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"syscall"
 	"testing"
 	"time"
 
@@ -152,7 +153,8 @@ func (t *FileTest) createInodeWithLocalParam(fileName string, local bool) {
 		&t.clock,
 		local,
 		&cfg.Config{},
-		semaphore.NewWeighted(math.MaxInt64))
+		semaphore.NewWeighted(math.MaxInt64),
+		nil)
 
 	t.in.Lock()
 }
@@ -541,6 +543,85 @@ func (t *FileTest) TestTruncateNegative() {
 	assert.False(t.T(), gcsSynced)
 }
 
+func (t *FileTest) TestFallocateExtendsFile() {
+	assert.Equal(t.T(), "taco", t.initialContents)
+
+	err := t.in.Fallocate(t.ctx, 2, 4, false)
+
+	require.NoError(t.T(), err)
+	attrs, err := t.in.Attributes(t.ctx, true)
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), uint64(6), attrs.Size)
+	var buf [1024]byte
+	n, err := t.in.Read(t.ctx, buf[:], 0)
+	if err == io.EOF {
+		err = nil
+	}
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), "taco\x00\x00", string(buf[:n]))
+}
+
+func (t *FileTest) TestFallocateWithinFileLeavesItUnchanged() {
+	assert.Equal(t.T(), "taco", t.initialContents)
+
+	err := t.in.Fallocate(t.ctx, 0, 2, false)
+
+	require.NoError(t.T(), err)
+	attrs, err := t.in.Attributes(t.ctx, true)
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), uint64(4), attrs.Size)
+}
+
+func (t *FileTest) TestFallocateKeepSize() {
+	assert.Equal(t.T(), "taco", t.initialContents)
+
+	err := t.in.Fallocate(t.ctx, 0, 100, true)
+
+	require.NoError(t.T(), err)
+	// Nothing needs to be downloaded.
+	assert.Nil(t.T(), t.in.content)
+	attrs, err := t.in.Attributes(t.ctx, true)
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), uint64(4), attrs.Size)
+}
+
+func (t *FileTest) TestFallocateLocalFile() {
+	t.createInodeWithLocalParam("test", true)
+
+	err := t.in.Fallocate(t.ctx, 0, 3, false)
+
+	require.NoError(t.T(), err)
+	attrs, err := t.in.Attributes(t.ctx, true)
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), uint64(3), attrs.Size)
+}
+
+func (t *FileTest) TestPunchHole() {
+	assert.Equal(t.T(), "taco", t.initialContents)
+
+	err := t.in.PunchHole(t.ctx, 1, 2)
+
+	require.NoError(t.T(), err)
+	var buf [1024]byte
+	n, err := t.in.Read(t.ctx, buf[:], 0)
+	if err == io.EOF {
+		err = nil
+	}
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), "t\x00\x00o", string(buf[:n]))
+}
+
+func (t *FileTest) TestFallocateAndPunchHoleWithBufferedWritesAreNotSupported() {
+	t.createInodeWithLocalParam("test", true)
+	t.in.config = &cfg.Config{Write: *getWriteConfig()}
+	t.createBufferedWriteHandler(true, util.Write)
+
+	assert.ErrorIs(t.T(), t.in.Fallocate(t.ctx, 0, 10, false), syscall.EOPNOTSUPP)
+	assert.ErrorIs(t.T(), t.in.Fallocate(t.ctx, 0, 10, true), syscall.EOPNOTSUPP)
+	assert.ErrorIs(t.T(), t.in.PunchHole(t.ctx, 0, 10), syscall.EOPNOTSUPP)
+	assert.Nil(t.T(), t.in.content)
+}
+
 func (t *FileTest) TestWriteThenSync() {
 	testcases := []struct {
 		name     string
@@ -1329,6 +1410,26 @@ func (t *FileTest) TestOpenReader_ThrowsFileClobberedError() {
 	assert.True(t.T(), errors.As(err, &fcErr), "expected FileClobberedError but got %v", err)
 }
 
+func (t *FileTest) TestSetLinks() {
+	name := t.in.Name().GcsObjectName()
+	assert.Equal(t.T(), uint32(1), t.in.LinkCount())
+	assert.Equal(t.T(), []string{name}, t.in.Links())
+
+	err := t.in.SetLinks(t.ctx, []string{name, "qux", "bar"})
+
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), uint32(3), t.in.LinkCount())
+	assert.Equal(t.T(), []string{"bar", name, "qux"}, t.in.Links())
+	attrs, err := t.in.Attributes(t.ctx, true)
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), uint32(3), attrs.Nlink)
+	// The links are persisted in the object metadata.
+	m, _, err := t.bucket.StatObject(t.ctx, &gcs.StatObjectRequest{Name: name})
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), "3", m.Metadata[LinkCountMetadataKey])
+	assert.Equal(t.T(), []string{"bar", name, "qux"}, Links(m))
+}
+
 func (t *FileTest) TestSetMtime_ContentNotFaultedIn() {
 	var err error
 	var attrs fuseops.InodeAttributes
@@ -0,0 +1,119 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inode
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"syscall"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+)
+
+// The hard links of a file are emulated by copies of its backing object. Each
+// copy records the names of all the copies in the metadata, so that unlinking
+// or renaming one of them can update the link counts of the others.
+
+// A GCS object metadata key for the names of the objects that are hard links
+// to the same file, including the object itself, as a sorted JSON array.
+const LinksMetadataKey = "gcsfuse_links"
+
+// The link names share the custom metadata of an object with user extended
+// attributes, so leave them at least half of it.
+const maxLinksMetadataBytes = maxCustomMetadataBytes / 2
+
+// Links returns the names of the objects recorded as hard links to the same
+// file as o, including o itself.
+func Links(o *gcs.MinObject) []string {
+	links := recordedLinks(o)
+	if !slices.Contains(links, o.Name) {
+		// The object was copied, renamed or overwritten by someone else.
+		return []string{o.Name}
+	}
+	return links
+}
+
+// recordedLinks returns the names of the objects recorded in the metadata of o
+// as hard links, which may no longer include o itself.
+func recordedLinks(o *gcs.MinObject) []string {
+	var links []string
+	if err := json.Unmarshal([]byte(o.Metadata[LinksMetadataKey]), &links); err != nil {
+		return nil
+	}
+	return links
+}
+
+// LinkMetadata returns the custom metadata recording that an object is one of
+// the supplied hard links. It fails with EMLINK if there are too many names to
+// record.
+func LinkMetadata(links []string) (map[string]*string, error) {
+	links = slices.Clone(links)
+	slices.Sort(links)
+	links = slices.Compact(links)
+	encoded, err := json.Marshal(links)
+	if err != nil {
+		return nil, fmt.Errorf("json.Marshal: %w", err)
+	}
+	if len(LinksMetadataKey)+len(encoded) > maxLinksMetadataBytes {
+		return nil, syscall.EMLINK
+	}
+
+	formattedLinks := string(encoded)
+	formattedCount := strconv.Itoa(len(links))
+	return map[string]*string{
+		LinksMetadataKey:     &formattedLinks,
+		LinkCountMetadataKey: &formattedCount,
+	}, nil
+}
+
+// UpdateLinks records the supplied hard links on the latest generation of the
+// named object, provided that it still lists member as one of its links, as
+// the object a link was renamed to does. It does nothing if the object no
+// longer exists, or has been replaced by one that isn't linked to member.
+func UpdateLinks(ctx context.Context, bucket gcs.Bucket, name string, member string, links []string) error {
+	m, _, err := bucket.StatObject(ctx, &gcs.StatObjectRequest{
+		Name:              name,
+		ForceFetchFromGcs: true,
+	})
+	var notFoundErr *gcs.NotFoundError
+	if errors.As(err, &notFoundErr) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("StatObject: %w", err)
+	}
+	if !slices.Contains(recordedLinks(m), member) {
+		return nil
+	}
+
+	metadata, err := LinkMetadata(links)
+	if err != nil {
+		return err
+	}
+	_, err = bucket.UpdateObject(ctx, &gcs.UpdateObjectRequest{
+		Name:                       m.Name,
+		Generation:                 m.Generation,
+		MetaGenerationPrecondition: &m.MetaGeneration,
+		Metadata:                   metadata,
+	})
+	if err != nil {
+		return fmt.Errorf("UpdateObject: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inode
+
+import (
+	"strings"
+	"syscall"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (t *FileTest) TestLinkMetadataFailsForTooManyLinks() {
+	links := []string{strings.Repeat("a", maxLinksMetadataBytes), "b"}
+
+	_, err := LinkMetadata(links)
+
+	assert.ErrorIs(t.T(), err, syscall.EMLINK)
+}
+
+func (t *FileTest) TestUpdateLinksUpdatesObjectListingMember() {
+	metadata, err := LinkMetadata([]string{"bar", "baz", "qux"})
+	require.NoError(t.T(), err)
+	_, err = t.bucket.CreateObject(t.ctx, &gcs.CreateObjectRequest{
+		Name:     "bar",
+		Contents: strings.NewReader("taco"),
+		Metadata: map[string]string{
+			LinkCountMetadataKey: *metadata[LinkCountMetadataKey],
+			LinksMetadataKey:     *metadata[LinksMetadataKey],
+		},
+	})
+	require.NoError(t.T(), err)
+
+	err = UpdateLinks(t.ctx, t.bucket, "bar", "qux", []string{"bar", "baz"})
+
+	require.NoError(t.T(), err)
+	m, _, err := t.bucket.StatObject(t.ctx, &gcs.StatObjectRequest{Name: "bar", ForceFetchFromGcs: true})
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), "2", m.Metadata[LinkCountMetadataKey])
+	assert.Equal(t.T(), []string{"bar", "baz"}, Links(m))
+}
+
+func (t *FileTest) TestUpdateLinksIgnoresObjectNotListingMember() {
+	_, err := storageutil.CreateObject(t.ctx, t.bucket, "bar", []byte("taco"))
+	require.NoError(t.T(), err)
+
+	err = UpdateLinks(t.ctx, t.bucket, "bar", "qux", []string{"bar", "baz"})
+
+	require.NoError(t.T(), err)
+	m, _, err := t.bucket.StatObject(t.ctx, &gcs.StatObjectRequest{Name: "bar", ForceFetchFromGcs: true})
+	require.NoError(t.T(), err)
+	assert.NotContains(t.T(), m.Metadata, LinkCountMetadataKey)
+	assert.NotContains(t.T(), m.Metadata, LinksMetadataKey)
+}
+
+func (t *FileTest) TestUpdateLinksIgnoresMissingObject() {
+	err := UpdateLinks(t.ctx, t.bucket, "bar", "qux", []string{"bar", "baz"})
+
+	assert.NoError(t.T(), err)
+}
@@ -567,6 +567,23 @@ func (t *NonHNSDirTest) TestCreateChildDirWhenBucketTypeIsNonHNSWithSuccess() {
 	assert.Equal(t.T(), metadata.ExplicitDirType, t.typeCache.Get(t.fixedTime.Now(), name))
 }
 
+func (t *NonHNSDirTest) TestCreateChildLinkDeletesCloneWhenLinkCountUpdateFails() {
+	const name = "qux"
+	dstName := path.Join(dirInodeName, name)
+	src := &gcs.MinObject{Name: "foo", Generation: 3, MetaGeneration: 1}
+	clone := &gcs.Object{Name: dstName, Generation: 7, MetaGeneration: 1}
+	t.mockBucket.On("CopyObject", t.ctx, mock.Anything).Return(clone, nil)
+	t.mockBucket.On("UpdateObject", t.ctx, mock.Anything).Return((*gcs.Object)(nil), fmt.Errorf("mock error"))
+	t.mockBucket.On("DeleteObject", t.ctx, &gcs.DeleteObjectRequest{Name: dstName, Generation: 7, MetaGenerationPrecondition: &clone.MetaGeneration}).Return(nil)
+
+	result, err := t.in.CreateChildLink(t.ctx, name, src, []string{src.Name, dstName})
+
+	t.mockBucket.AssertExpectations(t.T())
+	assert.Error(t.T(), err)
+	assert.Nil(t.T(), result)
+	assert.Equal(t.T(), metadata.Type(0), t.typeCache.Get(t.fixedTime.Now(), name))
+}
+
 func (t *HNSDirTest) TestDeleteObjects() {
 	// Arrange
 	objectNames := []string{"dir1/file1.txt", "dir2/"}
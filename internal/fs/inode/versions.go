@@ -0,0 +1,501 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inode
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/metadata"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/gcsx"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/locker"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/jacobsa/fuse/fuseops"
+	"github.com/jacobsa/fuse/fuseutil"
+	"golang.org/x/net/context"
+)
+
+// VersionsDirSuffix is the suffix of the names of the virtual directories
+// holding the noncurrent generations of objects, e.g. "file.txt@versions".
+const VersionsDirSuffix = "@versions"
+
+const versionTimeLayout = "20060102T150405Z"
+
+// Version is a noncurrent generation of an object, named after its creation
+// time and generation, e.g. "20260102T150405Z-1767366245000000", so that
+// names sort oldest first.
+type Version struct {
+	Name   string
+	Object *gcs.MinObject
+}
+
+// NoncurrentVersions lists the noncurrent generations of the named object,
+// oldest first. Buckets that can't list versions fail with ENOTSUP.
+func NoncurrentVersions(ctx context.Context, bucket gcs.Bucket, objectName string) ([]Version, error) {
+	var versions []Version
+	req := &gcs.ListObjectsRequest{Prefix: objectName, Versions: true}
+	for {
+		listing, err := bucket.ListObjects(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		if len(listing.Lifetimes) != len(listing.MinObjects) {
+			return nil, fmt.Errorf("the bucket doesn't list versions: %w", syscall.ENOTSUP)
+		}
+
+		// The versions of the name are listed first among the objects it is a
+		// prefix of.
+		done := listing.ContinuationToken == ""
+		for i, o := range listing.MinObjects {
+			if o.Name != objectName {
+				done = true
+				break
+			}
+			l := listing.Lifetimes[i]
+			if l.Deleted.IsZero() {
+				continue
+			}
+			versions = append(versions, Version{
+				Name:   fmt.Sprintf("%s-%d", l.Created.UTC().Format(versionTimeLayout), o.Generation),
+				Object: o,
+			})
+		}
+		if done {
+			return versions, nil
+		}
+		req.ContinuationToken = listing.ContinuationToken
+	}
+}
+
+////////////////////////////////////////////////////////////////////////
+// VersionsDirInode
+////////////////////////////////////////////////////////////////////////
+
+// VersionsDirInode is a read-only directory of the noncurrent generations of
+// an object, named as by NoncurrentVersions. Its children are looked up with
+// LookUpVersion rather than LookUpChild.
+//
+// It isn't a BucketOwnedInode, so that it isn't mistaken for a directory of
+// objects. Renames into or out of it fail with EXDEV.
+type VersionsDirInode struct {
+	/////////////////////////
+	// Constant data
+	/////////////////////////
+
+	id fuseops.InodeID
+
+	// INVARIANT: name.IsDir()
+	name Name
+
+	// The name of the object whose versions are listed.
+	objectName string
+
+	bucket *gcsx.SyncerBucket
+	attrs  fuseops.InodeAttributes
+
+	/////////////////////////
+	// Mutable state
+	/////////////////////////
+
+	mu locker.RWLocker
+
+	// GUARDED_BY(mu)
+	lc lookupCount
+}
+
+var _ DirInode = &VersionsDirInode{}
+
+// NewVersionsDirInode creates a directory inode listing the noncurrent
+// generations of the named object in the supplied bucket.
+func NewVersionsDirInode(
+	id fuseops.InodeID,
+	name Name,
+	objectName string,
+	bucket *gcsx.SyncerBucket,
+	attrs fuseops.InodeAttributes) (d *VersionsDirInode) {
+	d = &VersionsDirInode{
+		id:         id,
+		name:       name,
+		objectName: objectName,
+		bucket:     bucket,
+		attrs:      attrs,
+	}
+	d.lc.Init(id)
+	d.mu = locker.NewRW("VersionsDirInode"+name.GcsObjectName(), func() {})
+
+	return
+}
+
+func (d *VersionsDirInode) Lock() {
+	d.mu.Lock()
+}
+
+func (d *VersionsDirInode) Unlock() {
+	d.mu.Unlock()
+}
+
+func (d *VersionsDirInode) RLock() {
+	d.mu.RLock()
+}
+
+func (d *VersionsDirInode) RUnlock() {
+	d.mu.RUnlock()
+}
+
+func (d *VersionsDirInode) LockForChildLookup() {
+	d.mu.RLock()
+}
+
+func (d *VersionsDirInode) UnlockForChildLookup() {
+	d.mu.RUnlock()
+}
+
+func (d *VersionsDirInode) ID() fuseops.InodeID {
+	return d.id
+}
+
+func (d *VersionsDirInode) Name() Name {
+	return d.name
+}
+
+// ObjectBucket returns the bucket of the object whose versions are listed.
+func (d *VersionsDirInode) ObjectBucket() *gcsx.SyncerBucket {
+	return d.bucket
+}
+
+// LOCKS_REQUIRED(d)
+func (d *VersionsDirInode) IncrementLookupCount() {
+	d.lc.Inc()
+}
+
+// LOCKS_REQUIRED(d)
+func (d *VersionsDirInode) DecrementLookupCount(n uint64) (destroy bool) {
+	destroy = d.lc.Dec(n)
+	return
+}
+
+// LOCKS_REQUIRED(d)
+func (d *VersionsDirInode) Destroy() (err error) {
+	// Nothing interesting to do.
+	return
+}
+
+// LOCKS_REQUIRED(d)
+func (d *VersionsDirInode) Attributes(
+	ctx context.Context, clobberedCheck bool) (attrs fuseops.InodeAttributes, err error) {
+	attrs = d.attrs
+	attrs.Nlink = 1
+
+	return
+}
+
+// LookUpVersion returns the noncurrent generation with the given name, or nil
+// if there is none.
+//
+// LOCKS_REQUIRED(d)
+func (d *VersionsDirInode) LookUpVersion(ctx context.Context, name string) (*gcs.MinObject, error) {
+	versions, err := NoncurrentVersions(ctx, d.bucket, d.objectName)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range versions {
+		if v.Name == name {
+			return v.Object, nil
+		}
+	}
+	return nil, nil
+}
+
+// Versions are looked up with LookUpVersion.
+//
+// LOCKS_REQUIRED(d)
+func (d *VersionsDirInode) LookUpChild(ctx context.Context, name string) (*Core, error) {
+	return nil, nil
+}
+
+func (d *VersionsDirInode) ReadDescendants(ctx context.Context, limit int) (map[Name]*Core, error) {
+	return nil, syscall.ENOTSUP
+}
+
+// LOCKS_REQUIRED(d)
+func (d *VersionsDirInode) ReadEntries(
+	ctx context.Context,
+	tok string) (entries []fuseutil.Dirent, unsupportedPaths []string, newTok string, err error) {
+	versions, err := NoncurrentVersions(ctx, d.bucket, d.objectName)
+	if err != nil {
+		return
+	}
+	for _, v := range versions {
+		entries = append(entries, fuseutil.Dirent{
+			Name: v.Name,
+			Type: fuseutil.DT_File,
+		})
+	}
+
+	return
+}
+
+// The cores returned are named after the versions rather than their object,
+// so they fail SanityCheck: their inodes are minted with NewVersionInode.
+//
+// LOCKS_REQUIRED(d)
+func (d *VersionsDirInode) ReadEntryCores(ctx context.Context, tok string) (cores map[Name]*Core, unsupportedPaths []string, newTok string, err error) {
+	versions, err := NoncurrentVersions(ctx, d.bucket, d.objectName)
+	if err != nil {
+		return
+	}
+	cores = make(map[Name]*Core)
+	for _, v := range versions {
+		name := NewFileName(d.name, v.Name)
+		cores[name] = &Core{
+			FullName:  name,
+			Bucket:    d.bucket,
+			MinObject: v.Object,
+		}
+	}
+
+	return
+}
+
+func (d *VersionsDirInode) LocalFileEntries(localFileInodes map[Name]Inode) (localEntries map[string]fuseutil.Dirent) {
+	return nil
+}
+
+func (d *VersionsDirInode) ShouldInvalidateKernelListCache(ttl time.Duration) bool {
+	// Versions are added whenever the object is replaced.
+	return true
+}
+
+func (d *VersionsDirInode) InvalidateKernelListCache() {}
+
+func (d *VersionsDirInode) IsUnlinked() bool {
+	return false
+}
+
+func (d *VersionsDirInode) Unlink() {}
+
+// The type cache only holds children looked up with LookUpChild.
+
+func (d *VersionsDirInode) InsertFileIntoTypeCache(_ string) {}
+
+func (d *VersionsDirInode) EraseFromTypeCache(_ string) {}
+
+func (d *VersionsDirInode) EraseFromTypeCacheWithGivenPrefix(_ string) {}
+
+func (d *VersionsDirInode) SnapshotTypeCache() []metadata.TypeCacheSnapshotEntry {
+	return nil
+}
+
+func (d *VersionsDirInode) RestoreTypeCache(_ []metadata.TypeCacheSnapshotEntry) {}
+
+// A versions directory is read-only. Attempts to modify it fail with EROFS.
+
+func (d *VersionsDirInode) CreateChildFile(ctx context.Context, name string) (*Core, error) {
+	return nil, syscall.EROFS
+}
+
+func (d *VersionsDirInode) CreateLocalChildFileCore(_ string) (Core, error) {
+	return Core{}, syscall.EROFS
+}
+
+func (d *VersionsDirInode) CloneToChildFile(ctx context.Context, name string, src *gcs.MinObject) (*Core, error) {
+	return nil, syscall.EROFS
+}
+
+func (d *VersionsDirInode) CreateChildLink(ctx context.Context, name string, src *gcs.MinObject, links []string) (*Core, error) {
+	return nil, syscall.EROFS
+}
+
+func (d *VersionsDirInode) CreateChildSymlink(ctx context.Context, name string, target string) (*Core, error) {
+	return nil, syscall.EROFS
+}
+
+func (d *VersionsDirInode) CreateChildDir(ctx context.Context, name string) (*Core, error) {
+	return nil, syscall.EROFS
+}
+
+func (d *VersionsDirInode) DeleteChildFile(
+	ctx context.Context,
+	name string,
+	generation int64,
+	metaGeneration *int64) error {
+	return syscall.EROFS
+}
+
+func (d *VersionsDirInode) DeleteChildDir(
+	ctx context.Context,
+	name string,
+	isImplicitDir bool,
+	dirInode DirInode) error {
+	return syscall.EROFS
+}
+
+func (d *VersionsDirInode) DeleteObjects(ctx context.Context, objectNames []string) error {
+	return syscall.EROFS
+}
+
+func (d *VersionsDirInode) RenameFile(ctx context.Context, fileToRename *gcs.MinObject, destinationFileName string) (*gcs.Object, error) {
+	return nil, syscall.EROFS
+}
+
+func (d *VersionsDirInode) RenameFolder(ctx context.Context, folderName string, destinationFolderId string) (*gcs.Folder, error) {
+	return nil, syscall.EROFS
+}
+
+////////////////////////////////////////////////////////////////////////
+// VersionInode
+////////////////////////////////////////////////////////////////////////
+
+// VersionInode is a read-only file holding a noncurrent generation of an
+// object, found in a VersionsDirInode. Generations are immutable, so reads go
+// straight to GCS without any caching.
+type VersionInode struct {
+	/////////////////////////
+	// Constant data
+	/////////////////////////
+
+	id     fuseops.InodeID
+	name   Name
+	bucket *gcsx.SyncerBucket
+	src    gcs.MinObject
+	attrs  fuseops.InodeAttributes
+
+	/////////////////////////
+	// Mutable state
+	/////////////////////////
+
+	mu sync.Mutex
+
+	// GUARDED_BY(mu)
+	lc lookupCount
+}
+
+var _ Inode = &VersionInode{}
+
+// NewVersionInode creates a file inode for the supplied noncurrent generation.
+// The write permission bits of attrs.Mode are cleared.
+func NewVersionInode(
+	id fuseops.InodeID,
+	name Name,
+	bucket *gcsx.SyncerBucket,
+	m *gcs.MinObject,
+	attrs fuseops.InodeAttributes) (v *VersionInode) {
+	v = &VersionInode{
+		id:     id,
+		name:   name,
+		bucket: bucket,
+		src:    *m,
+		attrs: fuseops.InodeAttributes{
+			Size:  m.Size,
+			Nlink: 1,
+			Uid:   attrs.Uid,
+			Gid:   attrs.Gid,
+			Mode:  attrs.Mode &^ 0222,
+			Atime: m.Updated,
+			Ctime: m.Updated,
+			Mtime: m.Updated,
+		},
+	}
+	v.lc.Init(id)
+
+	return
+}
+
+func (v *VersionInode) Lock() {
+	v.mu.Lock()
+}
+
+func (v *VersionInode) Unlock() {
+	v.mu.Unlock()
+}
+
+func (v *VersionInode) ID() fuseops.InodeID {
+	return v.id
+}
+
+func (v *VersionInode) Name() Name {
+	return v.name
+}
+
+// Source returns the generation held by the inode.
+func (v *VersionInode) Source() *gcs.MinObject {
+	return &v.src
+}
+
+// LOCKS_REQUIRED(v.mu)
+func (v *VersionInode) IncrementLookupCount() {
+	v.lc.Inc()
+}
+
+// LOCKS_REQUIRED(v.mu)
+func (v *VersionInode) DecrementLookupCount(n uint64) (destroy bool) {
+	destroy = v.lc.Dec(n)
+	return
+}
+
+// LOCKS_REQUIRED(v.mu)
+func (v *VersionInode) Destroy() (err error) {
+	// Nothing to do.
+	return
+}
+
+func (v *VersionInode) Attributes(
+	ctx context.Context, clobberedCheck bool) (attrs fuseops.InodeAttributes, err error) {
+	attrs = v.attrs
+	return
+}
+
+func (v *VersionInode) Unlink() {
+}
+
+// Read reads the generation into dst starting at the given offset, returning
+// io.EOF if it ends before dst is full.
+//
+// Does not require the lock to be held.
+func (v *VersionInode) Read(ctx context.Context, dst []byte, offset int64) (n int, err error) {
+	size := int64(v.src.Size)
+	if offset >= size {
+		return 0, io.EOF
+	}
+	end := min(offset+int64(len(dst)), size)
+
+	rc, err := v.bucket.NewReaderWithReadHandle(ctx, &gcs.ReadObjectRequest{
+		Name:       v.src.Name,
+		Generation: v.src.Generation,
+		Range: &gcs.ByteRange{
+			Start: uint64(offset),
+			Limit: uint64(end),
+		},
+		ReadCompressed: v.src.HasContentEncodingGzip(),
+	})
+	if err != nil {
+		err = fmt.Errorf("NewReader: %w", err)
+		return
+	}
+	defer rc.Close()
+
+	n, err = io.ReadFull(rc, dst[:end-offset])
+	if err == io.ErrUnexpectedEOF {
+		err = io.EOF
+	}
+	if err == nil && end == size && n < len(dst) {
+		err = io.EOF
+	}
+
+	return
+}
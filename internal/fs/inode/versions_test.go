@@ -0,0 +1,203 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inode
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/metadata"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/gcsx"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/fake"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"github.com/jacobsa/fuse/fuseops"
+	"github.com/jacobsa/fuse/fuseutil"
+	"github.com/jacobsa/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+var versionsT0 = time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+
+type objectVersion struct {
+	object   gcs.MinObject
+	lifetime gcs.ObjectLifetime
+	contents string
+}
+
+// versionedBucket lists the versions of its objects, one per page, and serves
+// their contents.
+type versionedBucket struct {
+	gcs.Bucket
+
+	// Ordered by name and then generation.
+	versions []objectVersion
+}
+
+func (b *versionedBucket) ListObjects(_ context.Context, req *gcs.ListObjectsRequest) (*gcs.Listing, error) {
+	start := 0
+	if req.ContinuationToken != "" {
+		start, _ = strconv.Atoi(req.ContinuationToken)
+	}
+	listing := &gcs.Listing{}
+	for i := start; i < len(b.versions); i++ {
+		v := b.versions[i]
+		if !strings.HasPrefix(v.object.Name, req.Prefix) {
+			continue
+		}
+		listing.MinObjects = append(listing.MinObjects, &v.object)
+		listing.Lifetimes = append(listing.Lifetimes, v.lifetime)
+		if i+1 < len(b.versions) {
+			listing.ContinuationToken = strconv.Itoa(i + 1)
+		}
+		break
+	}
+	return listing, nil
+}
+
+func (b *versionedBucket) NewReaderWithReadHandle(_ context.Context, req *gcs.ReadObjectRequest) (gcs.StorageReader, error) {
+	for _, v := range b.versions {
+		if v.object.Name == req.Name && v.object.Generation == req.Generation {
+			contents := v.contents[req.Range.Start:req.Range.Limit]
+			return &fake.FakeReader{ReadCloser: io.NopCloser(strings.NewReader(contents))}, nil
+		}
+	}
+	return nil, &gcs.NotFoundError{}
+}
+
+func newVersionedBucket() *gcsx.SyncerBucket {
+	at := func(hours int) time.Time { return versionsT0.Add(time.Duration(hours) * time.Hour) }
+	version := func(name string, generation int64, contents string, lifetime gcs.ObjectLifetime) objectVersion {
+		return objectVersion{
+			object:   gcs.MinObject{Name: name, Generation: generation, Size: uint64(len(contents)), Updated: lifetime.Created},
+			lifetime: lifetime,
+			contents: contents,
+		}
+	}
+	return &gcsx.SyncerBucket{Bucket: &versionedBucket{versions: []objectVersion{
+		version("dir/a", 1, "first", gcs.ObjectLifetime{Created: at(0), Deleted: at(1)}),
+		version("dir/a", 2, "second", gcs.ObjectLifetime{Created: at(1), Deleted: at(2)}),
+		version("dir/a", 3, "current", gcs.ObjectLifetime{Created: at(2)}),
+		version("dir/a/b", 4, "other", gcs.ObjectLifetime{Created: at(0), Deleted: at(1)}),
+	}}}
+}
+
+func newVersionsDir(bucket *gcsx.SyncerBucket, objectName string) *VersionsDirInode {
+	return NewVersionsDirInode(
+		fuseops.RootInodeID+1,
+		NewDirName(NewRootName(""), objectName+VersionsDirSuffix),
+		objectName,
+		bucket,
+		fuseops.InodeAttributes{Mode: 0555})
+}
+
+func TestNoncurrentVersions(t *testing.T) {
+	versions, err := NoncurrentVersions(context.Background(), newVersionedBucket(), "dir/a")
+
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	assert.Equal(t, "20260102T150405Z-1", versions[0].Name)
+	assert.Equal(t, int64(1), versions[0].Object.Generation)
+	assert.Equal(t, "20260102T160405Z-2", versions[1].Name)
+	assert.Equal(t, int64(2), versions[1].Object.Generation)
+}
+
+func TestNoncurrentVersionsRequiresListingVersions(t *testing.T) {
+	bucket := fake.NewFakeBucket(timeutil.RealClock(), "some-bucket", gcs.BucketType{})
+	_, err := storageutil.CreateObject(context.Background(), bucket, "dir/a", []byte("taco"))
+	require.NoError(t, err)
+
+	_, err = NoncurrentVersions(context.Background(), bucket, "dir/a")
+
+	assert.ErrorIs(t, err, syscall.ENOTSUP)
+}
+
+func TestVersionsDirReadEntries(t *testing.T) {
+	d := newVersionsDir(newVersionedBucket(), "dir/a")
+
+	entries, _, tok, err := d.ReadEntries(context.Background(), "")
+
+	require.NoError(t, err)
+	assert.Empty(t, tok)
+	assert.Equal(t, []fuseutil.Dirent{
+		{Name: "20260102T150405Z-1", Type: fuseutil.DT_File},
+		{Name: "20260102T160405Z-2", Type: fuseutil.DT_File},
+	}, entries)
+}
+
+func TestVersionsDirReadEntryCores(t *testing.T) {
+	d := newVersionsDir(newVersionedBucket(), "dir/a")
+
+	cores, _, tok, err := d.ReadEntryCores(context.Background(), "")
+
+	require.NoError(t, err)
+	assert.Empty(t, tok)
+	require.Len(t, cores, 2)
+	for name, generation := range map[string]int64{"20260102T150405Z-1": 1, "20260102T160405Z-2": 2} {
+		fullName := NewFileName(d.Name(), name)
+		require.Contains(t, cores, fullName)
+		assert.Equal(t, fullName, cores[fullName].FullName)
+		assert.Equal(t, generation, cores[fullName].MinObject.Generation)
+		assert.Equal(t, metadata.RegularFileType, cores[fullName].Type())
+	}
+}
+
+func TestVersionsDirLookUpVersion(t *testing.T) {
+	d := newVersionsDir(newVersionedBucket(), "dir/a")
+
+	m, err := d.LookUpVersion(context.Background(), "20260102T160405Z-2")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), m.Generation)
+	m, err = d.LookUpVersion(context.Background(), "20260102T170405Z-3")
+	require.NoError(t, err)
+	assert.Nil(t, m)
+}
+
+func TestVersionsDirIsReadOnly(t *testing.T) {
+	d := newVersionsDir(newVersionedBucket(), "dir/a")
+
+	_, err := d.CreateChildFile(context.Background(), "foo")
+	assert.ErrorIs(t, err, syscall.EROFS)
+	err = d.DeleteChildFile(context.Background(), "20260102T150405Z-1", 1, nil)
+	assert.ErrorIs(t, err, syscall.EROFS)
+}
+
+func TestVersionInodeRead(t *testing.T) {
+	bucket := newVersionedBucket()
+	d := newVersionsDir(bucket, "dir/a")
+	m, err := d.LookUpVersion(context.Background(), "20260102T160405Z-2")
+	require.NoError(t, err)
+	v := NewVersionInode(fuseops.RootInodeID+2, NewFileName(d.Name(), "20260102T160405Z-2"), bucket, m, fuseops.InodeAttributes{Mode: 0644})
+
+	attrs, err := v.Attributes(context.Background(), true)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(len("second")), attrs.Size)
+	assert.Equal(t, 0444, int(attrs.Mode))
+	buf := make([]byte, 4)
+	n, err := v.Read(context.Background(), buf, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "econ", string(buf[:n]))
+	n, err = v.Read(context.Background(), buf, 4)
+	assert.Equal(t, io.EOF, err)
+	assert.Equal(t, "nd", string(buf[:n]))
+	_, err = v.Read(context.Background(), buf, 6)
+	assert.Equal(t, io.EOF, err)
+}
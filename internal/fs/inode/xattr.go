@@ -0,0 +1,268 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inode
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"unicode/utf8"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/fs/gcsfuse_errors"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"github.com/jacobsa/fuse"
+	"golang.org/x/net/context"
+)
+
+const (
+	// UserXattrPrefix is the namespace of extended attributes that are stored as
+	// custom metadata on the backing object. The attribute "user.foo" is stored
+	// under the metadata key "foo".
+	UserXattrPrefix = "user."
+
+	// GcsfuseXattrPrefix is the namespace of read-only extended attributes that
+	// expose properties of the backing object.
+	GcsfuseXattrPrefix = "gcsfuse."
+
+	XattrGeneration     = GcsfuseXattrPrefix + "generation"
+	XattrMetaGeneration = GcsfuseXattrPrefix + "metageneration"
+	XattrContentType    = GcsfuseXattrPrefix + "content_type"
+	XattrStorageClass   = GcsfuseXattrPrefix + "storage_class"
+	XattrCRC32C         = GcsfuseXattrPrefix + "crc32c"
+
+	// Flags accepted by setxattr(2).
+	XattrCreate  = 0x1
+	XattrReplace = 0x2
+
+	// GCS limits the total size of custom metadata keys and values on an
+	// object to 8 KiB.
+	maxCustomMetadataBytes = 8 * 1024
+)
+
+// Prefixes of custom metadata keys that gcsfuse uses internally. They are
+// neither listed nor writable through the user namespace.
+var reservedMetadataKeyPrefixes = []string{"gcsfuse_", "goog-reserved-"}
+
+func isReservedMetadataKey(key string) bool {
+	for _, p := range reservedMetadataKeyPrefixes {
+		if strings.HasPrefix(key, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// userXattrMetadataKey returns the metadata key backing the supplied user
+// namespace attribute, or an errno suitable for returning to the kernel.
+func userXattrMetadataKey(name string) (string, error) {
+	if !strings.HasPrefix(name, UserXattrPrefix) {
+		return "", syscall.ENOTSUP
+	}
+	key := strings.TrimPrefix(name, UserXattrPrefix)
+	if key == "" {
+		return "", syscall.EINVAL
+	}
+	if isReservedMetadataKey(key) {
+		return "", syscall.EPERM
+	}
+	return key, nil
+}
+
+func encodeCRC32C(crc uint32) string {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], crc)
+	return base64.StdEncoding.EncodeToString(b[:])
+}
+
+// ListXattr returns the names of the extended attributes of the file, which
+// are the user attributes stored in object metadata followed by the
+// read-only gcsfuse attributes. Local files have no attributes until they are
+// synced to GCS.
+//
+// LOCKS_REQUIRED(f.mu)
+func (f *FileInode) ListXattr() (names []string) {
+	if f.IsLocal() {
+		return
+	}
+
+	for key := range f.src.Metadata {
+		if !isReservedMetadataKey(key) {
+			names = append(names, UserXattrPrefix+key)
+		}
+	}
+	slices.Sort(names)
+
+	names = append(names, XattrGeneration, XattrMetaGeneration, XattrContentType, XattrStorageClass)
+	if f.src.CRC32C != nil {
+		names = append(names, XattrCRC32C)
+	}
+	return
+}
+
+// GetXattr returns the value of the named extended attribute. It returns
+// ENOATTR if the attribute doesn't exist.
+//
+// LOCKS_REQUIRED(f.mu)
+func (f *FileInode) GetXattr(ctx context.Context, name string) (value []byte, err error) {
+	if f.IsLocal() {
+		err = fuse.ENOATTR
+		return
+	}
+
+	if strings.HasPrefix(name, UserXattrPrefix) {
+		key := strings.TrimPrefix(name, UserXattrPrefix)
+		v, ok := f.src.Metadata[key]
+		if !ok || isReservedMetadataKey(key) {
+			err = fuse.ENOATTR
+			return
+		}
+		value = []byte(v)
+		return
+	}
+
+	switch name {
+	case XattrGeneration:
+		value = []byte(strconv.FormatInt(f.src.Generation, 10))
+	case XattrMetaGeneration:
+		value = []byte(strconv.FormatInt(f.src.MetaGeneration, 10))
+	case XattrCRC32C:
+		if f.src.CRC32C == nil {
+			err = fuse.ENOATTR
+			return
+		}
+		value = []byte(encodeCRC32C(*f.src.CRC32C))
+	case XattrContentType, XattrStorageClass:
+		// These aren't part of the min object, so fetch the full object record,
+		// making sure it still matches the generation we are known by.
+		var o *gcs.Object
+		o, err = f.fetchLatestGcsObject(ctx)
+		if err != nil {
+			return
+		}
+		if name == XattrContentType {
+			value = []byte(o.ContentType)
+		} else {
+			value = []byte(o.StorageClass)
+		}
+	default:
+		err = fuse.ENOATTR
+	}
+	return
+}
+
+// SetXattr stores the supplied user namespace attribute as custom metadata on
+// the backing object. flags follows the semantics of setxattr(2).
+//
+// REQUIRES: !f.IsLocal()
+// REQUIRES: !f.IsUsingBWH()
+// LOCKS_REQUIRED(f.mu)
+func (f *FileInode) SetXattr(ctx context.Context, name string, value []byte, flags uint32) (err error) {
+	if strings.HasPrefix(name, GcsfuseXattrPrefix) {
+		return syscall.EPERM
+	}
+	key, err := userXattrMetadataKey(name)
+	if err != nil {
+		return err
+	}
+	if !utf8.Valid(value) {
+		return syscall.EINVAL
+	}
+
+	existing, exists := f.src.Metadata[key]
+	if flags&XattrCreate != 0 && exists {
+		return syscall.EEXIST
+	}
+	if flags&XattrReplace != 0 && !exists {
+		return fuse.ENOATTR
+	}
+
+	size := 0
+	for k, v := range f.src.Metadata {
+		size += len(k) + len(v)
+	}
+	if exists {
+		size -= len(key) + len(existing)
+	}
+	if size+len(key)+len(value) > maxCustomMetadataBytes {
+		return syscall.ENOSPC
+	}
+
+	v := string(value)
+	return f.updateMetadata(ctx, map[string]*string{key: &v})
+}
+
+// RemoveXattr deletes the supplied user namespace attribute from the custom
+// metadata of the backing object.
+//
+// REQUIRES: !f.IsLocal()
+// REQUIRES: !f.IsUsingBWH()
+// LOCKS_REQUIRED(f.mu)
+func (f *FileInode) RemoveXattr(ctx context.Context, name string) (err error) {
+	if strings.HasPrefix(name, GcsfuseXattrPrefix) {
+		return syscall.EPERM
+	}
+	key, err := userXattrMetadataKey(name)
+	if err != nil {
+		return err
+	}
+	if _, ok := f.src.Metadata[key]; !ok {
+		return fuse.ENOATTR
+	}
+
+	return f.updateMetadata(ctx, map[string]*string{key: nil})
+}
+
+// updateMetadata patches the custom metadata of the generation of the object
+// this inode is known by, and adopts the resulting meta-generation.
+//
+// LOCKS_REQUIRED(f.mu)
+func (f *FileInode) updateMetadata(ctx context.Context, metadata map[string]*string) error {
+	if f.IsLocal() {
+		return fmt.Errorf("updateMetadata: %q has not been synced to GCS: %w", f.name.GcsObjectName(), syscall.ENOENT)
+	}
+
+	srcGen := f.SourceGeneration()
+	o, err := f.bucket.UpdateObject(ctx, &gcs.UpdateObjectRequest{
+		Name:                       f.src.Name,
+		Generation:                 srcGen.Object,
+		MetaGenerationPrecondition: &srcGen.Metadata,
+		Metadata:                   metadata,
+	})
+
+	var notFoundErr *gcs.NotFoundError
+	var preconditionErr *gcs.PreconditionError
+	if errors.As(err, &notFoundErr) || errors.As(err, &preconditionErr) {
+		return &gcsfuse_errors.FileClobberedError{
+			Err:        fmt.Errorf("UpdateObject: %w", err),
+			ObjectName: f.src.Name,
+		}
+	}
+	if errors.Is(err, errors.ErrUnsupported) {
+		return fmt.Errorf("UpdateObject: %w", syscall.ENOTSUP)
+	}
+	if err != nil {
+		return fmt.Errorf("UpdateObject: %w", err)
+	}
+
+	f.src = *storageutil.ConvertObjToMinObject(o)
+	f.updateMRDWrapper()
+	return nil
+}
@@ -0,0 +1,135 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inode
+
+import (
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/fs/gcsfuse_errors"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"github.com/jacobsa/fuse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (t *FileTest) TestListXattrForObjectWithoutMetadata() {
+	names := t.in.ListXattr()
+
+	assert.Equal(t.T(), []string{XattrGeneration, XattrMetaGeneration, XattrContentType, XattrStorageClass, XattrCRC32C}, names)
+}
+
+func (t *FileTest) TestListXattrForLocalFile() {
+	t.createInodeWithLocalParam("test", true)
+
+	assert.Empty(t.T(), t.in.ListXattr())
+}
+
+func (t *FileTest) TestSetAndGetUserXattr() {
+	err := t.in.SetXattr(t.ctx, "user.owner", []byte("pipeline-a"), 0)
+	require.NoError(t.T(), err)
+
+	value, err := t.in.GetXattr(t.ctx, "user.owner")
+
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), "pipeline-a", string(value))
+	assert.Contains(t.T(), t.in.ListXattr(), "user.owner")
+	// The attribute is persisted as custom metadata on the same generation.
+	m, _, err := t.bucket.StatObject(t.ctx, &gcs.StatObjectRequest{Name: t.in.Name().GcsObjectName()})
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), "pipeline-a", m.Metadata["owner"])
+	assert.Equal(t.T(), t.backingObj.Generation, m.Generation)
+	assert.Equal(t.T(), m.MetaGeneration, t.in.SourceGeneration().Metadata)
+}
+
+func (t *FileTest) TestSetXattrFlags() {
+	require.NoError(t.T(), t.in.SetXattr(t.ctx, "user.a", []byte("1"), 0))
+
+	assert.ErrorIs(t.T(), t.in.SetXattr(t.ctx, "user.a", []byte("2"), XattrCreate), syscall.EEXIST)
+	assert.ErrorIs(t.T(), t.in.SetXattr(t.ctx, "user.b", []byte("2"), XattrReplace), fuse.ENOATTR)
+	assert.NoError(t.T(), t.in.SetXattr(t.ctx, "user.a", []byte("2"), XattrReplace))
+	assert.NoError(t.T(), t.in.SetXattr(t.ctx, "user.b", []byte("2"), XattrCreate))
+}
+
+func (t *FileTest) TestSetXattrRejectsUnsupportedNames() {
+	testCases := []struct {
+		name string
+		want error
+	}{
+		{name: XattrGeneration, want: syscall.EPERM},
+		{name: "user.gcsfuse_mtime", want: syscall.EPERM},
+		{name: "user.", want: syscall.EINVAL},
+		{name: "security.selinux", want: syscall.ENOTSUP},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func() {
+			assert.ErrorIs(t.T(), t.in.SetXattr(t.ctx, tc.name, []byte("x"), 0), tc.want)
+		})
+	}
+}
+
+func (t *FileTest) TestSetXattrExceedingMetadataLimit() {
+	err := t.in.SetXattr(t.ctx, "user.big", []byte(strings.Repeat("x", maxCustomMetadataBytes)), 0)
+
+	assert.ErrorIs(t.T(), err, syscall.ENOSPC)
+}
+
+func (t *FileTest) TestRemoveXattr() {
+	require.NoError(t.T(), t.in.SetXattr(t.ctx, "user.owner", []byte("pipeline-a"), 0))
+
+	err := t.in.RemoveXattr(t.ctx, "user.owner")
+
+	require.NoError(t.T(), err)
+	_, err = t.in.GetXattr(t.ctx, "user.owner")
+	assert.ErrorIs(t.T(), err, fuse.ENOATTR)
+	assert.ErrorIs(t.T(), t.in.RemoveXattr(t.ctx, "user.owner"), fuse.ENOATTR)
+}
+
+func (t *FileTest) TestGetGcsfuseXattrs() {
+	m, e, err := t.bucket.StatObject(t.ctx, &gcs.StatObjectRequest{
+		Name:                           fileName,
+		ForceFetchFromGcs:              true,
+		ReturnExtendedObjectAttributes: true,
+	})
+	require.NoError(t.T(), err)
+	expected := map[string]string{
+		XattrGeneration:     strconv.FormatInt(m.Generation, 10),
+		XattrMetaGeneration: strconv.FormatInt(m.MetaGeneration, 10),
+		XattrCRC32C:         encodeCRC32C(*m.CRC32C),
+		XattrContentType:    e.ContentType,
+		XattrStorageClass:   e.StorageClass,
+	}
+
+	for name, want := range expected {
+		value, err := t.in.GetXattr(t.ctx, name)
+
+		require.NoError(t.T(), err)
+		assert.Equal(t.T(), want, string(value), name)
+	}
+}
+
+func (t *FileTest) TestGetXattrAfterClobbering() {
+	_, err := storageutil.CreateObject(t.ctx, t.bucket, fileName, []byte("burrito"))
+	require.NoError(t.T(), err)
+
+	_, err = t.in.GetXattr(t.ctx, XattrContentType)
+
+	var clobberedErr *gcsfuse_errors.FileClobberedError
+	assert.ErrorAs(t.T(), err, &clobberedErr)
+	assert.ErrorAs(t.T(), t.in.SetXattr(t.ctx, "user.a", []byte("1"), 0), &clobberedErr)
+}
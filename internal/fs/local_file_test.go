@@ -39,6 +39,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+	"golang.org/x/sys/unix"
 )
 
 // //////////////////////////////////////////////////////////////////////
@@ -649,6 +650,28 @@ func (t *LocalFileTest) TestSyncOnUnlinkedLocalFile() {
 	t.validateObjectNotFoundErr(FileName)
 }
 
+func (t *LocalFileTest) TestSyncFSWritesAllLocalFilesToGCS() {
+	// Create two local files with contents.
+	_, t.f1 = t.createLocalFile(FileName)
+	_, t.f2 = t.createLocalFile(FileName2)
+	_, err := t.f1.WriteString(FileContents)
+	require.NoError(t.T(), err)
+	_, err = t.f2.WriteString(FileContents + FileContents)
+	require.NoError(t.T(), err)
+
+	// A single syncfs call on either file syncs both of them.
+	err = unix.Syncfs(int(t.f1.Fd()))
+
+	require.NoError(t.T(), err)
+	t.validateObjectContents(FileName, FileContents)
+	t.validateObjectContents(FileName2, FileContents+FileContents)
+	// Writes after syncfs are still synced at close.
+	_, err = t.f1.WriteString(FileContents)
+	require.NoError(t.T(), err)
+	t.closeFileAndValidateObjectContents(&t.f1, FileName, FileContents+FileContents)
+	t.closeFileAndValidateObjectContents(&t.f2, FileName2, FileContents+FileContents)
+}
+
 func (t *LocalFileTest) TestUnlinkOfSyncedLocalFile() {
 	// Create local file and sync to GCS.
 	var filePath string
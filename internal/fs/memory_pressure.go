@@ -0,0 +1,129 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fs
+
+import (
+	"context"
+	"sync"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/bufferedread"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/logger"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/perf"
+	"github.com/googlecloudplatform/gcsfuse/v3/metrics"
+)
+
+// blockLimits applies the read and write global-max-blocks limits, as
+// configured or lowered under memory pressure:
+//   - Under moderate pressure, no more blocks are allocated for buffered
+//     reads: new readers read from GCS directly, existing ones make do with
+//     the blocks they have, and those with adaptive prefetching hand back
+//     their free blocks. Half of the write blocks remain available.
+//   - Under critical pressure, no blocks are available for streaming writes
+//     either: new writes are staged on disk, and files already being written
+//     wait for their blocks to be uploaded to reuse them.
+//
+// Blocks in use aren't reclaimed: the limits are lowered as they are released.
+type blockLimits struct {
+	read       *blockLimit
+	write      *blockLimit
+	readShares *bufferedread.BlockShares
+
+	metricHandle metrics.MetricHandle
+
+	mu sync.Mutex
+
+	// GUARDED_BY(mu)
+	readMax  int64
+	writeMax int64
+	pressure perf.MemoryPressure
+}
+
+func newBlockLimits(readMax, writeMax int64, metricHandle metrics.MetricHandle) *blockLimits {
+	return &blockLimits{
+		read:         newBlockLimit(readMax),
+		write:        newBlockLimit(writeMax),
+		readShares:   bufferedread.NewBlockShares(readMax),
+		metricHandle: metricHandle,
+		readMax:      readMax,
+		writeMax:     writeMax,
+	}
+}
+
+// setMax changes the configured limits.
+func (l *blockLimits) setMax(readMax, writeMax int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.readMax, l.writeMax = readMax, writeMax
+	l.apply()
+}
+
+// setPressure changes the memory pressure the limits are lowered for.
+func (l *blockLimits) setPressure(p perf.MemoryPressure) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.metricHandle.MemoryPressureState(-1, pressureAttr(l.pressure))
+	l.metricHandle.MemoryPressureState(1, pressureAttr(p))
+	l.pressure = p
+	l.apply()
+}
+
+// LOCKS_REQUIRED(l.mu)
+func (l *blockLimits) apply() {
+	readMax, writeMax := l.readMax, l.writeMax
+	switch l.pressure {
+	case perf.MemoryPressureModerate:
+		readMax, writeMax = 0, writeMax/2
+	case perf.MemoryPressureCritical:
+		readMax, writeMax = 0, 0
+	}
+	l.read.set(readMax)
+	l.readShares.SetLimit(readMax)
+	l.write.set(writeMax)
+}
+
+// watchMemoryPressure starts lowering the block limits under the memory
+// pressure of the cgroup v2 of the process, if enabled. Without a cgroup v2
+// with memory accounting, a warning is logged and the limits are left alone.
+func (fs *fileSystem) watchMemoryPressure(c *cfg.MemoryPressureConfig) {
+	if !c.Enable {
+		return
+	}
+	dir, err := perf.CgroupDir("/proc/self/cgroup", "/proc/self/mountinfo")
+	if err == nil {
+		_, err = perf.ReadCgroupMemory(dir)
+	}
+	if err != nil {
+		logger.Warnf("Not watching memory pressure: %v", err)
+		return
+	}
+
+	var ctx context.Context
+	ctx, fs.stopWatchingMemory = context.WithCancel(context.Background())
+	fs.metricHandle.MemoryPressureState(1, metrics.PressureNoneAttr)
+	thresholds := perf.MemoryPressureThresholds{ModeratePercent: c.ModeratePercent, CriticalPercent: c.CriticalPercent}
+	go perf.WatchMemoryPressure(ctx, dir, c.SampleInterval, thresholds, fs.blockLimits.setPressure)
+}
+
+func pressureAttr(p perf.MemoryPressure) metrics.Pressure {
+	switch p {
+	case perf.MemoryPressureModerate:
+		return metrics.PressureModerateAttr
+	case perf.MemoryPressureCritical:
+		return metrics.PressureCriticalAttr
+	default:
+		return metrics.PressureNoneAttr
+	}
+}
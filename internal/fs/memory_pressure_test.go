@@ -0,0 +1,84 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/perf"
+	"github.com/googlecloudplatform/gcsfuse/v3/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// awaitSettled waits for the supplied limits to stop lowering.
+func awaitSettled(t *testing.T, limits ...*blockLimit) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		for _, l := range limits {
+			l.mu.Lock()
+			settled := l.reserved == l.target
+			l.mu.Unlock()
+			if !settled {
+				return false
+			}
+		}
+		return true
+	}, time.Second, time.Millisecond)
+}
+
+// assertAvailable checks that exactly n blocks can be acquired from the limit.
+func assertAvailable(t *testing.T, l *blockLimit, n int64) {
+	t.Helper()
+	if assert.True(t, l.sem.TryAcquire(n)) {
+		assert.False(t, l.sem.TryAcquire(1))
+		l.sem.Release(n)
+	}
+}
+
+func TestBlockLimitsLoweredUnderMemoryPressure(t *testing.T) {
+	l := newBlockLimits(4, 4, metrics.NewNoopMetrics())
+
+	l.setPressure(perf.MemoryPressureModerate)
+	awaitSettled(t, l.read, l.write)
+	assertAvailable(t, l.read, 0)
+	assertAvailable(t, l.write, 2)
+
+	l.setPressure(perf.MemoryPressureCritical)
+	awaitSettled(t, l.read, l.write)
+	assertAvailable(t, l.read, 0)
+	assertAvailable(t, l.write, 0)
+
+	l.setPressure(perf.MemoryPressureNone)
+	awaitSettled(t, l.read, l.write)
+	assertAvailable(t, l.read, 4)
+	assertAvailable(t, l.write, 4)
+}
+
+func TestBlockLimitsReconfiguredUnderMemoryPressure(t *testing.T) {
+	l := newBlockLimits(4, 4, metrics.NewNoopMetrics())
+	l.setPressure(perf.MemoryPressureModerate)
+
+	l.setMax(8, 8)
+
+	awaitSettled(t, l.read, l.write)
+	assertAvailable(t, l.read, 0)
+	assertAvailable(t, l.write, 4)
+	l.setPressure(perf.MemoryPressureNone)
+	awaitSettled(t, l.read, l.write)
+	assertAvailable(t, l.read, 8)
+	assertAvailable(t, l.write, 8)
+}
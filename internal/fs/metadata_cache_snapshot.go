@@ -0,0 +1,159 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/metadata"
+	cacheutil "github.com/googlecloudplatform/gcsfuse/v3/internal/cache/util"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/fs/inode"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/logger"
+)
+
+// Where and for what the stat and type caches of a mount are persisted.
+type metadataCachePersister struct {
+	path       string
+	bucketName string
+
+	// See metadataCacheConfigHash.
+	configHash string
+}
+
+// metadataCacheConfigHash returns a digest of the parts of the config that
+// affect what the metadata caches contain, so that a snapshot isn't reloaded
+// into a mount that would have cached something else.
+func metadataCacheConfigHash(c *cfg.Config) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%q|%t|%t|%t|%+v",
+		c.OnlyDir,
+		c.ImplicitDirs,
+		c.EnableHns,
+		c.List.EnableEmptyManagedFolders,
+		c.MetadataCache)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// restoreMetadataCaches reloads the snapshot left by the previous mount of the
+// bucket, if any. Stat cache entries go straight into the shared stat cache;
+// type cache entries are held until the inode of their directory is minted.
+//
+// The snapshot is removed once read, so that a crash can't cause it to be
+// reloaded after its entries have gone stale.
+func (fs *fileSystem) restoreMetadataCaches(root inode.DirInode) {
+	p := fs.metadataCachePersister
+	s, err := metadata.ReadSnapshot(p.path, p.bucketName, p.configHash)
+	if errors.Is(err, os.ErrNotExist) {
+		return
+	}
+	if rmErr := os.Remove(p.path); rmErr != nil && !errors.Is(rmErr, os.ErrNotExist) {
+		logger.Warnf("Failed to remove metadata cache snapshot %s: %v", p.path, rmErr)
+	}
+	if err != nil {
+		logger.Warnf("Discarding metadata cache snapshot %s: %v", p.path, err)
+		return
+	}
+
+	fs.bucketManager.RestoreStatCache(s.StatEntries, fs.cacheClock.Now())
+
+	rootName := root.Name().GcsObjectName()
+	root.Lock()
+	root.RestoreTypeCache(s.TypeEntries[rootName])
+	root.Unlock()
+	delete(s.TypeEntries, rootName)
+	fs.pendingTypeCacheEntries = s.TypeEntries
+
+	logger.Infof("Reloaded %d stat cache entries and the type caches of %d directories from %s",
+		len(s.StatEntries), len(s.TypeEntries)+1, p.path)
+}
+
+// restorePendingTypeCache seeds the type cache of a newly minted directory
+// inode with the entries reloaded for it, if any.
+//
+// LOCKS_REQUIRED(fs.mu)
+func (fs *fileSystem) restorePendingTypeCache(d inode.DirInode) {
+	name := d.Name().GcsObjectName()
+	entries, ok := fs.pendingTypeCacheEntries[name]
+	if !ok {
+		return
+	}
+	delete(fs.pendingTypeCacheEntries, name)
+
+	// The inode isn't visible to anyone else yet, so there's no need to lock it.
+	d.RestoreTypeCache(entries)
+}
+
+// persistMetadataCaches writes the unexpired entries of the stat cache and of
+// the type caches of all directory inodes to the snapshot file, for the next
+// mount of the bucket to reload.
+//
+// LOCKS_EXCLUDED(fs.mu)
+func (fs *fileSystem) persistMetadataCaches() {
+	p := fs.metadataCachePersister
+
+	// Inode locks must not be acquired while holding fs.mu, so collect the
+	// directories first.
+	fs.mu.Lock()
+	var dirs []inode.DirInode
+	for _, in := range fs.inodes {
+		if d, ok := in.(inode.DirInode); ok {
+			dirs = append(dirs, d)
+		}
+	}
+	pending := fs.pendingTypeCacheEntries
+	fs.pendingTypeCacheEntries = nil
+	fs.mu.Unlock()
+
+	s := &metadata.Snapshot{
+		BucketName:  p.bucketName,
+		ConfigHash:  p.configHash,
+		TypeEntries: make(map[string][]metadata.TypeCacheSnapshotEntry),
+	}
+	for _, d := range dirs {
+		d.Lock()
+		entries := d.SnapshotTypeCache()
+		d.Unlock()
+		if len(entries) > 0 {
+			s.TypeEntries[d.Name().GcsObjectName()] = entries
+		}
+	}
+
+	// Entries reloaded for directories that were never looked up are carried
+	// over; expired ones are dropped by the next mount.
+	for name, entries := range pending {
+		if _, ok := s.TypeEntries[name]; !ok {
+			s.TypeEntries[name] = entries
+		}
+	}
+
+	s.StatEntries = fs.bucketManager.SnapshotStatCache(fs.cacheClock.Now())
+
+	if err := cacheutil.CreateCacheDirectoryIfNotPresentAt(path.Dir(p.path), cacheutil.DefaultDirPerm); err != nil {
+		logger.Warnf("Failed to persist the metadata cache: %v", err)
+		return
+	}
+	if err := metadata.WriteSnapshot(p.path, s); err != nil {
+		logger.Warnf("Failed to persist the metadata cache to %s: %v", p.path, err)
+		return
+	}
+	logger.Infof("Persisted %d stat cache entries and the type caches of %d directories to %s",
+		len(s.StatEntries), len(s.TypeEntries), p.path)
+}
@@ -0,0 +1,152 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fs_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/metadata"
+	cacheutil "github.com/googlecloudplatform/gcsfuse/v3/internal/cache/util"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/fs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/fake"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/jacobsa/fuse/fuseops"
+	"github.com/jacobsa/fuse/fuseutil"
+	"github.com/jacobsa/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createFileSystemWithPersistedMetadataCache(ctx context.Context, t *testing.T, bucket gcs.Bucket, cacheDir, mountPoint string) fuseutil.FileSystem {
+	t.Helper()
+	serverCfg := &fs.ServerConfig{
+		NewConfig: &cfg.Config{
+			CacheDir: cfg.ResolvedPath(cacheDir),
+			MetadataCache: cfg.MetadataCacheConfig{
+				EnablePersistence:  true,
+				TypeCacheMaxSizeMb: 1,
+			},
+			Write: cfg.WriteConfig{GlobalMaxBlocks: 1},
+			Read:  cfg.ReadConfig{GlobalMaxBlocks: 1},
+		},
+		CacheClock:      &timeutil.SimulatedClock{},
+		DirTypeCacheTTL: time.Hour,
+		BucketName:      bucket.Name(),
+		MountPoint:      mountPoint,
+		BucketManager: &fakeBucketManager{
+			buckets: map[string]gcs.Bucket{bucket.Name(): bucket},
+		},
+		SequentialReadSizeMb: 200,
+	}
+	server, err := fs.NewFileSystem(ctx, serverCfg)
+	require.NoError(t, err, "NewFileSystem")
+	return server
+}
+
+// metadataCacheSnapshotPaths returns the paths of the snapshots in the supplied
+// cache directory.
+func metadataCacheSnapshotPaths(t *testing.T, cacheDir string) []string {
+	t.Helper()
+	paths, err := filepath.Glob(path.Join(cacheDir, cacheutil.MetadataCache, "test-bucket-*.json"))
+	require.NoError(t, err)
+	return paths
+}
+
+func readMetadataCacheSnapshot(t *testing.T, p string) *metadata.Snapshot {
+	t.Helper()
+	contents, err := os.ReadFile(p)
+	require.NoError(t, err)
+	var s metadata.Snapshot
+	require.NoError(t, json.Unmarshal(contents, &s))
+	return &s
+}
+
+func TestMetadataCachePersistedAcrossRemounts(t *testing.T) {
+	ctx := context.Background()
+	cacheDir := t.TempDir()
+	bucket := fake.NewFakeBucket(timeutil.RealClock(), "test-bucket", gcs.BucketType{})
+	createWithContents(ctx, t, bucket, "dir/", "")
+	createWithContents(ctx, t, bucket, "dir/foo", "taco")
+	server := createFileSystemWithPersistedMetadataCache(ctx, t, bucket, cacheDir, "/mnt")
+	dirOp := &fuseops.LookUpInodeOp{Parent: fuseops.RootInodeID, Name: "dir"}
+	require.NoError(t, server.LookUpInode(ctx, dirOp))
+	require.NoError(t, server.LookUpInode(ctx, &fuseops.LookUpInodeOp{Parent: dirOp.Entry.Child, Name: "foo"}))
+
+	server.Destroy()
+
+	snapshotPaths := metadataCacheSnapshotPaths(t, cacheDir)
+	require.Len(t, snapshotPaths, 1)
+	snapshotPath := snapshotPaths[0]
+	s := readMetadataCacheSnapshot(t, snapshotPath)
+	assert.Equal(t, "test-bucket", s.BucketName)
+	require.Len(t, s.TypeEntries[""], 1)
+	assert.Equal(t, "dir", s.TypeEntries[""][0].Name)
+	assert.Equal(t, metadata.ExplicitDirType, s.TypeEntries[""][0].Type)
+	require.Len(t, s.TypeEntries["dir/"], 1)
+	assert.Equal(t, "foo", s.TypeEntries["dir/"][0].Name)
+	assert.Equal(t, metadata.RegularFileType, s.TypeEntries["dir/"][0].Type)
+
+	// The snapshot is consumed by the next mount, and the entries of
+	// directories that weren't looked up are carried over to the next one.
+	server = createFileSystemWithPersistedMetadataCache(ctx, t, bucket, cacheDir, "/mnt")
+	_, err := os.Stat(snapshotPath)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	server.Destroy()
+
+	s = readMetadataCacheSnapshot(t, snapshotPath)
+	require.Len(t, s.TypeEntries["dir/"], 1)
+	assert.Equal(t, "foo", s.TypeEntries["dir/"][0].Name)
+}
+
+func TestMetadataCacheSnapshotDiscardedOnConfigChange(t *testing.T) {
+	ctx := context.Background()
+	cacheDir := t.TempDir()
+	bucket := fake.NewFakeBucket(timeutil.RealClock(), "test-bucket", gcs.BucketType{})
+	createFileSystemWithPersistedMetadataCache(ctx, t, bucket, cacheDir, "/mnt").Destroy()
+	snapshotPaths := metadataCacheSnapshotPaths(t, cacheDir)
+	require.Len(t, snapshotPaths, 1)
+	snapshotPath := snapshotPaths[0]
+	require.NoError(t, metadata.WriteSnapshot(snapshotPath, &metadata.Snapshot{
+		BucketName: "test-bucket",
+		ConfigHash: "stale",
+		TypeEntries: map[string][]metadata.TypeCacheSnapshotEntry{
+			"dir/": {{Name: "foo", Type: metadata.RegularFileType, Expiry: time.Now().Add(time.Hour)}},
+		},
+	}))
+
+	server := createFileSystemWithPersistedMetadataCache(ctx, t, bucket, cacheDir, "/mnt")
+	server.Destroy()
+
+	s := readMetadataCacheSnapshot(t, snapshotPath)
+	assert.Empty(t, s.TypeEntries)
+}
+
+func TestMountsSharingCacheDirKeepSeparateMetadataCacheSnapshots(t *testing.T) {
+	ctx := context.Background()
+	cacheDir := t.TempDir()
+	bucket := fake.NewFakeBucket(timeutil.RealClock(), "test-bucket", gcs.BucketType{})
+
+	createFileSystemWithPersistedMetadataCache(ctx, t, bucket, cacheDir, "/mnt/a").Destroy()
+	createFileSystemWithPersistedMetadataCache(ctx, t, bucket, cacheDir, "/mnt/b").Destroy()
+
+	assert.Len(t, metadataCacheSnapshotPaths(t, cacheDir), 2)
+}
@@ -440,8 +440,7 @@ func TestFallocate_Metrics(t *testing.T) {
 	err = server.Fallocate(ctx, op)
 	waitForMetricsProcessing()
 
-	// The operation is not implemented, so we expect an error.
-	assert.Error(t, err)
+	assert.NoError(t, err)
 	attrs := attribute.NewSet(attribute.String("fs_op", "Others"))
 	metrics.VerifyCounterMetric(t, ctx, reader, "fs/ops_count", attrs, 1)
 	metrics.VerifyHistogramMetric(t, ctx, reader, "fs/ops_latency", attrs, 1)
@@ -0,0 +1,254 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fs
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	cacheutil "github.com/googlecloudplatform/gcsfuse/v3/internal/cache/util"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/fs/inode"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/logger"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/metrics"
+	"github.com/jacobsa/fuse/fuseops"
+	"golang.org/x/sync/errgroup"
+)
+
+// The characters that make a prefetch path a pattern rather than a prefix, as
+// interpreted by path.Match.
+const globMetaChars = `*?[\`
+
+// prefetchPattern matches the names of the objects of a bucket to prefetch:
+// those starting with it, or matching it if it holds globMetaChars.
+type prefetchPattern struct {
+	bucket  gcs.Bucket
+	pattern string
+}
+
+type prefetchObject struct {
+	bucket gcs.Bucket
+	object *gcs.MinObject
+}
+
+// readPrefetchManifest returns the paths listed in a prefetch manifest, one per
+// line. Blank lines and lines starting with # are ignored.
+func readPrefetchManifest(p string) ([]string, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var paths []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		paths = append(paths, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading prefetch manifest %s: %w", p, err)
+	}
+	return paths, nil
+}
+
+// prefetchPatternFor returns the pattern of a path relative to the mount point
+// within its bucket. When all buckets are mounted, the bucket is set up if it
+// hasn't been looked up yet.
+//
+// LOCKS_EXCLUDED(fs.mu)
+func (fs *fileSystem) prefetchPatternFor(ctx context.Context, p string) (prefetchPattern, error) {
+	p = strings.TrimPrefix(p, "/")
+	if _, err := path.Match(p, ""); err != nil {
+		return prefetchPattern{}, fmt.Errorf("invalid pattern %q: %w", p, err)
+	}
+
+	fs.mu.Lock()
+	root := fs.inodes[fuseops.RootInodeID].(inode.DirInode)
+	fs.mu.Unlock()
+
+	if b, ok := root.(inode.BucketOwnedInode); ok {
+		return prefetchPattern{bucket: b.Bucket(), pattern: p}, nil
+	}
+
+	bucketName, pattern, _ := strings.Cut(p, "/")
+	if bucketName == "" || strings.ContainsAny(bucketName, globMetaChars) {
+		return prefetchPattern{}, fmt.Errorf("%q must start with a bucket name, as all buckets are mounted", p)
+	}
+	root.Lock()
+	core, err := root.LookUpChild(ctx, bucketName)
+	root.Unlock()
+	if err != nil {
+		return prefetchPattern{}, fmt.Errorf("setting up bucket %q: %w", bucketName, err)
+	}
+	return prefetchPattern{bucket: core.Bucket, pattern: pattern}, nil
+}
+
+// prefetchOnMount starts prefetching the paths listed by the
+// file-cache.prefetch-paths and file-cache.prefetch-manifest settings, if any.
+func (fs *fileSystem) prefetchOnMount(ctx context.Context) error {
+	paths := fs.newConfig.FileCache.PrefetchPaths
+	if manifest := string(fs.newConfig.FileCache.PrefetchManifest); manifest != "" {
+		manifestPaths, err := readPrefetchManifest(manifest)
+		if err != nil {
+			return err
+		}
+		paths = append(slices.Clip(paths), manifestPaths...)
+	}
+	if len(paths) == 0 {
+		return nil
+	}
+	return fs.startPrefetch(ctx, paths)
+}
+
+// startPrefetch starts downloading the objects matching the supplied paths,
+// relative to the mount point, into the file cache in the background. See
+// prefetchPattern.
+func (fs *fileSystem) startPrefetch(ctx context.Context, paths []string) error {
+	if fs.fileCacheHandler == nil {
+		return errors.New("the file cache is disabled")
+	}
+
+	patterns := make([]prefetchPattern, 0, len(paths))
+	for _, p := range paths {
+		pattern, err := fs.prefetchPatternFor(ctx, p)
+		if err != nil {
+			return err
+		}
+		patterns = append(patterns, pattern)
+	}
+
+	fs.prefetches.Add(1)
+	go func() {
+		defer fs.prefetches.Done()
+		fs.prefetch(fs.prefetchCtx, patterns)
+	}()
+	return nil
+}
+
+// prefetch downloads the objects matching the supplied patterns into the file
+// cache, up to fs.prefetchConcurrency at a time, and logs a summary once done.
+// Progress is reported through the file_cache/prefetch_* metrics.
+func (fs *fileSystem) prefetch(ctx context.Context, patterns []prefetchPattern) {
+	objects := make(chan prefetchObject)
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.Go(func() error {
+		defer close(objects)
+		for _, p := range patterns {
+			if err := listPrefetchObjects(groupCtx, p, objects, fs.metricHandle); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	var completed, excluded, failed, bytes atomic.Int64
+	for range fs.prefetchConcurrency {
+		group.Go(func() error {
+			for o := range objects {
+				switch fs.prefetchObject(groupCtx, o) {
+				case metrics.StatusCompletedAttr:
+					completed.Add(1)
+					bytes.Add(int64(o.object.Size))
+				case metrics.StatusExcludedAttr:
+					excluded.Add(1)
+				default:
+					failed.Add(1)
+				}
+			}
+			return nil
+		})
+	}
+
+	err := group.Wait()
+	logger.Infof("Prefetched %d objects (%d bytes) into the file cache, excluded %d and failed %d",
+		completed.Load(), bytes.Load(), excluded.Load(), failed.Load())
+	if err != nil && !errors.Is(err, context.Canceled) {
+		logger.Errorf("Prefetching into the file cache stopped early: %v", err)
+	}
+}
+
+// prefetchObject downloads the supplied object, counted as pending, into the
+// file cache and records the outcome in the metrics.
+func (fs *fileSystem) prefetchObject(ctx context.Context, o prefetchObject) metrics.Status {
+	err := fs.fileCacheHandler.Prefetch(ctx, o.object, o.bucket)
+	fs.metricHandle.FileCachePrefetchPendingCount(-1)
+	switch {
+	case err == nil:
+		fs.metricHandle.FileCachePrefetchCount(1, metrics.StatusCompletedAttr)
+		fs.metricHandle.FileCachePrefetchBytesCount(int64(o.object.Size))
+		return metrics.StatusCompletedAttr
+	case errors.Is(err, cacheutil.ErrFileExcludedFromCacheByRegex):
+		fs.metricHandle.FileCachePrefetchCount(1, metrics.StatusExcludedAttr)
+		return metrics.StatusExcludedAttr
+	default:
+		fs.metricHandle.FileCachePrefetchCount(1, metrics.StatusFailedAttr)
+		logger.Warnf("Prefetching %q of bucket %q into the file cache: %v", o.object.Name, o.bucket.Name(), err)
+		return metrics.StatusFailedAttr
+	}
+}
+
+// listPrefetchObjects sends the objects matching the supplied pattern to the
+// supplied channel, counting them as pending.
+func listPrefetchObjects(ctx context.Context, p prefetchPattern, objects chan<- prefetchObject, metricHandle metrics.MetricHandle) error {
+	prefix := p.pattern
+	glob := false
+	if i := strings.IndexAny(p.pattern, globMetaChars); i >= 0 {
+		prefix = p.pattern[:i]
+		glob = true
+	}
+
+	req := &gcs.ListObjectsRequest{Prefix: prefix}
+	for {
+		listing, err := p.bucket.ListObjects(ctx, req)
+		if err != nil {
+			return fmt.Errorf("listing %q in bucket %q: %w", prefix, p.bucket.Name(), err)
+		}
+
+		for _, o := range listing.MinObjects {
+			// Directory placeholders have nothing to cache.
+			if strings.HasSuffix(o.Name, "/") {
+				continue
+			}
+			if glob {
+				if matched, _ := path.Match(p.pattern, o.Name); !matched {
+					continue
+				}
+			}
+			metricHandle.FileCachePrefetchPendingCount(1)
+			select {
+			case objects <- prefetchObject{bucket: p.bucket, object: o}:
+			case <-ctx.Done():
+				metricHandle.FileCachePrefetchPendingCount(-1)
+				return ctx.Err()
+			}
+		}
+
+		if listing.ContinuationToken == "" {
+			return nil
+		}
+		req.ContinuationToken = listing.ContinuationToken
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fs
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/fake"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"github.com/googlecloudplatform/gcsfuse/v3/metrics"
+	"github.com/jacobsa/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadPrefetchManifest(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "manifest")
+	require.NoError(t, os.WriteFile(p, []byte("# Training data.\ndata/train/\n\n  data/*.idx  \n"), 0600))
+
+	paths, err := readPrefetchManifest(p)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"data/train/", "data/*.idx"}, paths)
+}
+
+func TestListPrefetchObjects(t *testing.T) {
+	bucket := fake.NewFakeBucket(timeutil.RealClock(), "some-bucket", gcs.BucketType{})
+	require.NoError(t, storageutil.CreateObjects(context.Background(), bucket, map[string][]byte{
+		"a/":        nil,
+		"a/b":       []byte("taco"),
+		"a/b.idx":   []byte("taco"),
+		"a/c/d":     []byte("taco"),
+		"a/c/e":     []byte("taco"),
+		"ab":        []byte("taco"),
+		"b/c.idx":   []byte("taco"),
+		"b/c/d.idx": []byte("taco"),
+	}))
+	testCases := []struct {
+		pattern  string
+		expected []string
+	}{
+		{pattern: "a/", expected: []string{"a/b", "a/b.idx", "a/c/d", "a/c/e"}},
+		{pattern: "a", expected: []string{"a/b", "a/b.idx", "a/c/d", "a/c/e", "ab"}},
+		{pattern: "", expected: []string{"a/b", "a/b.idx", "a/c/d", "a/c/e", "ab", "b/c.idx", "b/c/d.idx"}},
+		{pattern: "*/*.idx", expected: []string{"a/b.idx", "b/c.idx"}},
+		{pattern: "a/c/[d]", expected: []string{"a/c/d"}},
+		{pattern: "c*", expected: nil},
+	}
+
+	for _, tc := range testCases {
+		objects := make(chan prefetchObject, 10)
+
+		err := listPrefetchObjects(context.Background(), prefetchPattern{bucket: bucket, pattern: tc.pattern}, objects, metrics.NewNoopMetrics())
+
+		require.NoError(t, err, tc.pattern)
+		close(objects)
+		var names []string
+		for o := range objects {
+			names = append(names, o.object.Name)
+		}
+		assert.Equal(t, tc.expected, names, tc.pattern)
+	}
+}
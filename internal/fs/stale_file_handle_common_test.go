@@ -24,7 +24,9 @@ import (
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
 	"github.com/googlecloudplatform/gcsfuse/v3/tools/integration_tests/util/operations"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+	"golang.org/x/sys/unix"
 )
 
 // //////////////////////////////////////////////////////////////////////
@@ -117,6 +119,35 @@ func (t *staleFileHandleCommon) TestClobberedFileSyncAndCloseThrowsStaleFileHand
 	assert.Equal(t.T(), "foobar", string(contents))
 }
 
+func (t *staleFileHandleCommon) TestClobberedFileSyncFSThrowsStaleFileHandleErrorAndSyncsOtherFiles() {
+	// Dirty the file and another file.
+	_, err := t.f1.Write([]byte("taco"))
+	assert.NoError(t.T(), err)
+	t.f2, err = os.OpenFile(path.Join(mntDir, "bar"), os.O_RDWR|os.O_CREATE|syscall.O_DIRECT, filePerms)
+	require.NoError(t.T(), err)
+	_, err = t.f2.Write([]byte("burrito"))
+	assert.NoError(t.T(), err)
+	// Replace the underlying object with a new generation.
+	clobberFile(t.T(), "foobar")
+
+	err = unix.Syncfs(int(t.f2.Fd()))
+
+	operations.ValidateESTALEError(t.T(), err)
+	// Validate that the other file is synced nevertheless.
+	contents, err := storageutil.ReadObject(ctx, bucket, "bar")
+	assert.NoError(t.T(), err)
+	assert.Equal(t.T(), "burrito", string(contents))
+	err = t.f1.Close()
+	operations.ValidateESTALEError(t.T(), err)
+	// Make f1 nil, so that another attempt is not taken in TearDown to close the
+	// file.
+	t.f1 = nil
+	// Validate that object is not updated with un-synced content.
+	contents, err = storageutil.ReadObject(ctx, bucket, "foo")
+	assert.NoError(t.T(), err)
+	assert.Equal(t.T(), "foobar", string(contents))
+}
+
 func (t *staleFileHandleCommon) TestFileDeletedLocallySyncAndCloseDoNotThrowError() {
 	// Dirty the file by giving it some contents.
 	n, err := t.f1.Write([]byte("foobar"))
@@ -1288,7 +1288,7 @@ func TestTraceFallocate(t *testing.T) {
 			}
 
 			err = m.Fallocate(ctx, op)
-			assert.Error(t, err) // The operation is not implemented, so we expect an error.
+			assert.NoError(t, err)
 
 			ss := ex.GetSpans()
 			require.Len(t, ss, len(tt.spans))
@@ -1330,7 +1330,7 @@ func TestTraceSyncFS(t *testing.T) {
 			}
 
 			err = m.SyncFS(ctx, op)
-			assert.Error(t, err) // The operation is not implemented, so we expect an error.
+			assert.NoError(t, err)
 
 			ss := ex.GetSpans()
 			require.Len(t, ss, len(tt.spans))
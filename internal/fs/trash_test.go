@@ -0,0 +1,63 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fs_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/fs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/fake"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"github.com/jacobsa/fuse/fuseops"
+	"github.com/jacobsa/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRmDirMovesPlaceholderToTrash(t *testing.T) {
+	ctx := context.Background()
+	bucket := fake.NewFakeBucket(timeutil.RealClock(), "test-bucket", gcs.BucketType{})
+	createWithContents(ctx, t, bucket, "dir/", "")
+	server, err := fs.NewFileSystem(ctx, &fs.ServerConfig{
+		NewConfig: &cfg.Config{
+			Trash: cfg.TrashConfig{Enable: true, Prefix: ".trash"},
+			Write: cfg.WriteConfig{GlobalMaxBlocks: 1},
+			Read:  cfg.ReadConfig{GlobalMaxBlocks: 1},
+		},
+		CacheClock: &timeutil.SimulatedClock{},
+		BucketName: bucket.Name(),
+		BucketManager: &fakeBucketManager{
+			buckets: map[string]gcs.Bucket{bucket.Name(): bucket},
+		},
+		SequentialReadSizeMb: 200,
+		DirTypeCacheTTL:      time.Hour,
+	})
+	require.NoError(t, err, "NewFileSystem")
+	defer server.Destroy()
+	require.NoError(t, server.LookUpInode(ctx, &fuseops.LookUpInodeOp{Parent: fuseops.RootInodeID, Name: "dir"}))
+
+	require.NoError(t, server.RmDir(ctx, &fuseops.RmDirOp{Parent: fuseops.RootInodeID, Name: "dir"}))
+
+	objects, _, err := storageutil.ListAll(ctx, bucket, &gcs.ListObjectsRequest{})
+	require.NoError(t, err)
+	require.Len(t, objects, 1)
+	assert.True(t, strings.HasPrefix(objects[0].Name, ".trash/"), objects[0].Name)
+	assert.True(t, strings.HasSuffix(objects[0].Name, "/dir/"), objects[0].Name)
+}
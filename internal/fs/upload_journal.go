@@ -0,0 +1,63 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fs
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/bufferedwrites"
+	cacheutil "github.com/googlecloudplatform/gcsfuse/v3/internal/cache/util"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+)
+
+// mountStateFileName returns the name, with the supplied extension, of a file
+// kept under the cache directory by the mount of the supplied bucket,
+// restricted to onlyDir if set, on mountPoint. Mounts sharing a cache
+// directory each get their own files, picked up again by the next mount of the
+// same bucket and directory on the same mount point.
+func mountStateFileName(bucketName, onlyDir, mountPoint, ext string) string {
+	h := sha256.Sum256([]byte(fmt.Sprintf("%q|%q", onlyDir, mountPoint)))
+	return fmt.Sprintf("%s-%s%s", bucketName, hex.EncodeToString(h[:8]), ext)
+}
+
+// recoverUploads resolves the streaming uploads of the supplied bucket left in
+// progress by the previous mount on mountPoint, keeping those that fail for
+// the next mount, and starts journaling the uploads of this mount.
+func (fs *fileSystem) recoverUploads(ctx context.Context, bucket gcs.Bucket, c *cfg.Config, mountPoint string) error {
+	dir := path.Join(string(c.CacheDir), cacheutil.UploadJournalDir)
+	if err := cacheutil.CreateCacheDirectoryIfNotPresentAt(dir, cacheutil.DefaultDirPerm); err != nil {
+		return fmt.Errorf("while creating journal directory: %w", err)
+	}
+	journalPath := path.Join(dir, mountStateFileName(bucket.Name(), c.OnlyDir, mountPoint, ".jsonl"))
+	uploads, err := bufferedwrites.ReadUploadJournal(journalPath)
+	if err != nil {
+		return fmt.Errorf("while reading journal: %w", err)
+	}
+
+	abort := c.Write.InterruptedUploadAction == cfg.InterruptedUploadActionAbort
+	incomplete, pending := bufferedwrites.RecoverUploads(ctx, bucket, uploads, abort)
+	journal, err := bufferedwrites.NewUploadJournal(journalPath, cacheutil.DefaultFilePerm, pending)
+	if err != nil {
+		return fmt.Errorf("while opening journal: %w", err)
+	}
+	fs.incompleteUploads = incomplete
+	fs.uploadJournal = journal
+	return nil
+}
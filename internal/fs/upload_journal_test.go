@@ -0,0 +1,79 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fs_test
+
+import (
+	"context"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	cacheutil "github.com/googlecloudplatform/gcsfuse/v3/internal/cache/util"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/fs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/fake"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/jacobsa/fuse/fuseutil"
+	"github.com/jacobsa/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createFileSystemWithUploadJournal(ctx context.Context, t *testing.T, bucket gcs.Bucket, cacheDir, onlyDir, mountPoint string) fuseutil.FileSystem {
+	t.Helper()
+	serverCfg := &fs.ServerConfig{
+		NewConfig: &cfg.Config{
+			CacheDir: cfg.ResolvedPath(cacheDir),
+			OnlyDir:  onlyDir,
+			Write: cfg.WriteConfig{
+				GlobalMaxBlocks:         1,
+				EnableUploadJournal:     true,
+				InterruptedUploadAction: cfg.InterruptedUploadActionResume,
+			},
+			Read: cfg.ReadConfig{GlobalMaxBlocks: 1},
+		},
+		CacheClock:      &timeutil.SimulatedClock{},
+		DirTypeCacheTTL: time.Hour,
+		BucketName:      bucket.Name(),
+		MountPoint:      mountPoint,
+		BucketManager: &fakeBucketManager{
+			buckets: map[string]gcs.Bucket{bucket.Name(): bucket},
+		},
+		SequentialReadSizeMb: 200,
+	}
+	server, err := fs.NewFileSystem(ctx, serverCfg)
+	require.NoError(t, err, "NewFileSystem")
+	return server
+}
+
+func TestMountsSharingCacheDirKeepSeparateUploadJournals(t *testing.T) {
+	ctx := context.Background()
+	cacheDir := t.TempDir()
+	bucket := fake.NewFakeBucket(timeutil.RealClock(), "test-bucket", gcs.BucketType{})
+
+	servers := []fuseutil.FileSystem{
+		createFileSystemWithUploadJournal(ctx, t, bucket, cacheDir, "", "/mnt/a"),
+		createFileSystemWithUploadJournal(ctx, t, bucket, cacheDir, "", "/mnt/b"),
+		createFileSystemWithUploadJournal(ctx, t, bucket, cacheDir, "dir", "/mnt/a"),
+	}
+	for _, s := range servers {
+		defer s.Destroy()
+	}
+
+	entries, err := os.ReadDir(path.Join(cacheDir, cacheutil.UploadJournalDir))
+	require.NoError(t, err)
+	assert.Len(t, entries, len(servers))
+}
@@ -40,6 +40,17 @@ func errno(err error, preconditionErrCfg bool) error {
 		return nil
 	}
 
+	// Errors aggregated over several files, e.g. by SyncFS, map to the first
+	// errno that isn't ignored, so that an ignored error can't hide the others.
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range joined.Unwrap() {
+			if mapped := errno(e, preconditionErrCfg); mapped != nil {
+				return mapped
+			}
+		}
+		return nil
+	}
+
 	// The object is modified or deleted by a concurrent process.
 	var clobberedErr *gcsfuse_errors.FileClobberedError
 	if errors.As(err, &clobberedErr) {
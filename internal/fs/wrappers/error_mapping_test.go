@@ -15,6 +15,7 @@
 package wrappers
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"syscall"
@@ -113,3 +114,38 @@ func (testSuite *ErrorMapping) TestFileClobberedErrorWithoutPreconditionErrCfg()
 
 	assert.Equal(testSuite.T(), nil, gotErrno)
 }
+
+func (testSuite *ErrorMapping) TestJoinedErrorsWithoutPreconditionErrCfg() {
+	clobberedErr := &gcsfuse_errors.FileClobberedError{
+		Err:        fmt.Errorf("some error"),
+		ObjectName: "foo.txt",
+	}
+	joinedErr := errors.Join(clobberedErr, fmt.Errorf("bar.txt: %w", syscall.ENOSPC))
+
+	gotErrno := errno(joinedErr, false)
+
+	assert.Equal(testSuite.T(), syscall.ENOSPC, gotErrno)
+}
+
+func (testSuite *ErrorMapping) TestJoinedErrorsWithPreconditionErrCfg() {
+	clobberedErr := &gcsfuse_errors.FileClobberedError{
+		Err:        fmt.Errorf("some error"),
+		ObjectName: "foo.txt",
+	}
+	joinedErr := errors.Join(clobberedErr, fmt.Errorf("bar.txt: %w", syscall.ENOSPC))
+
+	gotErrno := errno(joinedErr, true)
+
+	assert.Equal(testSuite.T(), syscall.ESTALE, gotErrno)
+}
+
+func (testSuite *ErrorMapping) TestJoinedIgnoredErrors() {
+	clobberedErr := &gcsfuse_errors.FileClobberedError{
+		Err:        fmt.Errorf("some error"),
+		ObjectName: "foo.txt",
+	}
+
+	gotErrno := errno(errors.Join(clobberedErr, clobberedErr), false)
+
+	assert.Equal(testSuite.T(), nil, gotErrno)
+}
@@ -0,0 +1,128 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// A collection of tests for extended attributes backed by object metadata.
+package fs_test
+
+import (
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/sys/unix"
+)
+
+type XattrTest struct {
+	suite.Suite
+	fsTest
+}
+
+func TestXattrTestSuite(t *testing.T) {
+	suite.Run(t, new(XattrTest))
+}
+
+func (t *XattrTest) SetupSuite() {
+	t.serverCfg.NewConfig = &cfg.Config{
+		FileSystem: cfg.FileSystemConfig{
+			EnableXattrs: true,
+		},
+	}
+	t.fsTest.SetUpTestSuite()
+}
+
+func (t *XattrTest) TearDownTest() {
+	t.fsTest.TearDown()
+}
+
+func (t *XattrTest) TearDownSuite() {
+	t.fsTest.TearDownTestSuite()
+}
+
+func getXattr(t *testing.T, filePath, name string) string {
+	t.Helper()
+	buf := make([]byte, 1024)
+	n, err := unix.Getxattr(filePath, name, buf)
+	require.NoError(t, err)
+	return string(buf[:n])
+}
+
+func (t *XattrTest) TestSetXattrIsStoredInObjectMetadata() {
+	_, err := storageutil.CreateObject(ctx, bucket, "foo", []byte("taco"))
+	require.NoError(t.T(), err)
+	filePath := path.Join(mntDir, "foo")
+
+	err = unix.Setxattr(filePath, "user.owner", []byte("pipeline-a"), 0)
+
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), "pipeline-a", getXattr(t.T(), filePath, "user.owner"))
+	m, _, err := bucket.StatObject(ctx, &gcs.StatObjectRequest{Name: "foo"})
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), "pipeline-a", m.Metadata["owner"])
+}
+
+func (t *XattrTest) TestXattrOnLocalFileSurvivesClose() {
+	filePath := path.Join(mntDir, "bar")
+	f, err := os.Create(filePath)
+	require.NoError(t.T(), err)
+	_, err = f.WriteString("burrito")
+	require.NoError(t.T(), err)
+
+	err = unix.Fsetxattr(int(f.Fd()), "user.stage", []byte("raw"), 0)
+	require.NoError(t.T(), err)
+	_, err = f.WriteString(" bowl")
+	require.NoError(t.T(), err)
+	require.NoError(t.T(), f.Close())
+
+	m, _, err := bucket.StatObject(ctx, &gcs.StatObjectRequest{Name: "bar"})
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), "raw", m.Metadata["stage"])
+	assert.Equal(t.T(), uint64(len("burrito bowl")), m.Size)
+}
+
+func (t *XattrTest) TestListAndRemoveXattr() {
+	_, err := storageutil.CreateObject(ctx, bucket, "foo", []byte("taco"))
+	require.NoError(t.T(), err)
+	filePath := path.Join(mntDir, "foo")
+	require.NoError(t.T(), unix.Setxattr(filePath, "user.a", []byte("1"), 0))
+
+	buf := make([]byte, 1024)
+	n, err := unix.Listxattr(filePath, buf)
+	require.NoError(t.T(), err)
+	names := strings.Split(strings.TrimSuffix(string(buf[:n]), "\x00"), "\x00")
+	assert.Contains(t.T(), names, "user.a")
+	assert.Contains(t.T(), names, "gcsfuse.generation")
+
+	require.NoError(t.T(), unix.Removexattr(filePath, "user.a"))
+	_, err = unix.Getxattr(filePath, "user.a", buf)
+	assert.ErrorIs(t.T(), err, syscall.ENODATA)
+}
+
+func (t *XattrTest) TestGcsfuseXattrsAreReadOnly() {
+	o, err := storageutil.CreateObject(ctx, bucket, "foo", []byte("taco"))
+	require.NoError(t.T(), err)
+	filePath := path.Join(mntDir, "foo")
+
+	assert.Equal(t.T(), strconv.FormatInt(o.Generation, 10), getXattr(t.T(), filePath, "gcsfuse.generation"))
+	err = unix.Setxattr(filePath, "gcsfuse.generation", []byte("1"), 0)
+	assert.ErrorIs(t.T(), err, syscall.EPERM)
+}
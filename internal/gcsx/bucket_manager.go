@@ -18,13 +18,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"path"
+	"sync"
 	"time"
 
 	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/lru"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/metadata"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/canned"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/logger"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/monitor"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/ratelimit"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage"
@@ -41,6 +44,8 @@ type BucketConfig struct {
 	EgressBandwidthLimitBytesPerSecond float64
 	OpRateLimitHz                      float64
 	StatCacheMaxSizeMB                 uint64
+	// Name of the eviction policy of the stat cache, see lru.NewPolicy.
+	StatCacheEvictionPolicy string
 	// Config for TTL of entries for existing file in stat cache
 	StatCacheTTL time.Duration
 	// Config for TTL of entries for non-existing file in stat cache
@@ -69,6 +74,23 @@ type BucketConfig struct {
 	TmpObjectPrefix          string
 	// Used in Zonal buckets to determine if objects should be finalized or not.
 	FinalizeFileForRapid bool
+
+	// If non-zero, the buckets are read-only snapshots of their objects as of
+	// that time.
+	SnapshotTime time.Time
+	// If non-nil, the buckets are read-only snapshots made of the generations
+	// of their objects in the manifest.
+	GenerationManifest GenerationManifest
+
+	// If non-empty, the prefix of the trash of unlinked objects, whose objects
+	// are periodically purged after TrashRetention if that is non-zero.
+	TrashPrefix    string
+	TrashRetention time.Duration
+
+	// If non-nil, the contents of objects are encrypted on the client with data
+	// keys wrapped by this key. Files are then never appended to by composing
+	// objects, whatever AppendThreshold is.
+	KeyEncryptionKey KeyEncryptionKey
 }
 
 // BucketManager manages the lifecycle of buckets.
@@ -77,61 +99,134 @@ type BucketManager interface {
 		ctx context.Context,
 		name string, isMultibucketMount bool, metricHandle metrics.MetricHandle) (b SyncerBucket, err error)
 
+	// Returns the unexpired entries of the stat cache shared by the buckets,
+	// for persisting across mounts. Returns nil if the stat cache is disabled.
+	SnapshotStatCache(now time.Time) []metadata.StatCacheSnapshotEntry
+
+	// Seeds the stat cache shared by the buckets with persisted entries.
+	RestoreStatCache(entries []metadata.StatCacheSnapshotEntry, now time.Time)
+
+	// Erases the entries of the named bucket whose object names start with the
+	// given prefix from the stat cache. An empty bucket name matches all the
+	// buckets.
+	EraseStatCacheEntries(bucketName string, prefix string)
+
+	// Erases the entry of the named object of the named bucket from the stat
+	// cache, if any.
+	EraseStatCacheEntry(bucketName string, objectName string)
+
+	// Changes the rate limits of the buckets set up so far and from now on.
+	// Buckets set up with rate limiting disabled stay unlimited.
+	SetRateLimits(opRateLimitHz float64, egressBandwidthLimit float64)
+
+	// Changes the TTLs of the stat cache entries inserted from now on by the
+	// buckets set up so far and from now on. Buckets set up with the stat cache
+	// disabled keep it disabled.
+	SetStatCacheTTLs(ttl time.Duration, negativeTTL time.Duration)
+
+	// Sets the function called with the objects that the buckets set up from
+	// now on fetch from GCS with StatObject and ListObjects, if the stat cache
+	// is enabled. The bucket name is empty unless multiple buckets are mounted.
+	// The function is called synchronously, so it must not block.
+	SetStatCacheObserver(observer func(bucketName string, objs []*gcs.MinObject))
+
 	// Shuts down the bucket manager and its buckets
 	ShutDown()
 }
 
+// ttlSettable and observable are implemented by the buckets returned by
+// caching.NewFastStatBucket.
+type ttlSettable interface {
+	SetTTLs(primaryCacheTTL time.Duration, negativeCacheTTL time.Duration)
+}
+
+type observable interface {
+	SetObserver(observer func(objs []*gcs.MinObject))
+}
+
 type bucketManager struct {
-	config          BucketConfig
 	storageHandle   storage.StorageHandle
 	sharedStatCache *lru.Cache
 
+	mu sync.Mutex
+
+	// GUARDED_BY(mu)
+	config BucketConfig
+
+	// The throttles and stat caching buckets of the buckets set up so far.
+	//
+	// GUARDED_BY(mu)
+	opThrottles      []ratelimit.Throttle
+	egressThrottles  []ratelimit.Throttle
+	statCacheBuckets []ttlSettable
+
+	// The views of the shared stat cache of the buckets set up so far, keyed by
+	// bucket name.
+	//
+	// GUARDED_BY(mu)
+	statCaches map[string]metadata.StatCache
+
+	// GUARDED_BY(mu)
+	statCacheObserver func(bucketName string, objs []*gcs.MinObject)
+
 	// Garbage collector
 	gcCtx                 context.Context
 	stopGarbageCollecting func()
 }
 
-func NewBucketManager(config BucketConfig, storageHandle storage.StorageHandle) BucketManager {
+func NewBucketManager(config BucketConfig, storageHandle storage.StorageHandle, metricHandle metrics.MetricHandle) BucketManager {
 	var c *lru.Cache
 	if config.StatCacheMaxSizeMB > 0 {
-		c = lru.NewCache(util.MiBsToBytes(config.StatCacheMaxSizeMB))
+		policy, err := lru.NewPolicy(config.StatCacheEvictionPolicy)
+		if err != nil {
+			logger.Warnf("Falling back to LRU eviction for the stat cache: %v", err)
+			policy = nil
+		}
+		c = lru.NewCacheWithPolicy(util.MiBsToBytes(config.StatCacheMaxSizeMB), policy, func(count int) {
+			metricHandle.CacheEvictionCount(int64(count), metrics.CacheNameStatAttr, metrics.EvictionPolicy(lru.PolicyName(policy)))
+		})
 	}
 
 	bm := &bucketManager{
 		config:          config,
 		storageHandle:   storageHandle,
 		sharedStatCache: c,
+		statCaches:      make(map[string]metadata.StatCache),
 	}
 	bm.gcCtx, bm.stopGarbageCollecting = context.WithCancel(context.Background())
 	return bm
 }
 
+// The window of time in which rate limits are enforced to within a few
+// percent.
+const rateLimitWindow = 8 * time.Hour
+
+// rateLimitOrMax treats a disabled limit as a very large one.
+func rateLimitOrMax(limit float64) float64 {
+	if !(limit > 0) {
+		return 1e15
+	}
+	return limit
+}
+
 func setUpRateLimiting(
 	in gcs.Bucket,
 	opRateLimitHz float64,
-	egressBandwidthLimit float64) (out gcs.Bucket, err error) {
+	egressBandwidthLimit float64) (out gcs.Bucket, opThrottle ratelimit.Throttle, egressThrottle ratelimit.Throttle, err error) {
 	// If no rate limiting has been requested, just return the bucket.
 	if !(opRateLimitHz > 0 || egressBandwidthLimit > 0) {
 		out = in
 		return
 	}
 
-	// Treat a disabled limit as a very large one.
-	if !(opRateLimitHz > 0) {
-		opRateLimitHz = 1e15
-	}
-
-	if !(egressBandwidthLimit > 0) {
-		egressBandwidthLimit = 1e15
-	}
+	opRateLimitHz = rateLimitOrMax(opRateLimitHz)
+	egressBandwidthLimit = rateLimitOrMax(egressBandwidthLimit)
 
 	// Choose token bucket capacities, targeting only a few percent error in each
 	// window of the given size.
-	const window = 8 * time.Hour
-
 	opCapacity, err := ratelimit.ChooseLimiterCapacity(
 		opRateLimitHz,
-		window)
+		rateLimitWindow)
 
 	if err != nil {
 		err = fmt.Errorf("choosing operation token bucket capacity: %w", err)
@@ -140,7 +235,7 @@ func setUpRateLimiting(
 
 	egressCapacity, err := ratelimit.ChooseLimiterCapacity(
 		egressBandwidthLimit,
-		window)
+		rateLimitWindow)
 
 	if err != nil {
 		err = fmt.Errorf("choosing egress bandwidth token bucket capacity: %w", err)
@@ -148,8 +243,8 @@ func setUpRateLimiting(
 	}
 
 	// Create the throttles.
-	opThrottle := ratelimit.NewThrottle(opRateLimitHz, opCapacity)
-	egressThrottle := ratelimit.NewThrottle(egressBandwidthLimit, egressCapacity)
+	opThrottle = ratelimit.NewThrottle(opRateLimitHz, opCapacity)
+	egressThrottle = ratelimit.NewThrottle(egressBandwidthLimit, egressCapacity)
 
 	// And the bucket.
 	out = ratelimit.NewThrottledBucket(
@@ -166,12 +261,16 @@ func (bm *bucketManager) SetUpBucket(
 	isMultibucketMount bool,
 	metricHandle metrics.MetricHandle,
 ) (sb SyncerBucket, err error) {
+	bm.mu.Lock()
+	config := bm.config
+	bm.mu.Unlock()
+
 	var b gcs.Bucket
 	// Set up the appropriate backing bucket.
 	if name == canned.FakeBucketName {
 		b = canned.MakeFakeBucket(ctx)
 	} else {
-		b, err = bm.storageHandle.BucketHandle(ctx, name, bm.config.BillingProject, bm.config.FinalizeFileForRapid)
+		b, err = bm.storageHandle.BucketHandle(ctx, name, config.BillingProject, config.FinalizeFileForRapid)
 		if err != nil {
 			err = fmt.Errorf("BucketHandle: %w", err)
 			return
@@ -181,14 +280,35 @@ func (bm *bucketManager) SetUpBucket(
 	// Enable monitoring.
 	b = monitor.NewMonitoringBucket(b, metricHandle)
 
-	if bm.config.LogSeverity == cfg.TraceLogSeverity {
+	if config.LogSeverity == cfg.TraceLogSeverity {
 		// Enable gcs logs.
 		b = storage.NewDebugBucket(b)
 	}
 
+	// Encrypt the contents of objects, if requested. This goes beneath
+	// everything else, which sees the sizes and contents of the objects as
+	// they are before encryption.
+	appendThreshold := config.AppendThreshold
+	if config.KeyEncryptionKey != nil {
+		if b.BucketType().Zonal {
+			err = errors.New("client-side encryption isn't supported for zonal buckets")
+			return
+		}
+		b = NewEncryptionBucket(config.KeyEncryptionKey, b)
+		appendThreshold = math.MaxInt64
+	}
+
+	// Pin the objects to the generations of a snapshot, if requested.
+	isSnapshot := !config.SnapshotTime.IsZero() || config.GenerationManifest != nil
+	if !config.SnapshotTime.IsZero() {
+		b = NewSnapshotBucket(config.SnapshotTime, b)
+	} else if config.GenerationManifest != nil {
+		b = NewManifestBucket(config.GenerationManifest[name], b)
+	}
+
 	// Limit to a requested prefix of the bucket, if any.
-	if bm.config.OnlyDir != "" {
-		b, err = NewPrefixBucket(path.Clean(bm.config.OnlyDir)+"/", b)
+	if config.OnlyDir != "" {
+		b, err = NewPrefixBucket(path.Clean(config.OnlyDir)+"/", b)
 		if err != nil {
 			err = fmt.Errorf("NewPrefixBucket: %w", err)
 			return
@@ -196,10 +316,10 @@ func (bm *bucketManager) SetUpBucket(
 	}
 
 	// Enable rate limiting, if requested.
-	b, err = setUpRateLimiting(
+	b, opThrottle, egressThrottle, err := setUpRateLimiting(
 		b,
-		bm.config.OpRateLimitHz,
-		bm.config.EgressBandwidthLimitBytesPerSecond)
+		config.OpRateLimitHz,
+		config.EgressBandwidthLimitBytesPerSecond)
 
 	if err != nil {
 		err = fmt.Errorf("setUpRateLimiting: %w", err)
@@ -208,34 +328,43 @@ func (bm *bucketManager) SetUpBucket(
 
 	// Enable cached StatObject results based on stat cache config.
 	// Disabling stat cache with below config also disables negative stat cache.
-	if bm.config.StatCacheTTL != 0 && bm.sharedStatCache != nil {
-		var statCache metadata.StatCache
+	if config.StatCacheTTL != 0 && bm.sharedStatCache != nil {
+		var viewName string
 		if isMultibucketMount {
-			statCache = metadata.NewStatCacheBucketView(bm.sharedStatCache, name)
-		} else {
-			statCache = metadata.NewStatCacheBucketView(bm.sharedStatCache, "")
+			viewName = name
 		}
+		statCache := metadata.NewStatCacheBucketView(bm.sharedStatCache, viewName)
 
 		b = caching.NewFastStatBucket(
-			bm.config.StatCacheTTL,
+			config.StatCacheTTL,
 			statCache,
 			timeutil.RealClock(),
 			b,
-			bm.config.NegativeStatCacheTTL)
+			config.NegativeStatCacheTTL)
+		bm.mu.Lock()
+		if fsb, ok := b.(ttlSettable); ok {
+			bm.statCacheBuckets = append(bm.statCacheBuckets, fsb)
+		}
+		if fsb, ok := b.(observable); ok && bm.statCacheObserver != nil {
+			observer := bm.statCacheObserver
+			fsb.SetObserver(func(objs []*gcs.MinObject) { observer(viewName, objs) })
+		}
+		bm.statCaches[name] = statCache
+		bm.mu.Unlock()
 	}
 
 	// Enable content type awareness
 	b = NewContentTypeBucket(b)
 
 	// Enable Syncer
-	if bm.config.TmpObjectPrefix == "" {
+	if config.TmpObjectPrefix == "" {
 		err = errors.New("you must set TmpObjectPrefix")
 		return
 	}
 	sb = NewSyncerBucket(
-		bm.config.AppendThreshold,
-		bm.config.ChunkTransferTimeoutSecs,
-		bm.config.TmpObjectPrefix,
+		appendThreshold,
+		config.ChunkTransferTimeoutSecs,
+		config.TmpObjectPrefix,
 		b)
 
 	// Fetch bucket type from storage layout api and set bucket type.
@@ -250,12 +379,117 @@ func (bm *bucketManager) SetUpBucket(
 		}
 	}
 
-	// Periodically garbage collect temporary objects
-	go garbageCollect(bm.gcCtx, bm.config.TmpObjectPrefix, sb)
+	if opThrottle != nil {
+		bm.mu.Lock()
+		bm.opThrottles = append(bm.opThrottles, opThrottle)
+		bm.egressThrottles = append(bm.egressThrottles, egressThrottle)
+		bm.mu.Unlock()
+	}
+
+	// Periodically garbage collect temporary objects, which snapshots can't
+	// have.
+	if !isSnapshot {
+		go garbageCollect(bm.gcCtx, config.TmpObjectPrefix, sb)
+	}
+
+	// Periodically purge the trash, if any.
+	if config.TrashPrefix != "" && config.TrashRetention > 0 && !isSnapshot {
+		go purgeTrash(bm.gcCtx, TrashPrefix(config.TrashPrefix), config.TrashRetention, sb)
+	}
 
 	return
 }
 
+func (bm *bucketManager) SnapshotStatCache(now time.Time) []metadata.StatCacheSnapshotEntry {
+	if bm.sharedStatCache == nil {
+		return nil
+	}
+	return metadata.SnapshotStatCache(bm.sharedStatCache, now)
+}
+
+func (bm *bucketManager) RestoreStatCache(entries []metadata.StatCacheSnapshotEntry, now time.Time) {
+	if bm.sharedStatCache == nil {
+		return
+	}
+	metadata.RestoreStatCache(bm.sharedStatCache, entries, now)
+}
+
+func (bm *bucketManager) EraseStatCacheEntries(bucketName string, prefix string) {
+	bm.mu.Lock()
+	var statCaches []metadata.StatCache
+	for name, statCache := range bm.statCaches {
+		if bucketName == "" || name == bucketName {
+			statCaches = append(statCaches, statCache)
+		}
+	}
+	bm.mu.Unlock()
+
+	for _, statCache := range statCaches {
+		statCache.EraseEntriesWithGivenPrefix(prefix)
+	}
+}
+
+func (bm *bucketManager) EraseStatCacheEntry(bucketName string, objectName string) {
+	bm.mu.Lock()
+	statCache, ok := bm.statCaches[bucketName]
+	bm.mu.Unlock()
+
+	if ok {
+		statCache.Erase(objectName)
+	}
+}
+
+func (bm *bucketManager) SetRateLimits(opRateLimitHz float64, egressBandwidthLimit float64) {
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
+
+	// Rate limiting is only set up if either of the limits was enabled.
+	if !(bm.config.OpRateLimitHz > 0 || bm.config.EgressBandwidthLimitBytesPerSecond > 0) {
+		return
+	}
+	opRate, egressRate := rateLimitOrMax(opRateLimitHz), rateLimitOrMax(egressBandwidthLimit)
+	opCapacity, err := ratelimit.ChooseLimiterCapacity(opRate, rateLimitWindow)
+	if err != nil {
+		logger.Warnf("SetRateLimits: choosing operation token bucket capacity: %v", err)
+		return
+	}
+	egressCapacity, err := ratelimit.ChooseLimiterCapacity(egressRate, rateLimitWindow)
+	if err != nil {
+		logger.Warnf("SetRateLimits: choosing egress bandwidth token bucket capacity: %v", err)
+		return
+	}
+
+	bm.config.OpRateLimitHz = opRateLimitHz
+	bm.config.EgressBandwidthLimitBytesPerSecond = egressBandwidthLimit
+	for _, t := range bm.opThrottles {
+		t.SetRate(opRate, opCapacity)
+	}
+	for _, t := range bm.egressThrottles {
+		t.SetRate(egressRate, egressCapacity)
+	}
+}
+
+func (bm *bucketManager) SetStatCacheTTLs(ttl time.Duration, negativeTTL time.Duration) {
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
+
+	if bm.config.StatCacheTTL == 0 {
+		return
+	}
+	bm.config.StatCacheTTL = ttl
+	bm.config.NegativeStatCacheTTL = negativeTTL
+	for _, b := range bm.statCacheBuckets {
+		b.SetTTLs(ttl, negativeTTL)
+	}
+}
+
+func (bm *bucketManager) SetStatCacheObserver(observer func(bucketName string, objs []*gcs.MinObject)) {
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
+
+	bm.statCacheObserver = observer
+}
+
 func (bm *bucketManager) ShutDown() {
 	bm.stopGarbageCollecting()
 }
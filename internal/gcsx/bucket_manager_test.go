@@ -22,9 +22,11 @@ import (
 
 	"cloud.google.com/go/storage/control/apiv2/controlpb"
 	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/metadata"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
 	"github.com/googlecloudplatform/gcsfuse/v3/metrics"
+	. "github.com/jacobsa/oglematchers"
 	. "github.com/jacobsa/ogletest"
 	"github.com/stretchr/testify/mock"
 )
@@ -84,7 +86,7 @@ func (t *BucketManagerTest) TestNewBucketManagerMethod() {
 		TmpObjectPrefix:                    "TmpObjectPrefix",
 	}
 
-	bm := NewBucketManager(bucketConfig, t.storageHandle)
+	bm := NewBucketManager(bucketConfig, t.storageHandle, metrics.NewNoopMetrics())
 
 	ExpectNe(nil, bm)
 }
@@ -186,3 +188,133 @@ func (t *BucketManagerTest) TestSetUpBucketMethodWhenBucketDoesNotExist_IsMultiB
 	ExpectTrue(strings.Contains(err.Error(), "error in iterating through objects: storage: bucket doesn't exist"))
 	ExpectNe(nil, bucket.Syncer)
 }
+
+func (t *BucketManagerTest) TestStatCacheSnapshotAndRestore() {
+	bm := NewBucketManager(BucketConfig{StatCacheMaxSizeMB: 1}, t.storageHandle, metrics.NewNoopMetrics())
+	now := time.Now()
+	entries := []metadata.StatCacheSnapshotEntry{
+		{Key: "foo", Object: &gcs.MinObject{Name: "foo", Generation: 1}, Expiration: now.Add(time.Minute)},
+		{Key: "bar", Expiration: now.Add(time.Minute)},
+		{Key: "expired", Object: &gcs.MinObject{Name: "expired", Generation: 1}, Expiration: now.Add(-time.Minute)},
+	}
+
+	bm.RestoreStatCache(entries, now)
+	snapshot := bm.SnapshotStatCache(now)
+
+	AssertEq(2, len(snapshot))
+	ExpectEq("foo", snapshot[0].Key)
+	ExpectEq(1, snapshot[0].Object.Generation)
+	ExpectEq("bar", snapshot[1].Key)
+	ExpectEq(nil, snapshot[1].Object)
+}
+
+func (t *BucketManagerTest) TestStatCacheSnapshotWithStatCacheDisabled() {
+	bm := NewBucketManager(BucketConfig{}, t.storageHandle, metrics.NewNoopMetrics())
+	now := time.Now()
+
+	bm.RestoreStatCache([]metadata.StatCacheSnapshotEntry{{Key: "bar", Expiration: now.Add(time.Minute)}}, now)
+
+	ExpectEq(nil, bm.SnapshotStatCache(now))
+}
+
+func (t *BucketManagerTest) TestEraseStatCacheEntries() {
+	bm := NewBucketManager(BucketConfig{
+		StatCacheMaxSizeMB: 1,
+		StatCacheTTL:       time.Minute,
+		TmpObjectPrefix:    "TmpObjectPrefix",
+	}, t.storageHandle, metrics.NewNoopMetrics())
+	_, err := bm.SetUpBucket(context.Background(), TestBucketName, true, metrics.NewNoopMetrics())
+	AssertEq(nil, err)
+	now := time.Now()
+	bm.RestoreStatCache([]metadata.StatCacheSnapshotEntry{
+		{Key: TestBucketName + "/dir/a", Expiration: now.Add(time.Minute)},
+		{Key: TestBucketName + "/other", Expiration: now.Add(time.Minute)},
+		{Key: "other-bucket/dir/a", Expiration: now.Add(time.Minute)},
+	}, now)
+
+	bm.EraseStatCacheEntries(TestBucketName, "dir/")
+	bm.EraseStatCacheEntries("other-bucket", "")
+
+	snapshot := bm.SnapshotStatCache(now)
+	AssertEq(2, len(snapshot))
+	ExpectEq(TestBucketName+"/other", snapshot[0].Key)
+	ExpectEq("other-bucket/dir/a", snapshot[1].Key)
+
+	bm.EraseStatCacheEntries("", "")
+
+	snapshot = bm.SnapshotStatCache(now)
+	AssertEq(1, len(snapshot))
+	ExpectEq("other-bucket/dir/a", snapshot[0].Key)
+}
+
+func (t *BucketManagerTest) TestEraseStatCacheEntry() {
+	bm := NewBucketManager(BucketConfig{
+		StatCacheMaxSizeMB: 1,
+		StatCacheTTL:       time.Minute,
+		TmpObjectPrefix:    "TmpObjectPrefix",
+	}, t.storageHandle, metrics.NewNoopMetrics())
+	_, err := bm.SetUpBucket(context.Background(), TestBucketName, true, metrics.NewNoopMetrics())
+	AssertEq(nil, err)
+	now := time.Now()
+	bm.RestoreStatCache([]metadata.StatCacheSnapshotEntry{
+		{Key: TestBucketName + "/dir/", Expiration: now.Add(time.Minute)},
+		{Key: TestBucketName + "/dir/a", Expiration: now.Add(time.Minute)},
+		{Key: TestBucketName + "/dir/ab", Expiration: now.Add(time.Minute)},
+	}, now)
+
+	bm.EraseStatCacheEntry(TestBucketName, "dir/a")
+	bm.EraseStatCacheEntry("other-bucket", "dir/")
+
+	snapshot := bm.SnapshotStatCache(now)
+	AssertEq(2, len(snapshot))
+	ExpectEq(TestBucketName+"/dir/", snapshot[0].Key)
+	ExpectEq(TestBucketName+"/dir/ab", snapshot[1].Key)
+}
+
+func (t *BucketManagerTest) TestSetStatCacheObserver() {
+	bm := NewBucketManager(BucketConfig{
+		StatCacheMaxSizeMB: 1,
+		StatCacheTTL:       time.Minute,
+		TmpObjectPrefix:    "TmpObjectPrefix",
+	}, t.storageHandle, metrics.NewNoopMetrics())
+	var observedBucketNames, observedObjectNames []string
+	bm.SetStatCacheObserver(func(bucketName string, objs []*gcs.MinObject) {
+		for _, o := range objs {
+			observedBucketNames = append(observedBucketNames, bucketName)
+			observedObjectNames = append(observedObjectNames, o.Name)
+		}
+	})
+	ctx := context.Background()
+	bucket, err := bm.SetUpBucket(ctx, TestBucketName, true, metrics.NewNoopMetrics())
+	AssertEq(nil, err)
+	// Ignore the objects fetched while setting up the bucket.
+	observedBucketNames, observedObjectNames = nil, nil
+
+	_, _, err = bucket.StatObject(ctx, &gcs.StatObjectRequest{Name: storage.TestObjectName})
+
+	AssertEq(nil, err)
+	ExpectThat(observedBucketNames, ElementsAre(TestBucketName))
+	ExpectThat(observedObjectNames, ElementsAre(storage.TestObjectName))
+}
+
+func (t *BucketManagerTest) TestSetRateLimitsThrottlesOperationsThatWereUnlimited() {
+	bm := NewBucketManager(BucketConfig{
+		// Only the egress bandwidth is limited at first.
+		EgressBandwidthLimitBytesPerSecond: 1 << 30,
+		TmpObjectPrefix:                    "TmpObjectPrefix",
+	}, t.storageHandle, metrics.NewNoopMetrics())
+	defer bm.ShutDown()
+	ctx := context.Background()
+	bucket, err := bm.SetUpBucket(ctx, TestBucketName, false, metrics.NewNoopMetrics())
+	AssertEq(nil, err)
+
+	bm.SetRateLimits(20, 1<<30)
+
+	start := time.Now()
+	for range 5 {
+		_, _, err = bucket.StatObject(ctx, &gcs.StatObjectRequest{Name: storage.TestObjectName, ForceFetchFromGcs: true})
+		AssertEq(nil, err)
+	}
+	// 5 operations at 20 Hz, without the tokens accumulated while unlimited.
+	ExpectGe(time.Since(start), 200*time.Millisecond)
+}
@@ -0,0 +1,748 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcsx
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"syscall"
+
+	storagev2 "cloud.google.com/go/storage"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"golang.org/x/net/context"
+)
+
+// Objects are encrypted with AES-256-GCM under a random data key of their own,
+// in frames of encryptionFrameSize bytes of contents. Each frame is sealed with
+// its index as nonce and with whether it is the final frame as additional data,
+// so that frames can't be reordered and objects can't be truncated unnoticed.
+// All frames but the final one are full, so the final one is empty if the size
+// of the contents is a multiple of the frame size.
+//
+// The data key, wrapped by a key-encryption key, is kept in the metadata of
+// the object along with the ID of the key-encryption key.
+const (
+	encryptionSchemeMetadataKey = "gcsfuse_encryption"
+	encryptionKeyMetadataKey    = "gcsfuse_encryption_key"
+	encryptionKeyIDMetadataKey  = "gcsfuse_encryption_key_id"
+
+	encryptionScheme    = "AES256-GCM-64K"
+	encryptionFrameSize = 64 << 10
+	encryptionTagSize   = 16
+
+	encryptedFrameSize = encryptionFrameSize + encryptionTagSize
+
+	// The maximum number of data keys cached, wrapped and unwrapped.
+	maxCachedEncryptionKeys = 10000
+)
+
+var errUnsupportedWithEncryption = fmt.Errorf("not supported for encrypted objects: %w", syscall.ENOTSUP)
+
+// plaintextSize returns the size of the contents of an encrypted object of the
+// supplied size.
+func plaintextSize(ciphertextSize uint64) (uint64, error) {
+	frames := ciphertextSize/encryptedFrameSize + 1
+	if ciphertextSize%encryptedFrameSize < encryptionTagSize {
+		return 0, fmt.Errorf("size %d is invalid for an encrypted object", ciphertextSize)
+	}
+	return ciphertextSize - frames*encryptionTagSize, nil
+}
+
+type frameSealer struct {
+	aead  cipher.AEAD
+	frame uint64
+}
+
+func frameNonce(frame uint64) []byte {
+	nonce := make([]byte, 12)
+	binary.BigEndian.PutUint64(nonce[4:], frame)
+	return nonce
+}
+
+func frameAAD(final bool) []byte {
+	if final {
+		return []byte{1}
+	}
+	return []byte{0}
+}
+
+// seal appends the next frame, sealed, to dst.
+func (s *frameSealer) seal(dst []byte, plaintext []byte, final bool) []byte {
+	dst = s.aead.Seal(dst, frameNonce(s.frame), plaintext, frameAAD(final))
+	s.frame++
+	return dst
+}
+
+// NewEncryptionBucket creates a wrapper bucket that encrypts the contents of
+// the objects it creates with data keys wrapped by kek, and decrypts those of
+// encrypted objects it reads. Objects that aren't encrypted are read as they
+// are.
+//
+// Objects returned by the bucket have the sizes of their contents, and none of
+// the metadata used for encryption. Encrypted objects can't be composed or
+// appended to.
+func NewEncryptionBucket(kek KeyEncryptionKey, wrapped gcs.Bucket) gcs.Bucket {
+	return &encryptionBucket{
+		kek:     kek,
+		wrapped: wrapped,
+		objects: make(map[objectGeneration]encryptedObject),
+		aeads:   make(map[string]cipher.AEAD),
+	}
+}
+
+type objectGeneration struct {
+	name       string
+	generation int64
+}
+
+// What is needed to read a generation of an object.
+type encryptedObject struct {
+	// The wrapped data key, empty if the object isn't encrypted.
+	wrappedKey string
+
+	// The size of the object in GCS.
+	size uint64
+}
+
+type encryptionBucket struct {
+	kek     KeyEncryptionKey
+	wrapped gcs.Bucket
+
+	mu sync.Mutex
+
+	// The objects seen in listings and stats.
+	//
+	// GUARDED_BY(mu)
+	objects map[objectGeneration]encryptedObject
+
+	// Ciphers for data keys, keyed by their wrapped form.
+	//
+	// GUARDED_BY(mu)
+	aeads map[string]cipher.AEAD
+}
+
+func newDataKeyAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("NewCipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// Remembers the supplied generation of an object, and translates it in place
+// for the layers above.
+func (b *encryptionBucket) recordObject(m *gcs.MinObject) error {
+	o := encryptedObject{size: m.Size}
+	if scheme, ok := m.Metadata[encryptionSchemeMetadataKey]; ok {
+		if scheme != encryptionScheme {
+			return fmt.Errorf("object %q is encrypted with unsupported scheme %q", m.Name, scheme)
+		}
+		size, err := plaintextSize(m.Size)
+		if err != nil {
+			return fmt.Errorf("object %q: %w", m.Name, err)
+		}
+		o.wrappedKey = m.Metadata[encryptionKeyMetadataKey]
+		m.Size = size
+		// Checksums are of the encrypted contents.
+		m.CRC32C = nil
+		stripEncryptionMetadata(m.Metadata)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.objects) >= maxCachedEncryptionKeys {
+		clear(b.objects)
+	}
+	b.objects[objectGeneration{m.Name, m.Generation}] = o
+	return nil
+}
+
+func (b *encryptionBucket) recordFullObject(o *gcs.Object) error {
+	if o == nil {
+		return nil
+	}
+	encrypted := o.Metadata[encryptionSchemeMetadataKey] != ""
+	m := &gcs.MinObject{
+		Name:       o.Name,
+		Size:       o.Size,
+		Generation: o.Generation,
+		Metadata:   o.Metadata,
+	}
+	if err := b.recordObject(m); err != nil {
+		return err
+	}
+	o.Size = m.Size
+	if encrypted {
+		o.CRC32C = nil
+		o.MD5 = nil
+	}
+	return nil
+}
+
+func stripEncryptionMetadata(metadata map[string]string) {
+	delete(metadata, encryptionSchemeMetadataKey)
+	delete(metadata, encryptionKeyMetadataKey)
+	delete(metadata, encryptionKeyIDMetadataKey)
+}
+
+// Looks up the supplied generation of an object, zero meaning the latest.
+func (b *encryptionBucket) lookUpObject(ctx context.Context, name string, generation int64) (int64, encryptedObject, error) {
+	if generation != 0 {
+		b.mu.Lock()
+		o, ok := b.objects[objectGeneration{name, generation}]
+		b.mu.Unlock()
+		if ok {
+			return generation, o, nil
+		}
+	}
+
+	m, _, err := b.StatObject(ctx, &gcs.StatObjectRequest{Name: name, ForceFetchFromGcs: true})
+	if err != nil {
+		return 0, encryptedObject{}, err
+	}
+	if generation == 0 {
+		generation = m.Generation
+	} else if m.Generation != generation {
+		// Noncurrent generations are only found by listing them, which records
+		// them all.
+		_, _, err = storageutil.ListAll(ctx, b, &gcs.ListObjectsRequest{Prefix: name, Versions: true})
+		if err != nil {
+			return 0, encryptedObject{}, err
+		}
+	}
+
+	b.mu.Lock()
+	o, ok := b.objects[objectGeneration{name, generation}]
+	b.mu.Unlock()
+	if !ok {
+		return 0, encryptedObject{}, &gcs.NotFoundError{Err: fmt.Errorf("generation %d of object %q not found", generation, name)}
+	}
+	return generation, o, nil
+}
+
+func (b *encryptionBucket) aead(ctx context.Context, wrappedKey string) (cipher.AEAD, error) {
+	b.mu.Lock()
+	aead, ok := b.aeads[wrappedKey]
+	b.mu.Unlock()
+	if ok {
+		return aead, nil
+	}
+
+	wrapped, err := base64.StdEncoding.DecodeString(wrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("decoding wrapped data key: %w", err)
+	}
+	key, err := b.kek.UnwrapKey(ctx, wrapped)
+	if err != nil {
+		return nil, fmt.Errorf("UnwrapKey: %w", err)
+	}
+	aead, err = newDataKeyAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	b.cacheAEAD(wrappedKey, aead)
+	return aead, nil
+}
+
+func (b *encryptionBucket) cacheAEAD(wrappedKey string, aead cipher.AEAD) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.aeads) >= maxCachedEncryptionKeys {
+		clear(b.aeads)
+	}
+	b.aeads[wrappedKey] = aead
+}
+
+// Generates a data key for a new object, returning its cipher and the
+// metadata recording it.
+func (b *encryptionBucket) newDataKey(ctx context.Context) (cipher.AEAD, map[string]string, error) {
+	key := make([]byte, encryptionKeyLen)
+	if _, err := rand.Read(key); err != nil {
+		return nil, nil, fmt.Errorf("rand.Read: %w", err)
+	}
+	wrapped, err := b.kek.WrapKey(ctx, key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("WrapKey: %w", err)
+	}
+	aead, err := newDataKeyAEAD(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	wrappedKey := base64.StdEncoding.EncodeToString(wrapped)
+	b.cacheAEAD(wrappedKey, aead)
+	return aead, map[string]string{
+		encryptionSchemeMetadataKey: encryptionScheme,
+		encryptionKeyMetadataKey:    wrappedKey,
+		encryptionKeyIDMetadataKey:  b.kek.ID(),
+	}, nil
+}
+
+// Returns a copy of the supplied request for a new encrypted object, and the
+// cipher of its data key.
+func (b *encryptionBucket) encryptedCreateRequest(ctx context.Context, req *gcs.CreateObjectRequest) (*gcs.CreateObjectRequest, cipher.AEAD, error) {
+	aead, metadata, err := b.newDataKey(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	mReq := new(gcs.CreateObjectRequest)
+	*mReq = *req
+	for k, v := range req.Metadata {
+		if _, ok := metadata[k]; !ok {
+			metadata[k] = v
+		}
+	}
+	mReq.Metadata = metadata
+	// Checksums are of the contents, not of what is uploaded.
+	mReq.CRC32C = nil
+	mReq.MD5 = nil
+	return mReq, aead, nil
+}
+
+func (b *encryptionBucket) Name() string {
+	return b.wrapped.Name()
+}
+
+func (b *encryptionBucket) BucketType() gcs.BucketType {
+	return b.wrapped.BucketType()
+}
+
+func (b *encryptionBucket) GCSName(object *gcs.MinObject) string {
+	return b.wrapped.GCSName(object)
+}
+
+func (b *encryptionBucket) NewReaderWithReadHandle(
+	ctx context.Context,
+	req *gcs.ReadObjectRequest) (gcs.StorageReader, error) {
+	generation, o, err := b.lookUpObject(ctx, req.Name, req.Generation)
+	if err != nil {
+		return nil, err
+	}
+
+	mReq := new(gcs.ReadObjectRequest)
+	*mReq = *req
+	mReq.Generation = generation
+	if o.wrappedKey == "" {
+		return b.wrapped.NewReaderWithReadHandle(ctx, mReq)
+	}
+
+	aead, err := b.aead(ctx, o.wrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("object %q: %w", req.Name, err)
+	}
+
+	// Read the frames that the requested range of the contents is in.
+	size, err := plaintextSize(o.size)
+	if err != nil {
+		return nil, err
+	}
+	start, limit := uint64(0), size
+	if req.Range != nil {
+		start, limit = req.Range.Start, min(req.Range.Limit, size)
+	}
+	r := &decryptingReader{
+		name:       req.Name,
+		aead:       aead,
+		size:       size,
+		frame:      start / encryptionFrameSize,
+		skip:       int(start % encryptionFrameSize),
+		remaining:  limit - min(start, limit),
+		ciphertext: make([]byte, encryptedFrameSize),
+	}
+	if r.remaining == 0 {
+		return r, nil
+	}
+	// Reads up to the end of the contents also check the final frame, so that
+	// truncated objects aren't read as if they were complete.
+	lastFrame := (limit - 1) / encryptionFrameSize
+	if limit == size {
+		lastFrame = size / encryptionFrameSize
+	}
+	r.frames = lastFrame - r.frame + 1
+	mReq.Range = &gcs.ByteRange{
+		Start: r.frame * encryptedFrameSize,
+		Limit: min((lastFrame+1)*encryptedFrameSize, o.size),
+	}
+	r.wrapped, err = b.wrapped.NewReaderWithReadHandle(ctx, mReq)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (b *encryptionBucket) NewMultiRangeDownloader(
+	context.Context, *gcs.MultiRangeDownloaderRequest) (gcs.MultiRangeDownloader, error) {
+	return nil, errUnsupportedWithEncryption
+}
+
+func (b *encryptionBucket) CreateObject(
+	ctx context.Context,
+	req *gcs.CreateObjectRequest) (*gcs.Object, error) {
+	mReq, aead, err := b.encryptedCreateRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	mReq.Contents = &encryptingReader{
+		sealer:    frameSealer{aead: aead},
+		plaintext: req.Contents,
+		buf:       make([]byte, encryptionFrameSize),
+	}
+
+	o, err := b.wrapped.CreateObject(ctx, mReq)
+	if err != nil {
+		return nil, err
+	}
+	if err = b.recordFullObject(o); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+func (b *encryptionBucket) CreateObjectChunkWriter(ctx context.Context, req *gcs.CreateObjectRequest, chunkSize int, callBack func(bytesUploadedSoFar int64)) (gcs.Writer, error) {
+	mReq, aead, err := b.encryptedCreateRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	wc, err := b.wrapped.CreateObjectChunkWriter(ctx, mReq, chunkSize, callBack)
+	if err != nil {
+		return nil, err
+	}
+	return &encryptingWriter{
+		Writer: wc,
+		sealer: frameSealer{aead: aead},
+		buf:    make([]byte, 0, encryptionFrameSize),
+	}, nil
+}
+
+func (b *encryptionBucket) CreateAppendableObjectWriter(context.Context, *gcs.CreateObjectChunkWriterRequest) (gcs.Writer, error) {
+	return nil, errUnsupportedWithEncryption
+}
+
+func (b *encryptionBucket) FinalizeUpload(ctx context.Context, w gcs.Writer) (*gcs.MinObject, error) {
+	if ew, ok := w.(*encryptingWriter); ok {
+		if err := ew.finish(); err != nil {
+			return nil, err
+		}
+		w = ew.Writer
+	}
+
+	m, err := b.wrapped.FinalizeUpload(ctx, w)
+	if err != nil {
+		return nil, err
+	}
+	if m != nil {
+		if err = b.recordObject(m); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+func (b *encryptionBucket) FlushPendingWrites(context.Context, gcs.Writer) (*gcs.MinObject, error) {
+	return nil, errUnsupportedWithEncryption
+}
+
+func (b *encryptionBucket) CopyObject(
+	ctx context.Context,
+	req *gcs.CopyObjectRequest) (*gcs.Object, error) {
+	// Copies keep the metadata, and so the wrapped data key, of their sources.
+	o, err := b.wrapped.CopyObject(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if err = b.recordFullObject(o); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+func (b *encryptionBucket) ComposeObjects(
+	context.Context, *gcs.ComposeObjectsRequest) (*gcs.Object, error) {
+	// Each source has a data key of its own, so a composite object couldn't be
+	// decrypted.
+	return nil, errUnsupportedWithEncryption
+}
+
+func (b *encryptionBucket) StatObject(
+	ctx context.Context,
+	req *gcs.StatObjectRequest) (*gcs.MinObject, *gcs.ExtendedObjectAttributes, error) {
+	m, e, err := b.wrapped.StatObject(ctx, req)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = b.recordObject(m); err != nil {
+		return nil, nil, err
+	}
+	return m, e, nil
+}
+
+func (b *encryptionBucket) ListObjects(
+	ctx context.Context,
+	req *gcs.ListObjectsRequest) (*gcs.Listing, error) {
+	l, err := b.wrapped.ListObjects(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range l.MinObjects {
+		if err = b.recordObject(m); err != nil {
+			return nil, err
+		}
+	}
+	return l, nil
+}
+
+func (b *encryptionBucket) UpdateObject(
+	ctx context.Context,
+	req *gcs.UpdateObjectRequest) (*gcs.Object, error) {
+	// Don't let the metadata used for encryption be changed.
+	mReq := new(gcs.UpdateObjectRequest)
+	*mReq = *req
+	if req.Metadata != nil {
+		mReq.Metadata = make(map[string]*string)
+		for k, v := range req.Metadata {
+			switch k {
+			case encryptionSchemeMetadataKey, encryptionKeyMetadataKey, encryptionKeyIDMetadataKey:
+			default:
+				mReq.Metadata[k] = v
+			}
+		}
+	}
+
+	o, err := b.wrapped.UpdateObject(ctx, mReq)
+	if err != nil {
+		return nil, err
+	}
+	if err = b.recordFullObject(o); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+func (b *encryptionBucket) DeleteObject(
+	ctx context.Context,
+	req *gcs.DeleteObjectRequest) error {
+	return b.wrapped.DeleteObject(ctx, req)
+}
+
+func (b *encryptionBucket) MoveObject(ctx context.Context, req *gcs.MoveObjectRequest) (*gcs.Object, error) {
+	o, err := b.wrapped.MoveObject(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if err = b.recordFullObject(o); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+func (b *encryptionBucket) DeleteFolder(ctx context.Context, folderName string) error {
+	return b.wrapped.DeleteFolder(ctx, folderName)
+}
+
+func (b *encryptionBucket) GetFolder(ctx context.Context, folderName string) (*gcs.Folder, error) {
+	return b.wrapped.GetFolder(ctx, folderName)
+}
+
+func (b *encryptionBucket) RenameFolder(ctx context.Context, folderName string, destinationFolderId string) (*gcs.Folder, error) {
+	return b.wrapped.RenameFolder(ctx, folderName, destinationFolderId)
+}
+
+func (b *encryptionBucket) CreateFolder(ctx context.Context, folderName string) (*gcs.Folder, error) {
+	return b.wrapped.CreateFolder(ctx, folderName)
+}
+
+////////////////////////////////////////////////////////////////////////
+// Readers and writers
+////////////////////////////////////////////////////////////////////////
+
+// encryptingReader reads the encrypted form of the contents read from
+// plaintext.
+type encryptingReader struct {
+	sealer    frameSealer
+	plaintext io.Reader
+
+	// A frame of plaintext, and the sealed frames not yet read.
+	buf    []byte
+	sealed []byte
+	done   bool
+}
+
+func (r *encryptingReader) Read(p []byte) (int, error) {
+	for len(r.sealed) == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+
+		n, err := io.ReadFull(r.plaintext, r.buf)
+		final := false
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			final = true
+		} else if err != nil {
+			return 0, err
+		}
+		r.sealed = r.sealer.seal(r.sealed[:0], r.buf[:n], final)
+		r.done = final
+	}
+
+	n := copy(p, r.sealed)
+	r.sealed = r.sealed[n:]
+	return n, nil
+}
+
+// encryptingWriter writes the encrypted form of what is written to it to the
+// wrapped writer.
+type encryptingWriter struct {
+	gcs.Writer
+	sealer frameSealer
+
+	// Plaintext not yet sealed, less than a frame.
+	buf      []byte
+	sealed   []byte
+	finished bool
+}
+
+func (w *encryptingWriter) writeFrame(plaintext []byte, final bool) error {
+	w.sealed = w.sealer.seal(w.sealed[:0], plaintext, final)
+	_, err := w.Writer.Write(w.sealed)
+	return err
+}
+
+func (w *encryptingWriter) Write(p []byte) (int, error) {
+	if w.finished {
+		return 0, errors.New("write after the final frame")
+	}
+
+	n := len(p)
+	for len(w.buf)+len(p) >= encryptionFrameSize {
+		// A full frame is never the final one.
+		var err error
+		if len(w.buf) == 0 {
+			err = w.writeFrame(p[:encryptionFrameSize], false)
+			p = p[encryptionFrameSize:]
+		} else {
+			k := encryptionFrameSize - len(w.buf)
+			w.buf = append(w.buf, p[:k]...)
+			p = p[k:]
+			err = w.writeFrame(w.buf, false)
+			w.buf = w.buf[:0]
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+	w.buf = append(w.buf, p...)
+	return n, nil
+}
+
+// finish writes the final frame, if not written yet.
+func (w *encryptingWriter) finish() error {
+	if w.finished {
+		return nil
+	}
+	w.finished = true
+	return w.writeFrame(w.buf, true)
+}
+
+func (w *encryptingWriter) Close() error {
+	if err := w.finish(); err != nil {
+		return err
+	}
+	return w.Writer.Close()
+}
+
+func (w *encryptingWriter) Flush() (int64, error) {
+	return 0, errUnsupportedWithEncryption
+}
+
+// decryptingReader reads a range of the contents of an encrypted object from
+// a reader of the frames it is in.
+type decryptingReader struct {
+	name    string
+	wrapped gcs.StorageReader // nil if the range is empty
+	aead    cipher.AEAD
+
+	// The size of the contents of the object.
+	size uint64
+
+	// The index of the next frame to decrypt, the number of leading bytes of it
+	// to skip, the number of frames left to decrypt and the number of bytes of
+	// contents left to read.
+	frame     uint64
+	skip      int
+	frames    uint64
+	remaining uint64
+
+	ciphertext []byte
+	plaintext  []byte
+}
+
+func (r *decryptingReader) ReadHandle() storagev2.ReadHandle {
+	if r.wrapped == nil {
+		return nil
+	}
+	return r.wrapped.ReadHandle()
+}
+
+func (r *decryptingReader) Read(p []byte) (int, error) {
+	for len(r.plaintext) == 0 {
+		if r.frames == 0 {
+			return 0, io.EOF
+		}
+
+		final := r.frame == r.size/encryptionFrameSize
+		frameSize := encryptedFrameSize
+		if final {
+			frameSize = int(r.size%encryptionFrameSize) + encryptionTagSize
+		}
+		if _, err := io.ReadFull(r.wrapped, r.ciphertext[:frameSize]); err != nil {
+			return 0, fmt.Errorf("reading frame %d of %q: %w", r.frame, r.name, err)
+		}
+		plaintext, err := r.aead.Open(r.ciphertext[:0], frameNonce(r.frame), r.ciphertext[:frameSize], frameAAD(final))
+		if err != nil {
+			return 0, fmt.Errorf("decrypting frame %d of %q: %w", r.frame, r.name, err)
+		}
+
+		r.frame++
+		r.frames--
+		r.plaintext = plaintext[r.skip:]
+		r.skip = 0
+		if uint64(len(r.plaintext)) > r.remaining {
+			r.plaintext = r.plaintext[:r.remaining]
+		}
+	}
+
+	n := copy(p, r.plaintext)
+	r.plaintext = r.plaintext[n:]
+	r.remaining -= uint64(n)
+	return n, nil
+}
+
+func (r *decryptingReader) Close() error {
+	if r.wrapped == nil {
+		return nil
+	}
+	return r.wrapped.Close()
+}
@@ -0,0 +1,233 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcsx
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/fake"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"github.com/jacobsa/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+func newTestKEK(t *testing.T) KeyEncryptionKey {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "kek")
+	require.NoError(t, os.WriteFile(path, bytes.Repeat([]byte{7}, encryptionKeyLen), 0600))
+	kek, err := NewKeyFileKEK(path)
+	require.NoError(t, err)
+	return kek
+}
+
+func newTestEncryptionBucket(t *testing.T) (raw gcs.Bucket, encrypted gcs.Bucket) {
+	t.Helper()
+	raw = fake.NewFakeBucket(timeutil.RealClock(), "some-bucket", gcs.BucketType{})
+	return raw, NewEncryptionBucket(newTestKEK(t), raw)
+}
+
+func randomContents(size int) []byte {
+	contents := make([]byte, size)
+	rand.New(rand.NewSource(int64(size))).Read(contents)
+	return contents
+}
+
+func readRange(t *testing.T, bucket gcs.Bucket, name string, start, limit uint64) []byte {
+	t.Helper()
+	r, err := bucket.NewReaderWithReadHandle(context.Background(), &gcs.ReadObjectRequest{
+		Name:  name,
+		Range: &gcs.ByteRange{Start: start, Limit: limit},
+	})
+	require.NoError(t, err)
+	defer r.Close()
+	contents, err := io.ReadAll(r)
+	require.NoError(t, err)
+	return contents
+}
+
+func TestPlaintextSize(t *testing.T) {
+	for _, size := range []uint64{0, 1, encryptionFrameSize - 1, encryptionFrameSize, 3*encryptionFrameSize + 5} {
+		frames := size/encryptionFrameSize + 1
+		got, err := plaintextSize(size + frames*encryptionTagSize)
+		require.NoError(t, err)
+		assert.Equal(t, size, got)
+	}
+	_, err := plaintextSize(encryptedFrameSize + 3)
+	assert.Error(t, err)
+}
+
+func TestEncryptionBucketCreateObject(t *testing.T) {
+	for _, size := range []int{0, 1, encryptionFrameSize - 1, encryptionFrameSize, 3*encryptionFrameSize + 5} {
+		raw, bucket := newTestEncryptionBucket(t)
+		contents := randomContents(size)
+
+		o, err := bucket.CreateObject(context.Background(), &gcs.CreateObjectRequest{
+			Name:     "foo",
+			Contents: bytes.NewReader(contents),
+			Metadata: map[string]string{"bar": "baz"},
+		})
+
+		require.NoError(t, err, size)
+		assert.Equal(t, uint64(size), o.Size)
+		assert.Equal(t, map[string]string{"bar": "baz"}, o.Metadata)
+		m, _, err := bucket.StatObject(context.Background(), &gcs.StatObjectRequest{Name: "foo"})
+		require.NoError(t, err)
+		assert.Equal(t, uint64(size), m.Size)
+		assert.Nil(t, m.CRC32C)
+		assert.Equal(t, map[string]string{"bar": "baz"}, m.Metadata)
+		read, err := storageutil.ReadObject(context.Background(), bucket, "foo")
+		require.NoError(t, err)
+		assert.Equal(t, contents, read)
+		// The contents are encrypted at rest.
+		stored, err := storageutil.ReadObject(context.Background(), raw, "foo")
+		require.NoError(t, err)
+		assert.Equal(t, size+(size/encryptionFrameSize+1)*encryptionTagSize, len(stored))
+		if size >= 16 {
+			assert.False(t, bytes.Contains(stored, contents[:16]))
+		}
+	}
+}
+
+func TestEncryptionBucketRangeReads(t *testing.T) {
+	_, bucket := newTestEncryptionBucket(t)
+	contents := randomContents(3*encryptionFrameSize + 5)
+	_, err := storageutil.CreateObject(context.Background(), bucket, "foo", contents)
+	require.NoError(t, err)
+	size := uint64(len(contents))
+
+	for _, r := range []gcs.ByteRange{
+		{Start: 0, Limit: 10},
+		{Start: encryptionFrameSize - 3, Limit: encryptionFrameSize + 3},
+		{Start: encryptionFrameSize, Limit: 2 * encryptionFrameSize},
+		{Start: 17, Limit: 3*encryptionFrameSize + 1},
+		{Start: size - 2, Limit: size + 10},
+		{Start: size + 2, Limit: size + 10},
+		{Start: 5, Limit: 5},
+	} {
+		expected := contents[min(r.Start, size):min(r.Limit, size)]
+		assert.Equal(t, expected, readRange(t, bucket, "foo", r.Start, r.Limit), r.String())
+	}
+}
+
+func TestEncryptionBucketChunkWriter(t *testing.T) {
+	_, bucket := newTestEncryptionBucket(t)
+	contents := randomContents(2*encryptionFrameSize + 100)
+	w, err := bucket.CreateObjectChunkWriter(context.Background(), &gcs.CreateObjectRequest{Name: "foo"}, 1<<20, nil)
+	require.NoError(t, err)
+
+	// Write in pieces that straddle frames.
+	for rest := contents; len(rest) > 0; {
+		n := min(len(rest), 40000)
+		_, err = w.Write(rest[:n])
+		require.NoError(t, err)
+		rest = rest[n:]
+	}
+	m, err := bucket.FinalizeUpload(context.Background(), w)
+
+	require.NoError(t, err)
+	assert.Equal(t, uint64(len(contents)), m.Size)
+	assert.Empty(t, m.Metadata)
+	read, err := storageutil.ReadObject(context.Background(), bucket, "foo")
+	require.NoError(t, err)
+	assert.Equal(t, contents, read)
+}
+
+func TestEncryptionBucketReadsUnencryptedObjects(t *testing.T) {
+	raw, bucket := newTestEncryptionBucket(t)
+	_, err := storageutil.CreateObject(context.Background(), raw, "foo", []byte("taco"))
+	require.NoError(t, err)
+
+	m, _, err := bucket.StatObject(context.Background(), &gcs.StatObjectRequest{Name: "foo"})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4), m.Size)
+	assert.Equal(t, []byte("ac"), readRange(t, bucket, "foo", 1, 3))
+}
+
+func TestEncryptionBucketDetectsTampering(t *testing.T) {
+	raw, bucket := newTestEncryptionBucket(t)
+	contents := randomContents(2 * encryptionFrameSize)
+	_, err := storageutil.CreateObject(context.Background(), bucket, "foo", contents)
+	require.NoError(t, err)
+	m, _, err := raw.StatObject(context.Background(), &gcs.StatObjectRequest{Name: "foo"})
+	require.NoError(t, err)
+	stored, err := storageutil.ReadObject(context.Background(), raw, "foo")
+	require.NoError(t, err)
+
+	for name, tampered := range map[string][]byte{
+		"flipped_bit": append(append([]byte{}, stored[:10]...), append([]byte{stored[10] ^ 1}, stored[11:]...)...),
+		// Dropping the final frame leaves a valid size, but makes the last full
+		// frame look final.
+		"truncated": stored[:encryptedFrameSize+encryptionTagSize],
+	} {
+		_, err = raw.CreateObject(context.Background(), &gcs.CreateObjectRequest{
+			Name:     name,
+			Contents: bytes.NewReader(tampered),
+			Metadata: m.Metadata,
+		})
+		require.NoError(t, err)
+
+		_, err = storageutil.ReadObject(context.Background(), bucket, name)
+
+		assert.ErrorContains(t, err, "decrypting frame", name)
+	}
+}
+
+func TestEncryptionBucketKeepsEncryptionMetadata(t *testing.T) {
+	raw, bucket := newTestEncryptionBucket(t)
+	_, err := storageutil.CreateObject(context.Background(), bucket, "foo", []byte("taco"))
+	require.NoError(t, err)
+
+	o, err := bucket.UpdateObject(context.Background(), &gcs.UpdateObjectRequest{
+		Name:     "foo",
+		Metadata: map[string]*string{encryptionKeyMetadataKey: nil},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4), o.Size)
+	m, _, err := raw.StatObject(context.Background(), &gcs.StatObjectRequest{Name: "foo"})
+	require.NoError(t, err)
+	assert.NotEmpty(t, m.Metadata[encryptionKeyMetadataKey])
+}
+
+func TestEncryptionBucketCopiesStayReadable(t *testing.T) {
+	_, bucket := newTestEncryptionBucket(t)
+	_, err := storageutil.CreateObject(context.Background(), bucket, "foo", []byte("taco"))
+	require.NoError(t, err)
+
+	o, err := bucket.CopyObject(context.Background(), &gcs.CopyObjectRequest{SrcName: "foo", DstName: "bar"})
+
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4), o.Size)
+	read, err := storageutil.ReadObject(context.Background(), bucket, "bar")
+	require.NoError(t, err)
+	assert.Equal(t, "taco", string(read))
+}
+
+func TestEncryptionBucketDoesNotCompose(t *testing.T) {
+	_, bucket := newTestEncryptionBucket(t)
+
+	_, err := bucket.ComposeObjects(context.Background(), &gcs.ComposeObjectsRequest{DstName: "foo"})
+
+	assert.ErrorIs(t, err, syscall.ENOTSUP)
+}
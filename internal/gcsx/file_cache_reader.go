@@ -149,6 +149,11 @@ func (fc *FileCacheReader) tryReadingFromFileCache(ctx context.Context, p []byte
 				logger.Warnf("tryReadingFromFileCache: while creating CacheHandle: %v", err)
 				err = nil
 				return 0, false, nil
+			case errors.Is(err, lru.ErrNotAdmitted):
+				// Fall back to GCS if the eviction policy of the cache deems the file
+				// not worth caching yet.
+				err = nil
+				return 0, false, nil
 			case errors.Is(err, cacheUtil.ErrCacheHandleNotRequiredForRandomRead):
 				// Fall back to GCS if it is a random read, cacheFileForRangeRead is
 				// false and there doesn't already exist file in cache.
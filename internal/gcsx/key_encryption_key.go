@@ -0,0 +1,167 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcsx
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"golang.org/x/net/context"
+)
+
+// The length of keys, both key-encryption keys and data keys: AES-256.
+const encryptionKeyLen = storageutil.AES256KeyLen
+
+// KeyEncryptionKey wraps the per-object data keys of encrypted objects, whose
+// wrapped form is kept in the metadata of the objects.
+type KeyEncryptionKey interface {
+	// ID identifies the key, e.g. to tell which key an object's data key was
+	// wrapped with. It must not reveal the key.
+	ID() string
+
+	WrapKey(ctx context.Context, key []byte) ([]byte, error)
+	UnwrapKey(ctx context.Context, wrapped []byte) ([]byte, error)
+}
+
+////////////////////////////////////////////////////////////////////////
+// Key files
+////////////////////////////////////////////////////////////////////////
+
+// Additional data authenticated along with wrapped keys.
+var keyFileAAD = []byte("gcsfuse data key")
+
+// NewKeyFileKEK loads a key-encryption key from a file holding 32 bytes, either
+// raw or base64 encoded.
+func NewKeyFileKEK(path string) (KeyEncryptionKey, error) {
+	key, err := storageutil.ReadAES256KeyFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("NewCipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("NewGCM: %w", err)
+	}
+
+	sum := sha256.Sum256(key)
+	return &keyFileKEK{
+		id:   "sha256:" + hex.EncodeToString(sum[:8]),
+		aead: aead,
+	}, nil
+}
+
+type keyFileKEK struct {
+	id   string
+	aead cipher.AEAD
+}
+
+func (k *keyFileKEK) ID() string {
+	return k.id
+}
+
+// Keys are wrapped as a random nonce followed by their sealed form.
+func (k *keyFileKEK) WrapKey(_ context.Context, key []byte) ([]byte, error) {
+	nonce := make([]byte, k.aead.NonceSize(), k.aead.NonceSize()+len(key)+k.aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("rand.Read: %w", err)
+	}
+	return k.aead.Seal(nonce, nonce, key, keyFileAAD), nil
+}
+
+func (k *keyFileKEK) UnwrapKey(_ context.Context, wrapped []byte) ([]byte, error) {
+	if len(wrapped) < k.aead.NonceSize() {
+		return nil, errors.New("wrapped key is too short")
+	}
+	nonce, sealed := wrapped[:k.aead.NonceSize()], wrapped[k.aead.NonceSize():]
+	key, err := k.aead.Open(nil, nonce, sealed, keyFileAAD)
+	if err != nil {
+		return nil, fmt.Errorf("key %s can't unwrap the data key: %w", k.id, err)
+	}
+	return key, nil
+}
+
+////////////////////////////////////////////////////////////////////////
+// Plugins
+////////////////////////////////////////////////////////////////////////
+
+// NewPluginKEK returns a key-encryption key held by an external program, such
+// as a client of a KMS. The program is run with a single argument:
+//
+//   - "id" prints an identifier of the key.
+//   - "wrap" reads a data key from standard input and prints its wrapped form.
+//   - "unwrap" reads a wrapped data key and prints the data key.
+//
+// It must exit with a non-zero status on failure.
+func NewPluginKEK(ctx context.Context, path string) (KeyEncryptionKey, error) {
+	k := &pluginKEK{path: path}
+	id, err := k.run(ctx, "id", nil)
+	if err != nil {
+		return nil, err
+	}
+	k.id = strings.TrimSpace(string(id))
+	if k.id == "" {
+		return nil, fmt.Errorf("%s id: printed an empty key ID", path)
+	}
+	return k, nil
+}
+
+type pluginKEK struct {
+	path string
+	id   string
+}
+
+func (k *pluginKEK) run(ctx context.Context, command string, stdin []byte) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, k.path, command)
+	cmd.Stdin = bytes.NewReader(stdin)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w: %s", k.path, command, err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+func (k *pluginKEK) ID() string {
+	return k.id
+}
+
+func (k *pluginKEK) WrapKey(ctx context.Context, key []byte) ([]byte, error) {
+	return k.run(ctx, "wrap", key)
+}
+
+func (k *pluginKEK) UnwrapKey(ctx context.Context, wrapped []byte) ([]byte, error) {
+	key, err := k.run(ctx, "unwrap", wrapped)
+	if err != nil {
+		return nil, err
+	}
+	if len(key) != encryptionKeyLen {
+		return nil, fmt.Errorf("%s unwrap: printed a %d-byte key, want %d bytes", k.path, len(key), encryptionKeyLen)
+	}
+	return key, nil
+}
@@ -0,0 +1,106 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcsx
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+func TestKeyFileKEK(t *testing.T) {
+	key := bytes.Repeat([]byte{7}, encryptionKeyLen)
+	dir := t.TempDir()
+	rawPath := filepath.Join(dir, "raw")
+	require.NoError(t, os.WriteFile(rawPath, key, 0600))
+	base64Path := filepath.Join(dir, "base64")
+	require.NoError(t, os.WriteFile(base64Path, []byte(base64.StdEncoding.EncodeToString(key)+"\n"), 0600))
+	raw, err := NewKeyFileKEK(rawPath)
+	require.NoError(t, err)
+	encoded, err := NewKeyFileKEK(base64Path)
+	require.NoError(t, err)
+	dataKey := bytes.Repeat([]byte{1}, encryptionKeyLen)
+
+	wrapped, err := raw.WrapKey(context.Background(), dataKey)
+	require.NoError(t, err)
+	unwrapped, err := encoded.UnwrapKey(context.Background(), wrapped)
+
+	require.NoError(t, err)
+	assert.Equal(t, dataKey, unwrapped)
+	assert.Equal(t, raw.ID(), encoded.ID())
+	assert.NotContains(t, string(wrapped), string(dataKey))
+}
+
+func TestKeyFileKEKRejectsOtherKeys(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "kek")
+	require.NoError(t, os.WriteFile(path, bytes.Repeat([]byte{7}, encryptionKeyLen), 0600))
+	otherPath := filepath.Join(dir, "other")
+	require.NoError(t, os.WriteFile(otherPath, bytes.Repeat([]byte{8}, encryptionKeyLen), 0600))
+	kek, err := NewKeyFileKEK(path)
+	require.NoError(t, err)
+	other, err := NewKeyFileKEK(otherPath)
+	require.NoError(t, err)
+	wrapped, err := kek.WrapKey(context.Background(), bytes.Repeat([]byte{1}, encryptionKeyLen))
+	require.NoError(t, err)
+
+	_, err = other.UnwrapKey(context.Background(), wrapped)
+
+	assert.Error(t, err)
+	assert.NotEqual(t, kek.ID(), other.ID())
+}
+
+func TestKeyFileKEKRequiresAKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kek")
+	require.NoError(t, os.WriteFile(path, []byte("too short"), 0600))
+
+	_, err := NewKeyFileKEK(path)
+
+	assert.Error(t, err)
+}
+
+func TestPluginKEK(t *testing.T) {
+	// A plugin that "wraps" keys by reversing their bytes.
+	path := filepath.Join(t.TempDir(), "plugin")
+	script := `#!/bin/sh
+case "$1" in
+  id) echo "test-key" ;;
+  wrap|unwrap) od -An -v -tx1 | tr -s ' \n' '\n\n' | sed '/^$/d' | tac | sed 's/^/\\x/' | tr -d '\n' | xargs -0 printf ;;
+  *) echo "unknown command $1" >&2; exit 1 ;;
+esac
+`
+	require.NoError(t, os.WriteFile(path, []byte(script), 0700))
+	kek, err := NewPluginKEK(context.Background(), path)
+	require.NoError(t, err)
+	dataKey := make([]byte, encryptionKeyLen)
+	for i := range dataKey {
+		dataKey[i] = byte(i + 1)
+	}
+
+	wrapped, err := kek.WrapKey(context.Background(), dataKey)
+	require.NoError(t, err)
+	unwrapped, err := kek.UnwrapKey(context.Background(), wrapped)
+
+	require.NoError(t, err)
+	assert.Equal(t, "test-key", kek.ID())
+	assert.Equal(t, byte(encryptionKeyLen), wrapped[0])
+	assert.Equal(t, dataKey, unwrapped)
+}
@@ -279,6 +279,10 @@ func (rr *randomReader) tryReadingFromFileCache(ctx context.Context,
 			if errors.Is(err, lru.ErrInvalidEntrySize) {
 				logger.Warnf("tryReadingFromFileCache: while creating CacheHandle: %v", err)
 				return 0, false, nil
+			} else if errors.Is(err, lru.ErrNotAdmitted) {
+				// Fall back to GCS if the eviction policy of the cache deems the file
+				// not worth caching yet.
+				return 0, false, nil
 			} else if errors.Is(err, cacheutil.ErrCacheHandleNotRequiredForRandomRead) {
 				// Fall back to GCS if it is a random read, cacheFileForRangeRead is
 				// False and there doesn't already exist file in cache.
@@ -51,6 +51,7 @@ type ReadManagerConfig struct {
 	MrdWrapper            *gcsx.MultiRangeDownloaderWrapper
 	Config                *cfg.Config
 	GlobalMaxBlocksSem    *semaphore.Weighted
+	BlockShares           *bufferedread.BlockShares
 	WorkerPool            workerpool.WorkerPool
 }
 
@@ -82,6 +83,7 @@ func NewReadManager(object *gcs.MinObject, bucket gcs.Bucket, config *ReadManage
 			InitialPrefetchBlockCnt: readConfig.StartBlocksPerHandle,
 			MinBlocksPerHandle:      readConfig.MinBlocksPerHandle,
 			RandomSeekThreshold:     readConfig.RandomSeekThreshold,
+			AdaptivePrefetch:        readConfig.AdaptivePrefetch,
 		}
 		opts := &bufferedread.BufferedReaderOptions{
 			Object:             object,
@@ -90,6 +92,7 @@ func NewReadManager(object *gcs.MinObject, bucket gcs.Bucket, config *ReadManage
 			GlobalMaxBlocksSem: config.GlobalMaxBlocksSem,
 			WorkerPool:         config.WorkerPool,
 			MetricHandle:       config.MetricHandle,
+			BlockShares:        config.BlockShares,
 		}
 		bufferedReader, err := bufferedread.NewBufferedReader(opts)
 		if err != nil {
@@ -0,0 +1,374 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcsx
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"golang.org/x/net/context"
+)
+
+// GenerationManifest maps bucket names to the generations of their objects,
+// by object name.
+type GenerationManifest map[string]map[string]int64
+
+// LoadGenerationManifest reads a generation manifest from a file with one
+// "gs://BUCKET/OBJECT#GENERATION" per line, as printed by
+// "gcloud storage ls -a". Empty lines are ignored.
+func LoadGenerationManifest(path string) (GenerationManifest, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	m := make(GenerationManifest)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<20)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		bucketName, objectName, generation, err := parseManifestLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, lineNo, err)
+		}
+		if m[bucketName] == nil {
+			m[bucketName] = make(map[string]int64)
+		}
+		if _, ok := m[bucketName][objectName]; ok {
+			return nil, fmt.Errorf("%s:%d: gs://%s/%s is listed more than once", path, lineNo, bucketName, objectName)
+		}
+		m[bucketName][objectName] = generation
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func parseManifestLine(line string) (bucketName string, objectName string, generation int64, err error) {
+	rest, ok := strings.CutPrefix(line, "gs://")
+	if !ok {
+		err = fmt.Errorf("%q doesn't start with gs://", line)
+		return
+	}
+	bucketName, rest, _ = strings.Cut(rest, "/")
+	i := strings.LastIndexByte(rest, '#')
+	if bucketName == "" || i <= 0 {
+		err = fmt.Errorf("%q is not of the form gs://BUCKET/OBJECT#GENERATION", line)
+		return
+	}
+	objectName = rest[:i]
+	if generation, err = strconv.ParseInt(rest[i+1:], 10, 64); err != nil || generation <= 0 {
+		err = fmt.Errorf("malformed generation in %q", line)
+	}
+	return
+}
+
+// NewSnapshotBucket creates a read-only view on the wrapped bucket as it was
+// at the supplied time: each object name resolves to the generation that was
+// live then, if any, as found by listing versions. Without object versioning,
+// objects replaced or deleted since then are missing from the view.
+func NewSnapshotBucket(at time.Time, wrapped gcs.Bucket) gcs.Bucket {
+	return &snapshotBucket{
+		wrapped: wrapped,
+		inSnapshot: func(_ *gcs.MinObject, l gcs.ObjectLifetime) bool {
+			return !l.Created.After(at) && (l.Deleted.IsZero() || l.Deleted.After(at))
+		},
+	}
+}
+
+// NewManifestBucket creates a read-only view on the wrapped bucket made of
+// the supplied generations, by object name. Other objects don't exist in the
+// view.
+func NewManifestBucket(generations map[string]int64, wrapped gcs.Bucket) gcs.Bucket {
+	names := make([]string, 0, len(generations))
+	for name := range generations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return &snapshotBucket{
+		wrapped: wrapped,
+		inSnapshot: func(o *gcs.MinObject, _ gcs.ObjectLifetime) bool {
+			g, ok := generations[o.Name]
+			return ok && o.Generation == g
+		},
+		names: names,
+	}
+}
+
+// snapshotBucket is a read-only view on a bucket made of a single generation
+// of some of its objects, found by listing versions. Modifications fail with
+// EROFS.
+type snapshotBucket struct {
+	wrapped gcs.Bucket
+
+	// Returns true if the supplied generation of an object, with the supplied
+	// lifetime, is in the snapshot. At most one generation of each name is.
+	inSnapshot func(o *gcs.MinObject, l gcs.ObjectLifetime) bool
+
+	// The sorted names of the objects in the snapshot, if known, used to leave
+	// out the collapsed runs without any without listing their versions.
+	names []string
+}
+
+var errReadOnlySnapshot = fmt.Errorf("the bucket is a read-only snapshot: %w", syscall.EROFS)
+
+func (b *snapshotBucket) Name() string {
+	return b.wrapped.Name()
+}
+
+func (b *snapshotBucket) BucketType() gcs.BucketType {
+	return b.wrapped.BucketType()
+}
+
+func (b *snapshotBucket) GCSName(object *gcs.MinObject) string {
+	return b.wrapped.GCSName(object)
+}
+
+// hasNameWithPrefix returns true if the names of objects in the snapshot are
+// unknown, or if one of them starts with the supplied prefix.
+func (b *snapshotBucket) hasNameWithPrefix(prefix string) bool {
+	if b.names == nil {
+		return true
+	}
+	i, _ := slices.BinarySearch(b.names, prefix)
+	return i < len(b.names) && strings.HasPrefix(b.names[i], prefix)
+}
+
+// hasObjectWithPrefix returns true if the name of an object in the snapshot
+// starts with the supplied prefix. Unless the names are known, this lists the
+// versions under the prefix until it finds one.
+func (b *snapshotBucket) hasObjectWithPrefix(ctx context.Context, prefix string) (bool, error) {
+	if b.names != nil {
+		return b.hasNameWithPrefix(prefix), nil
+	}
+
+	req := &gcs.ListObjectsRequest{Prefix: prefix}
+	for {
+		listing, err := b.listVersions(ctx, req)
+		if err != nil {
+			return false, err
+		}
+		for i, o := range listing.MinObjects {
+			if b.inSnapshot(o, listing.Lifetimes[i]) {
+				return true, nil
+			}
+		}
+		if listing.ContinuationToken == "" {
+			return false, nil
+		}
+		req.ContinuationToken = listing.ContinuationToken
+	}
+}
+
+// listVersions lists the versions of the objects matching the request.
+func (b *snapshotBucket) listVersions(ctx context.Context, req *gcs.ListObjectsRequest) (*gcs.Listing, error) {
+	mReq := new(gcs.ListObjectsRequest)
+	*mReq = *req
+	mReq.Versions = true
+
+	listing, err := b.wrapped.ListObjects(ctx, mReq)
+	if err != nil {
+		return nil, err
+	}
+	if len(listing.Lifetimes) != len(listing.MinObjects) {
+		return nil, errors.New("the bucket doesn't support listing versions")
+	}
+	return listing, nil
+}
+
+// resolve returns the generation of the named object in the snapshot.
+func (b *snapshotBucket) resolve(ctx context.Context, name string) (*gcs.MinObject, error) {
+	if !b.hasNameWithPrefix(name) {
+		return nil, &gcs.NotFoundError{Err: fmt.Errorf("object %q is not in the snapshot", name)}
+	}
+
+	// The versions of the name are listed first among the objects it is a
+	// prefix of.
+	req := &gcs.ListObjectsRequest{Prefix: name}
+	for {
+		listing, err := b.listVersions(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		for i, o := range listing.MinObjects {
+			if o.Name != name {
+				break
+			}
+			if b.inSnapshot(o, listing.Lifetimes[i]) {
+				return o, nil
+			}
+		}
+		last := len(listing.MinObjects) - 1
+		if listing.ContinuationToken == "" || (last >= 0 && listing.MinObjects[last].Name != name) {
+			break
+		}
+		req.ContinuationToken = listing.ContinuationToken
+	}
+	return nil, &gcs.NotFoundError{Err: fmt.Errorf("object %q is not in the snapshot", name)}
+}
+
+func (b *snapshotBucket) ListObjects(
+	ctx context.Context,
+	req *gcs.ListObjectsRequest) (*gcs.Listing, error) {
+	listing, err := b.listVersions(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &gcs.Listing{ContinuationToken: listing.ContinuationToken}
+	for i, o := range listing.MinObjects {
+		if !b.inSnapshot(o, listing.Lifetimes[i]) {
+			continue
+		}
+		result.MinObjects = append(result.MinObjects, o)
+		if req.Versions {
+			result.Lifetimes = append(result.Lifetimes, listing.Lifetimes[i])
+		}
+	}
+	// Leave out the runs, i.e. directories, without any object in the snapshot.
+	for _, p := range listing.CollapsedRuns {
+		ok, err := b.hasObjectWithPrefix(ctx, p)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			result.CollapsedRuns = append(result.CollapsedRuns, p)
+		}
+	}
+	return result, nil
+}
+
+func (b *snapshotBucket) StatObject(
+	ctx context.Context,
+	req *gcs.StatObjectRequest) (*gcs.MinObject, *gcs.ExtendedObjectAttributes, error) {
+	m, err := b.resolve(ctx, req.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Listings don't return the extended attributes.
+	var e *gcs.ExtendedObjectAttributes
+	if req.ReturnExtendedObjectAttributes {
+		e = &gcs.ExtendedObjectAttributes{}
+	}
+	return m, e, nil
+}
+
+func (b *snapshotBucket) NewReaderWithReadHandle(
+	ctx context.Context,
+	req *gcs.ReadObjectRequest) (gcs.StorageReader, error) {
+	mReq := new(gcs.ReadObjectRequest)
+	*mReq = *req
+	if mReq.Generation == 0 {
+		m, err := b.resolve(ctx, req.Name)
+		if err != nil {
+			return nil, err
+		}
+		mReq.Generation = m.Generation
+	}
+	return b.wrapped.NewReaderWithReadHandle(ctx, mReq)
+}
+
+func (b *snapshotBucket) NewMultiRangeDownloader(
+	ctx context.Context,
+	req *gcs.MultiRangeDownloaderRequest) (gcs.MultiRangeDownloader, error) {
+	mReq := new(gcs.MultiRangeDownloaderRequest)
+	*mReq = *req
+	if mReq.Generation == 0 {
+		m, err := b.resolve(ctx, req.Name)
+		if err != nil {
+			return nil, err
+		}
+		mReq.Generation = m.Generation
+	}
+	return b.wrapped.NewMultiRangeDownloader(ctx, mReq)
+}
+
+func (b *snapshotBucket) GetFolder(ctx context.Context, folderName string) (*gcs.Folder, error) {
+	ok, err := b.hasObjectWithPrefix(ctx, folderName)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, &gcs.NotFoundError{Err: fmt.Errorf("folder %q is not in the snapshot", folderName)}
+	}
+	return b.wrapped.GetFolder(ctx, folderName)
+}
+
+func (b *snapshotBucket) CreateObject(context.Context, *gcs.CreateObjectRequest) (*gcs.Object, error) {
+	return nil, errReadOnlySnapshot
+}
+
+func (b *snapshotBucket) CreateObjectChunkWriter(context.Context, *gcs.CreateObjectRequest, int, func(int64)) (gcs.Writer, error) {
+	return nil, errReadOnlySnapshot
+}
+
+func (b *snapshotBucket) CreateAppendableObjectWriter(context.Context, *gcs.CreateObjectChunkWriterRequest) (gcs.Writer, error) {
+	return nil, errReadOnlySnapshot
+}
+
+func (b *snapshotBucket) FinalizeUpload(context.Context, gcs.Writer) (*gcs.MinObject, error) {
+	return nil, errReadOnlySnapshot
+}
+
+func (b *snapshotBucket) FlushPendingWrites(context.Context, gcs.Writer) (*gcs.MinObject, error) {
+	return nil, errReadOnlySnapshot
+}
+
+func (b *snapshotBucket) CopyObject(context.Context, *gcs.CopyObjectRequest) (*gcs.Object, error) {
+	return nil, errReadOnlySnapshot
+}
+
+func (b *snapshotBucket) ComposeObjects(context.Context, *gcs.ComposeObjectsRequest) (*gcs.Object, error) {
+	return nil, errReadOnlySnapshot
+}
+
+func (b *snapshotBucket) UpdateObject(context.Context, *gcs.UpdateObjectRequest) (*gcs.Object, error) {
+	return nil, errReadOnlySnapshot
+}
+
+func (b *snapshotBucket) DeleteObject(context.Context, *gcs.DeleteObjectRequest) error {
+	return errReadOnlySnapshot
+}
+
+func (b *snapshotBucket) MoveObject(context.Context, *gcs.MoveObjectRequest) (*gcs.Object, error) {
+	return nil, errReadOnlySnapshot
+}
+
+func (b *snapshotBucket) DeleteFolder(context.Context, string) error {
+	return errReadOnlySnapshot
+}
+
+func (b *snapshotBucket) RenameFolder(context.Context, string, string) (*gcs.Folder, error) {
+	return nil, errReadOnlySnapshot
+}
+
+func (b *snapshotBucket) CreateFolder(context.Context, string) (*gcs.Folder, error) {
+	return nil, errReadOnlySnapshot
+}
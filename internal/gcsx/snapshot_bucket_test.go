@@ -0,0 +1,217 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcsx
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+var t0 = time.Date(2026, 1, 2, 15, 0, 0, 0, time.UTC)
+
+type objectVersion struct {
+	name       string
+	generation int64
+	lifetime   gcs.ObjectLifetime
+}
+
+// versionedBucket lists the versions of its objects, one per page, and
+// records the reads.
+type versionedBucket struct {
+	gcs.Bucket
+
+	// Ordered by name and then generation.
+	versions []objectVersion
+	reads    []*gcs.ReadObjectRequest
+}
+
+func (b *versionedBucket) ListObjects(_ context.Context, req *gcs.ListObjectsRequest) (*gcs.Listing, error) {
+	start := 0
+	if req.ContinuationToken != "" {
+		start, _ = strconv.Atoi(req.ContinuationToken)
+	}
+	listing := &gcs.Listing{}
+	for i := start; i < len(b.versions); i++ {
+		v := b.versions[i]
+		if !strings.HasPrefix(v.name, req.Prefix) || (!req.Versions && !v.lifetime.Deleted.IsZero()) {
+			continue
+		}
+		if req.Delimiter != "" {
+			if j := strings.Index(v.name[len(req.Prefix):], req.Delimiter); j >= 0 {
+				run := v.name[:len(req.Prefix)+j+1]
+				if n := len(listing.CollapsedRuns); n == 0 || listing.CollapsedRuns[n-1] != run {
+					listing.CollapsedRuns = append(listing.CollapsedRuns, run)
+				}
+				continue
+			}
+		}
+		listing.MinObjects = append(listing.MinObjects, &gcs.MinObject{Name: v.name, Generation: v.generation})
+		if req.Versions {
+			listing.Lifetimes = append(listing.Lifetimes, v.lifetime)
+		}
+		if req.Delimiter == "" && i+1 < len(b.versions) {
+			listing.ContinuationToken = strconv.Itoa(i + 1)
+			break
+		}
+	}
+	return listing, nil
+}
+
+func (b *versionedBucket) NewReaderWithReadHandle(_ context.Context, req *gcs.ReadObjectRequest) (gcs.StorageReader, error) {
+	b.reads = append(b.reads, req)
+	return nil, nil
+}
+
+func newVersionedBucket() *versionedBucket {
+	at := func(hours int) time.Time { return t0.Add(time.Duration(hours) * time.Hour) }
+	return &versionedBucket{versions: []objectVersion{
+		// Replaced at 2h.
+		{name: "a", generation: 1, lifetime: gcs.ObjectLifetime{Created: at(0), Deleted: at(2)}},
+		{name: "a", generation: 2, lifetime: gcs.ObjectLifetime{Created: at(2)}},
+		// Created at 3h.
+		{name: "b", generation: 3, lifetime: gcs.ObjectLifetime{Created: at(3)}},
+		{name: "c", generation: 4, lifetime: gcs.ObjectLifetime{Created: at(0)}},
+		// Deleted at 1h.
+		{name: "d/x", generation: 5, lifetime: gcs.ObjectLifetime{Created: at(0), Deleted: at(1)}},
+		{name: "e/y", generation: 6, lifetime: gcs.ObjectLifetime{Created: at(0)}},
+	}}
+}
+
+func names(objects []*gcs.MinObject) (result []string) {
+	for _, o := range objects {
+		result = append(result, o.Name+"#"+strconv.FormatInt(o.Generation, 10))
+	}
+	return
+}
+
+func TestSnapshotBucketListsObjectsLiveAtTime(t *testing.T) {
+	b := NewSnapshotBucket(t0.Add(90*time.Minute), newVersionedBucket())
+
+	listing, err := b.ListObjects(context.Background(), &gcs.ListObjectsRequest{Delimiter: "/"})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a#1", "c#4"}, names(listing.MinObjects))
+	// Everything under d/ was deleted by then.
+	assert.Equal(t, []string{"e/"}, listing.CollapsedRuns)
+	assert.Nil(t, listing.Lifetimes)
+	_, err = b.GetFolder(context.Background(), "d/")
+	var notFoundErr *gcs.NotFoundError
+	assert.ErrorAs(t, err, &notFoundErr)
+}
+
+func TestSnapshotBucketStatsObjectsLiveAtTime(t *testing.T) {
+	b := NewSnapshotBucket(t0.Add(30*time.Minute), newVersionedBucket())
+
+	a, _, err := b.StatObject(context.Background(), &gcs.StatObjectRequest{Name: "a"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), a.Generation)
+	x, _, err := b.StatObject(context.Background(), &gcs.StatObjectRequest{Name: "d/x"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), x.Generation)
+
+	for _, name := range []string{"b", "d/", "f"} {
+		_, _, err = b.StatObject(context.Background(), &gcs.StatObjectRequest{Name: name})
+
+		var notFoundErr *gcs.NotFoundError
+		assert.ErrorAs(t, err, &notFoundErr, name)
+	}
+}
+
+func TestSnapshotBucketReadsPinnedGenerations(t *testing.T) {
+	wrapped := newVersionedBucket()
+	b := NewSnapshotBucket(t0.Add(30*time.Minute), wrapped)
+
+	_, err := b.NewReaderWithReadHandle(context.Background(), &gcs.ReadObjectRequest{Name: "a"})
+	require.NoError(t, err)
+	_, err = b.NewReaderWithReadHandle(context.Background(), &gcs.ReadObjectRequest{Name: "c", Generation: 4})
+	require.NoError(t, err)
+
+	require.Len(t, wrapped.reads, 2)
+	assert.Equal(t, int64(1), wrapped.reads[0].Generation)
+	assert.Equal(t, int64(4), wrapped.reads[1].Generation)
+	_, err = b.NewReaderWithReadHandle(context.Background(), &gcs.ReadObjectRequest{Name: "b"})
+	assert.Error(t, err)
+}
+
+func TestManifestBucketListsObjectsInManifest(t *testing.T) {
+	b := NewManifestBucket(map[string]int64{"a": 2, "d/x": 5, "c": 1}, newVersionedBucket())
+
+	listing, err := b.ListObjects(context.Background(), &gcs.ListObjectsRequest{Delimiter: "/"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a#2"}, names(listing.MinObjects))
+	assert.Equal(t, []string{"d/"}, listing.CollapsedRuns)
+
+	x, _, err := b.StatObject(context.Background(), &gcs.StatObjectRequest{Name: "d/x"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), x.Generation)
+	_, _, err = b.StatObject(context.Background(), &gcs.StatObjectRequest{Name: "c"})
+	var notFoundErr *gcs.NotFoundError
+	assert.ErrorAs(t, err, &notFoundErr)
+}
+
+func TestSnapshotBucketRejectsModifications(t *testing.T) {
+	b := NewSnapshotBucket(t0, newVersionedBucket())
+
+	_, err := b.CreateObject(context.Background(), &gcs.CreateObjectRequest{Name: "a"})
+	assert.True(t, errors.Is(err, syscall.EROFS), "err: %v", err)
+	err = b.DeleteObject(context.Background(), &gcs.DeleteObjectRequest{Name: "a"})
+	assert.True(t, errors.Is(err, syscall.EROFS), "err: %v", err)
+	_, err = b.MoveObject(context.Background(), &gcs.MoveObjectRequest{SrcName: "a", DstName: "b"})
+	assert.True(t, errors.Is(err, syscall.EROFS), "err: %v", err)
+}
+
+func TestLoadGenerationManifest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "manifest")
+	require.NoError(t, os.WriteFile(path, []byte("gs://b1/a#1\n\ngs://b1/dir/c#d#2\ngs://b2/a#3\n"), 0600))
+
+	m, err := LoadGenerationManifest(path)
+
+	require.NoError(t, err)
+	assert.Equal(t, GenerationManifest{
+		"b1": {"a": 1, "dir/c#d": 2},
+		"b2": {"a": 3},
+	}, m)
+}
+
+func TestLoadGenerationManifestRejectsMalformedManifests(t *testing.T) {
+	for _, contents := range []string{
+		"b1/a#1",
+		"gs://b1/a",
+		"gs://b1/#1",
+		"gs:///a#1",
+		"gs://b1/a#x",
+		"gs://b1/a#1\ngs://b1/a#2",
+	} {
+		t.Run(contents, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "manifest")
+			require.NoError(t, os.WriteFile(path, []byte(contents), 0600))
+
+			_, err := LoadGenerationManifest(path)
+
+			assert.Error(t, err)
+		})
+	}
+}
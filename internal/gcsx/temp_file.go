@@ -39,6 +39,10 @@ type TempFile interface {
 	io.WriterAt
 	Truncate(n int64) (err error)
 
+	// Zero the supplied range of the file without changing its size. The part
+	// of the range beyond the end of the file is ignored.
+	PunchHole(offset int64, length int64) (err error)
+
 	// Retrieve the file name
 	Name() string
 
@@ -151,6 +155,9 @@ const (
 	fileDestroyed            = "fileDestroyed"
 )
 
+// The largest buffer of zeros written at once by PunchHole.
+const punchHoleChunkSize = 1 << 20
+
 type tempFile struct {
 	/////////////////////////
 	// Dependencies
@@ -305,6 +312,38 @@ func (tf *tempFile) Truncate(n int64) error {
 	return tf.f.Truncate(n)
 }
 
+func (tf *tempFile) PunchHole(offset int64, length int64) error {
+	sr, err := tf.Stat()
+	if err != nil {
+		return fmt.Errorf("cannot PunchHole incomplete file: %w", err)
+	}
+
+	end := minInt64(offset+length, sr.Size)
+	if offset >= end {
+		return nil
+	}
+
+	// Update our state regarding being dirty.
+	tf.dirtyThreshold = minInt64(tf.dirtyThreshold, offset)
+
+	tf.state = fileDirty
+
+	newMtime := tf.clock.Now()
+	tf.mtime = &newMtime
+
+	// Overwrite the range with zeros a chunk at a time.
+	zeros := make([]byte, minInt64(end-offset, punchHoleChunkSize))
+	for offset < end {
+		n := minInt64(end-offset, int64(len(zeros)))
+		if _, err := tf.f.WriteAt(zeros[:n], offset); err != nil {
+			return err
+		}
+		offset += n
+	}
+
+	return nil
+}
+
 func (tf *tempFile) SetMtime(mtime time.Time) {
 	tf.mtime = &mtime
 }
@@ -104,6 +104,12 @@ func (tf *checkingTempFile) Truncate(n int64) error {
 	return tf.wrapped.Truncate(n)
 }
 
+func (tf *checkingTempFile) PunchHole(offset int64, length int64) error {
+	tf.wrapped.CheckInvariants()
+	defer tf.wrapped.CheckInvariants()
+	return tf.wrapped.PunchHole(offset, length)
+}
+
 func (tf *checkingTempFile) SetMtime(mtime time.Time) {
 	tf.wrapped.CheckInvariants()
 	defer tf.wrapped.CheckInvariants()
@@ -236,6 +242,48 @@ func (t *TempFileTest) Truncate() {
 	ExpectEq(expected, string(actual))
 }
 
+func (t *TempFileTest) PunchHole() {
+	// Call
+	err := t.tf.PunchHole(2, 3)
+	ExpectEq(nil, err)
+
+	// Check Stat.
+	sr, err := t.tf.Stat()
+
+	AssertEq(nil, err)
+	ExpectEq(initialContentSize, sr.Size)
+	ExpectEq(2, sr.DirtyThreshold)
+	ExpectThat(sr.Mtime, Pointee(timeutil.TimeEq(t.clock.Now())))
+
+	// Read back.
+	expected := []byte(initialContent)
+	copy(expected[2:5], "\x00\x00\x00")
+
+	actual, err := readAll(&t.tf)
+	AssertEq(nil, err)
+	ExpectEq(string(expected), string(actual))
+}
+
+func (t *TempFileTest) PunchHole_PastEndOfFile() {
+	// Call
+	err := t.tf.PunchHole(int64(initialContentSize)-1, 100)
+	ExpectEq(nil, err)
+
+	// Check Stat.
+	sr, err := t.tf.Stat()
+
+	AssertEq(nil, err)
+	ExpectEq(initialContentSize, sr.Size)
+	ExpectEq(initialContentSize-1, sr.DirtyThreshold)
+
+	// Read back.
+	expected := initialContent[:initialContentSize-1] + "\x00"
+
+	actual, err := readAll(&t.tf)
+	AssertEq(nil, err)
+	ExpectEq(expected, string(actual))
+}
+
 func (t *TempFileTest) SetMtime() {
 	mtime := time.Date(2015, 4, 5, 2, 15, 0, 0, time.Local)
 	AssertThat(mtime, Not(timeutil.TimeEq(t.clock.Now())))
@@ -0,0 +1,267 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcsx
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/logger"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"github.com/jacobsa/timeutil"
+	"golang.org/x/net/context"
+	"golang.org/x/sync/errgroup"
+)
+
+// The layout of the names of the subdirectories of the trash, in UTC.
+const trashTimeLayout = "20060102T150405Z"
+
+// TrashPrefix returns the prefix of the names of objects in the trash, ending
+// with a slash, for the configured one.
+func TrashPrefix(prefix string) string {
+	return path.Clean(strings.Trim(prefix, "/")) + "/"
+}
+
+// Trash moves objects under a prefix of their bucket, in a subdirectory named
+// after the time they were moved, rather than deleting them. For example,
+// "dir/foo" unlinked at 15:04:05 UTC on 2 January 2026 is kept as
+// ".trash/20260102T150405Z/dir/foo". If another "dir/foo" was moved there
+// within the same second, it is kept under a subdirectory also named after
+// its generation, e.g. ".trash/20260102T150405Z-1767366245000000/dir/foo".
+type Trash struct {
+	prefix string
+	clock  timeutil.Clock
+
+	// Move objects with MoveObject even in buckets other than hierarchical and
+	// zonal ones, where they are otherwise copied and then deleted.
+	useMoveObject bool
+}
+
+// NewTrash creates a trash under the supplied prefix of buckets.
+func NewTrash(prefix string, useMoveObject bool, clock timeutil.Clock) *Trash {
+	return &Trash{
+		prefix:        TrashPrefix(prefix),
+		clock:         clock,
+		useMoveObject: useMoveObject,
+	}
+}
+
+// Contains returns true if the named object is in the trash.
+func (t *Trash) Contains(objectName string) bool {
+	return strings.HasPrefix(objectName, t.prefix)
+}
+
+// MoveObject moves the latest generation of the named object into the trash,
+// returning the name it is kept under.
+//
+// REQUIRES: !t.Contains(objectName)
+func (t *Trash) MoveObject(ctx context.Context, bucket gcs.Bucket, objectName string) (string, error) {
+	stamp := t.clock.Now().UTC().Format(trashTimeLayout)
+	dstName, err := t.moveObject(ctx, bucket, objectName, stamp, nil)
+	var preconditionErr *gcs.PreconditionError
+	if !errors.As(err, &preconditionErr) {
+		return dstName, err
+	}
+
+	// An object of the same name was moved into the trash within the same
+	// second. Keep this one apart, under a subdirectory also named after its
+	// generation.
+	m, _, err := bucket.StatObject(ctx, &gcs.StatObjectRequest{Name: objectName, ForceFetchFromGcs: true})
+	if err != nil {
+		return "", fmt.Errorf("StatObject: %w", err)
+	}
+	return t.moveObject(ctx, bucket, objectName, fmt.Sprintf("%s-%d", stamp, m.Generation), m)
+}
+
+// moveObject moves the named object under the supplied subdirectory of the
+// trash, provided that nothing is there already. If m is non-nil, only the
+// generation it describes is moved.
+func (t *Trash) moveObject(ctx context.Context, bucket gcs.Bucket, objectName string, subdir string, m *gcs.MinObject) (string, error) {
+	dstName := t.prefix + subdir + "/" + objectName
+	var noDst int64
+
+	if bt := bucket.BucketType(); t.useMoveObject || bt.Hierarchical || bt.Zonal {
+		req := &gcs.MoveObjectRequest{
+			SrcName:                   objectName,
+			DstName:                   dstName,
+			DstGenerationPrecondition: &noDst,
+		}
+		if m != nil {
+			req.SrcGeneration = m.Generation
+			req.SrcMetaGenerationPrecondition = &m.MetaGeneration
+		}
+		_, err := bucket.MoveObject(ctx, req)
+		if err != nil {
+			return "", fmt.Errorf("MoveObject: %w", err)
+		}
+		return dstName, nil
+	}
+
+	// Copy and then delete exactly the generation that was copied, in case the
+	// object is replaced in the meantime.
+	if m == nil {
+		var err error
+		m, _, err = bucket.StatObject(ctx, &gcs.StatObjectRequest{Name: objectName, ForceFetchFromGcs: true})
+		if err != nil {
+			return "", fmt.Errorf("StatObject: %w", err)
+		}
+	}
+	_, err := bucket.CopyObject(ctx, &gcs.CopyObjectRequest{
+		SrcName:                       objectName,
+		DstName:                       dstName,
+		SrcGeneration:                 m.Generation,
+		SrcMetaGenerationPrecondition: &m.MetaGeneration,
+		DstGenerationPrecondition:     &noDst,
+	})
+	if err != nil {
+		return "", fmt.Errorf("CopyObject: %w", err)
+	}
+	err = bucket.DeleteObject(ctx, &gcs.DeleteObjectRequest{
+		Name:       objectName,
+		Generation: m.Generation,
+	})
+	if err != nil {
+		return "", fmt.Errorf("DeleteObject: %w", err)
+	}
+	return dstName, nil
+}
+
+// trashTime returns the time the named object was moved into the trash under
+// the supplied prefix, as recorded in its name.
+func trashTime(prefix string, objectName string) (time.Time, error) {
+	subdir, _, ok := strings.Cut(strings.TrimPrefix(objectName, prefix), "/")
+	if !ok {
+		return time.Time{}, errors.New("not in a trash subdirectory")
+	}
+	// Strip the generation of the subdirectories of objects moved within the
+	// same second as another of the same name.
+	stamp, _, _ := strings.Cut(subdir, "-")
+	return time.Parse(trashTimeLayout, stamp)
+}
+
+func purgeTrashOnce(
+	ctx context.Context,
+	prefix string,
+	retention time.Duration,
+	now time.Time,
+	bucket gcs.Bucket) (objectsDeleted uint64, err error) {
+	group, ctx := errgroup.WithContext(ctx)
+
+	// List all objects in the trash.
+	minObjects := make(chan *gcs.MinObject, 100)
+	group.Go(func() (err error) {
+		defer close(minObjects)
+		err = storageutil.ListPrefix(ctx, bucket, prefix, minObjects)
+		if err != nil {
+			err = fmt.Errorf("ListPrefix: %w", err)
+			return
+		}
+
+		return
+	})
+
+	// Filter to those kept for longer than the retention period. Objects that
+	// weren't moved there by a trash are left alone.
+	expired := make(chan *gcs.MinObject, 100)
+	group.Go(func() (err error) {
+		defer close(expired)
+		for o := range minObjects {
+			trashed, err := trashTime(prefix, o.Name)
+			if err != nil || now.Sub(trashed) < retention {
+				continue
+			}
+
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+
+			case expired <- o:
+			}
+		}
+
+		return
+	})
+
+	// Delete those objects.
+	group.Go(func() (err error) {
+		for o := range expired {
+			err = bucket.DeleteObject(
+				ctx,
+				&gcs.DeleteObjectRequest{
+					Name:       o.Name,
+					Generation: o.Generation,
+				})
+
+			var notFoundErr *gcs.NotFoundError
+			if errors.As(err, &notFoundErr) {
+				err = nil
+				continue
+			}
+			if err != nil {
+				err = fmt.Errorf("DeleteObject(%q): %w", o.Name, err)
+				return
+			}
+
+			atomic.AddUint64(&objectsDeleted, 1)
+		}
+
+		return
+	})
+
+	err = group.Wait()
+	return
+}
+
+// Periodically delete the objects kept in the trash under the supplied prefix
+// for longer than the retention period, until the context is cancelled.
+func purgeTrash(
+	ctx context.Context,
+	prefix string,
+	retention time.Duration,
+	bucket gcs.Bucket) {
+	const period = 10 * time.Minute
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+		}
+
+		startTime := time.Now()
+		objectsDeleted, err := purgeTrashOnce(ctx, prefix, retention, startTime, bucket)
+
+		if err != nil {
+			logger.Infof(
+				"Trash purge failed after deleting %d objects in %v, with error: %v",
+				objectsDeleted,
+				time.Since(startTime),
+				err)
+		} else if objectsDeleted > 0 {
+			logger.Infof(
+				"Trash purge deleted %d objects in %v.",
+				objectsDeleted,
+				time.Since(startTime))
+		}
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcsx
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/fake"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"github.com/jacobsa/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+func listNames(t *testing.T, bucket gcs.Bucket) []string {
+	t.Helper()
+	objects, _, err := storageutil.ListAll(context.Background(), bucket, &gcs.ListObjectsRequest{})
+	require.NoError(t, err)
+	var result []string
+	for _, o := range objects {
+		result = append(result, o.Name)
+	}
+	return result
+}
+
+func TestTrashPrefix(t *testing.T) {
+	for _, prefix := range []string{".trash", ".trash/", "/.trash//"} {
+		assert.Equal(t, ".trash/", TrashPrefix(prefix), prefix)
+	}
+}
+
+func TestTrashMovesObjects(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		bucketType    gcs.BucketType
+		useMoveObject bool
+	}{
+		{name: "copy_and_delete"},
+		{name: "move", useMoveObject: true},
+		{name: "hierarchical", bucketType: gcs.BucketType{Hierarchical: true}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			clock := &timeutil.SimulatedClock{}
+			clock.SetTime(t0.Add(4*time.Minute + 5*time.Second))
+			bucket := fake.NewFakeBucket(clock, "some-bucket", tc.bucketType)
+			_, err := storageutil.CreateObject(context.Background(), bucket, "dir/foo", []byte("taco"))
+			require.NoError(t, err)
+			trash := NewTrash(".trash", tc.useMoveObject, clock)
+
+			name, err := trash.MoveObject(context.Background(), bucket, "dir/foo")
+
+			require.NoError(t, err)
+			assert.Equal(t, ".trash/20260102T150405Z/dir/foo", name)
+			assert.True(t, trash.Contains(name))
+			assert.False(t, trash.Contains("dir/foo"))
+			contents, err := storageutil.ReadObject(context.Background(), bucket, name)
+			require.NoError(t, err)
+			assert.Equal(t, "taco", string(contents))
+			_, _, err = bucket.StatObject(context.Background(), &gcs.StatObjectRequest{Name: "dir/foo"})
+			var notFoundErr *gcs.NotFoundError
+			assert.ErrorAs(t, err, &notFoundErr)
+		})
+	}
+}
+
+func TestTrashKeepsObjectsMovedWithinTheSameSecondApart(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		useMoveObject bool
+	}{
+		{name: "copy_and_delete"},
+		{name: "move", useMoveObject: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			clock := &timeutil.SimulatedClock{}
+			clock.SetTime(t0.Add(4*time.Minute + 5*time.Second))
+			bucket := fake.NewFakeBucket(clock, "some-bucket", gcs.BucketType{})
+			trash := NewTrash(".trash", tc.useMoveObject, clock)
+			_, err := storageutil.CreateObject(context.Background(), bucket, "foo", []byte("taco"))
+			require.NoError(t, err)
+			first, err := trash.MoveObject(context.Background(), bucket, "foo")
+			require.NoError(t, err)
+			o, err := storageutil.CreateObject(context.Background(), bucket, "foo", []byte("burrito"))
+			require.NoError(t, err)
+
+			second, err := trash.MoveObject(context.Background(), bucket, "foo")
+
+			require.NoError(t, err)
+			assert.Equal(t, ".trash/20260102T150405Z/foo", first)
+			assert.Equal(t, fmt.Sprintf(".trash/20260102T150405Z-%d/foo", o.Generation), second)
+			contents, err := storageutil.ReadObject(context.Background(), bucket, first)
+			require.NoError(t, err)
+			assert.Equal(t, "taco", string(contents))
+			contents, err = storageutil.ReadObject(context.Background(), bucket, second)
+			require.NoError(t, err)
+			assert.Equal(t, "burrito", string(contents))
+			stamp, err := trashTime(".trash/", second)
+			require.NoError(t, err)
+			assert.Equal(t, clock.Now().UTC(), stamp)
+		})
+	}
+}
+
+func TestTrashMoveObjectFailsForMissingObjects(t *testing.T) {
+	bucket := fake.NewFakeBucket(timeutil.RealClock(), "some-bucket", gcs.BucketType{})
+	trash := NewTrash(".trash/", false, timeutil.RealClock())
+
+	_, err := trash.MoveObject(context.Background(), bucket, "foo")
+
+	var notFoundErr *gcs.NotFoundError
+	assert.ErrorAs(t, err, &notFoundErr)
+}
+
+func TestPurgeTrashOnceDeletesExpiredObjects(t *testing.T) {
+	bucket := fake.NewFakeBucket(timeutil.RealClock(), "some-bucket", gcs.BucketType{})
+	for _, name := range []string{
+		".trash/20260101T150000Z/a",
+		".trash/20260101T150000Z/dir/b",
+		".trash/20260102T140000Z/c",
+		".trash/foo",
+		".trash/not-a-time/d",
+		"e",
+	} {
+		_, err := storageutil.CreateObject(context.Background(), bucket, name, []byte("taco"))
+		require.NoError(t, err)
+	}
+
+	deleted, err := purgeTrashOnce(context.Background(), ".trash/", 12*time.Hour, t0, bucket)
+
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), deleted)
+	assert.Equal(t, []string{".trash/20260102T140000Z/c", ".trash/foo", ".trash/not-a-time/d", "e"}, listNames(t, bucket))
+}
@@ -49,6 +49,11 @@ var (
 	defaultLogger        *slog.Logger
 	mountUUID            string
 	setupMountUUIDOnce   sync.Once
+
+	// levelVars are the levels of all the loggers created so far, which
+	// SetLogSeverity changes.
+	levelVarsMu sync.Mutex
+	levelVars   []*slog.LevelVar
 )
 
 // InitLogFile initializes the logger factory to create loggers that print to
@@ -226,10 +231,8 @@ type loggerFactory struct {
 
 func (f *loggerFactory) newLogger(level string) *slog.Logger {
 	// create a new logger
-	var programLevel = new(slog.LevelVar)
-	logger := slog.New(f.handler(programLevel, ""))
+	logger := slog.New(f.handler(newLevelVar(level), ""))
 	slog.SetDefault(logger)
-	setLoggingLevel(level, programLevel)
 	return logger
 }
 
@@ -239,13 +242,34 @@ func loggerAttr(fsName string) []slog.Attr {
 
 // create a new logger with mountInstanceID set as custom attribute on logger.
 func (f *loggerFactory) newLoggerWithMountInstanceID(level, fsName string) *slog.Logger {
-	var programLevel = new(slog.LevelVar)
-	logger := slog.New(f.handler(programLevel, "").WithAttrs(loggerAttr(fsName)))
+	logger := slog.New(f.handler(newLevelVar(level), "").WithAttrs(loggerAttr(fsName)))
 	slog.SetDefault(logger)
-	setLoggingLevel(level, programLevel)
 	return logger
 }
 
+// newLevelVar returns a level set to the supplied severity, which follows the
+// changes made by SetLogSeverity.
+func newLevelVar(level string) *slog.LevelVar {
+	programLevel := new(slog.LevelVar)
+	setLoggingLevel(level, programLevel)
+
+	levelVarsMu.Lock()
+	defer levelVarsMu.Unlock()
+	levelVars = append(levelVars, programLevel)
+	return programLevel
+}
+
+// SetLogSeverity changes the severity of the logs written by all the loggers
+// created so far, and of those created afterwards.
+func SetLogSeverity(severity cfg.LogSeverity) {
+	levelVarsMu.Lock()
+	defer levelVarsMu.Unlock()
+	defaultLoggerFactory.level = string(severity)
+	for _, programLevel := range levelVars {
+		setLoggingLevel(string(severity), programLevel)
+	}
+}
+
 func (f *loggerFactory) createJsonOrTextHandler(writer io.Writer, levelVar *slog.LevelVar, prefix string) slog.Handler {
 	if f.format == textFormat {
 		return slog.NewTextHandler(writer, getHandlerOptions(levelVar, prefix, f.format))
@@ -485,3 +485,18 @@ func TestSetupMountUUID_Success(t *testing.T) {
 		})
 	}
 }
+
+func TestSetLogSeverityChangesLevelOfExistingLoggers(t *testing.T) {
+	var buf bytes.Buffer
+	defaultLoggerFactory.format = textFormat
+	defaultLogger = slog.New(defaultLoggerFactory.createJsonOrTextHandler(&buf, newLevelVar(cfg.INFO), ""))
+	t.Cleanup(func() { SetLogSeverity(cfg.InfoLogSeverity) })
+	Debugf("www.hiddenExample.com")
+	require.Empty(t, buf.String())
+
+	SetLogSeverity(cfg.DebugLogSeverity)
+
+	Debugf("www.debugExample.com")
+	assert.Contains(t, buf.String(), "severity=DEBUG message=www.debugExample.com")
+	assert.Equal(t, cfg.DEBUG, defaultLoggerFactory.level)
+}
@@ -15,11 +15,16 @@
 package perf
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"runtime"
 	"runtime/pprof"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
@@ -79,3 +84,260 @@ func HandleMemoryProfileSignals() {
 		}
 	}
 }
+
+////////////////////////////////////////////////////////////////////////
+// Memory pressure
+////////////////////////////////////////////////////////////////////////
+
+// MemoryPressure is how close the cgroup of the process is to running out of
+// memory.
+type MemoryPressure int
+
+const (
+	MemoryPressureNone MemoryPressure = iota
+	MemoryPressureModerate
+	MemoryPressureCritical
+)
+
+func (p MemoryPressure) String() string {
+	switch p {
+	case MemoryPressureNone:
+		return "none"
+	case MemoryPressureModerate:
+		return "moderate"
+	case MemoryPressureCritical:
+		return "critical"
+	default:
+		return fmt.Sprintf("MemoryPressure(%d)", int(p))
+	}
+}
+
+const (
+	// The number of percentage points the memory usage must fall below the
+	// threshold of a pressure level to leave it, so as not to flap around it.
+	memoryPressureHysteresisPercent = 5
+
+	// The avg10 values of memory.pressure above which the pressure is at least
+	// moderate or critical: the percentages of the last 10 seconds during which
+	// some or all tasks of the cgroup were stalled waiting for memory.
+	psiSomeModerateAvg10 = 20
+	psiFullCriticalAvg10 = 10
+)
+
+// CgroupMemory is a sample of the memory accounting of a cgroup v2.
+type CgroupMemory struct {
+	// The working set, i.e. memory.current less the inactive page cache
+	// (inactive_file in memory.stat), which the kernel reclaims before running
+	// out of memory, and memory.max or 0 if it is unlimited.
+	Current uint64
+	Max     uint64
+
+	// The some and full avg10 values of memory.pressure, or 0 if pressure
+	// stall information is unavailable.
+	SomeAvg10 float64
+	FullAvg10 float64
+}
+
+// MemoryPressureThresholds are the memory usages, as percentages of the
+// memory limit of the cgroup, at which the pressure becomes moderate and
+// critical.
+type MemoryPressureThresholds struct {
+	ModeratePercent int64
+	CriticalPercent int64
+}
+
+// Classify returns the pressure level of the supplied sample, given the
+// previous level.
+func (t MemoryPressureThresholds) Classify(m CgroupMemory, prev MemoryPressure) MemoryPressure {
+	level := MemoryPressureNone
+	if m.Max > 0 {
+		percent := float64(m.Current) * 100 / float64(m.Max)
+		moderate, critical := float64(t.ModeratePercent), float64(t.CriticalPercent)
+		if prev >= MemoryPressureModerate {
+			moderate -= memoryPressureHysteresisPercent
+		}
+		if prev >= MemoryPressureCritical {
+			critical -= memoryPressureHysteresisPercent
+		}
+		switch {
+		case percent >= critical:
+			level = MemoryPressureCritical
+		case percent >= moderate:
+			level = MemoryPressureModerate
+		}
+	}
+
+	if m.FullAvg10 >= psiFullCriticalAvg10 {
+		level = MemoryPressureCritical
+	} else if m.SomeAvg10 >= psiSomeModerateAvg10 {
+		level = max(level, MemoryPressureModerate)
+	}
+	return level
+}
+
+// CgroupDir returns the directory of the cgroup v2 of the process, given the
+// paths of /proc/self/cgroup and /proc/self/mountinfo.
+func CgroupDir(cgroupFile, mountInfoFile string) (string, error) {
+	cgroups, err := os.ReadFile(cgroupFile)
+	if err != nil {
+		return "", err
+	}
+	var cgroup string
+	found := false
+	for _, line := range strings.Split(string(cgroups), "\n") {
+		if p, ok := strings.CutPrefix(line, "0::"); ok {
+			cgroup, found = p, true
+			break
+		}
+	}
+	if !found {
+		return "", fmt.Errorf("no cgroup v2 in %s", cgroupFile)
+	}
+
+	mounts, err := os.ReadFile(mountInfoFile)
+	if err != nil {
+		return "", err
+	}
+	for _, line := range strings.Split(string(mounts), "\n") {
+		// The fields are described in proc(5): the root of the mount within the
+		// file system is the fourth, the mount point is the fifth, and the file
+		// system type follows the " - " separator.
+		fields, rest, ok := strings.Cut(line, " - ")
+		if !ok || !strings.HasPrefix(rest, "cgroup2 ") {
+			continue
+		}
+		f := strings.Fields(fields)
+		if len(f) < 5 {
+			continue
+		}
+		// Outside a cgroup namespace, e.g. with the host's cgroup file system
+		// bind-mounted from the cgroup of a container, the mount only exposes
+		// the cgroups under its root.
+		rel, ok := cutPathPrefix(cgroup, f[3])
+		if !ok {
+			continue
+		}
+		return filepath.Join(f[4], rel), nil
+	}
+	return "", fmt.Errorf("no cgroup2 file system exposing %s in %s", cgroup, mountInfoFile)
+}
+
+// cutPathPrefix returns p relative to the directory dir, if it is within it.
+func cutPathPrefix(p, dir string) (string, bool) {
+	if dir == "/" {
+		return p, true
+	}
+	if p == dir {
+		return "/", true
+	}
+	return strings.CutPrefix(p, dir+"/")
+}
+
+// ReadCgroupMemory samples the memory accounting files of the cgroup v2 in
+// the supplied directory. memory.stat and memory.pressure are optional, but
+// failing to read them when they exist is an error.
+func ReadCgroupMemory(dir string) (CgroupMemory, error) {
+	var m CgroupMemory
+	current, err := os.ReadFile(filepath.Join(dir, "memory.current"))
+	if err != nil {
+		return m, err
+	}
+	if m.Current, err = strconv.ParseUint(strings.TrimSpace(string(current)), 10, 64); err != nil {
+		return m, fmt.Errorf("parsing memory.current: %w", err)
+	}
+
+	stat, err := os.ReadFile(filepath.Join(dir, "memory.stat"))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return m, err
+	}
+	// Lines like "inactive_file 12345".
+	for _, line := range strings.Split(string(stat), "\n") {
+		v, ok := strings.CutPrefix(line, "inactive_file ")
+		if !ok {
+			continue
+		}
+		inactive, err := strconv.ParseUint(strings.TrimSpace(v), 10, 64)
+		if err != nil {
+			return m, fmt.Errorf("parsing memory.stat: %w", err)
+		}
+		m.Current -= min(inactive, m.Current)
+		break
+	}
+
+	limit, err := os.ReadFile(filepath.Join(dir, "memory.max"))
+	if err != nil {
+		return m, err
+	}
+	if s := strings.TrimSpace(string(limit)); s != "max" {
+		if m.Max, err = strconv.ParseUint(s, 10, 64); err != nil {
+			return m, fmt.Errorf("parsing memory.max: %w", err)
+		}
+	}
+
+	pressure, err := os.ReadFile(filepath.Join(dir, "memory.pressure"))
+	if errors.Is(err, os.ErrNotExist) {
+		return m, nil
+	}
+	if err != nil {
+		return m, err
+	}
+	// Lines like "some avg10=1.23 avg60=0.50 avg300=0.10 total=12345".
+	for _, line := range strings.Split(string(pressure), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		avg10, ok := strings.CutPrefix(fields[1], "avg10=")
+		if !ok {
+			continue
+		}
+		v, err := strconv.ParseFloat(avg10, 64)
+		if err != nil {
+			return m, fmt.Errorf("parsing memory.pressure: %w", err)
+		}
+		switch fields[0] {
+		case "some":
+			m.SomeAvg10 = v
+		case "full":
+			m.FullAvg10 = v
+		}
+	}
+	return m, nil
+}
+
+// WatchMemoryPressure samples the memory accounting of the cgroup v2 in the
+// supplied directory at the supplied interval until the context is done,
+// calling onChange with the new pressure level whenever it changes. The level
+// starts as none. Sampling errors are treated as no pressure, and logged when
+// sampling starts failing.
+func WatchMemoryPressure(ctx context.Context, dir string, interval time.Duration, thresholds MemoryPressureThresholds, onChange func(MemoryPressure)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	level := MemoryPressureNone
+	loggedErr := false
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		newLevel := MemoryPressureNone
+		if m, err := ReadCgroupMemory(dir); err != nil {
+			if !loggedErr {
+				logger.Warnf("Sampling the memory usage of cgroup %s: %v", dir, err)
+				loggedErr = true
+			}
+		} else {
+			newLevel = thresholds.Classify(m, level)
+			loggedErr = false
+		}
+
+		if newLevel != level {
+			logger.Infof("Memory pressure changed from %v to %v", level, newLevel)
+			level = newLevel
+			onChange(level)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package perf
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, contents := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(contents), 0600))
+	}
+}
+
+func TestCgroupDir(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"cgroup": "4:memory:/v1/path\n0::/kubepods/pod1/container1\n",
+		"mountinfo": "22 1 0:20 / /proc rw,nosuid - proc proc rw\n" +
+			"30 25 0:26 / /sys/fs/cgroup rw,nosuid shared:4 - cgroup2 cgroup2 rw,nsdelegate\n",
+	})
+
+	cgroupDir, err := CgroupDir(filepath.Join(dir, "cgroup"), filepath.Join(dir, "mountinfo"))
+
+	require.NoError(t, err)
+	assert.Equal(t, "/sys/fs/cgroup/kubepods/pod1/container1", cgroupDir)
+}
+
+func TestCgroupDirStripsMountRoot(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"cgroup": "0::/kubepods/pod1/container1\n",
+		"mountinfo": "30 25 0:26 /kubepods/pod2 /sys/fs/cgroup rw - cgroup2 cgroup2 rw\n" +
+			"31 25 0:26 /kubepods/pod1 /sys/fs/cgroup rw - cgroup2 cgroup2 rw\n",
+	})
+
+	cgroupDir, err := CgroupDir(filepath.Join(dir, "cgroup"), filepath.Join(dir, "mountinfo"))
+
+	require.NoError(t, err)
+	assert.Equal(t, "/sys/fs/cgroup/container1", cgroupDir)
+}
+
+func TestCgroupDirWithoutCgroupV2(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"cgroup":    "4:memory:/v1/path\n",
+		"mountinfo": "22 1 0:20 / /proc rw,nosuid - proc proc rw\n",
+	})
+
+	_, err := CgroupDir(filepath.Join(dir, "cgroup"), filepath.Join(dir, "mountinfo"))
+
+	assert.Error(t, err)
+}
+
+func TestReadCgroupMemory(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"memory.current":  "800\n",
+		"memory.max":      "1000\n",
+		"memory.pressure": "some avg10=12.50 avg60=3.00 avg300=1.00 total=100\nfull avg10=2.25 avg60=1.00 avg300=0.00 total=10\n",
+	})
+
+	m, err := ReadCgroupMemory(dir)
+
+	require.NoError(t, err)
+	assert.Equal(t, CgroupMemory{Current: 800, Max: 1000, SomeAvg10: 12.5, FullAvg10: 2.25}, m)
+}
+
+func TestReadCgroupMemoryUnlimitedWithoutPressure(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"memory.current": "800\n", "memory.max": "max\n"})
+
+	m, err := ReadCgroupMemory(dir)
+
+	require.NoError(t, err)
+	assert.Equal(t, CgroupMemory{Current: 800}, m)
+}
+
+func TestReadCgroupMemoryExcludesInactivePageCache(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"memory.current": "800\n",
+		"memory.max":     "1000\n",
+		"memory.stat":    "anon 500\nfile 300\nactive_file 100\ninactive_file 200\n",
+	})
+
+	m, err := ReadCgroupMemory(dir)
+
+	require.NoError(t, err)
+	assert.Equal(t, CgroupMemory{Current: 600, Max: 1000}, m)
+}
+
+func TestReadCgroupMemoryFailsOnUnreadablePressure(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"memory.current": "800\n", "memory.max": "max\n"})
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "memory.pressure"), 0700))
+
+	_, err := ReadCgroupMemory(dir)
+
+	assert.Error(t, err)
+}
+
+func TestClassifyMemoryPressure(t *testing.T) {
+	thresholds := MemoryPressureThresholds{ModeratePercent: 80, CriticalPercent: 90}
+	testCases := []struct {
+		name     string
+		memory   CgroupMemory
+		prev     MemoryPressure
+		expected MemoryPressure
+	}{
+		{name: "low", memory: CgroupMemory{Current: 500, Max: 1000}, expected: MemoryPressureNone},
+		{name: "moderate", memory: CgroupMemory{Current: 800, Max: 1000}, expected: MemoryPressureModerate},
+		{name: "critical", memory: CgroupMemory{Current: 950, Max: 1000}, expected: MemoryPressureCritical},
+		{name: "unlimited", memory: CgroupMemory{Current: 950}, expected: MemoryPressureNone},
+		{name: "hysteresis", memory: CgroupMemory{Current: 870, Max: 1000}, prev: MemoryPressureCritical, expected: MemoryPressureCritical},
+		{name: "hysteresis_left", memory: CgroupMemory{Current: 840, Max: 1000}, prev: MemoryPressureCritical, expected: MemoryPressureModerate},
+		{name: "some_stalls", memory: CgroupMemory{Current: 500, Max: 1000, SomeAvg10: 30}, expected: MemoryPressureModerate},
+		{name: "full_stalls", memory: CgroupMemory{Current: 500, SomeAvg10: 30, FullAvg10: 15}, expected: MemoryPressureCritical},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, thresholds.Classify(tc.memory, tc.prev))
+		})
+	}
+}
+
+func TestWatchMemoryPressure(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"memory.current": "500\n", "memory.max": "1000\n"})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes := make(chan MemoryPressure, 10)
+	go WatchMemoryPressure(ctx, dir, time.Millisecond, MemoryPressureThresholds{ModeratePercent: 80, CriticalPercent: 90}, func(p MemoryPressure) { changes <- p })
+
+	writeFiles(t, dir, map[string]string{"memory.current": "950\n"})
+	assert.Equal(t, MemoryPressureCritical, <-changes)
+	writeFiles(t, dir, map[string]string{"memory.current": "100\n"})
+	assert.Equal(t, MemoryPressureNone, <-changes)
+}
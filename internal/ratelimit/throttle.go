@@ -15,6 +15,9 @@
 package ratelimit
 
 import (
+	"sync"
+	"time"
+
 	"golang.org/x/net/context"
 	"golang.org/x/time/rate"
 )
@@ -34,25 +37,64 @@ type Throttle interface {
 	//
 	// REQUIRES: tokens <= capacity
 	Wait(ctx context.Context, tokens uint64) (err error)
+
+	// Change the rate at which tokens are added to the bucket and its capacity,
+	// and drop the tokens accumulated so far, which would otherwise be spent at
+	// the former rate. Callers already waiting are admitted at the former rate,
+	// and those that go on to wait for more tokens than the new capacity are
+	// admitted once all of them have been added.
+	SetRate(rateHz float64, capacity uint64)
 }
 
 type limiter struct {
-	*rate.Limiter
+	mu sync.Mutex
+
+	// GUARDED_BY(mu)
+	l *rate.Limiter
 }
 
 func NewThrottle(
 	rateHz float64,
 	capacity uint64) (t Throttle) {
-	t = &limiter{rate.NewLimiter(rate.Limit(rateHz), int(capacity))}
+	t = &limiter{l: rate.NewLimiter(rate.Limit(rateHz), int(capacity))}
 	return
 }
 
+// LOCKS_EXCLUDED(l.mu)
+func (l *limiter) current() *rate.Limiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.l
+}
+
 func (l *limiter) Capacity() (c uint64) {
-	return uint64(l.Burst())
+	return uint64(l.current().Burst())
+}
+
+func (l *limiter) SetRate(rateHz float64, capacity uint64) {
+	// A new limiter starts full, so empty it.
+	lim := rate.NewLimiter(rate.Limit(rateHz), int(capacity))
+	lim.AllowN(time.Now(), int(capacity))
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.l = lim
 }
 
 func (l *limiter) Wait(
 	ctx context.Context,
 	tokens uint64) (err error) {
-	return l.WaitN(ctx, int(tokens))
+	lim := l.current()
+	burst := uint64(lim.Burst())
+	if burst == 0 {
+		return lim.WaitN(ctx, int(tokens))
+	}
+	for tokens > 0 {
+		n := min(tokens, burst)
+		if err = lim.WaitN(ctx, int(n)); err != nil {
+			return
+		}
+		tokens -= n
+	}
+	return
 }
@@ -47,6 +47,9 @@ func (ft *funcThrottle) Capacity() (c uint64) {
 	return 1024
 }
 
+func (ft *funcThrottle) SetRate(rateHz float64, capacity uint64) {
+}
+
 func (ft *funcThrottle) Wait(
 	ctx context.Context,
 	tokens uint64) (err error) {
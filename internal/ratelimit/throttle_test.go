@@ -202,3 +202,39 @@ func (t *ThrottleTest) TestIntegration() {
 			fmt.Sprintf("Test case %d. expected: %f", i, expected))
 	}
 }
+
+func (t *ThrottleTest) TestSetRate() {
+	throttle := ratelimit.NewThrottle(1, 1)
+	ctx := context.Background()
+	// Use up the initial token.
+	t.Require().NoError(throttle.Wait(ctx, 1))
+
+	throttle.SetRate(1000, 10)
+
+	ctx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	assert.NoError(t.T(), throttle.Wait(ctx, 1))
+	assert.Equal(t.T(), uint64(10), throttle.Capacity())
+}
+
+func (t *ThrottleTest) TestSetRateDropsAccumulatedTokens() {
+	throttle := ratelimit.NewThrottle(1000, 1000)
+
+	throttle.SetRate(1, 1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	assert.Error(t.T(), throttle.Wait(ctx, 1))
+}
+
+func (t *ThrottleTest) TestWaitForMoreTokensThanCapacityAfterSetRate() {
+	throttle := ratelimit.NewThrottle(1, 100)
+
+	throttle.SetRate(1000, 10)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	start := time.Now()
+	assert.NoError(t.T(), throttle.Wait(ctx, 50))
+	assert.GreaterOrEqual(t.T(), time.Since(start), 40*time.Millisecond)
+}
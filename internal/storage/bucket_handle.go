@@ -20,7 +20,9 @@
 package storage
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"time"
@@ -31,6 +33,7 @@ import (
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
 	"google.golang.org/api/iterator"
+	storagev1 "google.golang.org/api/storage/v1"
 )
 
 const FullFolderPathHNS = "projects/_/buckets/%s/folders/%s"
@@ -40,9 +43,30 @@ type bucketHandle struct {
 	gcs.Bucket
 	bucket               *storage.BucketHandle
 	bucketName           string
+	billingProject       string
 	bucketType           *gcs.BucketType
 	controlClient        StorageControlClient
 	finalizeFileForRapid bool
+
+	// The customer-supplied encryption keys of objects, if any.
+	keys *storageutil.CustomerSuppliedKeys
+
+	// The objects of the JSON API itself, for the requests the storage client
+	// can't make, or nil if the bucket is accessed over gRPC.
+	rawObjects *storagev1.ObjectsService
+
+	// Creates the retryers of the requests to rawObjects.
+	rawRetryer func() gax.Retryer
+}
+
+// object returns a handle of the named object, with its customer-supplied
+// encryption key if it has one.
+func (bh *bucketHandle) object(name string) *storage.ObjectHandle {
+	obj := bh.bucket.Object(name)
+	if key := bh.keys.KeyFor(name); key != nil {
+		obj = obj.Key(key)
+	}
+	return obj
 }
 
 func (bh *bucketHandle) Name() string {
@@ -75,7 +99,7 @@ func (bh *bucketHandle) NewReaderWithReadHandle(
 		length = end - start
 	}
 
-	obj := bh.bucket.Object(req.Name)
+	obj := bh.object(req.Name)
 
 	// Switching to the requested generation of object.
 	if req.Generation != 0 {
@@ -107,7 +131,7 @@ func (bh *bucketHandle) DeleteObject(ctx context.Context, req *gcs.DeleteObjectR
 		err = gcs.GetGCSError(err)
 	}()
 
-	obj := bh.bucket.Object(req.Name)
+	obj := bh.object(req.Name)
 
 	// Switching to the requested generation of the object. By default, generation
 	// is 0 which signifies the latest generation. Note: GCS will delete the
@@ -136,7 +160,7 @@ func (bh *bucketHandle) StatObject(ctx context.Context,
 
 	var attrs *storage.ObjectAttrs
 	// Retrieving object attrs through Go Storage Client.
-	attrs, err = bh.bucket.Object(req.Name).Attrs(ctx)
+	attrs, err = bh.object(req.Name).Attrs(ctx)
 	if err != nil {
 		err = fmt.Errorf("error in fetching object attributes: %w", err)
 		return
@@ -153,7 +177,7 @@ func (bh *bucketHandle) StatObject(ctx context.Context,
 }
 
 func (bh *bucketHandle) getObjectHandleWithPreconditionsSet(req *gcs.CreateObjectRequest) *storage.ObjectHandle {
-	obj := bh.bucket.Object(req.Name)
+	obj := bh.object(req.Name)
 
 	// GenerationPrecondition - If non-nil, the object will be created/overwritten
 	// only if the current generation for the object name is equal to the given value.
@@ -308,8 +332,8 @@ func (bh *bucketHandle) CopyObject(ctx context.Context, req *gcs.CopyObjectReque
 		err = gcs.GetGCSError(err)
 	}()
 
-	srcObj := bh.bucket.Object(req.SrcName)
-	dstObj := bh.bucket.Object(req.DstName)
+	srcObj := bh.object(req.SrcName)
+	dstObj := bh.object(req.DstName)
 
 	// Switching to the requested generation of source object.
 	if req.SrcGeneration != 0 {
@@ -321,6 +345,16 @@ func (bh *bucketHandle) CopyObject(ctx context.Context, req *gcs.CopyObjectReque
 		srcObj = srcObj.If(storage.Conditions{MetagenerationMatch: *req.SrcMetaGenerationPrecondition})
 	}
 
+	// Putting a condition on the current generation of the destination, where
+	// zero means the destination must not exist.
+	if req.DstGenerationPrecondition != nil {
+		if *req.DstGenerationPrecondition == 0 {
+			dstObj = dstObj.If(storage.Conditions{DoesNotExist: true})
+		} else {
+			dstObj = dstObj.If(storage.Conditions{GenerationMatch: *req.DstGenerationPrecondition})
+		}
+	}
+
 	objAttrs, err := dstObj.CopierFrom(srcObj).Run(ctx)
 
 	if err != nil {
@@ -361,6 +395,7 @@ func (bh *bucketHandle) ListObjects(ctx context.Context, req *gcs.ListObjectsReq
 		Projection:               getProjectionValue(req.ProjectionVal),
 		IncludeTrailingDelimiter: req.IncludeTrailingDelimiter,
 		IncludeFoldersAsPrefixes: req.IncludeFoldersAsPrefixes,
+		Versions:                 req.Versions,
 		//MaxResults: , (Field not present in storage.Query of Go Storage Library but present in ListObjectsQuery in Jacobsa code.)
 	}
 	minObjAttrs := []string{"Name", "Size", "Generation", "Metageneration", "Updated", "Metadata", "ContentEncoding", "CRC32C"}
@@ -369,6 +404,9 @@ func (bh *bucketHandle) ListObjects(ctx context.Context, req *gcs.ListObjectsReq
 		// For objects in regional buckets, this field will be *unset*.
 		minObjAttrs = append(minObjAttrs, "Finalized")
 	}
+	if req.Versions {
+		minObjAttrs = append(minObjAttrs, "Created", "Deleted")
+	}
 	err = query.SetAttrSelection(minObjAttrs)
 
 	if err != nil {
@@ -405,6 +443,9 @@ func (bh *bucketHandle) ListObjects(ctx context.Context, req *gcs.ListObjectsReq
 			// Converting attrs to *Object type.
 			currMinObject := storageutil.ObjectAttrsToMinObject(attrs)
 			list.MinObjects = append(list.MinObjects, currMinObject)
+			if req.Versions {
+				list.Lifetimes = append(list.Lifetimes, gcs.ObjectLifetime{Created: attrs.Created, Deleted: attrs.Deleted})
+			}
 		}
 
 		// itr.next returns all the objects present in the bucket. Hence adding a
@@ -429,7 +470,7 @@ func (bh *bucketHandle) UpdateObject(ctx context.Context, req *gcs.UpdateObjectR
 		err = gcs.GetGCSError(err)
 	}()
 
-	obj := bh.bucket.Object(req.Name)
+	obj := bh.object(req.Name)
 
 	if req.Generation != 0 {
 		obj = obj.Generation(req.Generation)
@@ -457,17 +498,27 @@ func (bh *bucketHandle) UpdateObject(ctx context.Context, req *gcs.UpdateObjectR
 		updateQuery.CacheControl = *req.CacheControl
 	}
 
+	var deletingKeys bool
 	if req.Metadata != nil {
 		updateQuery.Metadata = make(map[string]string)
 		for key, element := range req.Metadata {
 			if element != nil {
 				updateQuery.Metadata[key] = *element
+			} else {
+				deletingKeys = true
 			}
 		}
 	}
 
-	attrs, err := obj.Update(ctx, updateQuery)
+	if deletingKeys {
+		o, err = bh.patchObjectDeletingMetadataKeys(ctx, req)
+		if err != nil {
+			err = fmt.Errorf("error in updating object: %w", err)
+		}
+		return
+	}
 
+	attrs, err := obj.Update(ctx, updateQuery)
 	if err != nil {
 		err = fmt.Errorf("error in updating object: %w", err)
 		return
@@ -478,12 +529,68 @@ func (bh *bucketHandle) UpdateObject(ctx context.Context, req *gcs.UpdateObjectR
 	return
 }
 
+// patchObjectDeletingMetadataKeys applies the supplied update, which removes
+// some custom metadata keys, in a single patch of the JSON API setting them to
+// null, which the storage client can't send: it merges metadata on update and
+// can only remove all of it at once. The patch is retried like the requests of
+// the storage client: it sets absolute values, so it is idempotent, and more so
+// with a metageneration precondition.
+//
+// Removing keys isn't supported over gRPC.
+func (bh *bucketHandle) patchObjectDeletingMetadataKeys(ctx context.Context, req *gcs.UpdateObjectRequest) (*gcs.Object, error) {
+	if bh.rawObjects == nil {
+		return nil, fmt.Errorf("removing custom metadata keys over gRPC: %w", errors.ErrUnsupported)
+	}
+
+	patch := &storagev1.Object{Metadata: make(map[string]string)}
+	for key, element := range req.Metadata {
+		if element != nil {
+			patch.Metadata[key] = *element
+		} else {
+			patch.NullFields = append(patch.NullFields, "Metadata."+key)
+		}
+	}
+	setOrNull := func(field string, value *string, dst *string) {
+		switch {
+		case value == nil:
+		case *value == "":
+			patch.NullFields = append(patch.NullFields, field)
+		default:
+			*dst = *value
+		}
+	}
+	setOrNull("ContentType", req.ContentType, &patch.ContentType)
+	setOrNull("ContentEncoding", req.ContentEncoding, &patch.ContentEncoding)
+	setOrNull("ContentLanguage", req.ContentLanguage, &patch.ContentLanguage)
+	setOrNull("CacheControl", req.CacheControl, &patch.CacheControl)
+
+	call := bh.rawObjects.Patch(bh.bucketName, req.Name, patch).Context(ctx)
+	if req.Generation != 0 {
+		call = call.Generation(req.Generation)
+	}
+	if req.MetaGenerationPrecondition != nil {
+		call = call.IfMetagenerationMatch(*req.MetaGenerationPrecondition)
+	}
+	if bh.billingProject != "" {
+		call = call.UserProject(bh.billingProject)
+	}
+	var o *storagev1.Object
+	err := gax.Invoke(ctx, func(context.Context, gax.CallSettings) (err error) {
+		o, err = call.Do()
+		return
+	}, gax.WithRetry(bh.rawRetryer))
+	if err != nil {
+		return nil, err
+	}
+	return storageutil.RawObjectToBucketObject(o)
+}
+
 func (bh *bucketHandle) ComposeObjects(ctx context.Context, req *gcs.ComposeObjectsRequest) (o *gcs.Object, err error) {
 	defer func() {
 		err = gcs.GetGCSError(err)
 	}()
 
-	dstObj := bh.bucket.Object(req.DstName)
+	dstObj := bh.object(req.DstName)
 
 	dstObjConds := storage.Conditions{}
 	if req.DstMetaGenerationPrecondition != nil {
@@ -509,6 +616,8 @@ func (bh *bucketHandle) ComposeObjects(ctx context.Context, req *gcs.ComposeObje
 	// Converting the req.Sources list to a list of storage.ObjectHandle as expected by the Go Storage Client.
 	var srcObjList []*storage.ObjectHandle
 	for _, src := range req.Sources {
+		// Sources are decrypted with the key of the destination, so they must
+		// have been encrypted with it.
 		currSrcObj := bh.bucket.Object(src.Name)
 		// Switching to requested Generation of the object.
 		// Zero src generation is the latest generation, we are skipping it because by default it will take the latest one
@@ -544,11 +653,16 @@ func (bh *bucketHandle) DeleteFolder(ctx context.Context, folderName string) (er
 }
 
 func (bh *bucketHandle) MoveObject(ctx context.Context, req *gcs.MoveObjectRequest) (o *gcs.Object, err error) {
+	// Moves can't re-encrypt objects.
+	if !bytes.Equal(bh.keys.KeyFor(req.SrcName), bh.keys.KeyFor(req.DstName)) {
+		return bh.moveObjectByCopying(ctx, req)
+	}
+
 	defer func() {
 		err = gcs.GetGCSError(err)
 	}()
 
-	obj := bh.bucket.Object(req.SrcName)
+	obj := bh.object(req.SrcName)
 
 	// Switching to the requested generation of source object.
 	if req.SrcGeneration != 0 {
@@ -565,6 +679,16 @@ func (bh *bucketHandle) MoveObject(ctx context.Context, req *gcs.MoveObjectReque
 		Conditions: nil,
 	}
 
+	// Putting a condition on the current generation of the destination, where
+	// zero means the destination must not exist.
+	if req.DstGenerationPrecondition != nil {
+		if *req.DstGenerationPrecondition == 0 {
+			dstMoveObject.Conditions = &storage.Conditions{DoesNotExist: true}
+		} else {
+			dstMoveObject.Conditions = &storage.Conditions{GenerationMatch: *req.DstGenerationPrecondition}
+		}
+	}
+
 	attrs, err := obj.Move(ctx, dstMoveObject)
 	if err != nil {
 		err = fmt.Errorf("error in moving object: %w", err)
@@ -576,6 +700,43 @@ func (bh *bucketHandle) MoveObject(ctx context.Context, req *gcs.MoveObjectReque
 	return
 }
 
+// moveObjectByCopying moves an object by copying it, which re-encrypts it with
+// the key of its new name, and deleting the source. Unlike a move, this isn't
+// atomic: if the deletion fails, both objects are left.
+func (bh *bucketHandle) moveObjectByCopying(ctx context.Context, req *gcs.MoveObjectRequest) (*gcs.Object, error) {
+	// Pin the generation of the source, so that the one deleted is the one
+	// copied.
+	srcGeneration := req.SrcGeneration
+	if srcGeneration == 0 {
+		m, _, err := bh.StatObject(ctx, &gcs.StatObjectRequest{Name: req.SrcName, ForceFetchFromGcs: true})
+		if err != nil {
+			return nil, err
+		}
+		srcGeneration = m.Generation
+	}
+
+	o, err := bh.CopyObject(ctx, &gcs.CopyObjectRequest{
+		SrcName:                       req.SrcName,
+		DstName:                       req.DstName,
+		SrcGeneration:                 srcGeneration,
+		SrcMetaGenerationPrecondition: req.SrcMetaGenerationPrecondition,
+		DstGenerationPrecondition:     req.DstGenerationPrecondition,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = bh.DeleteObject(ctx, &gcs.DeleteObjectRequest{
+		Name:                       req.SrcName,
+		Generation:                 srcGeneration,
+		MetaGenerationPrecondition: req.SrcMetaGenerationPrecondition,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("deleting %q after copying it to %q: %w", req.SrcName, req.DstName, err)
+	}
+	return o, nil
+}
+
 func (bh *bucketHandle) RenameFolder(ctx context.Context, folderName string, destinationFolderId string) (folder *gcs.Folder, err error) {
 	defer func() {
 		err = gcs.GetGCSError(err)
@@ -651,7 +812,7 @@ func (bh *bucketHandle) NewMultiRangeDownloader(
 		err = gcs.GetGCSError(err)
 	}()
 
-	obj := bh.bucket.Object(req.Name)
+	obj := bh.object(req.Name)
 
 	// Switching to the requested generation of object.
 	if req.Generation != 0 {
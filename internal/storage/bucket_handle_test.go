@@ -15,9 +15,15 @@
 package storage
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
 	"reflect"
 	"strings"
 	"testing"
@@ -28,10 +34,14 @@ import (
 	"cloud.google.com/go/storage/control/apiv2/controlpb"
 	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"github.com/googlecloudplatform/gcsfuse/v3/metrics"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+	"google.golang.org/api/option"
+	storagev1 "google.golang.org/api/storage/v1"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -716,6 +726,20 @@ func (testSuite *BucketHandleTest) TestListObjectMethodWithPrefixObjectExist() {
 	assert.Equal(testSuite.T(), []string{TestObjectSubRootFolderName}, obj.CollapsedRuns)
 }
 
+func (testSuite *BucketHandleTest) TestListObjectMethodWithVersions() {
+	obj, err := testSuite.bucketHandle.ListObjects(context.Background(),
+		&gcs.ListObjectsRequest{
+			Prefix:   TestObjectName,
+			Versions: true,
+		})
+
+	assert.Nil(testSuite.T(), err)
+	assert.Equal(testSuite.T(), []string{TestObjectName}, minObjectsToMinObjectNames(obj.MinObjects))
+	assert.Equal(testSuite.T(), len(obj.MinObjects), len(obj.Lifetimes))
+	assert.False(testSuite.T(), obj.Lifetimes[0].Created.IsZero())
+	assert.True(testSuite.T(), obj.Lifetimes[0].Deleted.IsZero())
+}
+
 func (testSuite *BucketHandleTest) TestListObjectMethodWithPrefixObjectDoesNotExist() {
 	obj, err := testSuite.bucketHandle.ListObjects(context.Background(),
 		&gcs.ListObjectsRequest{
@@ -868,6 +892,7 @@ func (testSuite *BucketHandleTest) TestListObjectMethodWithZeroMaxResult() {
 // Hence, we are not writing tests for these parameters
 // https://github.com/GoogleCloudPlatform/gcsfuse/blob/master/vendor/github.com/fsouza/fake-gcs-server/fakestorage/object.go#L795
 func (testSuite *BucketHandleTest) TestUpdateObjectMethodWithValidObject() {
+	createBucketHandle(testSuite, &controlpb.StorageLayout{})
 	// Metadata value before updating object
 	minObj, _, err := testSuite.bucketHandle.StatObject(context.Background(),
 		&gcs.StatObjectRequest{
@@ -903,6 +928,44 @@ func (testSuite *BucketHandleTest) TestUpdateObjectMethodWithValidObject() {
 	assert.Equal(testSuite.T(), expectedMetaData[MetaDataKey], updatedObj.Metadata[MetaDataKey])
 }
 
+func (testSuite *BucketHandleTest) TestUpdateObjectMethodDeletingMetadataKey() {
+	createBucketHandle(testSuite, &controlpb.StorageLayout{})
+	newKey := "new_key"
+	newValue := "new_value"
+
+	updatedObj, err := testSuite.bucketHandle.UpdateObject(context.Background(),
+		&gcs.UpdateObjectRequest{
+			Name:       TestObjectName,
+			Generation: TestObjectGeneration,
+			Metadata: map[string]*string{
+				MetaDataKey: nil,
+				newKey:      &newValue,
+			},
+		})
+
+	// The fake storage server stores the nulls sent for the removed keys as
+	// empty values, so only the written key can be verified here; see
+	// TestUpdateObjectDeletingMetadataKeysPatchesThemToNull for the request.
+	require.NoError(testSuite.T(), err)
+	assert.Equal(testSuite.T(), newValue, updatedObj.Metadata[newKey])
+}
+
+func (testSuite *BucketHandleTest) TestUpdateObjectMethodDeletingMetadataKeyWithoutJSONAPI() {
+	createBucketHandle(testSuite, &controlpb.StorageLayout{})
+	testSuite.bucketHandle.rawObjects = nil
+
+	_, err := testSuite.bucketHandle.UpdateObject(context.Background(),
+		&gcs.UpdateObjectRequest{
+			Name:     TestObjectName,
+			Metadata: map[string]*string{MetaDataKey: nil},
+		})
+
+	assert.ErrorIs(testSuite.T(), err, errors.ErrUnsupported)
+	minObj, _, err := testSuite.bucketHandle.StatObject(context.Background(), &gcs.StatObjectRequest{Name: TestObjectName})
+	require.NoError(testSuite.T(), err)
+	assert.Equal(testSuite.T(), MetaDataValue, minObj.Metadata[MetaDataKey])
+}
+
 func (testSuite *BucketHandleTest) TestUpdateObjectMethodWithMissingObject() {
 	var notfound *gcs.NotFoundError
 
@@ -1623,3 +1686,126 @@ func (testSuite *BucketHandleTest) TestCreateFolderWithGivenName() {
 	assert.NoError(testSuite.T(), err)
 	assert.Equal(testSuite.T(), gcs.GCSFolder(TestBucketName, &mockFolder), folder)
 }
+
+func TestUpdateObjectDeletingMetadataKeysPatchesThemToNull(t *testing.T) {
+	var method, query string
+	var body map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		query = r.URL.RawQuery
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		fmt.Fprint(w, `{"name":"a","generation":"7","metageneration":"3","metadata":{"kept":"v"}}`)
+	}))
+	defer server.Close()
+	opts := []option.ClientOption{option.WithHTTPClient(server.Client()), option.WithEndpoint(server.URL)}
+	client, err := storage.NewClient(context.Background(), opts...)
+	require.NoError(t, err)
+	service, err := storagev1.NewService(context.Background(), opts...)
+	require.NoError(t, err)
+	bh := &bucketHandle{bucket: client.Bucket("bucket"), bucketName: "bucket", rawObjects: service.Objects}
+	value := "v"
+	metaGeneration := int64(2)
+
+	o, err := bh.UpdateObject(context.Background(), &gcs.UpdateObjectRequest{
+		Name:                       "a",
+		Generation:                 7,
+		MetaGenerationPrecondition: &metaGeneration,
+		Metadata:                   map[string]*string{"removed": nil, "kept": &value},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, http.MethodPatch, method)
+	assert.Contains(t, query, "generation=7")
+	assert.Contains(t, query, "ifMetagenerationMatch=2")
+	assert.Equal(t, map[string]any{"metadata": map[string]any{"removed": nil, "kept": "v"}}, body)
+	assert.Equal(t, int64(3), o.MetaGeneration)
+	assert.Equal(t, map[string]string{"kept": "v"}, o.Metadata)
+}
+
+func TestUpdateObjectDeletingMetadataKeysRetries(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		failures      int
+		maxAttempts   int
+		expectedCalls int
+		expectErr     bool
+	}{
+		{name: "succeeds after transient failures", failures: 2, expectedCalls: 3},
+		{name: "gives up after max attempts", failures: 5, maxAttempts: 2, expectedCalls: 2, expectErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				if calls <= tc.failures {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				fmt.Fprint(w, `{"name":"a","generation":"7","metageneration":"3"}`)
+			}))
+			defer server.Close()
+			opts := []option.ClientOption{option.WithHTTPClient(server.Client()), option.WithEndpoint(server.URL)}
+			client, err := storage.NewClient(context.Background(), opts...)
+			require.NoError(t, err)
+			service, err := storagev1.NewService(context.Background(), opts...)
+			require.NoError(t, err)
+			bh := &bucketHandle{
+				bucket:     client.Bucket("bucket"),
+				bucketName: "bucket",
+				rawObjects: service.Objects,
+				rawRetryer: rawRetryer(context.Background(), &storageutil.StorageClientConfig{
+					MaxRetrySleep:    time.Millisecond,
+					RetryMultiplier:  2,
+					MaxRetryAttempts: tc.maxAttempts,
+					MetricHandle:     metrics.NewNoopMetrics(),
+				}),
+			}
+			metaGeneration := int64(2)
+
+			_, err = bh.UpdateObject(context.Background(), &gcs.UpdateObjectRequest{
+				Name:                       "a",
+				MetaGenerationPrecondition: &metaGeneration,
+				Metadata:                   map[string]*string{"removed": nil},
+			})
+
+			assert.Equal(t, tc.expectErr, err != nil)
+			assert.Equal(t, tc.expectedCalls, calls)
+		})
+	}
+}
+
+func TestMoveObjectBetweenKeysCopiesAndDeletes(t *testing.T) {
+	dir := t.TempDir()
+	keyPath := path.Join(dir, "key")
+	require.NoError(t, os.WriteFile(keyPath, bytes.Repeat([]byte{1}, 32), 0600))
+	mapPath := path.Join(dir, "map")
+	require.NoError(t, os.WriteFile(mapPath, []byte("secret/ "+keyPath+"\n"), 0600))
+	keys, err := storageutil.LoadCustomerSuppliedKeys("", mapPath)
+	require.NoError(t, err)
+	var requests []string
+	var dstKeyHash string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path+"?generation="+r.URL.Query().Get("sourceGeneration")+r.URL.Query().Get("generation"))
+		switch r.Method {
+		case http.MethodPost:
+			dstKeyHash = r.Header.Get("X-Goog-Encryption-Key-Sha256")
+			fmt.Fprint(w, `{"done":true,"resource":{"name":"secret/a","generation":"8","metageneration":"1"}}`)
+		case http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+	client, err := storage.NewClient(context.Background(), option.WithHTTPClient(server.Client()), option.WithEndpoint(server.URL))
+	require.NoError(t, err)
+	bh := &bucketHandle{bucket: client.Bucket("bucket"), bucketName: "bucket", keys: keys}
+
+	o, err := bh.MoveObject(context.Background(), &gcs.MoveObjectRequest{SrcName: "a", DstName: "secret/a", SrcGeneration: 7})
+
+	require.NoError(t, err)
+	assert.Equal(t, "secret/a", o.Name)
+	assert.NotEmpty(t, dstKeyHash)
+	assert.Equal(t, []string{
+		"POST /b/bucket/o/a/rewriteTo/b/bucket/o/secret/a?generation=7",
+		"DELETE /b/bucket/o/a?generation=7",
+	}, requests)
+}
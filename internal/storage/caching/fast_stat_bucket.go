@@ -66,19 +66,63 @@ type fastStatBucket struct {
 	wrapped gcs.Bucket
 
 	/////////////////////////
-	// Constant data
+	// Mutable state
 	/////////////////////////
 
 	// TTL for entries for existing files and folders in the cache.
+	//
+	// GUARDED_BY(mu)
 	primaryCacheTTL time.Duration
 	// TTL for entries for non-existing files and folders in the cache.
+	//
+	// GUARDED_BY(mu)
 	negativeCacheTTL time.Duration
+
+	// Called with the objects fetched from GCS by StatObject and ListObjects,
+	// if set.
+	//
+	// GUARDED_BY(mu)
+	observer func(objs []*gcs.MinObject)
+}
+
+// SetTTLs changes the TTLs of the entries inserted into the cache from now on.
+//
+// LOCKS_EXCLUDED(b.mu)
+func (b *fastStatBucket) SetTTLs(primaryCacheTTL time.Duration, negativeCacheTTL time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.primaryCacheTTL = primaryCacheTTL
+	b.negativeCacheTTL = negativeCacheTTL
+}
+
+// SetObserver sets the function called with the objects fetched from GCS by
+// StatObject and ListObjects, after they are inserted into the cache. It is
+// called synchronously, so it must not block.
+//
+// LOCKS_EXCLUDED(b.mu)
+func (b *fastStatBucket) SetObserver(observer func(objs []*gcs.MinObject)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.observer = observer
 }
 
 ////////////////////////////////////////////////////////////////////////
 // Helpers
 ////////////////////////////////////////////////////////////////////////
 
+// LOCKS_EXCLUDED(b.mu)
+func (b *fastStatBucket) observe(objs []*gcs.MinObject) {
+	b.mu.Lock()
+	observer := b.observer
+	b.mu.Unlock()
+
+	if observer != nil && len(objs) > 0 {
+		observer(objs)
+	}
+}
+
 // LOCKS_EXCLUDED(b.mu)
 func (b *fastStatBucket) insertMultiple(objs []*gcs.Object) {
 	b.mu.Lock()
@@ -363,13 +407,19 @@ func (b *fastStatBucket) ListObjects(
 		return
 	}
 
-	if b.BucketType().Hierarchical {
-		b.insertHierarchicalListing(listing)
+	// Listings of versions include noncurrent generations, which must not be
+	// mistaken for the current ones.
+	if req.Versions {
 		return
 	}
 
-	// note anything we found.
-	b.insertMultipleMinObjects(listing.MinObjects)
+	if b.BucketType().Hierarchical {
+		b.insertHierarchicalListing(listing)
+	} else {
+		// note anything we found.
+		b.insertMultipleMinObjects(listing.MinObjects)
+	}
+	b.observe(listing.MinObjects)
 	return
 }
 
@@ -449,6 +499,7 @@ func (b *fastStatBucket) StatObjectFromGcs(ctx context.Context,
 
 	// Put the object in cache.
 	b.insertMinObject(m)
+	b.observe([]*gcs.MinObject{m})
 
 	return
 }
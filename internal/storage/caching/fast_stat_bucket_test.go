@@ -770,6 +770,34 @@ func (t *StatObjectTest) WrappedSaysNotFound() {
 	ExpectThat(err, Error(HasSubstr("burrito")))
 }
 
+func (t *StatObjectTest) UsesTTLsSetAfterCreation() {
+	const name = "taco"
+	t.bucket.(interface {
+		SetTTLs(time.Duration, time.Duration)
+	}).SetTTLs(2*primaryCacheTTL, 2*negativeCacheTTL)
+
+	// LookUp
+	ExpectCall(t.cache, "LookUp")(Any(), Any()).
+		WillOnce(Return(false, nil))
+
+	// Wrapped
+	ExpectCall(t.wrapped, "StatObject")(Any(), Any()).
+		WillOnce(Return(nil, nil, &gcs.NotFoundError{Err: errors.New("burrito")}))
+
+	// AddNegativeEntry
+	ExpectCall(t.cache, "AddNegativeEntry")(
+		name,
+		timeutil.TimeEq(t.clock.Now().Add(2*negativeCacheTTL)))
+
+	// Call
+	req := &gcs.StatObjectRequest{
+		Name: name,
+	}
+
+	_, _, err := t.bucket.StatObject(context.TODO(), req)
+	ExpectThat(err, HasSameTypeAs(&gcs.NotFoundError{}))
+}
+
 func (t *StatObjectTest) WrappedSucceeds() {
 	const name = "taco"
 
@@ -798,6 +826,34 @@ func (t *StatObjectTest) WrappedSucceeds() {
 	ExpectEq(minObj, m)
 }
 
+func (t *StatObjectTest) CallsObserverWithFetchedObject() {
+	var observed []*gcs.MinObject
+	t.bucket.(interface {
+		SetObserver(func([]*gcs.MinObject))
+	}).SetObserver(func(objs []*gcs.MinObject) { observed = append(observed, objs...) })
+
+	// LookUp
+	ExpectCall(t.cache, "LookUp")(Any(), Any()).
+		WillOnce(Return(false, nil))
+
+	// Wrapped
+	minObj := &gcs.MinObject{
+		Name: "taco",
+	}
+
+	ExpectCall(t.wrapped, "StatObject")(Any(), Any()).
+		WillOnce(Return(minObj, nil, nil))
+
+	// Insert
+	ExpectCall(t.cache, "Insert")(Any(), Any())
+
+	// Call
+	_, _, err := t.bucket.StatObject(context.TODO(), &gcs.StatObjectRequest{Name: "taco"})
+
+	AssertEq(nil, err)
+	ExpectThat(observed, ElementsAre(minObj))
+}
+
 ////////////////////////////////////////////////////////////////////////
 // ListObjects
 ////////////////////////////////////////////////////////////////////////
@@ -877,6 +933,55 @@ func (t *ListObjectsTest) NonEmptyListing() {
 	ExpectEq(expected, listing)
 }
 
+func (t *ListObjectsTest) CallsObserverWithListedObjects() {
+	var observed []*gcs.MinObject
+	t.bucket.(interface {
+		SetObserver(func([]*gcs.MinObject))
+	}).SetObserver(func(objs []*gcs.MinObject) { observed = append(observed, objs...) })
+
+	// Wrapped
+	o0 := &gcs.MinObject{Name: "taco"}
+	o1 := &gcs.MinObject{Name: "burrito"}
+
+	ExpectCall(t.wrapped, "BucketType")().
+		WillOnce(Return(gcs.BucketType{}))
+
+	ExpectCall(t.wrapped, "ListObjects")(Any(), Any()).
+		WillOnce(Return(&gcs.Listing{MinObjects: []*gcs.MinObject{o0, o1}}, nil))
+
+	// Insert
+	ExpectCall(t.cache, "Insert")(Any(), Any()).Times(2)
+
+	// Call
+	_, err := t.bucket.ListObjects(context.TODO(), &gcs.ListObjectsRequest{})
+
+	AssertEq(nil, err)
+	ExpectThat(observed, ElementsAre(o0, o1))
+}
+
+func (t *ListObjectsTest) ListingOfVersionsIsNotCached() {
+	var observed []*gcs.MinObject
+	t.bucket.(interface {
+		SetObserver(func([]*gcs.MinObject))
+	}).SetObserver(func(objs []*gcs.MinObject) { observed = append(observed, objs...) })
+
+	// Wrapped
+	expected := &gcs.Listing{
+		MinObjects: []*gcs.MinObject{{Name: "taco", Generation: 1}, {Name: "taco", Generation: 2}},
+		Lifetimes:  make([]gcs.ObjectLifetime, 2),
+	}
+
+	ExpectCall(t.wrapped, "ListObjects")(Any(), Any()).
+		WillOnce(Return(expected, nil))
+
+	// Call
+	listing, err := t.bucket.ListObjects(context.TODO(), &gcs.ListObjectsRequest{Versions: true})
+
+	AssertEq(nil, err)
+	ExpectEq(expected, listing)
+	ExpectEq(0, len(observed))
+}
+
 func (t *ListObjectsTest) NonEmptyListingForHNS() {
 	// wrapped
 	o0 := &gcs.MinObject{Name: "taco"}
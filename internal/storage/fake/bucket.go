@@ -797,6 +797,26 @@ func (b *bucket) CopyObject(
 		}
 	}
 
+	// Check the destination precondition.
+	existingIndex := b.objects.find(req.DstName)
+	if req.DstGenerationPrecondition != nil {
+		var existingGen int64
+		if existingIndex < len(b.objects) {
+			existingGen = b.objects[existingIndex].metadata.Generation
+		}
+
+		if existingGen != *req.DstGenerationPrecondition {
+			err = &gcs.PreconditionError{
+				Err: fmt.Errorf(
+					"precondition failed: object %q has generation %v",
+					req.DstName,
+					existingGen),
+			}
+
+			return
+		}
+	}
+
 	// Copy it and assign a new generation number, to ensure that the generation
 	// number for the destination name is strictly increasing.
 	dst := b.objects[srcIndex]
@@ -807,7 +827,6 @@ func (b *bucket) CopyObject(
 	dst.metadata.Generation = b.prevGeneration
 
 	// Insert into our array.
-	existingIndex := b.objects.find(req.DstName)
 	if existingIndex < len(b.objects) {
 		b.objects[existingIndex] = dst
 	} else {
@@ -1086,6 +1105,24 @@ func (b *bucket) MoveObject(ctx context.Context, req *gcs.MoveObjectRequest) (*g
 		}
 	}
 
+	// Check the destination precondition.
+	if req.DstGenerationPrecondition != nil {
+		var existingGen int64
+		if existingIndex := b.objects.find(req.DstName); existingIndex < len(b.objects) {
+			existingGen = b.objects[existingIndex].metadata.Generation
+		}
+
+		if existingGen != *req.DstGenerationPrecondition {
+			err = &gcs.PreconditionError{
+				Err: fmt.Errorf(
+					"precondition failed: object %q has generation %v",
+					req.DstName,
+					existingGen),
+			}
+			return nil, err
+		}
+	}
+
 	// Move it and assign a new generation number, to ensure that the generation
 	// number for the destination name is strictly increasing.
 	dst := b.objects[srcIndex]
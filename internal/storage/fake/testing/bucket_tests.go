@@ -1741,6 +1741,59 @@ func (t *copyTest) SrcMetaGenerationPrecondition_Satisfied() {
 	ExpectEq(nil, err)
 }
 
+func (t *copyTest) DstGenerationPrecondition_Unsatisfied() {
+	var err error
+
+	// Create a source object and a destination object.
+	_, err = storageutil.CreateObject(t.ctx, t.bucket, "foo", []byte("taco"))
+	AssertEq(nil, err)
+
+	_, err = storageutil.CreateObject(t.ctx, t.bucket, "bar", []byte("burrito"))
+	AssertEq(nil, err)
+
+	// Attempt to copy, with a precondition that the destination doesn't exist.
+	var precond int64
+	req := &gcs.CopyObjectRequest{
+		SrcName:                   "foo",
+		DstName:                   "bar",
+		DstGenerationPrecondition: &precond,
+	}
+
+	_, err = t.bucket.CopyObject(t.ctx, req)
+	AssertThat(err, HasSameTypeAs(&gcs.PreconditionError{}))
+
+	// The destination should not have been overwritten.
+	contents, err := storageutil.ReadObject(t.ctx, t.bucket, "bar")
+	AssertEq(nil, err)
+	ExpectEq("burrito", string(contents))
+}
+
+func (t *copyTest) DstGenerationPrecondition_Satisfied() {
+	var err error
+
+	// Create a source object and a destination object.
+	_, err = storageutil.CreateObject(t.ctx, t.bucket, "foo", []byte("taco"))
+	AssertEq(nil, err)
+
+	dst, err := storageutil.CreateObject(t.ctx, t.bucket, "bar", []byte("burrito"))
+	AssertEq(nil, err)
+
+	// Copy, with a precondition on the destination's generation.
+	req := &gcs.CopyObjectRequest{
+		SrcName:                   "foo",
+		DstName:                   "bar",
+		DstGenerationPrecondition: &dst.Generation,
+	}
+
+	_, err = t.bucket.CopyObject(t.ctx, req)
+	AssertEq(nil, err)
+
+	// The destination should have been overwritten.
+	contents, err := storageutil.ReadObject(t.ctx, t.bucket, "bar")
+	AssertEq(nil, err)
+	ExpectEq("taco", string(contents))
+}
+
 ////////////////////////////////////////////////////////////////////////
 // Compose
 ////////////////////////////////////////////////////////////////////////
@@ -15,10 +15,14 @@
 package storage
 
 import (
+	"context"
+
 	"github.com/fsouza/fake-gcs-server/fakestorage"
 	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"google.golang.org/api/option"
+	storagev1 "google.golang.org/api/storage/v1"
 )
 
 const TestBucketName string = "gcsfuse-default-bucket"
@@ -63,10 +67,17 @@ func (f *fakeStorage) CreateStorageHandle() (sh StorageHandle) {
 	if f.mockClient == nil {
 		f.mockClient = new(MockStorageControlClient)
 	}
+	rawHTTPService, err := storagev1.NewService(context.Background(),
+		option.WithHTTPClient(f.fakeStorageServer.HTTPClient()),
+		option.WithEndpoint(f.fakeStorageServer.URL()+"/storage/v1/"))
+	if err != nil {
+		panic(err)
+	}
 	sh = &storageClient{
 		httpClient:               f.fakeStorageServer.Client(),
 		grpcClient:               f.fakeStorageServer.Client(),
 		grpcClientWithBidiConfig: f.fakeStorageServer.Client(),
+		rawHTTPService:           rawHTTPService,
 		storageControlClient:     f.mockClient,
 		clientConfig:             storageutil.StorageClientConfig{ClientProtocol: f.protocol},
 	}
@@ -18,6 +18,7 @@ import (
 	"crypto/md5"
 	"fmt"
 	"io"
+	"time"
 
 	storagev2 "cloud.google.com/go/storage"
 	storagev1 "google.golang.org/api/storage/v1"
@@ -320,6 +321,20 @@ type ListObjectsRequest struct {
 	// the current flow, default value will be full and callers can override it
 	// using this param.
 	ProjectionVal Projection
+
+	// List the noncurrent generations of the objects too, ordered by name and
+	// then generation. The lifetimes of the generations are returned in
+	// Listing.Lifetimes.
+	Versions bool
+}
+
+// ObjectLifetime is the period during which a generation of an object was
+// live: from its creation until it was replaced or deleted, if it was.
+type ObjectLifetime struct {
+	Created time.Time
+
+	// Zero for live generations.
+	Deleted time.Time
 }
 
 // Listing contains a set of objects and delimter-based collapsed runs returned
@@ -357,6 +372,11 @@ type Listing struct {
 	// and deleted concurrently with a single or multiple listing requests may or
 	// may not be returned.
 	ContinuationToken string
+
+	// For listings of versions, the lifetimes of the generations in MinObjects,
+	// at the same indexes. Nil for other listings, and for listings of versions
+	// by buckets that don't support them.
+	Lifetimes []ObjectLifetime
 }
 
 // A request to update the metadata of an object, accepted by
@@ -427,6 +447,11 @@ type MoveObjectRequest struct {
 	// If non-nil, the destination object will be created/overwritten only if the
 	// current meta-generation for the source object is equal to the given value.
 	SrcMetaGenerationPrecondition *int64
+
+	// Destination object will be overwritten only if the current
+	// generation is equal to the given value. Zero means the object does not
+	// exist.
+	DstGenerationPrecondition *int64
 }
 
 // CreateObjectChunkWriterRequest represents a request to create a storage.Writer
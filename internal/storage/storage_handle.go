@@ -36,6 +36,7 @@ import (
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"golang.org/x/oauth2"
 	option "google.golang.org/api/option"
+	storagev1 "google.golang.org/api/storage/v1"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 
@@ -63,7 +64,10 @@ type StorageHandle interface {
 }
 
 type storageClient struct {
-	httpClient               *storage.Client
+	httpClient *storage.Client
+	// rawHTTPService is a client of the JSON API itself, created along with
+	// httpClient.
+	rawHTTPService           *storagev1.Service
 	grpcClient               *storage.Client
 	grpcClientWithBidiConfig *storage.Client
 	clientConfig             storageutil.StorageClientConfig
@@ -179,6 +183,39 @@ func setRetryConfig(ctx context.Context, sc *storage.Client, clientConfig *stora
 	}
 }
 
+// rawRetryer returns a function creating the retryers of the requests made to
+// the JSON API without the storage client, which retry like setRetryConfig
+// makes the storage client retry.
+func rawRetryer(ctx context.Context, clientConfig *storageutil.StorageClientConfig) func() gax.Retryer {
+	return func() gax.Retryer {
+		return &maxAttemptsRetryer{
+			Retryer: gax.OnErrorFunc(gax.Backoff{
+				Max:        clientConfig.MaxRetrySleep,
+				Multiplier: clientConfig.RetryMultiplier,
+			}, func(err error) bool {
+				return storageutil.ShouldRetryWithMonitoring(ctx, err, clientConfig.MetricHandle)
+			}),
+			maxAttempts: clientConfig.MaxRetryAttempts,
+		}
+	}
+}
+
+// maxAttemptsRetryer stops retrying after maxAttempts attempts in all, unless
+// maxAttempts is zero.
+type maxAttemptsRetryer struct {
+	gax.Retryer
+	maxAttempts int
+	attempts    int
+}
+
+func (r *maxAttemptsRetryer) Retry(err error) (time.Duration, bool) {
+	r.attempts++
+	if r.maxAttempts != 0 && r.attempts >= r.maxAttempts {
+		return 0, false
+	}
+	return r.Retryer.Retry(err)
+}
+
 // Followed https://pkg.go.dev/cloud.google.com/go/storage#hdr-Experimental_gRPC_API to create the gRPC client.
 func createGRPCClientHandle(ctx context.Context, clientConfig *storageutil.StorageClientConfig, enableBidiConfig bool) (sc *storage.Client, err error) {
 
@@ -207,8 +244,9 @@ func createGRPCClientHandle(ctx context.Context, clientConfig *storageutil.Stora
 	return
 }
 
-func createHTTPClientHandle(ctx context.Context, clientConfig *storageutil.StorageClientConfig) (sc *storage.Client, err error) {
-	var clientOpts []option.ClientOption
+// httpClientOptions returns the options of the clients using the JSON API:
+// authentication, the HTTP client and the endpoint.
+func httpClientOptions(ctx context.Context, clientConfig *storageutil.StorageClientConfig) (clientOpts []option.ClientOption, err error) {
 	var tokenSrc oauth2.TokenSource = nil
 
 	if clientConfig.EnableGoogleLibAuth {
@@ -224,8 +262,7 @@ func createHTTPClientHandle(ctx context.Context, clientConfig *storageutil.Stora
 	var httpClient *http.Client
 	httpClient, err = storageutil.CreateHttpClient(clientConfig, tokenSrc)
 	if err != nil {
-		err = fmt.Errorf("while creating http endpoint: %w", err)
-		return
+		return nil, fmt.Errorf("while creating http endpoint: %w", err)
 	}
 
 	clientOpts = append(clientOpts, option.WithHTTPClient(httpClient))
@@ -234,15 +271,37 @@ func createHTTPClientHandle(ctx context.Context, clientConfig *storageutil.Stora
 		clientOpts = append(clientOpts, option.WithoutAuthentication())
 	}
 
-	// Create client with JSON read flow, if EnableJasonRead flag is set.
-	if clientConfig.ExperimentalEnableJsonRead {
-		clientOpts = append(clientOpts, storage.WithJSONReads())
-	}
-
 	// Add Custom endpoint option.
 	if clientConfig.CustomEndpoint != "" {
 		clientOpts = append(clientOpts, option.WithEndpoint(clientConfig.CustomEndpoint))
 	}
+	return
+}
+
+// createRawHTTPService creates a client of the JSON API itself, for the
+// requests the storage client can't make.
+func createRawHTTPService(ctx context.Context, clientConfig *storageutil.StorageClientConfig) (*storagev1.Service, error) {
+	clientOpts, err := httpClientOptions(ctx, clientConfig)
+	if err != nil {
+		return nil, err
+	}
+	service, err := storagev1.NewService(ctx, clientOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("JSON API client creation failed: %w", err)
+	}
+	return service, nil
+}
+
+func createHTTPClientHandle(ctx context.Context, clientConfig *storageutil.StorageClientConfig) (sc *storage.Client, err error) {
+	clientOpts, err := httpClientOptions(ctx, clientConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create client with JSON read flow, if EnableJasonRead flag is set.
+	if clientConfig.ExperimentalEnableJsonRead {
+		clientOpts = append(clientOpts, storage.WithJSONReads())
+	}
 
 	if clientConfig.ReadStallRetryConfig.Enable {
 		// Hidden way to modify the increase rate for dynamic delay algorithm in go-sdk.
@@ -378,6 +437,12 @@ func (sh *storageClient) getClient(ctx context.Context, isbucketZonal bool) (*st
 	if sh.clientConfig.ClientProtocol == cfg.HTTP1 || sh.clientConfig.ClientProtocol == cfg.HTTP2 {
 		if sh.httpClient == nil {
 			sh.httpClient, err = createHTTPClientHandle(ctx, &sh.clientConfig)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if sh.rawHTTPService == nil {
+			sh.rawHTTPService, err = createRawHTTPService(ctx, &sh.clientConfig)
 		}
 		return sh.httpClient, err
 	}
@@ -442,9 +507,15 @@ func (sh *storageClient) BucketHandle(ctx context.Context, bucketName string, bi
 	bh = &bucketHandle{
 		bucket:               storageBucketHandle,
 		bucketName:           bucketName,
+		billingProject:       billingProject,
 		controlClient:        controlClient,
 		bucketType:           bucketType,
 		finalizeFileForRapid: finalizeFileForRapid,
+		keys:                 sh.clientConfig.CustomerSuppliedKeys,
+	}
+	if !bucketType.Zonal && sh.clientConfig.ClientProtocol != cfg.GRPC && sh.rawHTTPService != nil {
+		bh.rawObjects = sh.rawHTTPService.Objects
+		bh.rawRetryer = rawRetryer(ctx, &sh.clientConfig)
 	}
 
 	return
@@ -85,6 +85,10 @@ type StorageClientConfig struct {
 	TracingEnabled bool
 
 	EnableHTTPDNSCache bool
+
+	// The customer-supplied encryption keys of objects, passed on all the
+	// requests for their contents and metadata. Nil if there are none.
+	CustomerSuppliedKeys *CustomerSuppliedKeys
 }
 
 func CreateHttpClient(storageClientConfig *StorageClientConfig, tokenSrc oauth2.TokenSource) (httpClient *http.Client, err error) {
@@ -164,6 +168,7 @@ func CreateHttpClient(storageClientConfig *StorageClientConfig, tokenSrc oauth2.
 			}), otelhttp.WithTracerProvider(otel.GetTracerProvider()))
 		}
 	}
+	httpClient.Transport = &resumableSessionRoundTripper{wrapped: httpClient.Transport}
 	return httpClient, err
 }
 
@@ -0,0 +1,153 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storageutil
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// The length of AES-256 keys.
+const AES256KeyLen = 32
+
+// ReadAES256KeyFile reads an AES-256 key from a file holding it, either raw or
+// base64 encoded.
+func ReadAES256KeyFile(path string) ([]byte, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(contents) == AES256KeyLen {
+		return contents, nil
+	}
+	key, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(contents)))
+	if err != nil || len(key) != AES256KeyLen {
+		return nil, fmt.Errorf("%q must hold a %d-byte key, raw or base64 encoded", path, AES256KeyLen)
+	}
+	return key, nil
+}
+
+type prefixKey struct {
+	prefix string
+	key    []byte
+}
+
+// CustomerSuppliedKeys are the customer-supplied encryption keys (CSEK) that
+// objects are encrypted with in GCS, by object name. A nil value has no keys.
+type CustomerSuppliedKeys struct {
+	// The key of objects whose names don't match any prefix, if any.
+	defaultKey []byte
+
+	// Sorted by decreasing length of prefix, so that the longest prefix that
+	// matches a name comes first.
+	prefixKeys []prefixKey
+}
+
+// LoadCustomerSuppliedKeys loads the keys in the supplied key file, used for
+// all objects, and key map file, whose keys override it for the objects whose
+// names start with their prefixes. Either may be empty, and nil is returned if
+// both are.
+//
+// The key map file has one "PREFIX KEY_FILE" pair per line, split at the last
+// run of white space. Relative key file paths are relative to the directory
+// of the key map file. Blank lines and lines starting with # are ignored.
+func LoadCustomerSuppliedKeys(keyFile string, keyMapFile string) (*CustomerSuppliedKeys, error) {
+	if keyFile == "" && keyMapFile == "" {
+		return nil, nil
+	}
+
+	keys := &CustomerSuppliedKeys{}
+	if keyFile != "" {
+		key, err := ReadAES256KeyFile(keyFile)
+		if err != nil {
+			return nil, err
+		}
+		keys.defaultKey = key
+	}
+
+	if keyMapFile != "" {
+		prefixKeys, err := loadKeyMap(keyMapFile)
+		if err != nil {
+			return nil, err
+		}
+		keys.prefixKeys = prefixKeys
+	}
+
+	return keys, nil
+}
+
+func loadKeyMap(path string) ([]prefixKey, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var prefixKeys []prefixKey
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(f)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		i := strings.LastIndexAny(line, " \t")
+		if i < 0 {
+			return nil, fmt.Errorf("%s:%d: %q is not of the form PREFIX KEY_FILE", path, lineNo, line)
+		}
+		prefix, keyFile := strings.TrimSpace(line[:i]), line[i+1:]
+		if seen[prefix] {
+			return nil, fmt.Errorf("%s:%d: prefix %q is listed more than once", path, lineNo, prefix)
+		}
+		seen[prefix] = true
+
+		if !filepath.IsAbs(keyFile) {
+			keyFile = filepath.Join(filepath.Dir(path), keyFile)
+		}
+		key, err := ReadAES256KeyFile(keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, lineNo, err)
+		}
+		prefixKeys = append(prefixKeys, prefixKey{prefix: prefix, key: key})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(prefixKeys, func(i, j int) bool {
+		return len(prefixKeys[i].prefix) > len(prefixKeys[j].prefix)
+	})
+	return prefixKeys, nil
+}
+
+// KeyFor returns the key that the named object is encrypted with, or nil if
+// it isn't encrypted with a customer-supplied key.
+func (k *CustomerSuppliedKeys) KeyFor(objectName string) []byte {
+	if k == nil {
+		return nil
+	}
+	for _, pk := range k.prefixKeys {
+		if strings.HasPrefix(objectName, pk.prefix) {
+			return pk.key
+		}
+	}
+	return k.defaultKey
+}
@@ -0,0 +1,107 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storageutil
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeKeyFile(t *testing.T, dir string, name string, b byte) ([]byte, string) {
+	t.Helper()
+	key := bytes.Repeat([]byte{b}, AES256KeyLen)
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(base64.StdEncoding.EncodeToString(key)+"\n"), 0600))
+	return key, path
+}
+
+func TestReadAES256KeyFile(t *testing.T) {
+	dir := t.TempDir()
+	key, encodedPath := writeKeyFile(t, dir, "encoded", 1)
+	rawPath := filepath.Join(dir, "raw")
+	require.NoError(t, os.WriteFile(rawPath, key, 0600))
+	shortPath := filepath.Join(dir, "short")
+	require.NoError(t, os.WriteFile(shortPath, []byte("c2hvcnQ="), 0600))
+
+	for _, path := range []string{encodedPath, rawPath} {
+		got, err := ReadAES256KeyFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, key, got)
+	}
+	_, err := ReadAES256KeyFile(shortPath)
+	assert.Error(t, err)
+}
+
+func TestLoadCustomerSuppliedKeysWithoutFiles(t *testing.T) {
+	keys, err := LoadCustomerSuppliedKeys("", "")
+
+	require.NoError(t, err)
+	assert.Nil(t, keys)
+	assert.Nil(t, keys.KeyFor("foo"))
+}
+
+func TestLoadCustomerSuppliedKeys(t *testing.T) {
+	dir := t.TempDir()
+	defaultKey, defaultPath := writeKeyFile(t, dir, "default", 1)
+	aKey, _ := writeKeyFile(t, dir, "a", 2)
+	abKey, abPath := writeKeyFile(t, dir, "ab", 3)
+	mapPath := filepath.Join(dir, "map")
+	require.NoError(t, os.WriteFile(mapPath, []byte("# Keys by prefix.\n\na/ a\na/b c/\t"+abPath+"\n"), 0600))
+
+	keys, err := LoadCustomerSuppliedKeys(defaultPath, mapPath)
+
+	require.NoError(t, err)
+	assert.Equal(t, aKey, keys.KeyFor("a/foo"))
+	assert.Equal(t, abKey, keys.KeyFor("a/b c/foo"))
+	assert.Equal(t, aKey, keys.KeyFor("a/b/foo"))
+	assert.Equal(t, defaultKey, keys.KeyFor("b/foo"))
+}
+
+func TestLoadCustomerSuppliedKeysWithoutDefault(t *testing.T) {
+	dir := t.TempDir()
+	aKey, _ := writeKeyFile(t, dir, "a", 2)
+	mapPath := filepath.Join(dir, "map")
+	require.NoError(t, os.WriteFile(mapPath, []byte("a/ a\n"), 0600))
+
+	keys, err := LoadCustomerSuppliedKeys("", mapPath)
+
+	require.NoError(t, err)
+	assert.Equal(t, aKey, keys.KeyFor("a/foo"))
+	assert.Nil(t, keys.KeyFor("b/foo"))
+}
+
+func TestLoadCustomerSuppliedKeysRejectsInvalidMaps(t *testing.T) {
+	dir := t.TempDir()
+	writeKeyFile(t, dir, "a", 2)
+	for name, contents := range map[string]string{
+		"missing_key_file":  "a/",
+		"duplicate_prefix":  "a/ a\na/ a\n",
+		"unreadable_key":    "a/ missing\n",
+		"invalid_key_bytes": "a/ map\n",
+	} {
+		mapPath := filepath.Join(dir, "map")
+		require.NoError(t, os.WriteFile(mapPath, []byte(contents), 0600))
+
+		_, err := LoadCustomerSuppliedKeys("", mapPath)
+
+		assert.Error(t, err, name)
+	}
+}
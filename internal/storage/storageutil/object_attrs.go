@@ -16,6 +16,9 @@ package storageutil
 
 import (
 	"crypto/md5"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
 	"time"
 
 	"cloud.google.com/go/storage"
@@ -249,3 +252,80 @@ func ConvertMinObjectToObject(m *gcs.MinObject) *gcs.Object {
 		Finalized:       m.Finalized,
 	}
 }
+
+// RawObjectToBucketObject converts an object returned by the JSON API, for the
+// requests made without the storage client.
+func RawObjectToBucketObject(o *storagev1.Object) (*gcs.Object, error) {
+	if o == nil {
+		return nil, nil
+	}
+
+	var md5Hash *[md5.Size]byte
+	if o.Md5Hash != "" {
+		b, err := base64.StdEncoding.DecodeString(o.Md5Hash)
+		if err != nil || len(b) != md5.Size {
+			return nil, fmt.Errorf("malformed md5Hash %q", o.Md5Hash)
+		}
+		md5Hash = new([md5.Size]byte)
+		copy(md5Hash[:], b)
+	}
+
+	var crc *uint32
+	if o.Crc32c != "" {
+		b, err := base64.StdEncoding.DecodeString(o.Crc32c)
+		if err != nil || len(b) != 4 {
+			return nil, fmt.Errorf("malformed crc32c %q", o.Crc32c)
+		}
+		c := binary.BigEndian.Uint32(b)
+		crc = &c
+	}
+
+	parseTime := func(s string) (time.Time, error) {
+		if s == "" {
+			return time.Time{}, nil
+		}
+		return time.Parse(time.RFC3339, s)
+	}
+	updated, err := parseTime(o.Updated)
+	if err != nil {
+		return nil, fmt.Errorf("malformed updated: %w", err)
+	}
+	deleted, err := parseTime(o.TimeDeleted)
+	if err != nil {
+		return nil, fmt.Errorf("malformed timeDeleted: %w", err)
+	}
+	finalized, err := parseTime(o.TimeFinalized)
+	if err != nil {
+		return nil, fmt.Errorf("malformed timeFinalized: %w", err)
+	}
+
+	var owner string
+	if o.Owner != nil {
+		owner = o.Owner.Entity
+	}
+
+	return &gcs.Object{
+		Name:               o.Name,
+		ContentType:        o.ContentType,
+		ContentLanguage:    o.ContentLanguage,
+		CacheControl:       o.CacheControl,
+		Owner:              owner,
+		Size:               o.Size,
+		ContentEncoding:    o.ContentEncoding,
+		MD5:                md5Hash,
+		CRC32C:             crc,
+		MediaLink:          o.MediaLink,
+		Metadata:           o.Metadata,
+		Generation:         o.Generation,
+		MetaGeneration:     o.Metageneration,
+		StorageClass:       o.StorageClass,
+		Deleted:            deleted,
+		Updated:            updated,
+		Finalized:          finalized,
+		ComponentCount:     o.ComponentCount,
+		ContentDisposition: o.ContentDisposition,
+		CustomTime:         o.CustomTime,
+		EventBasedHold:     o.EventBasedHold,
+		Acl:                o.Acl,
+	}, nil
+}
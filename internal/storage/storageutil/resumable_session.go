@@ -0,0 +1,56 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storageutil
+
+import (
+	"context"
+	"net/http"
+)
+
+type resumableSessionKey struct{}
+
+// WithResumableSessionCallback returns a context that makes the HTTP client
+// created by CreateHttpClient call f with the URI of each resumable upload
+// session it starts on behalf of requests using the context. The storage
+// client doesn't expose the URI, which is needed to query or cancel the
+// session from another process.
+func WithResumableSessionCallback(ctx context.Context, f func(uri string)) context.Context {
+	return context.WithValue(ctx, resumableSessionKey{}, f)
+}
+
+// resumableSessionRoundTripper reports the URIs of the resumable upload
+// sessions started with a context set up by WithResumableSessionCallback.
+type resumableSessionRoundTripper struct {
+	wrapped http.RoundTripper
+}
+
+func (rs *resumableSessionRoundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	wrapped := rs.wrapped
+	if wrapped == nil {
+		wrapped = http.DefaultTransport
+	}
+	resp, err := wrapped.RoundTrip(r)
+
+	f, ok := r.Context().Value(resumableSessionKey{}).(func(string))
+	if !ok || err != nil || r.Method != http.MethodPost || r.URL.Query().Get("uploadType") != "resumable" {
+		return resp, err
+	}
+	if resp.StatusCode == http.StatusOK {
+		if uri := resp.Header.Get("Location"); uri != "" {
+			f(uri)
+		}
+	}
+	return resp, err
+}
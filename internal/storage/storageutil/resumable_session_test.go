@@ -0,0 +1,54 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storageutil
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResumableSessionRoundTripperReportsSessionURIs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Location", "https://storage.googleapis.com/upload/session")
+	}))
+	defer server.Close()
+	client := &http.Client{Transport: &resumableSessionRoundTripper{}}
+	var uris []string
+	ctx := WithResumableSessionCallback(context.Background(), func(uri string) { uris = append(uris, uri) })
+
+	for _, url := range []string{
+		server.URL + "/upload?uploadType=resumable",
+		// Not resumable upload session starts.
+		server.URL + "/upload?uploadType=multipart",
+		server.URL + "/o",
+	} {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
+		require.NoError(t, err)
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+	// Without a callback.
+	resp, err := client.Post(server.URL+"/upload?uploadType=resumable", "", nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, []string{"https://storage.googleapis.com/upload/session"}, uris)
+}
@@ -20,6 +20,23 @@ import (
 	"time"
 )
 
+// CacheName is a custom type for the cache_name attribute.
+type CacheName string
+
+const (
+	CacheNameFileAttr CacheName = "file"
+	CacheNameStatAttr CacheName = "stat"
+)
+
+// EvictionPolicy is a custom type for the eviction_policy attribute.
+type EvictionPolicy string
+
+const (
+	EvictionPolicy2qAttr      EvictionPolicy = "2q"
+	EvictionPolicyLruAttr     EvictionPolicy = "lru"
+	EvictionPolicyTinylfuAttr EvictionPolicy = "tinylfu"
+)
+
 // FsErrorCategory is a custom type for the fs_error_category attribute.
 type FsErrorCategory string
 
@@ -107,6 +124,15 @@ const (
 	IoMethodOpenedAttr     IoMethod = "opened"
 )
 
+// Pressure is a custom type for the pressure attribute.
+type Pressure string
+
+const (
+	PressureCriticalAttr Pressure = "critical"
+	PressureModerateAttr Pressure = "moderate"
+	PressureNoneAttr     Pressure = "none"
+)
+
 // ReadType is a custom type for the read_type attribute.
 type ReadType string
 
@@ -150,6 +176,15 @@ const (
 	RetryErrorCategorySTALLEDREADREQUESTAttr RetryErrorCategory = "STALLED_READ_REQUEST"
 )
 
+// Status is a custom type for the status attribute.
+type Status string
+
+const (
+	StatusCompletedAttr Status = "completed"
+	StatusExcludedAttr  Status = "excluded"
+	StatusFailedAttr    Status = "failed"
+)
+
 // MetricHandle provides an interface for recording metrics.
 // The methods of this interface are auto-generated from metrics.yaml.
 // Each method corresponds to a metric defined in metrics.yaml.
@@ -160,6 +195,18 @@ type MetricHandle interface {
 	// BufferedReadReadLatency - The cumulative distribution of latencies for ReadAt calls served by the buffered reader.
 	BufferedReadReadLatency(ctx context.Context, latency time.Duration)
 
+	// CacheEvictionCount - The cumulative number of entries evicted from a cache to make room for new ones, along with the cache and its eviction policy.
+	CacheEvictionCount(inc int64, cacheName CacheName, evictionPolicy EvictionPolicy)
+
+	// FileCachePrefetchBytesCount - The cumulative number of bytes of the objects prefetched into the file cache.
+	FileCachePrefetchBytesCount(inc int64)
+
+	// FileCachePrefetchCount - The cumulative number of objects prefetched into the file cache along with the status - completed/excluded/failed.
+	FileCachePrefetchCount(inc int64, status Status)
+
+	// FileCachePrefetchPendingCount - The number of objects waiting to be prefetched into the file cache.
+	FileCachePrefetchPendingCount(inc int64)
+
 	// FileCacheReadBytesCount - The cumulative number of bytes read from file cache along with read type - Sequential/Random
 	FileCacheReadBytesCount(inc int64, readType ReadType)
 
@@ -199,6 +246,9 @@ type MetricHandle interface {
 	// GcsRetryCount - The cumulative number of retry requests made to GCS.
 	GcsRetryCount(inc int64, retryErrorCategory RetryErrorCategory)
 
+	// MemoryPressureState - Whether the memory pressure of the cgroup of gcsfuse is at each level - none/moderate/critical: 1 for the current level and 0 for the others.
+	MemoryPressureState(inc int64, pressure Pressure)
+
 	// TestUpdownCounter - Test metric for updown counters.
 	TestUpdownCounter(inc int64)
 
@@ -26,6 +26,15 @@ func (*noopMetrics) BufferedReadFallbackTriggerCount(inc int64, reason Reason) {
 
 func (*noopMetrics) BufferedReadReadLatency(ctx context.Context, latency time.Duration) {}
 
+func (*noopMetrics) CacheEvictionCount(inc int64, cacheName CacheName, evictionPolicy EvictionPolicy) {
+}
+
+func (*noopMetrics) FileCachePrefetchBytesCount(inc int64) {}
+
+func (*noopMetrics) FileCachePrefetchCount(inc int64, status Status) {}
+
+func (*noopMetrics) FileCachePrefetchPendingCount(inc int64) {}
+
 func (*noopMetrics) FileCacheReadBytesCount(inc int64, readType ReadType) {}
 
 func (*noopMetrics) FileCacheReadCount(inc int64, cacheHit bool, readType ReadType) {}
@@ -54,6 +63,8 @@ func (*noopMetrics) GcsRequestLatencies(ctx context.Context, latency time.Durati
 
 func (*noopMetrics) GcsRetryCount(inc int64, retryErrorCategory RetryErrorCategory) {}
 
+func (*noopMetrics) MemoryPressureState(inc int64, pressure Pressure) {}
+
 func (*noopMetrics) TestUpdownCounter(inc int64) {}
 
 func (*noopMetrics) TestUpdownCounterWithAttrs(inc int64, requestType RequestType) {}
@@ -35,6 +35,15 @@ var (
 	unrecognizedAttr                                                                    atomic.Value
 	bufferedReadFallbackTriggerCountReasonInsufficientMemoryAttrSet                     = metric.WithAttributeSet(attribute.NewSet(attribute.String("reason", "insufficient_memory")))
 	bufferedReadFallbackTriggerCountReasonRandomReadDetectedAttrSet                     = metric.WithAttributeSet(attribute.NewSet(attribute.String("reason", "random_read_detected")))
+	cacheEvictionCountCacheNameFileEvictionPolicy2qAttrSet                              = metric.WithAttributeSet(attribute.NewSet(attribute.String("cache_name", "file"), attribute.String("eviction_policy", "2q")))
+	cacheEvictionCountCacheNameFileEvictionPolicyLruAttrSet                             = metric.WithAttributeSet(attribute.NewSet(attribute.String("cache_name", "file"), attribute.String("eviction_policy", "lru")))
+	cacheEvictionCountCacheNameFileEvictionPolicyTinylfuAttrSet                         = metric.WithAttributeSet(attribute.NewSet(attribute.String("cache_name", "file"), attribute.String("eviction_policy", "tinylfu")))
+	cacheEvictionCountCacheNameStatEvictionPolicy2qAttrSet                              = metric.WithAttributeSet(attribute.NewSet(attribute.String("cache_name", "stat"), attribute.String("eviction_policy", "2q")))
+	cacheEvictionCountCacheNameStatEvictionPolicyLruAttrSet                             = metric.WithAttributeSet(attribute.NewSet(attribute.String("cache_name", "stat"), attribute.String("eviction_policy", "lru")))
+	cacheEvictionCountCacheNameStatEvictionPolicyTinylfuAttrSet                         = metric.WithAttributeSet(attribute.NewSet(attribute.String("cache_name", "stat"), attribute.String("eviction_policy", "tinylfu")))
+	fileCachePrefetchCountStatusCompletedAttrSet                                        = metric.WithAttributeSet(attribute.NewSet(attribute.String("status", "completed")))
+	fileCachePrefetchCountStatusExcludedAttrSet                                         = metric.WithAttributeSet(attribute.NewSet(attribute.String("status", "excluded")))
+	fileCachePrefetchCountStatusFailedAttrSet                                           = metric.WithAttributeSet(attribute.NewSet(attribute.String("status", "failed")))
 	fileCacheReadBytesCountReadTypeParallelAttrSet                                      = metric.WithAttributeSet(attribute.NewSet(attribute.String("read_type", "Parallel")))
 	fileCacheReadBytesCountReadTypeRandomAttrSet                                        = metric.WithAttributeSet(attribute.NewSet(attribute.String("read_type", "Random")))
 	fileCacheReadBytesCountReadTypeSequentialAttrSet                                    = metric.WithAttributeSet(attribute.NewSet(attribute.String("read_type", "Sequential")))
@@ -552,6 +561,9 @@ var (
 	gcsRequestLatenciesGcsMethodUpdateObjectAttrSet                                     = metric.WithAttributeSet(attribute.NewSet(attribute.String("gcs_method", "UpdateObject")))
 	gcsRetryCountRetryErrorCategoryOTHERERRORSAttrSet                                   = metric.WithAttributeSet(attribute.NewSet(attribute.String("retry_error_category", "OTHER_ERRORS")))
 	gcsRetryCountRetryErrorCategorySTALLEDREADREQUESTAttrSet                            = metric.WithAttributeSet(attribute.NewSet(attribute.String("retry_error_category", "STALLED_READ_REQUEST")))
+	memoryPressureStatePressureCriticalAttrSet                                          = metric.WithAttributeSet(attribute.NewSet(attribute.String("pressure", "critical")))
+	memoryPressureStatePressureModerateAttrSet                                          = metric.WithAttributeSet(attribute.NewSet(attribute.String("pressure", "moderate")))
+	memoryPressureStatePressureNoneAttrSet                                              = metric.WithAttributeSet(attribute.NewSet(attribute.String("pressure", "none")))
 	testUpdownCounterWithAttrsRequestTypeAttr1AttrSet                                   = metric.WithAttributeSet(attribute.NewSet(attribute.String("request_type", "attr1")))
 	testUpdownCounterWithAttrsRequestTypeAttr2AttrSet                                   = metric.WithAttributeSet(attribute.NewSet(attribute.String("request_type", "attr2")))
 )
@@ -568,6 +580,17 @@ type otelMetrics struct {
 	wg                                                                                 *sync.WaitGroup
 	bufferedReadFallbackTriggerCountReasonInsufficientMemoryAtomic                     *atomic.Int64
 	bufferedReadFallbackTriggerCountReasonRandomReadDetectedAtomic                     *atomic.Int64
+	cacheEvictionCountCacheNameFileEvictionPolicy2qAtomic                              *atomic.Int64
+	cacheEvictionCountCacheNameFileEvictionPolicyLruAtomic                             *atomic.Int64
+	cacheEvictionCountCacheNameFileEvictionPolicyTinylfuAtomic                         *atomic.Int64
+	cacheEvictionCountCacheNameStatEvictionPolicy2qAtomic                              *atomic.Int64
+	cacheEvictionCountCacheNameStatEvictionPolicyLruAtomic                             *atomic.Int64
+	cacheEvictionCountCacheNameStatEvictionPolicyTinylfuAtomic                         *atomic.Int64
+	fileCachePrefetchBytesCountAtomic                                                  *atomic.Int64
+	fileCachePrefetchCountStatusCompletedAtomic                                        *atomic.Int64
+	fileCachePrefetchCountStatusExcludedAtomic                                         *atomic.Int64
+	fileCachePrefetchCountStatusFailedAtomic                                           *atomic.Int64
+	fileCachePrefetchPendingCountAtomic                                                *atomic.Int64
 	fileCacheReadBytesCountReadTypeParallelAtomic                                      *atomic.Int64
 	fileCacheReadBytesCountReadTypeRandomAtomic                                        *atomic.Int64
 	fileCacheReadBytesCountReadTypeSequentialAtomic                                    *atomic.Int64
@@ -1039,6 +1062,9 @@ type otelMetrics struct {
 	gcsRequestCountGcsMethodUpdateObjectAtomic                                         *atomic.Int64
 	gcsRetryCountRetryErrorCategoryOTHERERRORSAtomic                                   *atomic.Int64
 	gcsRetryCountRetryErrorCategorySTALLEDREADREQUESTAtomic                            *atomic.Int64
+	memoryPressureStatePressureCriticalAtomic                                          *atomic.Int64
+	memoryPressureStatePressureModerateAtomic                                          *atomic.Int64
+	memoryPressureStatePressureNoneAtomic                                              *atomic.Int64
 	testUpdownCounterAtomic                                                            *atomic.Int64
 	testUpdownCounterWithAttrsRequestTypeAttr1Atomic                                   *atomic.Int64
 	testUpdownCounterWithAttrsRequestTypeAttr2Atomic                                   *atomic.Int64
@@ -1076,6 +1102,76 @@ func (o *otelMetrics) BufferedReadReadLatency(
 	}
 }
 
+func (o *otelMetrics) CacheEvictionCount(
+	inc int64, cacheName CacheName, evictionPolicy EvictionPolicy) {
+	if inc < 0 {
+		logger.Errorf("Counter metric cache/eviction_count received a negative increment: %d", inc)
+		return
+	}
+	switch cacheName {
+	case CacheNameFileAttr:
+		switch evictionPolicy {
+		case EvictionPolicy2qAttr:
+			o.cacheEvictionCountCacheNameFileEvictionPolicy2qAtomic.Add(inc)
+		case EvictionPolicyLruAttr:
+			o.cacheEvictionCountCacheNameFileEvictionPolicyLruAtomic.Add(inc)
+		case EvictionPolicyTinylfuAttr:
+			o.cacheEvictionCountCacheNameFileEvictionPolicyTinylfuAtomic.Add(inc)
+		default:
+			updateUnrecognizedAttribute(string(evictionPolicy))
+			return
+		}
+	case CacheNameStatAttr:
+		switch evictionPolicy {
+		case EvictionPolicy2qAttr:
+			o.cacheEvictionCountCacheNameStatEvictionPolicy2qAtomic.Add(inc)
+		case EvictionPolicyLruAttr:
+			o.cacheEvictionCountCacheNameStatEvictionPolicyLruAtomic.Add(inc)
+		case EvictionPolicyTinylfuAttr:
+			o.cacheEvictionCountCacheNameStatEvictionPolicyTinylfuAtomic.Add(inc)
+		default:
+			updateUnrecognizedAttribute(string(evictionPolicy))
+			return
+		}
+	default:
+		updateUnrecognizedAttribute(string(cacheName))
+		return
+	}
+}
+
+func (o *otelMetrics) FileCachePrefetchBytesCount(
+	inc int64) {
+	if inc < 0 {
+		logger.Errorf("Counter metric file_cache/prefetch_bytes_count received a negative increment: %d", inc)
+		return
+	}
+	o.fileCachePrefetchBytesCountAtomic.Add(inc)
+}
+
+func (o *otelMetrics) FileCachePrefetchCount(
+	inc int64, status Status) {
+	if inc < 0 {
+		logger.Errorf("Counter metric file_cache/prefetch_count received a negative increment: %d", inc)
+		return
+	}
+	switch status {
+	case StatusCompletedAttr:
+		o.fileCachePrefetchCountStatusCompletedAtomic.Add(inc)
+	case StatusExcludedAttr:
+		o.fileCachePrefetchCountStatusExcludedAtomic.Add(inc)
+	case StatusFailedAttr:
+		o.fileCachePrefetchCountStatusFailedAtomic.Add(inc)
+	default:
+		updateUnrecognizedAttribute(string(status))
+		return
+	}
+}
+
+func (o *otelMetrics) FileCachePrefetchPendingCount(
+	inc int64) {
+	o.fileCachePrefetchPendingCountAtomic.Add(inc)
+}
+
 func (o *otelMetrics) FileCacheReadBytesCount(
 	inc int64, readType ReadType) {
 	if inc < 0 {
@@ -2387,6 +2483,21 @@ func (o *otelMetrics) GcsRetryCount(
 	}
 }
 
+func (o *otelMetrics) MemoryPressureState(
+	inc int64, pressure Pressure) {
+	switch pressure {
+	case PressureCriticalAttr:
+		o.memoryPressureStatePressureCriticalAtomic.Add(inc)
+	case PressureModerateAttr:
+		o.memoryPressureStatePressureModerateAtomic.Add(inc)
+	case PressureNoneAttr:
+		o.memoryPressureStatePressureNoneAtomic.Add(inc)
+	default:
+		updateUnrecognizedAttribute(string(pressure))
+		return
+	}
+}
+
 func (o *otelMetrics) TestUpdownCounter(
 	inc int64) {
 	o.testUpdownCounterAtomic.Add(inc)
@@ -2426,6 +2537,21 @@ func NewOTelMetrics(ctx context.Context, workers int, bufferSize int) (*otelMetr
 	var bufferedReadFallbackTriggerCountReasonInsufficientMemoryAtomic,
 		bufferedReadFallbackTriggerCountReasonRandomReadDetectedAtomic atomic.Int64
 
+	var cacheEvictionCountCacheNameFileEvictionPolicy2qAtomic,
+		cacheEvictionCountCacheNameFileEvictionPolicyLruAtomic,
+		cacheEvictionCountCacheNameFileEvictionPolicyTinylfuAtomic,
+		cacheEvictionCountCacheNameStatEvictionPolicy2qAtomic,
+		cacheEvictionCountCacheNameStatEvictionPolicyLruAtomic,
+		cacheEvictionCountCacheNameStatEvictionPolicyTinylfuAtomic atomic.Int64
+
+	var fileCachePrefetchBytesCountAtomic atomic.Int64
+
+	var fileCachePrefetchCountStatusCompletedAtomic,
+		fileCachePrefetchCountStatusExcludedAtomic,
+		fileCachePrefetchCountStatusFailedAtomic atomic.Int64
+
+	var fileCachePrefetchPendingCountAtomic atomic.Int64
+
 	var fileCacheReadBytesCountReadTypeParallelAtomic,
 		fileCacheReadBytesCountReadTypeRandomAtomic,
 		fileCacheReadBytesCountReadTypeSequentialAtomic,
@@ -2907,6 +3033,10 @@ func NewOTelMetrics(ctx context.Context, workers int, bufferSize int) (*otelMetr
 	var gcsRetryCountRetryErrorCategoryOTHERERRORSAtomic,
 		gcsRetryCountRetryErrorCategorySTALLEDREADREQUESTAtomic atomic.Int64
 
+	var memoryPressureStatePressureCriticalAtomic,
+		memoryPressureStatePressureModerateAtomic,
+		memoryPressureStatePressureNoneAtomic atomic.Int64
+
 	var testUpdownCounterAtomic atomic.Int64
 
 	var testUpdownCounterWithAttrsRequestTypeAttr1Atomic,
@@ -2926,7 +3056,46 @@ func NewOTelMetrics(ctx context.Context, workers int, bufferSize int) (*otelMetr
 		metric.WithUnit("us"),
 		metric.WithExplicitBucketBoundaries(1, 2, 3, 4, 5, 6, 8, 10, 13, 16, 20, 25, 30, 40, 50, 65, 80, 100, 130, 160, 200, 250, 300, 400, 500, 650, 800, 1000, 2000, 5000, 10000, 20000, 50000, 100000))
 
-	_, err2 := meter.Int64ObservableCounter("file_cache/read_bytes_count",
+	_, err2 := meter.Int64ObservableCounter("cache/eviction_count",
+		metric.WithDescription("The cumulative number of entries evicted from a cache to make room for new ones, along with the cache and its eviction policy."),
+		metric.WithUnit(""),
+		metric.WithInt64Callback(func(_ context.Context, obsrv metric.Int64Observer) error {
+			conditionallyObserve(obsrv, &cacheEvictionCountCacheNameFileEvictionPolicy2qAtomic, cacheEvictionCountCacheNameFileEvictionPolicy2qAttrSet)
+			conditionallyObserve(obsrv, &cacheEvictionCountCacheNameFileEvictionPolicyLruAtomic, cacheEvictionCountCacheNameFileEvictionPolicyLruAttrSet)
+			conditionallyObserve(obsrv, &cacheEvictionCountCacheNameFileEvictionPolicyTinylfuAtomic, cacheEvictionCountCacheNameFileEvictionPolicyTinylfuAttrSet)
+			conditionallyObserve(obsrv, &cacheEvictionCountCacheNameStatEvictionPolicy2qAtomic, cacheEvictionCountCacheNameStatEvictionPolicy2qAttrSet)
+			conditionallyObserve(obsrv, &cacheEvictionCountCacheNameStatEvictionPolicyLruAtomic, cacheEvictionCountCacheNameStatEvictionPolicyLruAttrSet)
+			conditionallyObserve(obsrv, &cacheEvictionCountCacheNameStatEvictionPolicyTinylfuAtomic, cacheEvictionCountCacheNameStatEvictionPolicyTinylfuAttrSet)
+			return nil
+		}))
+
+	_, err3 := meter.Int64ObservableCounter("file_cache/prefetch_bytes_count",
+		metric.WithDescription("The cumulative number of bytes of the objects prefetched into the file cache."),
+		metric.WithUnit("By"),
+		metric.WithInt64Callback(func(_ context.Context, obsrv metric.Int64Observer) error {
+			conditionallyObserve(obsrv, &fileCachePrefetchBytesCountAtomic)
+			return nil
+		}))
+
+	_, err4 := meter.Int64ObservableCounter("file_cache/prefetch_count",
+		metric.WithDescription("The cumulative number of objects prefetched into the file cache along with the status - completed/excluded/failed."),
+		metric.WithUnit(""),
+		metric.WithInt64Callback(func(_ context.Context, obsrv metric.Int64Observer) error {
+			conditionallyObserve(obsrv, &fileCachePrefetchCountStatusCompletedAtomic, fileCachePrefetchCountStatusCompletedAttrSet)
+			conditionallyObserve(obsrv, &fileCachePrefetchCountStatusExcludedAtomic, fileCachePrefetchCountStatusExcludedAttrSet)
+			conditionallyObserve(obsrv, &fileCachePrefetchCountStatusFailedAtomic, fileCachePrefetchCountStatusFailedAttrSet)
+			return nil
+		}))
+
+	_, err5 := meter.Int64ObservableUpDownCounter("file_cache/prefetch_pending_count",
+		metric.WithDescription("The number of objects waiting to be prefetched into the file cache."),
+		metric.WithUnit(""),
+		metric.WithInt64Callback(func(_ context.Context, obsrv metric.Int64Observer) error {
+			observeUpDownCounter(obsrv, &fileCachePrefetchPendingCountAtomic)
+			return nil
+		}))
+
+	_, err6 := meter.Int64ObservableCounter("file_cache/read_bytes_count",
 		metric.WithDescription("The cumulative number of bytes read from file cache along with read type - Sequential/Random"),
 		metric.WithUnit("By"),
 		metric.WithInt64Callback(func(_ context.Context, obsrv metric.Int64Observer) error {
@@ -2937,7 +3106,7 @@ func NewOTelMetrics(ctx context.Context, workers int, bufferSize int) (*otelMetr
 			return nil
 		}))
 
-	_, err3 := meter.Int64ObservableCounter("file_cache/read_count",
+	_, err7 := meter.Int64ObservableCounter("file_cache/read_count",
 		metric.WithDescription("Specifies the number of read requests made via file cache along with type - Sequential/Random and cache hit - true/false"),
 		metric.WithUnit(""),
 		metric.WithInt64Callback(func(_ context.Context, obsrv metric.Int64Observer) error {
@@ -2952,12 +3121,12 @@ func NewOTelMetrics(ctx context.Context, workers int, bufferSize int) (*otelMetr
 			return nil
 		}))
 
-	fileCacheReadLatencies, err4 := meter.Int64Histogram("file_cache/read_latencies",
+	fileCacheReadLatencies, err8 := meter.Int64Histogram("file_cache/read_latencies",
 		metric.WithDescription("The cumulative distribution of the file cache read latencies along with cache hit - true/false."),
 		metric.WithUnit("us"),
 		metric.WithExplicitBucketBoundaries(1, 2, 3, 4, 5, 6, 8, 10, 13, 16, 20, 25, 30, 40, 50, 65, 80, 100, 130, 160, 200, 250, 300, 400, 500, 650, 800, 1000, 2000, 5000, 10000, 20000, 50000, 100000))
 
-	_, err5 := meter.Int64ObservableCounter("fs/ops_count",
+	_, err9 := meter.Int64ObservableCounter("fs/ops_count",
 		metric.WithDescription("The cumulative number of ops processed by the file system."),
 		metric.WithUnit(""),
 		metric.WithInt64Callback(func(_ context.Context, obsrv metric.Int64Observer) error {
@@ -2989,7 +3158,7 @@ func NewOTelMetrics(ctx context.Context, workers int, bufferSize int) (*otelMetr
 			return nil
 		}))
 
-	_, err6 := meter.Int64ObservableCounter("fs/ops_error_count",
+	_, err10 := meter.Int64ObservableCounter("fs/ops_error_count",
 		metric.WithDescription("The cumulative number of errors generated by file system operations."),
 		metric.WithUnit(""),
 		metric.WithInt64Callback(func(_ context.Context, obsrv metric.Int64Observer) error {
@@ -3396,12 +3565,12 @@ func NewOTelMetrics(ctx context.Context, workers int, bufferSize int) (*otelMetr
 			return nil
 		}))
 
-	fsOpsLatency, err7 := meter.Int64Histogram("fs/ops_latency",
+	fsOpsLatency, err11 := meter.Int64Histogram("fs/ops_latency",
 		metric.WithDescription("The cumulative distribution of file system operation latencies"),
 		metric.WithUnit("us"),
 		metric.WithExplicitBucketBoundaries(1, 2, 3, 4, 5, 6, 8, 10, 13, 16, 20, 25, 30, 40, 50, 65, 80, 100, 130, 160, 200, 250, 300, 400, 500, 650, 800, 1000, 2000, 5000, 10000, 20000, 50000, 100000))
 
-	_, err8 := meter.Int64ObservableCounter("gcs/download_bytes_count",
+	_, err12 := meter.Int64ObservableCounter("gcs/download_bytes_count",
 		metric.WithDescription("The cumulative number of bytes downloaded from GCS along with type - Sequential/Random"),
 		metric.WithUnit("By"),
 		metric.WithInt64Callback(func(_ context.Context, obsrv metric.Int64Observer) error {
@@ -3412,7 +3581,7 @@ func NewOTelMetrics(ctx context.Context, workers int, bufferSize int) (*otelMetr
 			return nil
 		}))
 
-	_, err9 := meter.Int64ObservableCounter("gcs/read_bytes_count",
+	_, err13 := meter.Int64ObservableCounter("gcs/read_bytes_count",
 		metric.WithDescription("The cumulative number of bytes read from GCS objects."),
 		metric.WithUnit("By"),
 		metric.WithInt64Callback(func(_ context.Context, obsrv metric.Int64Observer) error {
@@ -3421,7 +3590,7 @@ func NewOTelMetrics(ctx context.Context, workers int, bufferSize int) (*otelMetr
 			return nil
 		}))
 
-	_, err10 := meter.Int64ObservableCounter("gcs/read_count",
+	_, err14 := meter.Int64ObservableCounter("gcs/read_count",
 		metric.WithDescription("Specifies the number of gcs reads made along with type - Sequential/Random"),
 		metric.WithUnit(""),
 		metric.WithInt64Callback(func(_ context.Context, obsrv metric.Int64Observer) error {
@@ -3432,7 +3601,7 @@ func NewOTelMetrics(ctx context.Context, workers int, bufferSize int) (*otelMetr
 			return nil
 		}))
 
-	_, err11 := meter.Int64ObservableCounter("gcs/reader_count",
+	_, err15 := meter.Int64ObservableCounter("gcs/reader_count",
 		metric.WithDescription("The cumulative number of GCS object readers opened or closed."),
 		metric.WithUnit(""),
 		metric.WithInt64Callback(func(_ context.Context, obsrv metric.Int64Observer) error {
@@ -3442,7 +3611,7 @@ func NewOTelMetrics(ctx context.Context, workers int, bufferSize int) (*otelMetr
 			return nil
 		}))
 
-	_, err12 := meter.Int64ObservableCounter("gcs/request_count",
+	_, err16 := meter.Int64ObservableCounter("gcs/request_count",
 		metric.WithDescription("The cumulative number of GCS requests processed along with the GCS method."),
 		metric.WithUnit(""),
 		metric.WithInt64Callback(func(_ context.Context, obsrv metric.Int64Observer) error {
@@ -3468,12 +3637,12 @@ func NewOTelMetrics(ctx context.Context, workers int, bufferSize int) (*otelMetr
 			return nil
 		}))
 
-	gcsRequestLatencies, err13 := meter.Int64Histogram("gcs/request_latencies",
+	gcsRequestLatencies, err17 := meter.Int64Histogram("gcs/request_latencies",
 		metric.WithDescription("The cumulative distribution of the GCS request latencies."),
 		metric.WithUnit("ms"),
 		metric.WithExplicitBucketBoundaries(1, 2, 3, 4, 5, 6, 8, 10, 13, 16, 20, 25, 30, 40, 50, 65, 80, 100, 130, 160, 200, 250, 300, 400, 500, 650, 800, 1000, 2000, 5000, 10000, 20000, 50000, 100000))
 
-	_, err14 := meter.Int64ObservableCounter("gcs/retry_count",
+	_, err18 := meter.Int64ObservableCounter("gcs/retry_count",
 		metric.WithDescription("The cumulative number of retry requests made to GCS."),
 		metric.WithUnit(""),
 		metric.WithInt64Callback(func(_ context.Context, obsrv metric.Int64Observer) error {
@@ -3482,7 +3651,17 @@ func NewOTelMetrics(ctx context.Context, workers int, bufferSize int) (*otelMetr
 			return nil
 		}))
 
-	_, err15 := meter.Int64ObservableUpDownCounter("test/updown_counter",
+	_, err19 := meter.Int64ObservableUpDownCounter("memory/pressure_state",
+		metric.WithDescription("Whether the memory pressure of the cgroup of gcsfuse is at each level - none/moderate/critical: 1 for the current level and 0 for the others."),
+		metric.WithUnit(""),
+		metric.WithInt64Callback(func(_ context.Context, obsrv metric.Int64Observer) error {
+			observeUpDownCounter(obsrv, &memoryPressureStatePressureCriticalAtomic, memoryPressureStatePressureCriticalAttrSet)
+			observeUpDownCounter(obsrv, &memoryPressureStatePressureModerateAtomic, memoryPressureStatePressureModerateAttrSet)
+			observeUpDownCounter(obsrv, &memoryPressureStatePressureNoneAtomic, memoryPressureStatePressureNoneAttrSet)
+			return nil
+		}))
+
+	_, err20 := meter.Int64ObservableUpDownCounter("test/updown_counter",
 		metric.WithDescription("Test metric for updown counters."),
 		metric.WithUnit(""),
 		metric.WithInt64Callback(func(_ context.Context, obsrv metric.Int64Observer) error {
@@ -3490,7 +3669,7 @@ func NewOTelMetrics(ctx context.Context, workers int, bufferSize int) (*otelMetr
 			return nil
 		}))
 
-	_, err16 := meter.Int64ObservableUpDownCounter("test/updown_counter_with_attrs",
+	_, err21 := meter.Int64ObservableUpDownCounter("test/updown_counter_with_attrs",
 		metric.WithDescription("Test metric for updown counters with attributes."),
 		metric.WithUnit(""),
 		metric.WithInt64Callback(func(_ context.Context, obsrv metric.Int64Observer) error {
@@ -3499,7 +3678,7 @@ func NewOTelMetrics(ctx context.Context, workers int, bufferSize int) (*otelMetr
 			return nil
 		}))
 
-	errs := []error{err0, err1, err2, err3, err4, err5, err6, err7, err8, err9, err10, err11, err12, err13, err14, err15, err16}
+	errs := []error{err0, err1, err2, err3, err4, err5, err6, err7, err8, err9, err10, err11, err12, err13, err14, err15, err16, err17, err18, err19, err20, err21}
 	if err := errors.Join(errs...); err != nil {
 		return nil, err
 	}
@@ -3510,6 +3689,17 @@ func NewOTelMetrics(ctx context.Context, workers int, bufferSize int) (*otelMetr
 		bufferedReadFallbackTriggerCountReasonInsufficientMemoryAtomic: &bufferedReadFallbackTriggerCountReasonInsufficientMemoryAtomic,
 		bufferedReadFallbackTriggerCountReasonRandomReadDetectedAtomic: &bufferedReadFallbackTriggerCountReasonRandomReadDetectedAtomic,
 		bufferedReadReadLatency:                                                            bufferedReadReadLatency,
+		cacheEvictionCountCacheNameFileEvictionPolicy2qAtomic:                              &cacheEvictionCountCacheNameFileEvictionPolicy2qAtomic,
+		cacheEvictionCountCacheNameFileEvictionPolicyLruAtomic:                             &cacheEvictionCountCacheNameFileEvictionPolicyLruAtomic,
+		cacheEvictionCountCacheNameFileEvictionPolicyTinylfuAtomic:                         &cacheEvictionCountCacheNameFileEvictionPolicyTinylfuAtomic,
+		cacheEvictionCountCacheNameStatEvictionPolicy2qAtomic:                              &cacheEvictionCountCacheNameStatEvictionPolicy2qAtomic,
+		cacheEvictionCountCacheNameStatEvictionPolicyLruAtomic:                             &cacheEvictionCountCacheNameStatEvictionPolicyLruAtomic,
+		cacheEvictionCountCacheNameStatEvictionPolicyTinylfuAtomic:                         &cacheEvictionCountCacheNameStatEvictionPolicyTinylfuAtomic,
+		fileCachePrefetchBytesCountAtomic:                                                  &fileCachePrefetchBytesCountAtomic,
+		fileCachePrefetchCountStatusCompletedAtomic:                                        &fileCachePrefetchCountStatusCompletedAtomic,
+		fileCachePrefetchCountStatusExcludedAtomic:                                         &fileCachePrefetchCountStatusExcludedAtomic,
+		fileCachePrefetchCountStatusFailedAtomic:                                           &fileCachePrefetchCountStatusFailedAtomic,
+		fileCachePrefetchPendingCountAtomic:                                                &fileCachePrefetchPendingCountAtomic,
 		fileCacheReadBytesCountReadTypeParallelAtomic:                                      &fileCacheReadBytesCountReadTypeParallelAtomic,
 		fileCacheReadBytesCountReadTypeRandomAtomic:                                        &fileCacheReadBytesCountReadTypeRandomAtomic,
 		fileCacheReadBytesCountReadTypeSequentialAtomic:                                    &fileCacheReadBytesCountReadTypeSequentialAtomic,
@@ -3984,6 +4174,9 @@ func NewOTelMetrics(ctx context.Context, workers int, bufferSize int) (*otelMetr
 		gcsRequestLatencies:                                        gcsRequestLatencies,
 		gcsRetryCountRetryErrorCategoryOTHERERRORSAtomic:           &gcsRetryCountRetryErrorCategoryOTHERERRORSAtomic,
 		gcsRetryCountRetryErrorCategorySTALLEDREADREQUESTAtomic:    &gcsRetryCountRetryErrorCategorySTALLEDREADREQUESTAtomic,
+		memoryPressureStatePressureCriticalAtomic:                  &memoryPressureStatePressureCriticalAtomic,
+		memoryPressureStatePressureModerateAtomic:                  &memoryPressureStatePressureModerateAtomic,
+		memoryPressureStatePressureNoneAtomic:                      &memoryPressureStatePressureNoneAtomic,
 		testUpdownCounterAtomic:                                    &testUpdownCounterAtomic,
 		testUpdownCounterWithAttrsRequestTypeAttr1Atomic:           &testUpdownCounterWithAttrsRequestTypeAttr1Atomic,
 		testUpdownCounterWithAttrsRequestTypeAttr2Atomic:           &testUpdownCounterWithAttrsRequestTypeAttr2Atomic,
@@ -222,6 +222,239 @@ func TestBufferedReadReadLatency(t *testing.T) {
 	assert.Equal(t, totalLatency.Microseconds(), dp.Sum)
 }
 
+func TestCacheEvictionCount(t *testing.T) {
+	tests := []struct {
+		name     string
+		f        func(m *otelMetrics)
+		expected map[attribute.Set]int64
+	}{
+		{
+			name: "cache_name_file_eviction_policy_2q",
+			f: func(m *otelMetrics) {
+				m.CacheEvictionCount(5, "file", "2q")
+			},
+			expected: map[attribute.Set]int64{
+				attribute.NewSet(attribute.String("cache_name", "file"), attribute.String("eviction_policy", "2q")): 5,
+			},
+		},
+		{
+			name: "cache_name_file_eviction_policy_lru",
+			f: func(m *otelMetrics) {
+				m.CacheEvictionCount(5, "file", "lru")
+			},
+			expected: map[attribute.Set]int64{
+				attribute.NewSet(attribute.String("cache_name", "file"), attribute.String("eviction_policy", "lru")): 5,
+			},
+		},
+		{
+			name: "cache_name_file_eviction_policy_tinylfu",
+			f: func(m *otelMetrics) {
+				m.CacheEvictionCount(5, "file", "tinylfu")
+			},
+			expected: map[attribute.Set]int64{
+				attribute.NewSet(attribute.String("cache_name", "file"), attribute.String("eviction_policy", "tinylfu")): 5,
+			},
+		},
+		{
+			name: "cache_name_stat_eviction_policy_2q",
+			f: func(m *otelMetrics) {
+				m.CacheEvictionCount(5, "stat", "2q")
+			},
+			expected: map[attribute.Set]int64{
+				attribute.NewSet(attribute.String("cache_name", "stat"), attribute.String("eviction_policy", "2q")): 5,
+			},
+		},
+		{
+			name: "cache_name_stat_eviction_policy_lru",
+			f: func(m *otelMetrics) {
+				m.CacheEvictionCount(5, "stat", "lru")
+			},
+			expected: map[attribute.Set]int64{
+				attribute.NewSet(attribute.String("cache_name", "stat"), attribute.String("eviction_policy", "lru")): 5,
+			},
+		},
+		{
+			name: "cache_name_stat_eviction_policy_tinylfu",
+			f: func(m *otelMetrics) {
+				m.CacheEvictionCount(5, "stat", "tinylfu")
+			},
+			expected: map[attribute.Set]int64{
+				attribute.NewSet(attribute.String("cache_name", "stat"), attribute.String("eviction_policy", "tinylfu")): 5,
+			},
+		}, {
+			name: "multiple_attributes_summed",
+			f: func(m *otelMetrics) {
+				m.CacheEvictionCount(5, "file", "2q")
+				m.CacheEvictionCount(2, "file", "lru")
+				m.CacheEvictionCount(3, "file", "2q")
+			},
+			expected: map[attribute.Set]int64{attribute.NewSet(attribute.String("cache_name", "file"), attribute.String("eviction_policy", "2q")): 8,
+				attribute.NewSet(attribute.String("cache_name", "file"), attribute.String("eviction_policy", "lru")): 2,
+			},
+		},
+		{
+			name: "negative_increment",
+			f: func(m *otelMetrics) {
+				m.CacheEvictionCount(-5, "file", "2q")
+				m.CacheEvictionCount(2, "file", "2q")
+			},
+			expected: map[attribute.Set]int64{attribute.NewSet(attribute.String("cache_name", "file"), attribute.String("eviction_policy", "2q")): 2},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			encoder := attribute.DefaultEncoder()
+			m, rd := setupOTel(ctx, t)
+
+			tc.f(m)
+			waitForMetricsProcessing()
+
+			metrics := gatherNonZeroCounterMetrics(ctx, t, rd)
+			metric, ok := metrics["cache/eviction_count"]
+			if len(tc.expected) == 0 {
+				assert.False(t, ok, "cache/eviction_count metric should not be found")
+				return
+			}
+			require.True(t, ok, "cache/eviction_count metric not found")
+			expectedMap := make(map[string]int64)
+			for k, v := range tc.expected {
+				expectedMap[k.Encoded(encoder)] = v
+			}
+			assert.Equal(t, expectedMap, metric)
+		})
+	}
+}
+
+func TestFileCachePrefetchBytesCount(t *testing.T) {
+	ctx := context.Background()
+	encoder := attribute.DefaultEncoder()
+	m, rd := setupOTel(ctx, t)
+
+	m.FileCachePrefetchBytesCount(1024)
+	m.FileCachePrefetchBytesCount(2048)
+	waitForMetricsProcessing()
+
+	metrics := gatherNonZeroCounterMetrics(ctx, t, rd)
+	metric, ok := metrics["file_cache/prefetch_bytes_count"]
+	require.True(t, ok, "file_cache/prefetch_bytes_count metric not found")
+	s := attribute.NewSet()
+	assert.Equal(t, map[string]int64{s.Encoded(encoder): 3072}, metric, "Positive increments should be summed.")
+
+	// Test negative increment
+	m.FileCachePrefetchBytesCount(-100)
+	waitForMetricsProcessing()
+
+	metrics = gatherNonZeroCounterMetrics(ctx, t, rd)
+	metric, ok = metrics["file_cache/prefetch_bytes_count"]
+	require.True(t, ok, "file_cache/prefetch_bytes_count metric not found after negative increment")
+	assert.Equal(t, map[string]int64{s.Encoded(encoder): 3072}, metric, "Negative increment should not change the metric value.")
+}
+
+func TestFileCachePrefetchCount(t *testing.T) {
+	tests := []struct {
+		name     string
+		f        func(m *otelMetrics)
+		expected map[attribute.Set]int64
+	}{
+		{
+			name: "status_completed",
+			f: func(m *otelMetrics) {
+				m.FileCachePrefetchCount(5, "completed")
+			},
+			expected: map[attribute.Set]int64{
+				attribute.NewSet(attribute.String("status", "completed")): 5,
+			},
+		},
+		{
+			name: "status_excluded",
+			f: func(m *otelMetrics) {
+				m.FileCachePrefetchCount(5, "excluded")
+			},
+			expected: map[attribute.Set]int64{
+				attribute.NewSet(attribute.String("status", "excluded")): 5,
+			},
+		},
+		{
+			name: "status_failed",
+			f: func(m *otelMetrics) {
+				m.FileCachePrefetchCount(5, "failed")
+			},
+			expected: map[attribute.Set]int64{
+				attribute.NewSet(attribute.String("status", "failed")): 5,
+			},
+		}, {
+			name: "multiple_attributes_summed",
+			f: func(m *otelMetrics) {
+				m.FileCachePrefetchCount(5, "completed")
+				m.FileCachePrefetchCount(2, "excluded")
+				m.FileCachePrefetchCount(3, "completed")
+			},
+			expected: map[attribute.Set]int64{attribute.NewSet(attribute.String("status", "completed")): 8,
+				attribute.NewSet(attribute.String("status", "excluded")): 2,
+			},
+		},
+		{
+			name: "negative_increment",
+			f: func(m *otelMetrics) {
+				m.FileCachePrefetchCount(-5, "completed")
+				m.FileCachePrefetchCount(2, "completed")
+			},
+			expected: map[attribute.Set]int64{attribute.NewSet(attribute.String("status", "completed")): 2},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			encoder := attribute.DefaultEncoder()
+			m, rd := setupOTel(ctx, t)
+
+			tc.f(m)
+			waitForMetricsProcessing()
+
+			metrics := gatherNonZeroCounterMetrics(ctx, t, rd)
+			metric, ok := metrics["file_cache/prefetch_count"]
+			if len(tc.expected) == 0 {
+				assert.False(t, ok, "file_cache/prefetch_count metric should not be found")
+				return
+			}
+			require.True(t, ok, "file_cache/prefetch_count metric not found")
+			expectedMap := make(map[string]int64)
+			for k, v := range tc.expected {
+				expectedMap[k.Encoded(encoder)] = v
+			}
+			assert.Equal(t, expectedMap, metric)
+		})
+	}
+}
+
+func TestFileCachePrefetchPendingCount(t *testing.T) {
+	ctx := context.Background()
+	encoder := attribute.DefaultEncoder()
+	m, rd := setupOTel(ctx, t)
+
+	m.FileCachePrefetchPendingCount(1024)
+	m.FileCachePrefetchPendingCount(2048)
+	waitForMetricsProcessing()
+
+	metrics := gatherNonZeroCounterMetrics(ctx, t, rd)
+	metric, ok := metrics["file_cache/prefetch_pending_count"]
+	require.True(t, ok, "file_cache/prefetch_pending_count metric not found")
+	s := attribute.NewSet()
+	assert.Equal(t, map[string]int64{s.Encoded(encoder): 3072}, metric, "Positive increments should be summed.")
+
+	// Test negative increment
+	m.FileCachePrefetchPendingCount(-100)
+	waitForMetricsProcessing()
+
+	metrics = gatherNonZeroCounterMetrics(ctx, t, rd)
+	metric, ok = metrics["file_cache/prefetch_pending_count"]
+	require.True(t, ok, "file_cache/prefetch_pending_count metric not found after negative increment")
+	assert.Equal(t, map[string]int64{s.Encoded(encoder): 2972}, metric, "Negative increment should change the metric value.")
+}
+
 func TestFileCacheReadBytesCount(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -5315,6 +5548,84 @@ func TestGcsRetryCount(t *testing.T) {
 	}
 }
 
+func TestMemoryPressureState(t *testing.T) {
+	tests := []struct {
+		name     string
+		f        func(m *otelMetrics)
+		expected map[attribute.Set]int64
+	}{
+		{
+			name: "pressure_critical",
+			f: func(m *otelMetrics) {
+				m.MemoryPressureState(5, "critical")
+			},
+			expected: map[attribute.Set]int64{
+				attribute.NewSet(attribute.String("pressure", "critical")): 5,
+			},
+		},
+		{
+			name: "pressure_moderate",
+			f: func(m *otelMetrics) {
+				m.MemoryPressureState(5, "moderate")
+			},
+			expected: map[attribute.Set]int64{
+				attribute.NewSet(attribute.String("pressure", "moderate")): 5,
+			},
+		},
+		{
+			name: "pressure_none",
+			f: func(m *otelMetrics) {
+				m.MemoryPressureState(5, "none")
+			},
+			expected: map[attribute.Set]int64{
+				attribute.NewSet(attribute.String("pressure", "none")): 5,
+			},
+		}, {
+			name: "multiple_attributes_summed",
+			f: func(m *otelMetrics) {
+				m.MemoryPressureState(5, "critical")
+				m.MemoryPressureState(2, "moderate")
+				m.MemoryPressureState(3, "critical")
+			},
+			expected: map[attribute.Set]int64{attribute.NewSet(attribute.String("pressure", "critical")): 8,
+				attribute.NewSet(attribute.String("pressure", "moderate")): 2,
+			},
+		},
+		{
+			name: "negative_increment",
+			f: func(m *otelMetrics) {
+				m.MemoryPressureState(-5, "critical")
+				m.MemoryPressureState(2, "critical")
+			},
+			expected: map[attribute.Set]int64{attribute.NewSet(attribute.String("pressure", "critical")): -3},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			encoder := attribute.DefaultEncoder()
+			m, rd := setupOTel(ctx, t)
+
+			tc.f(m)
+			waitForMetricsProcessing()
+
+			metrics := gatherNonZeroCounterMetrics(ctx, t, rd)
+			metric, ok := metrics["memory/pressure_state"]
+			if len(tc.expected) == 0 {
+				assert.False(t, ok, "memory/pressure_state metric should not be found")
+				return
+			}
+			require.True(t, ok, "memory/pressure_state metric not found")
+			expectedMap := make(map[string]int64)
+			for k, v := range tc.expected {
+				expectedMap[k.Encoded(encoder)] = v
+			}
+			assert.Equal(t, expectedMap, metric)
+		})
+	}
+}
+
 func TestTestUpdownCounter(t *testing.T) {
 	ctx := context.Background()
 	encoder := attribute.DefaultEncoder()
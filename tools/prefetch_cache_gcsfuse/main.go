@@ -21,6 +21,9 @@
 // This will prefetch the cache files from the specified bucket
 // with an optional file prefix to filter the GCS objects
 // and download them into the specified cache directory
+//
+// It fills the legacy content cache. To warm up the file cache of a mount
+// instead, use --file-cache-prefetch-paths or 'gcsfuse ctl prefetch'.
 package main
 
 import (